@@ -15,6 +15,9 @@ LDFLAGS += -X "main.BuildDate=$(BUILD_DATE)"
 LDFLAGS += -X "main.BuildHash=$(BUILD_HASH)"
 LDFLAGS += -X "main.BuildHashShort=$(BUILD_HASH_SHORT)"
 
+# Calendar provider, e.g. CALENDAR_PROVIDER=local for the in-memory provider
+LDFLAGS += -X "main.CalendarProvider=$(CALENDAR_PROVIDER)"
+
 GO_BUILD_FLAGS = -ldflags '$(LDFLAGS)'
 
 # Generates mock golang interfaces for testing
@@ -0,0 +1,35 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+)
+
+const (
+	ProviderCalDAV            = Kind
+	ProviderCalDAVDisplayName = "CalDAV Calendar"
+	ProviderCalDAVRepository  = "mattermost-plugin-mscalendar"
+)
+
+func GetCalDAVProviderConfig() config.ProviderConfig {
+	return config.ProviderConfig{
+		Name:        ProviderCalDAV,
+		DisplayName: ProviderCalDAVDisplayName,
+		Repository:  ProviderCalDAVRepository,
+
+		CommandTrigger: ProviderCalDAV,
+
+		TelemetryShortName: ProviderCalDAV,
+
+		BotUsername:    ProviderCalDAV,
+		BotDisplayName: ProviderCalDAVDisplayName,
+
+		Features: config.ProviderFeatures{
+			EncryptedStore:     true,
+			EventNotifications: false,
+			CredentialsConnect: true,
+		},
+	}
+}
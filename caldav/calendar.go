@@ -0,0 +1,180 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func (c *client) GetCalendars(_ string) ([]*remote.Calendar, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	p, err := c.discover()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav GetCalendars")
+	}
+	me, err := c.GetMe()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav GetCalendars")
+	}
+
+	result := []*remote.Calendar{}
+	for _, cal := range p.calendars {
+		result = append(result, &remote.Calendar{
+			ID:    cal.href,
+			Name:  cal.name,
+			Owner: me,
+		})
+	}
+	return result, nil
+}
+
+// GetSharedCalendars is not implemented: only the calendars of the
+// principal are discovered.
+func (c *client) GetSharedCalendars(_ string) ([]*remote.SharedCalendar, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetRoomLists() ([]*remote.RoomList, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetRooms(_ string) ([]*remote.Room, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetDefaultCalendarView(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	return c.GetEventsBetweenDates(remoteUserID, start, end)
+}
+
+// DoBatchCalendarViewDeltaRequests is not implemented: calendar views are read from the
+// server every time.
+func (c *client) DoBatchCalendarViewDeltaRequests(_ []*remote.CalendarViewDeltaParams) ([]*remote.CalendarViewDeltaResponse, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+// DoBatchViewCalendarRequests fetches the views one by one. A user client can
+// only read its own calendar, so requests for other users fail individually.
+func (c *client) DoBatchViewCalendarRequests(allParams []*remote.ViewCalendarParams) ([]*remote.ViewCalendarResponse, error) {
+	me, err := c.GetMe()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav ViewCalendar batch request")
+	}
+
+	result := []*remote.ViewCalendarResponse{}
+	for _, params := range allParams {
+		res := &remote.ViewCalendarResponse{
+			RemoteUserID: params.RemoteUserID,
+		}
+		if params.RemoteUserID != me.ID {
+			res.Error = &remote.APIError{
+				Code:    "ErrorAccessDenied",
+				Message: "a CalDAV user can only view their own calendar",
+			}
+			result = append(result, res)
+			continue
+		}
+
+		events, err := c.GetCalendarView(params.RemoteUserID, params.CalendarID, params.StartTime, params.EndTime)
+		if err != nil {
+			res.Error = &remote.APIError{Message: err.Error()}
+		}
+		res.Events = events
+		result = append(result, res)
+	}
+	return result, nil
+}
+
+// GetSchedule builds the free/busy schedule of the user from their events.
+// A user client can only read its own calendar, so requests for other users
+// fail individually.
+func (c *client) GetSchedule(requests []*remote.ScheduleUserInfo, startTime, endTime *remote.DateTime, availabilityViewInterval int) ([]*remote.ScheduleInformation, error) {
+	me, err := c.GetMe()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav GetSchedule")
+	}
+	ownID := me.ID
+	start, end := startTime.Time(), endTime.Time()
+	result := []*remote.ScheduleInformation{}
+	for _, req := range requests {
+		if req.RemoteUserID != ownID {
+			result = append(result, &remote.ScheduleInformation{
+				ScheduleID: req.Mail,
+				Error: &remote.ScheduleInformationError{
+					ResponseCode: "ErrorAccessDenied",
+					Message:      "a CalDAV user can only view their own calendar",
+				},
+			})
+			continue
+		}
+
+		events, err := c.GetEventsBetweenDates(req.RemoteUserID, start, end)
+		if err != nil {
+			return nil, errors.Wrap(err, "caldav GetSchedule")
+		}
+		result = append(result, remote.NewScheduleInformation(req.Mail, events, start, end, availabilityViewInterval))
+	}
+	return result, nil
+}
+
+// CreateCalendar creates a new calendar collection with MKCALENDAR.
+func (c *client) CreateCalendar(calIn *remote.Calendar) (*remote.Calendar, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	p, err := c.discover()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav CreateCalendar")
+	}
+
+	href := strings.TrimSuffix(p.homeSet, "/") + "/" + model.NewId() + "/"
+	_, _, err = c.do("MKCALENDAR", href, map[string]string{
+		"Content-Type": "application/xml; charset=utf-8",
+	}, fmt.Sprintf(mkcalendar, xmlEscape(calIn.Name)))
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav CreateCalendar")
+	}
+
+	me, err := c.GetMe()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav CreateCalendar")
+	}
+	c.principal = nil
+	return &remote.Calendar{
+		ID:    href,
+		Name:  calIn.Name,
+		Owner: me,
+	}, nil
+}
+
+func (c *client) DeleteCalendar(calID string) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+
+	_, _, err := c.do(http.MethodDelete, calID, nil, "")
+	if err != nil {
+		return errors.Wrap(err, "caldav DeleteCalendar")
+	}
+	c.principal = nil
+	return nil
+}
+
+func (c *client) FindMeetingTimes(_ *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error) {
+	return nil, remote.ErrNotImplemented
+}
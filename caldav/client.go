@@ -0,0 +1,107 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// maxResponseSize bounds the size of any response read from the server.
+const maxResponseSize = 10 * 1024 * 1024
+
+type client struct {
+	// caching the context here since it's a "single-use" client, usually used
+	// within a single API request
+	ctx context.Context
+
+	conf             *config.Config
+	httpClient       *http.Client
+	serverURL        *url.URL
+	mattermostUserID string
+	tokenHelpers     remote.UserTokenHelpers
+
+	// principal is discovered on first use.
+	principal *principal
+
+	bot.Logger
+	bot.Poster
+}
+
+// resolve turns an href returned by the server into an absolute URL.
+func (c *client) resolve(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return c.serverURL.ResolveReference(u).String()
+}
+
+// do sends a request and returns the response body. Any status other than
+// 2xx is returned as an error starting with the HTTP status, e.g.
+// "404 Not Found".
+func (c *client) do(method, u string, headers map[string]string, body string) ([]byte, http.Header, error) {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(c.ctx, method, c.resolve(u), r)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, nil, errors.Errorf("%s %s: %s", method, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, resp.Header, nil
+}
+
+func (c *client) propfind(u, depth, body string) (*multistatus, error) {
+	return c.multistatus("PROPFIND", u, depth, body)
+}
+
+func (c *client) report(u, depth, body string) (*multistatus, error) {
+	return c.multistatus("REPORT", u, depth, body)
+}
+
+func (c *client) multistatus(method, u, depth, body string) (*multistatus, error) {
+	data, _, err := c.do(method, u, map[string]string{
+		"Content-Type": "application/xml; charset=utf-8",
+		"Depth":        depth,
+	}, body)
+	if err != nil {
+		return nil, err
+	}
+
+	ms := &multistatus{}
+	err = xml.Unmarshal(data, ms)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid multistatus response")
+	}
+	return ms, nil
+}
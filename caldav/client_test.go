@@ -0,0 +1,239 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const testInvite = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:invite-1\r\n" +
+	"DTSTART:20240115T100000Z\r\n" +
+	"DTEND:20240115T110000Z\r\n" +
+	"SUMMARY:Review\r\n" +
+	"ORGANIZER:mailto:bob@example.com\r\n" +
+	"ATTENDEE;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:alice@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+type connectedHelpers struct{}
+
+func (connectedHelpers) CheckUserConnected(string) bool                   { return true }
+func (connectedHelpers) DisconnectUserFromStoreIfNecessary(error, string) {}
+func (connectedHelpers) RefreshAndStoreToken(token *oauth2.Token, _ *oauth2.Config, _ string) (*oauth2.Token, error) {
+	return token, nil
+}
+
+func newTestRemote(s *testServer) *impl {
+	conf := &config.Config{}
+	conf.CalDAVServerURL = s.URL + "/dav/"
+	return NewRemote(conf, &bot.NilLogger{}).(*impl)
+}
+
+func newTestClient(t *testing.T, s *testServer) remote.Client {
+	r := newTestRemote(s)
+	tok, err := r.NewTokenFromCredentials(context.Background(), []string{testUsername, testPassword})
+	require.NoError(t, err)
+
+	c, err := r.MakeUserClient(context.Background(), tok, "mattermost_user_id", nil, connectedHelpers{})
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewTokenFromCredentials(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	r := newTestRemote(s)
+
+	for _, tc := range []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{name: "valid credentials", args: []string{testUsername, testPassword}},
+		{name: "wrong password", args: []string{testUsername, "wrong"}, expectErr: true},
+		{name: "missing password", args: []string{testUsername}, expectErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tok, err := r.NewTokenFromCredentials(context.Background(), tc.args)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tokenTypeBasic, tok.Type())
+		})
+	}
+}
+
+func TestCheckConfiguration(t *testing.T) {
+	r := &impl{conf: &config.Config{}}
+	require.Error(t, r.CheckConfiguration(config.StoredConfig{}))
+	require.Error(t, r.CheckConfiguration(config.StoredConfig{CalDAVServerURL: "ftp://example.com"}))
+	require.NoError(t, r.CheckConfiguration(config.StoredConfig{CalDAVServerURL: "https://example.com/dav/"}))
+}
+
+func TestDiscovery(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	me, err := c.GetMe()
+	require.NoError(t, err)
+	require.Equal(t, testPrincipal, me.ID)
+	require.Equal(t, "alice@example.com", me.Mail)
+	require.Equal(t, "Alice", me.DisplayName)
+
+	calendars, err := c.GetCalendars(me.ID)
+	require.NoError(t, err)
+	require.Len(t, calendars, 1)
+	require.Equal(t, testCalendar, calendars[0].ID)
+	require.Equal(t, "Personal", calendars[0].Name)
+
+	settings, err := c.GetMailboxSettings(me.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", settings.TimeZone)
+}
+
+func TestCreateAndViewEvents(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	created, err := c.CreateEvent("", &remote.Event{
+		Subject: "Standup",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(15*time.Minute), "UTC"),
+		Attendees: []*remote.Attendee{{
+			EmailAddress: &remote.EmailAddress{Address: "bob@example.com"},
+		}},
+	})
+	require.NoError(t, err)
+	require.True(t, created.IsOrganizer)
+	require.Contains(t, s.get(testCalendar+created.ID+".ics"), "ORGANIZER:mailto:alice@example.com")
+
+	events, err := c.GetEventsBetweenDates(testPrincipal, start.Add(-time.Hour), start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "Standup", events[0].Subject)
+
+	events, err = c.GetDefaultCalendarView(testPrincipal, start.Add(time.Hour), start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	event, err := c.GetEvent(testPrincipal, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, created.ICalUID, event.ICalUID)
+
+	_, err = c.GetEvent(testPrincipal, "unknown")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404 Not Found")
+
+	res, err := c.DoBatchViewCalendarRequests([]*remote.ViewCalendarParams{
+		{RemoteUserID: testPrincipal, StartTime: start.Add(-time.Hour), EndTime: start.Add(time.Hour)},
+		{RemoteUserID: "/dav/principals/bob/", StartTime: start.Add(-time.Hour), EndTime: start.Add(time.Hour)},
+	})
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Len(t, res[0].Events, 1)
+	require.NotNil(t, res[1].Error)
+}
+
+func TestRespondToEvent(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	href := testCalendar + "invite-1.ics"
+	s.put(href, testInvite)
+
+	event, err := c.GetEvent(testPrincipal, "invite-1")
+	require.NoError(t, err)
+	require.False(t, event.IsOrganizer)
+	require.True(t, event.ResponseRequested)
+	require.Equal(t, remote.EventResponseStatusNotAnswered, event.ResponseStatus.Response)
+
+	require.NoError(t, c.AcceptEvent(testPrincipal, "invite-1", &remote.EventResponse{SendResponse: true}))
+	require.Contains(t, s.get(href), "PARTSTAT=ACCEPTED")
+
+	require.NoError(t, c.TentativelyAcceptEvent(testPrincipal, "invite-1", &remote.EventResponse{Comment: "Might be late"}))
+	require.Contains(t, s.get(href), "PARTSTAT=TENTATIVE")
+	require.Contains(t, s.get(href), "COMMENT:Might be late")
+	require.Contains(t, s.get(href), "SCHEDULE-AGENT=CLIENT")
+
+	require.NoError(t, c.AcceptEvent(testPrincipal, "invite-1", &remote.EventResponse{SendResponse: true}))
+	require.NotContains(t, s.get(href), "COMMENT")
+	require.NotContains(t, s.get(href), "SCHEDULE-AGENT")
+
+	err = c.DeclineEvent(testPrincipal, "invite-1", &remote.EventResponse{
+		SendResponse:    true,
+		ProposedNewTime: &remote.TimeSlot{},
+	})
+	require.ErrorIs(t, err, remote.ErrNotImplemented)
+
+	s.put(href, strings.Replace(testInvite, "SUMMARY:Review\r\n", "SUMMARY:Review\r\nSTATUS:CANCELLED\r\n", 1))
+	err = c.DeclineEvent(testPrincipal, "invite-1", &remote.EventResponse{SendResponse: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), errorEventCanceled)
+}
+
+func TestUpdateAndCancelEvent(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	c := newTestClient(t, s)
+
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	created, err := c.CreateEvent("", &remote.Event{
+		Subject:  "Standup",
+		Start:    remote.NewDateTime(start, "UTC"),
+		End:      remote.NewDateTime(start.Add(15*time.Minute), "UTC"),
+		Location: &remote.Location{DisplayName: "Room 1"},
+		Attendees: []*remote.Attendee{{
+			EmailAddress: &remote.EmailAddress{Address: "bob@example.com"},
+		}},
+	})
+	require.NoError(t, err)
+	href := testCalendar + created.ID + ".ics"
+
+	moved := start.Add(2 * time.Hour)
+	updated, err := c.UpdateEvent(testPrincipal, created.ID, &remote.Event{
+		Subject: "Daily standup",
+		Start:   remote.NewDateTime(moved, "UTC"),
+		End:     remote.NewDateTime(moved.Add(30*time.Minute), "UTC"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Daily standup", updated.Subject)
+	require.Equal(t, "Room 1", updated.Location.DisplayName)
+	require.True(t, updated.Start.Time().Equal(moved))
+	require.True(t, updated.End.Time().Equal(moved.Add(30*time.Minute)))
+	require.Contains(t, s.get(href), "SEQUENCE:1")
+
+	s.put(testCalendar+"invite-1.ics", testInvite)
+	err = c.CancelEvent(testPrincipal, "invite-1", "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), errorNotOrganizer)
+
+	require.NoError(t, c.CancelEvent(testPrincipal, created.ID, "Moved to Friday"))
+	require.Empty(t, s.get(href))
+
+	require.NoError(t, c.DeleteEvent(testPrincipal, "invite-1"))
+	require.Empty(t, s.get(testCalendar+"invite-1.ics"))
+
+	err = c.DeleteEvent(testPrincipal, "invite-1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404 Not Found")
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+type multistatus struct {
+	XMLName   xml.Name   `xml:"DAV: multistatus"`
+	Responses []response `xml:"DAV: response"`
+}
+
+type response struct {
+	Href      string     `xml:"DAV: href"`
+	Propstats []propstat `xml:"DAV: propstat"`
+}
+
+type propstat struct {
+	Prop   prop   `xml:"DAV: prop"`
+	Status string `xml:"DAV: status"`
+}
+
+type prop struct {
+	CurrentUserPrincipal       *hrefProp            `xml:"DAV: current-user-principal"`
+	DisplayName                string               `xml:"DAV: displayname"`
+	ResourceType               *resourceType        `xml:"DAV: resourcetype"`
+	GetETag                    string               `xml:"DAV: getetag"`
+	CalendarHomeSet            *hrefProp            `xml:"urn:ietf:params:xml:ns:caldav calendar-home-set"`
+	CalendarUserAddressSet     *hrefProp            `xml:"urn:ietf:params:xml:ns:caldav calendar-user-address-set"`
+	ScheduleDefaultCalendarURL *hrefProp            `xml:"urn:ietf:params:xml:ns:caldav schedule-default-calendar-URL"`
+	SupportedComponents        *supportedComponents `xml:"urn:ietf:params:xml:ns:caldav supported-calendar-component-set"`
+	CalendarTimezone           string               `xml:"urn:ietf:params:xml:ns:caldav calendar-timezone"`
+	CalendarData               string               `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+}
+
+type hrefProp struct {
+	Hrefs []string `xml:"DAV: href"`
+}
+
+func (h *hrefProp) first() string {
+	if h == nil || len(h.Hrefs) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(h.Hrefs[0])
+}
+
+type resourceType struct {
+	Calendar *struct{} `xml:"urn:ietf:params:xml:ns:caldav calendar"`
+}
+
+type supportedComponents struct {
+	Comps []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"urn:ietf:params:xml:ns:caldav comp"`
+}
+
+func (s *supportedComponents) supports(name string) bool {
+	// Servers that don't report the set support every component type.
+	if s == nil || len(s.Comps) == 0 {
+		return true
+	}
+	for _, comp := range s.Comps {
+		if strings.EqualFold(comp.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// prop returns the properties the server found for the resource.
+func (r *response) prop() *prop {
+	for i := range r.Propstats {
+		if strings.Contains(r.Propstats[i].Status, " 200 ") {
+			return &r.Propstats[i].Prop
+		}
+	}
+	return &prop{}
+}
+
+const (
+	xmlHeader = `<?xml version="1.0" encoding="utf-8"?>` + "\n"
+
+	propfindCurrentUserPrincipal = xmlHeader + `<d:propfind xmlns:d="DAV:">
+  <d:prop><d:current-user-principal/></d:prop>
+</d:propfind>`
+
+	propfindPrincipal = xmlHeader + `<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:displayname/>
+    <c:calendar-home-set/>
+    <c:calendar-user-address-set/>
+    <c:schedule-default-calendar-URL/>
+  </d:prop>
+</d:propfind>`
+
+	propfindCalendars = xmlHeader + `<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:displayname/>
+    <d:resourcetype/>
+    <c:supported-calendar-component-set/>
+    <c:calendar-timezone/>
+  </d:prop>
+</d:propfind>`
+
+	mkcalendar = xmlHeader + `<c:mkcalendar xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:set><d:prop>
+    <d:displayname>%s</d:displayname>
+    <c:supported-calendar-component-set><c:comp name="VEVENT"/></c:supported-calendar-component-set>
+  </d:prop></d:set>
+</c:mkcalendar>`
+)
+
+// timeRangeQuery returns a calendar-query REPORT body for the events
+// overlapping the range, asking the server to expand recurring events.
+func timeRangeQuery(start, end time.Time) string {
+	s := start.UTC().Format(ical.UTCDateTimeFormat)
+	e := end.UTC().Format(ical.UTCDateTimeFormat)
+	return fmt.Sprintf(xmlHeader+`<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:getetag/>
+    <c:calendar-data><c:expand start="%[1]s" end="%[2]s"/></c:calendar-data>
+  </d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VEVENT"><c:time-range start="%[1]s" end="%[2]s"/></c:comp-filter>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`, s, e)
+}
+
+// uidQuery returns a calendar-query REPORT body for the event with the UID.
+func uidQuery(uid string) string {
+	return fmt.Sprintf(xmlHeader+`<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:getetag/>
+    <c:calendar-data/>
+  </d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VEVENT">
+        <c:prop-filter name="UID"><c:text-match collation="i;octet">%s</c:text-match></c:prop-filter>
+      </c:comp-filter>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`, xmlEscape(uid))
+}
+
+func xmlEscape(s string) string {
+	b := &bytes.Buffer{}
+	_ = xml.EscapeText(b, []byte(s))
+	return b.String()
+}
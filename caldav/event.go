@@ -0,0 +1,403 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+const (
+	contentTypeCalendar = "text/calendar; charset=utf-8"
+
+	errorEventCanceled = "You can't respond to a meeting that's been canceled."
+	errorNotOrganizer  = "Your request can't be completed. You need to be an organizer to cancel a meeting."
+)
+
+// object is a calendar object resource stored on the server.
+type object struct {
+	href     string
+	etag     string
+	calendar *ical.Component
+}
+
+// events returns the VEVENTs of the object, the master event first.
+func (o *object) events() []*ical.Component {
+	events := o.calendar.ChildrenNamed(ical.CompEvent)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Prop("RECURRENCE-ID") == nil && events[j].Prop("RECURRENCE-ID") != nil
+	})
+	return events
+}
+
+func (c *client) query(calendarHref, body string) ([]*object, error) {
+	ms, err := c.report(calendarHref, "1", body)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []*object{}
+	for i := range ms.Responses {
+		r := &ms.Responses[i]
+		p := r.prop()
+		if p.CalendarData == "" {
+			continue
+		}
+		cal, err := ical.Parse(strings.NewReader(p.CalendarData))
+		if err != nil {
+			c.Logger.With(bot.LogContext{
+				"href": r.Href,
+			}).Warnf("caldav: skipping invalid calendar object: `%v`.", err)
+			continue
+		}
+		objects = append(objects, &object{
+			href:     r.Href,
+			etag:     p.GetETag,
+			calendar: cal,
+		})
+	}
+	return objects, nil
+}
+
+// findObject looks the event up by UID in every calendar of the user, the
+// default calendar first.
+func (c *client) findObject(p *principal, uid string) (*object, error) {
+	calendars := []*davCalendar{p.defaultCalendar}
+	for _, cal := range p.calendars {
+		if cal != p.defaultCalendar {
+			calendars = append(calendars, cal)
+		}
+	}
+	for _, cal := range calendars {
+		objects, err := c.query(cal.href, uidQuery(uid))
+		if err != nil {
+			return nil, err
+		}
+		if len(objects) > 0 {
+			return objects[0], nil
+		}
+	}
+	return nil, errors.New("404 Not Found: the event was not found")
+}
+
+func (c *client) toEvent(p *principal, vevent *ical.Component) (*remote.Event, error) {
+	return ical.ToEvent(vevent, p.location(), append([]string{p.mail(c.serverURL)}, p.addresses...)...)
+}
+
+func (c *client) GetEvent(_, eventID string) (*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	p, err := c.discover()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav GetEvent")
+	}
+	o, err := c.findObject(p, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav GetEvent")
+	}
+	events := o.events()
+	if len(events) == 0 {
+		return nil, errors.New("caldav GetEvent: the calendar object holds no event")
+	}
+	return c.toEvent(p, events[0])
+}
+
+// CreateEvent stores a new event in the calendar with the given href, or in
+// the default calendar when calendarID is empty. Invitations are sent by
+// servers implementing CalDAV scheduling (RFC 6638).
+func (c *client) CreateEvent(calendarID string, in *remote.Event) (*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	p, err := c.discover()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav CreateEvent")
+	}
+
+	uid := model.NewId()
+	cal, err := ical.FromEvent(in, uid, p.mail(c.serverURL))
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav CreateEvent")
+	}
+
+	target, err := p.calendar(calendarID)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav CreateEvent")
+	}
+
+	href := strings.TrimSuffix(target.href, "/") + "/" + uid + ".ics"
+	_, _, err = c.do(http.MethodPut, href, map[string]string{
+		"Content-Type":  contentTypeCalendar,
+		"If-None-Match": "*",
+	}, cal.String())
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return nil, errors.Wrap(err, "caldav CreateEvent")
+	}
+
+	return c.toEvent(p, cal.ChildrenNamed(ical.CompEvent)[0])
+}
+
+func (c *client) AcceptEvent(_, eventID string, details *remote.EventResponse) error {
+	return c.respond(eventID, remote.EventResponseStatusAccepted, details, "caldav AcceptEvent")
+}
+
+func (c *client) DeclineEvent(_, eventID string, details *remote.EventResponse) error {
+	return c.respond(eventID, remote.EventResponseStatusDeclined, details, "caldav DeclineEvent")
+}
+
+func (c *client) TentativelyAcceptEvent(_, eventID string, details *remote.EventResponse) error {
+	return c.respond(eventID, remote.EventResponseStatusTentative, details, "caldav TentativelyAcceptEvent")
+}
+
+// respond updates the user's PARTSTAT on every instance of the event. The
+// server schedules the reply, which can't carry a counter proposal.
+func (c *client) respond(eventID, response string, details *remote.EventResponse, errContext string) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+	if details.ProposedNewTime != nil {
+		return errors.Wrap(remote.ErrNotImplemented, errContext+": proposing a new time")
+	}
+
+	p, err := c.discover()
+	if err != nil {
+		return errors.Wrap(err, errContext)
+	}
+	o, err := c.findObject(p, eventID)
+	if err != nil {
+		return errors.Wrap(err, errContext)
+	}
+
+	addresses := append([]string{p.mail(c.serverURL)}, p.addresses...)
+	found := false
+	for _, vevent := range o.events() {
+		if strings.EqualFold(vevent.Text("STATUS"), "CANCELLED") {
+			return errors.Wrap(errors.New(errorEventCanceled), errContext)
+		}
+		if ical.SetPartStat(vevent, response, addresses...) {
+			ical.SetReplyOptions(vevent, details.Comment, details.SendResponse)
+			found = true
+		}
+	}
+	if !found {
+		return errors.Errorf("%s: you are not an attendee of this event", errContext)
+	}
+
+	err = c.store(o)
+	if err != nil {
+		return errors.Wrap(err, errContext)
+	}
+	return nil
+}
+
+// store writes the object back to the server. The If-Match header makes the
+// update fail rather than overwrite a concurrent change.
+func (c *client) store(o *object) error {
+	headers := map[string]string{"Content-Type": contentTypeCalendar}
+	if o.etag != "" {
+		headers["If-Match"] = o.etag
+	}
+	_, h, err := c.do(http.MethodPut, o.href, headers, o.calendar.String())
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return err
+	}
+	o.etag = h.Get("ETag")
+	return nil
+}
+
+// UpdateEvent changes the fields set on the given event, leaving the others
+// as they are. Recurring events are updated as a whole series. Servers
+// implementing CalDAV scheduling send the update to the attendees.
+func (c *client) UpdateEvent(_, eventID string, in *remote.Event) (*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	p, o, master, err := c.findEvent(eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav UpdateEvent")
+	}
+	err = ical.UpdateEvent(master, in, p.location())
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav UpdateEvent")
+	}
+	err = c.store(o)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav UpdateEvent")
+	}
+	return c.toEvent(p, master)
+}
+
+// CancelEvent cancels a meeting organized by the user and removes it from
+// their calendar. The comment is sent with the cancellation by servers
+// implementing CalDAV scheduling.
+func (c *client) CancelEvent(_, eventID, comment string) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+
+	p, o, master, err := c.findEvent(eventID)
+	if err != nil {
+		return errors.Wrap(err, "caldav CancelEvent")
+	}
+	e, err := c.toEvent(p, master)
+	if err != nil {
+		return errors.Wrap(err, "caldav CancelEvent")
+	}
+	if !e.IsOrganizer {
+		return errors.Wrap(errors.New(errorNotOrganizer), "caldav CancelEvent")
+	}
+
+	for _, vevent := range o.events() {
+		ical.CancelEvent(vevent, comment)
+	}
+	err = c.store(o)
+	if err != nil {
+		return errors.Wrap(err, "caldav CancelEvent")
+	}
+	err = c.remove(o)
+	if err != nil {
+		return errors.Wrap(err, "caldav CancelEvent")
+	}
+	return nil
+}
+
+// DeleteEvent removes the event from the calendar of the user. Servers
+// implementing CalDAV scheduling cancel the meetings deleted by their
+// organizer.
+func (c *client) DeleteEvent(_, eventID string) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+
+	_, o, _, err := c.findEvent(eventID)
+	if err != nil {
+		return errors.Wrap(err, "caldav DeleteEvent")
+	}
+	err = c.remove(o)
+	if err != nil {
+		return errors.Wrap(err, "caldav DeleteEvent")
+	}
+	return nil
+}
+
+func (c *client) remove(o *object) error {
+	headers := map[string]string{}
+	if o.etag != "" {
+		headers["If-Match"] = o.etag
+	}
+	_, _, err := c.do(http.MethodDelete, o.href, headers, "")
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return err
+	}
+	return nil
+}
+
+// findEvent returns the object holding the event and its master VEVENT.
+func (c *client) findEvent(eventID string) (*principal, *object, *ical.Component, error) {
+	p, err := c.discover()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	o, err := c.findObject(p, eventID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	events := o.events()
+	if len(events) == 0 {
+		return nil, nil, nil, errors.New("the calendar object holds no event")
+	}
+	return p, o, events[0], nil
+}
+
+// GetEventsBetweenDates returns the events of the user's default calendar
+// overlapping the range, with recurring events expanded by the server.
+func (c *client) GetEventsBetweenDates(_ string, start, end time.Time) ([]*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	events, err := c.eventsBetween("", start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav GetEventsBetweenDates")
+	}
+	return events, nil
+}
+
+// GetCalendarView returns the events of the calendar with the given href
+// overlapping the range.
+func (c *client) GetCalendarView(_, calendarID string, start, end time.Time) ([]*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	events, err := c.eventsBetween(calendarID, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav GetCalendarView")
+	}
+	return events, nil
+}
+
+// eventsBetween reads the events of a calendar of the user, their default
+// calendar when calendarHref is empty.
+func (c *client) eventsBetween(calendarHref string, start, end time.Time) ([]*remote.Event, error) {
+	p, err := c.discover()
+	if err != nil {
+		return nil, err
+	}
+	cal, err := p.calendar(calendarHref)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := c.query(cal.href, timeRangeQuery(start, end))
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return nil, err
+	}
+
+	result := []*remote.Event{}
+	for _, o := range objects {
+		for _, vevent := range o.events() {
+			e, err := c.toEvent(p, vevent)
+			if err != nil {
+				c.Logger.With(bot.LogContext{
+					"href": o.href,
+				}).Warnf("caldav: skipping invalid event: `%v`.", err)
+				continue
+			}
+			if !e.Start.Time().Before(end) || !e.End.Time().After(start) {
+				continue
+			}
+			result = append(result, e)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Time().Before(result[j].Start.Time())
+	})
+	return result, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const Kind = "caldav"
+
+// tokenTypeBasic makes oauth2.Token send the stored credentials with HTTP
+// Basic authentication.
+const tokenTypeBasic = "Basic"
+
+type impl struct {
+	conf   *config.Config
+	logger bot.Logger
+}
+
+func init() {
+	remote.Makers[Kind] = NewRemote
+}
+
+func NewRemote(conf *config.Config, logger bot.Logger) remote.Remote {
+	return &impl{
+		conf:   conf,
+		logger: logger,
+	}
+}
+
+func (r *impl) makeClient(ctx context.Context, token *oauth2.Token, mattermostUserID string, poster bot.Poster, userTokenHelpers remote.UserTokenHelpers) (*client, error) {
+	serverURL, err := parseServerURL(r.conf.CalDAVServerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{
+		ctx:              ctx,
+		conf:             r.conf,
+		httpClient:       oauth2.NewClient(ctx, oauth2.StaticTokenSource(token)),
+		serverURL:        serverURL,
+		mattermostUserID: mattermostUserID,
+		tokenHelpers:     userTokenHelpers,
+		Logger:           r.logger,
+		Poster:           poster,
+	}, nil
+}
+
+// MakeUserClient creates a new client authenticating with the credentials
+// stored for the user. CalDAV credentials don't expire, so there is nothing to
+// refresh.
+func (r *impl) MakeUserClient(ctx context.Context, oauthToken *oauth2.Token, mattermostUserID string, poster bot.Poster, userTokenHelpers remote.UserTokenHelpers) (remote.Client, error) {
+	if oauthToken == nil {
+		err := errors.New(ErrorUserInactive)
+		userTokenHelpers.DisconnectUserFromStoreIfNecessary(err, mattermostUserID)
+		return nil, errors.Wrap(err, "caldav MakeUserClient")
+	}
+
+	c, err := r.makeClient(ctx, oauthToken, mattermostUserID, poster, userTokenHelpers)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav MakeUserClient")
+	}
+	return c, nil
+}
+
+// MakeSuperuserClient is not supported: CalDAV servers have no application
+// credentials that can read every user's calendar. Jobs fall back to fetching
+// each user's calendar with their own credentials.
+func (r *impl) MakeSuperuserClient(_ context.Context) (remote.Client, error) {
+	return nil, remote.ErrSuperUserClientNotSupported
+}
+
+// NewOAuth2Config is only used to satisfy the token helpers: CalDAV users
+// connect with credentials, see NewTokenFromCredentials.
+func (r *impl) NewOAuth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		RedirectURL: r.conf.PluginURL + config.FullPathOAuth2Redirect,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  r.conf.CalDAVServerURL,
+			TokenURL: r.conf.CalDAVServerURL,
+		},
+	}
+}
+
+// NewTokenFromCredentials checks the username and password against the
+// CalDAV server and returns them as a token to store for the user.
+func (r *impl) NewTokenFromCredentials(ctx context.Context, args []string) (*oauth2.Token, error) {
+	if len(args) != 2 {
+		return nil, errors.New("please provide your username and an app password")
+	}
+
+	token := &oauth2.Token{
+		AccessToken: base64.StdEncoding.EncodeToString([]byte(args[0] + ":" + args[1])),
+		TokenType:   tokenTypeBasic,
+	}
+
+	c, err := r.makeClient(ctx, token, "", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	_, err = c.discover()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to sign in to the CalDAV server")
+	}
+	return token, nil
+}
+
+func (r *impl) CheckConfiguration(cfg config.StoredConfig) error {
+	_, err := parseServerURL(cfg.CalDAVServerURL)
+	return err
+}
+
+func parseServerURL(serverURL string) (*url.URL, error) {
+	if serverURL == "" {
+		return nil, errors.New("CalDAV server URL to be set in the config")
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Errorf("invalid CalDAV server URL %q", serverURL)
+	}
+	return u, nil
+}
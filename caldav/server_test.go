@@ -0,0 +1,186 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+const (
+	testUsername  = "alice"
+	testPassword  = "app-password"
+	testPrincipal = "/dav/principals/alice/"
+	testHome      = "/dav/calendars/alice/"
+	testCalendar  = "/dav/calendars/alice/personal/"
+	testTasks     = "/dav/calendars/alice/tasks/"
+)
+
+// testServer is a minimal CalDAV server stand-in holding the calendars of a
+// single user. Time-range queries don't expand recurring events.
+type testServer struct {
+	*httptest.Server
+
+	lock    sync.Mutex
+	objects map[string]string
+	etags   map[string]string
+	version int
+}
+
+func newTestServer() *testServer {
+	s := &testServer{
+		objects: map[string]string{},
+		etags:   map[string]string{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *testServer) put(href, data string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.version++
+	s.objects[href] = data
+	s.etags[href] = fmt.Sprintf(`"%d"`, s.version)
+}
+
+func (s *testServer) get(href string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.objects[href]
+}
+
+func (s *testServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok || username != testUsername || password != testPassword {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch {
+	case r.Method == "PROPFIND" && r.URL.Path == "/dav/":
+		s.multistatus(w, response207("/dav/", `<d:current-user-principal><d:href>`+testPrincipal+`</d:href></d:current-user-principal>`))
+	case r.Method == "PROPFIND" && r.URL.Path == testPrincipal:
+		s.multistatus(w, response207(testPrincipal, `<d:displayname>Alice</d:displayname>
+			<c:calendar-home-set><d:href>`+testHome+`</d:href></c:calendar-home-set>
+			<c:calendar-user-address-set><d:href>/dav/principals/alice/</d:href><d:href>mailto:alice@example.com</d:href></c:calendar-user-address-set>`))
+	case r.Method == "PROPFIND" && r.URL.Path == testHome:
+		s.multistatus(w,
+			response207(testHome, `<d:resourcetype><d:collection/></d:resourcetype>`),
+			response207(testCalendar, `<d:displayname>Personal</d:displayname>
+				<d:resourcetype><d:collection/><c:calendar/></d:resourcetype>
+				<c:supported-calendar-component-set><c:comp name="VEVENT"/></c:supported-calendar-component-set>
+				<c:calendar-timezone>BEGIN:VCALENDAR&#13;
+BEGIN:VTIMEZONE&#13;
+TZID:Europe/Berlin&#13;
+END:VTIMEZONE&#13;
+END:VCALENDAR&#13;
+</c:calendar-timezone>`),
+			response207(testTasks, `<d:displayname>Tasks</d:displayname>
+				<d:resourcetype><d:collection/><c:calendar/></d:resourcetype>
+				<c:supported-calendar-component-set><c:comp name="VTODO"/></c:supported-calendar-component-set>`),
+		)
+	case r.Method == "REPORT" && strings.HasPrefix(r.URL.Path, testHome):
+		s.report(w, r.URL.Path, string(body))
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, testHome):
+		etag, exists := s.etags[r.URL.Path]
+		if (r.Header.Get("If-None-Match") == "*" && exists) || (r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if _, err := ical.Parse(strings.NewReader(string(body))); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.version++
+		s.objects[r.URL.Path] = string(body)
+		s.etags[r.URL.Path] = fmt.Sprintf(`"%d"`, s.version)
+		w.Header().Set("ETag", s.etags[r.URL.Path])
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, ".ics"):
+		etag, exists := s.etags[r.URL.Path]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		delete(s.objects, r.URL.Path)
+		delete(s.etags, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "MKCALENDAR" || r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+var (
+	timeRangeRegexp = regexp.MustCompile(`<c:time-range start="([0-9TZ]+)" end="([0-9TZ]+)"/>`)
+	textMatchRegexp = regexp.MustCompile(`<c:text-match[^>]*>([^<]*)</c:text-match>`)
+)
+
+func (s *testServer) report(w http.ResponseWriter, calendarHref, body string) {
+	var start, end time.Time
+	if m := timeRangeRegexp.FindStringSubmatch(body); m != nil {
+		start, _ = time.Parse(ical.UTCDateTimeFormat, m[1])
+		end, _ = time.Parse(ical.UTCDateTimeFormat, m[2])
+	}
+	uid := ""
+	if m := textMatchRegexp.FindStringSubmatch(body); m != nil {
+		uid = m[1]
+	}
+
+	responses := []string{}
+	for href, data := range s.objects {
+		if !strings.HasPrefix(href, calendarHref) {
+			continue
+		}
+		cal, err := ical.Parse(strings.NewReader(data))
+		if err != nil {
+			continue
+		}
+		vevent := cal.ChildrenNamed(ical.CompEvent)[0]
+		if uid != "" && vevent.Text("UID") != uid {
+			continue
+		}
+		if !start.IsZero() {
+			eventStart, _, _ := ical.ParseTime(vevent.Prop("DTSTART"), time.UTC)
+			eventEnd, _, _ := ical.ParseTime(vevent.Prop("DTEND"), time.UTC)
+			if !eventStart.Before(end) || !eventEnd.After(start) {
+				continue
+			}
+		}
+		responses = append(responses, response207(href, `<d:getetag>`+s.etags[href]+`</d:getetag><c:calendar-data>`+xmlEscape(data)+`</c:calendar-data>`))
+	}
+	s.multistatus(w, responses...)
+}
+
+func (s *testServer) multistatus(w http.ResponseWriter, responses ...string) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">` + strings.Join(responses, "") + `</d:multistatus>`))
+}
+
+func response207(href, props string) string {
+	return `<d:response><d:href>` + href + `</d:href><d:propstat><d:prop>` + props + `</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`
+}
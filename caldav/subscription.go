@@ -0,0 +1,38 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// CalDAV has no push notifications, so the provider is configured without
+// event notifications and none of the subscription methods are implemented.
+
+func (c *client) CreateMySubscription(_, _, _ string) (*remote.Subscription, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) DeleteSubscription(_ *remote.Subscription) error {
+	return remote.ErrNotImplemented
+}
+
+func (c *client) RenewSubscription(_, _ string, _ *remote.Subscription) (*remote.Subscription, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) ListSubscriptions() ([]*remote.Subscription, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetNotificationData(_ *remote.Notification) (*remote.Notification, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (r *impl) HandleWebhook(w http.ResponseWriter, _ *http.Request) []*remote.Notification {
+	w.WriteHeader(http.StatusNotImplemented)
+	return nil
+}
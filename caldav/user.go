@@ -0,0 +1,211 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+const (
+	ErrorUserInactive = "You have been marked inactive because your CalDAV credentials are no longer valid. Please disconnect and reconnect your account again."
+	LogUserInactive   = "User %s is inactive. Please disconnect and reconnect your account."
+
+	defaultTimeZone = "UTC"
+)
+
+// principal is the authenticated CalDAV user and their calendars.
+type principal struct {
+	href            string
+	homeSet         string
+	displayName     string
+	addresses       []string
+	defaultCalendar *davCalendar
+	calendars       []*davCalendar
+}
+
+type davCalendar struct {
+	href     string
+	name     string
+	timeZone string
+}
+
+// discover finds the current user principal, their calendar home and the
+// calendars it contains (RFC 4791 and RFC 5397).
+func (c *client) discover() (*principal, error) {
+	if c.principal != nil {
+		return c.principal, nil
+	}
+
+	ms, err := c.propfind(c.serverURL.String(), "0", propfindCurrentUserPrincipal)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav discover principal")
+	}
+	p := &principal{}
+	for i := range ms.Responses {
+		if href := ms.Responses[i].prop().CurrentUserPrincipal.first(); href != "" {
+			p.href = href
+			break
+		}
+	}
+	if p.href == "" {
+		return nil, errors.New("caldav discover: the server did not report the current user principal")
+	}
+
+	ms, err = c.propfind(p.href, "0", propfindPrincipal)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav discover calendar home")
+	}
+	if len(ms.Responses) == 0 {
+		return nil, errors.New("caldav discover: empty principal response")
+	}
+	pp := ms.Responses[0].prop()
+	p.displayName = pp.DisplayName
+	p.homeSet = pp.CalendarHomeSet.first()
+	if p.homeSet == "" {
+		return nil, errors.New("caldav discover: the server did not report a calendar home")
+	}
+	if pp.CalendarUserAddressSet != nil {
+		for _, href := range pp.CalendarUserAddressSet.Hrefs {
+			if address := strings.TrimSpace(href); strings.HasPrefix(strings.ToLower(address), "mailto:") {
+				p.addresses = append(p.addresses, ical.Address(address))
+			}
+		}
+	}
+	defaultCalendarHref := pp.ScheduleDefaultCalendarURL.first()
+
+	ms, err = c.propfind(p.homeSet, "1", propfindCalendars)
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav discover calendars")
+	}
+	for i := range ms.Responses {
+		r := &ms.Responses[i]
+		cp := r.prop()
+		if cp.ResourceType == nil || cp.ResourceType.Calendar == nil || !cp.SupportedComponents.supports(ical.CompEvent) {
+			continue
+		}
+		cal := &davCalendar{
+			href:     r.Href,
+			name:     cp.DisplayName,
+			timeZone: calendarTimeZone(cp.CalendarTimezone),
+		}
+		if cal.name == "" {
+			cal.name = path.Base(strings.TrimSuffix(r.Href, "/"))
+		}
+		p.calendars = append(p.calendars, cal)
+		if p.defaultCalendar == nil || sameHref(r.Href, defaultCalendarHref) {
+			p.defaultCalendar = cal
+		}
+	}
+	if p.defaultCalendar == nil {
+		return nil, errors.New("caldav discover: no event calendar found")
+	}
+
+	c.principal = p
+	return p, nil
+}
+
+// calendar returns the calendar with the given href, or the default calendar
+// when href is empty.
+func (p *principal) calendar(href string) (*davCalendar, error) {
+	if href == "" {
+		return p.defaultCalendar, nil
+	}
+	for _, cal := range p.calendars {
+		if sameHref(cal.href, href) {
+			return cal, nil
+		}
+	}
+	return nil, errors.New("404 Not Found: the calendar was not found")
+}
+
+func sameHref(a, b string) bool {
+	pa, errA := url.Parse(a)
+	pb, errB := url.Parse(b)
+	if errA != nil || errB != nil || b == "" {
+		return false
+	}
+	return strings.TrimSuffix(pa.Path, "/") == strings.TrimSuffix(pb.Path, "/")
+}
+
+// calendarTimeZone extracts the TZID of a calendar-timezone property.
+func calendarTimeZone(data string) string {
+	if data == "" {
+		return ""
+	}
+	cal, err := ical.Parse(strings.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	for _, vtz := range cal.ChildrenNamed(ical.CompTimezone) {
+		if tzid := vtz.Text("TZID"); tzid != "" {
+			return tzid
+		}
+	}
+	return ""
+}
+
+// mail returns the address identifying the user in events they organize or
+// are invited to.
+func (p *principal) mail(serverURL *url.URL) string {
+	if len(p.addresses) > 0 {
+		return p.addresses[0]
+	}
+	return path.Base(strings.TrimSuffix(p.href, "/")) + "@" + serverURL.Hostname()
+}
+
+func (c *client) GetMe() (*remote.User, error) {
+	p, err := c.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	mail := p.mail(c.serverURL)
+	displayName := p.displayName
+	if displayName == "" {
+		displayName = mail
+	}
+	return &remote.User{
+		ID:                p.href,
+		DisplayName:       displayName,
+		UserPrincipalName: mail,
+		Mail:              mail,
+	}, nil
+}
+
+func (c *client) GetMailboxSettings(_ string) (*remote.MailboxSettings, error) {
+	p, err := c.discover()
+	if err != nil {
+		return nil, errors.Wrap(err, "caldav GetMailboxSettings")
+	}
+
+	timeZone := defaultTimeZone
+	if name := p.defaultCalendar.timeZone; name != "" && ical.LoadLocation(name, nil) != nil {
+		timeZone = name
+	}
+
+	settings := &remote.MailboxSettings{
+		TimeZone: timeZone,
+		WorkingHours: remote.WorkingHours{
+			StartTime:  "08:00:00.0000000",
+			EndTime:    "17:00:00.0000000",
+			DaysOfWeek: []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+		},
+	}
+	settings.WorkingHours.TimeZone.Name = timeZone
+	return settings, nil
+}
+
+// location returns the time zone floating times of the default calendar are
+// interpreted in.
+func (p *principal) location() *time.Location {
+	return ical.LoadLocation(p.defaultCalendar.timeZone, time.UTC)
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package caldav
+
+import (
+	"net/url"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func (c *client) GetSuperuserToken() (string, error) {
+	return "", remote.ErrSuperUserClientNotSupported
+}
+
+func (c *client) CallJSON(_, _ string, _, _ interface{}) ([]byte, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) CallFormPost(_, _ string, _ url.Values, _ interface{}) ([]byte, error) {
+	return nil, remote.ErrNotImplemented
+}
@@ -28,13 +28,21 @@ func Init(h *httputils.Handler, env engine.Env, notificationProcessor engine.Not
 
 	notificationRouter := h.Router.PathPrefix(config.PathNotification).Subrouter()
 	notificationRouter.HandleFunc(config.PathEvent, api.notification).Methods(http.MethodPost)
+	notificationRouter.HandleFunc(config.PathLifecycle, api.notification).Methods(http.MethodPost)
 
 	postActionRouter := h.Router.PathPrefix(config.PathPostAction).Subrouter()
 	postActionRouter.HandleFunc(config.PathAccept, api.postActionAccept).Methods(http.MethodPost)
 	postActionRouter.HandleFunc(config.PathDecline, api.postActionDecline).Methods(http.MethodPost)
 	postActionRouter.HandleFunc(config.PathTentative, api.postActionTentative).Methods(http.MethodPost)
 	postActionRouter.HandleFunc(config.PathRespond, api.postActionRespond).Methods(http.MethodPost)
+	postActionRouter.HandleFunc(config.PathRespondWithComment, api.postActionRespondWithComment).Methods(http.MethodPost)
 	postActionRouter.HandleFunc(config.PathConfirmStatusChange, api.postActionConfirmStatusChange).Methods(http.MethodPost)
+	postActionRouter.HandleFunc(config.PathBookRoom, api.postActionBookRoom).Methods(http.MethodPost)
+	postActionRouter.HandleFunc(config.PathScheduleMeeting, api.postActionScheduleMeeting).Methods(http.MethodPost)
+
+	dialogsRouter := h.Router.PathPrefix(config.PathDialogs).Subrouter()
+	dialogsRouter.HandleFunc(config.PathRespond, api.submitRespondDialog).Methods(http.MethodPost)
+	dialogsRouter.HandleFunc(config.PathConnect, api.submitConnectDialog).Methods(http.MethodPost)
 
 	dialogRouter := h.Router.PathPrefix(config.PathAutocomplete).Subrouter()
 	dialogRouter.HandleFunc(config.PathUsers, api.autocompleteConnectedUsers).Methods(http.MethodGet)
@@ -43,6 +51,8 @@ func Init(h *httputils.Handler, env engine.Env, notificationProcessor engine.Not
 	eventsRouter := apiRoutes.PathPrefix(config.PathEvents).Subrouter()
 	eventsRouter.HandleFunc(config.PathCreate, api.createEvent).Methods(http.MethodPost)
 	eventsRouter.HandleFunc(config.PathView, api.viewEvents).Methods(http.MethodGet)
+	eventsRouter.HandleFunc(config.PathEventID, api.updateEvent).Methods(http.MethodPatch)
+	eventsRouter.HandleFunc(config.PathEventID, api.cancelEvent).Methods(http.MethodDelete)
 	apiRoutes.HandleFunc(config.PathConnectedUser, api.connectedUserHandler).Methods(http.MethodGet)
 
 	apiRoutes.HandleFunc(config.PathProvider, api.getProviderConfiguration).Methods(http.MethodGet)
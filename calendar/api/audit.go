@@ -28,3 +28,47 @@ func (r CreateEventAuditResult) Auditable() map[string]any {
 		"ical_uid": r.ICalUID,
 	}
 }
+
+// UpdateEventAuditParams holds request audit data for the updateEvent operation.
+type UpdateEventAuditParams struct {
+	MattermostUserID string `json:"mattermost_user_id"`
+	EventID          string `json:"event_id"`
+	Rescheduled      bool   `json:"rescheduled"`
+}
+
+func (p UpdateEventAuditParams) Auditable() map[string]any {
+	return map[string]any{
+		"mattermost_user_id": p.MattermostUserID,
+		"event_id":           p.EventID,
+		"rescheduled":        p.Rescheduled,
+	}
+}
+
+// CancelEventAuditParams holds request audit data for the cancelEvent operation.
+type CancelEventAuditParams struct {
+	MattermostUserID string `json:"mattermost_user_id"`
+	EventID          string `json:"event_id"`
+	WithComment      bool   `json:"with_comment"`
+}
+
+func (p CancelEventAuditParams) Auditable() map[string]any {
+	return map[string]any{
+		"mattermost_user_id": p.MattermostUserID,
+		"event_id":           p.EventID,
+		"with_comment":       p.WithComment,
+	}
+}
+
+// EventAuditResult holds the outcome of the updateEvent and cancelEvent
+// operations.
+type EventAuditResult struct {
+	ICalUID     string `json:"ical_uid"`
+	IsOrganizer bool   `json:"is_organizer"`
+}
+
+func (r EventAuditResult) Auditable() map[string]any {
+	return map[string]any{
+		"ical_uid":     r.ICalUID,
+		"is_organizer": r.IsOrganizer,
+	}
+}
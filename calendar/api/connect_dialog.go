@@ -0,0 +1,47 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/httputils"
+)
+
+// submitConnectDialog connects the user with the credentials entered in the
+// connect dialog.
+func (api *api) submitConnectDialog(w http.ResponseWriter, req *http.Request) {
+	mattermostUserID := req.Header.Get("Mattermost-User-ID")
+	if mattermostUserID == "" {
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
+	if request.Cancelled {
+		return
+	}
+
+	username, _ := request.Submission[engine.ConnectDialogUsername].(string)
+	password, _ := request.Submission[engine.ConnectDialogPassword].(string)
+	if username == "" || password == "" {
+		writeDialogError(w, "Please enter your username and password.", nil)
+		return
+	}
+
+	err := engine.New(api.Env, mattermostUserID).ConnectWithCredentials(mattermostUserID, []string{username, password})
+	if err != nil {
+		writeDialogError(w, "Failed to connect: "+err.Error(), nil)
+		return
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestSubmitConnectDialog(t *testing.T) {
+	api, _, _, _, _, _, _, _ := GetMockSetup(t)
+	api.Config = &config.Config{Provider: config.ProviderConfig{DisplayName: "CalDAV"}}
+
+	tests := []struct {
+		name          string
+		submission    map[string]interface{}
+		expectedError string
+	}{
+		{
+			name: "Missing password",
+			submission: map[string]interface{}{
+				engine.ConnectDialogUsername: "alice",
+			},
+			expectedError: "Please enter your username and password.",
+		},
+		{
+			name: "Remote without credentials",
+			submission: map[string]interface{}{
+				engine.ConnectDialogUsername: "alice",
+				engine.ConnectDialogPassword: "secret",
+			},
+			expectedError: "Failed to connect: CalDAV does not support connecting with credentials",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _ := json.Marshal(model.SubmitDialogRequest{
+				UserId:     MockUserID,
+				Submission: tc.submission,
+			})
+			req := httptest.NewRequest(http.MethodPost, config.PathDialogs+config.PathConnect, bytes.NewBuffer(body))
+			req.Header.Set(MMUserIDHeader, MockUserID)
+			rec := httptest.NewRecorder()
+
+			api.submitConnectDialog(rec, req)
+
+			var response model.SubmitDialogResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+			assert.Equal(t, tc.expectedError, response.Error)
+		})
+	}
+}
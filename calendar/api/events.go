@@ -31,6 +31,13 @@ const (
 	maxSubjectLen     = 500
 	maxDescriptionLen = 8000
 	maxLocationLen    = 500
+
+	recurrenceDaily   = "daily"
+	recurrenceWeekly  = "weekly"
+	recurrenceMonthly = "monthly"
+
+	maxRecurrenceInterval = 99
+	maxRecurrenceCount    = 999
 )
 
 type createEventPayload struct {
@@ -44,6 +51,126 @@ type createEventPayload struct {
 	Subject     string `json:"subject"`
 	Location    string `json:"location,omitempty"`
 	ChannelID   string `json:"channel_id"`
+	// CalendarID is the calendar to create the event in, the default
+	// calendar of the user when empty.
+	CalendarID string `json:"calendar_id,omitempty"`
+
+	Recurrence *createEventRecurrence `json:"recurrence,omitempty"`
+
+	// OnlineMeeting adds an online meeting to the event, with the provider
+	// of OnlineMeetingProvider, Teams by default.
+	OnlineMeeting         bool   `json:"online_meeting,omitempty"`
+	OnlineMeetingProvider string `json:"online_meeting_provider,omitempty"`
+
+	// Rooms are the addresses of the rooms to book, invited as resource
+	// attendees.
+	Rooms []string `json:"rooms,omitempty"`
+}
+
+// createEventRecurrence repeats the event from its date. Weekly events repeat
+// on the day of the week of the event unless days are given, and monthly
+// events on its day of the month. The recurrence ends at EndDate, after Count
+// occurrences, or never when neither is set. A zero Interval or Count is not
+// set: the event repeats every period, and Count doesn't end the recurrence.
+type createEventRecurrence struct {
+	Frequency  string   `json:"frequency"`
+	Interval   int      `json:"interval,omitempty"`
+	DaysOfWeek []string `json:"days_of_week,omitempty"`
+	EndDate    string   `json:"end_date,omitempty"`
+	Count      int      `json:"count,omitempty"`
+}
+
+func (cer createEventRecurrence) ToRemoteRecurrence(date time.Time) *remote.PatternedRecurrence {
+	pattern := &remote.RecurrencePattern{
+		Interval: cer.Interval,
+	}
+	if pattern.Interval == 0 {
+		pattern.Interval = 1
+	}
+
+	switch cer.Frequency {
+	case recurrenceDaily:
+		pattern.Type = remote.RecurrencePatternDaily
+	case recurrenceWeekly:
+		pattern.Type = remote.RecurrencePatternWeekly
+		pattern.FirstDayOfWeek = "sunday"
+		for _, day := range cer.DaysOfWeek {
+			pattern.DaysOfWeek = append(pattern.DaysOfWeek, strings.ToLower(day))
+		}
+		if len(pattern.DaysOfWeek) == 0 {
+			pattern.DaysOfWeek = []string{strings.ToLower(date.Weekday().String())}
+		}
+	case recurrenceMonthly:
+		pattern.Type = remote.RecurrencePatternAbsoluteMonthly
+		pattern.DayOfMonth = date.Day()
+	}
+
+	rng := &remote.RecurrenceRange{
+		Type:      remote.RecurrenceRangeNoEnd,
+		StartDate: date.Format(createEventDateFormat),
+	}
+	switch {
+	case cer.EndDate != "":
+		rng.Type = remote.RecurrenceRangeEndDate
+		rng.EndDate = cer.EndDate
+	case cer.Count > 0:
+		rng.Type = remote.RecurrenceRangeNumbered
+		rng.NumberOfOccurrences = cer.Count
+	}
+
+	return &remote.PatternedRecurrence{
+		Pattern: pattern,
+		Range:   rng,
+	}
+}
+
+func (cer createEventRecurrence) IsValid(date time.Time) error {
+	switch cer.Frequency {
+	case recurrenceDaily, recurrenceWeekly, recurrenceMonthly:
+	default:
+		return fmt.Errorf("recurrence frequency must be one of %s, %s or %s", recurrenceDaily, recurrenceWeekly, recurrenceMonthly)
+	}
+	// Zero is the default interval of 1
+	if cer.Interval < 0 || cer.Interval > maxRecurrenceInterval {
+		return fmt.Errorf("recurrence interval must be between 1 and %d, or not set to repeat every period", maxRecurrenceInterval)
+	}
+
+	if len(cer.DaysOfWeek) > 0 && cer.Frequency != recurrenceWeekly {
+		return fmt.Errorf("days of the week can only be set for weekly recurrences")
+	}
+	for _, day := range cer.DaysOfWeek {
+		if !isDayOfWeek(day) {
+			return fmt.Errorf("invalid day of the week %q", day)
+		}
+	}
+
+	if cer.EndDate != "" && cer.Count != 0 {
+		return fmt.Errorf("recurrence cannot have both an end date and a number of occurrences")
+	}
+	// Zero leaves the recurrence to its end date, if any
+	if cer.Count < 0 || cer.Count > maxRecurrenceCount {
+		return fmt.Errorf("number of occurrences must be between 1 and %d, or not set", maxRecurrenceCount)
+	}
+	if cer.EndDate != "" {
+		end, err := time.ParseInLocation(createEventDateFormat, cer.EndDate, date.Location())
+		if err != nil {
+			return fmt.Errorf("invalid recurrence end date")
+		}
+		if end.Before(date) {
+			return fmt.Errorf("recurrence end date cannot be earlier than the event date")
+		}
+	}
+
+	return nil
+}
+
+func isDayOfWeek(s string) bool {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(s, day.String()) {
+			return true
+		}
+	}
+	return false
 }
 
 func (cep createEventPayload) ToRemoteEvent(loc *time.Location) (*remote.Event, error) {
@@ -93,11 +220,33 @@ func (cep createEventPayload) ToRemoteEvent(loc *time.Location) (*remote.Event,
 		}
 	}
 	evt.Subject = cep.Subject
+	if cep.Recurrence != nil {
+		date, err := cep.parseDate(loc)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing date")
+		}
+		evt.Recurrence = cep.Recurrence.ToRemoteRecurrence(date)
+	}
 	if cep.Location != "" {
 		evt.Location = &remote.Location{
 			DisplayName: cep.Location,
 		}
 	}
+	for _, room := range cep.Rooms {
+		evt.Attendees = append(evt.Attendees, &remote.Attendee{
+			Type: remote.AttendeeTypeResource,
+			EmailAddress: &remote.EmailAddress{
+				Address: room,
+			},
+		})
+	}
+	if cep.OnlineMeeting {
+		evt.IsOnlineMeeting = true
+		evt.OnlineMeetingProvider = cep.OnlineMeetingProvider
+		if evt.OnlineMeetingProvider == "" {
+			evt.OnlineMeetingProvider = remote.OnlineMeetingProviderTeams
+		}
+	}
 
 	return &evt, nil
 }
@@ -127,16 +276,45 @@ func (cep createEventPayload) IsValid(loc *time.Location) error {
 	if len(cep.Location) > maxLocationLen {
 		return fmt.Errorf("location must not exceed %d characters", maxLocationLen)
 	}
-	if len(cep.Attendees) > maxAttendees {
+	if len(cep.Attendees)+len(cep.Rooms) > maxAttendees {
 		return fmt.Errorf("number of attendees must not exceed %d", maxAttendees)
 	}
+	for _, room := range cep.Rooms {
+		if !strings.Contains(room, "@") {
+			return fmt.Errorf("invalid room address %q", room)
+		}
+	}
+
+	switch cep.OnlineMeetingProvider {
+	case "", remote.OnlineMeetingProviderTeams, remote.OnlineMeetingProviderSkypeForBusiness, remote.OnlineMeetingProviderSkypeForConsumer:
+	default:
+		return fmt.Errorf("invalid online meeting provider %q", cep.OnlineMeetingProvider)
+	}
+	if cep.OnlineMeetingProvider != "" && !cep.OnlineMeeting {
+		return fmt.Errorf("online meeting provider can only be set for online meetings")
+	}
 
+	if err := cep.isValidSchedule(loc); err != nil {
+		return err
+	}
+
+	if cep.Recurrence != nil {
+		date, _ := cep.parseDate(loc)
+		if err := cep.Recurrence.IsValid(date); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isValidSchedule checks the date and times of the event.
+func (cep createEventPayload) isValidSchedule(loc *time.Location) error {
 	if cep.Date == "" {
 		return fmt.Errorf("date must not be empty")
 	}
 
-	_, err := cep.parseDate(loc)
-	if err != nil {
+	if _, err := cep.parseDate(loc); err != nil {
 		return fmt.Errorf("invalid date")
 	}
 
@@ -209,6 +387,13 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 	}
 	model.AddEventParameterAuditableToAuditRec(auditRec, "create_event", auditParams)
 
+	if payload.OnlineMeeting && !api.Provider.Features.OnlineMeetings {
+		api.Logger.Errorf("createEvent, online meetings are not supported")
+		auditRec.AddErrorDesc("online meetings are not supported by the provider")
+		httputils.WriteBadRequestError(w, fmt.Errorf("online meetings are not supported by %s", api.Provider.DisplayName))
+		return
+	}
+
 	if payload.ChannelID != "" {
 		if !api.PluginAPI.CanLinkEventToChannel(payload.ChannelID, user.MattermostUserID) {
 			api.Logger.With(bot.LogContext{"userID": mattermostUserID, "channelID": payload.ChannelID}).Errorf("createEvent, user don't have permission to link events in the selected channel")
@@ -278,6 +463,10 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 			}
 
 			emailAddress = attendeeUser.Remote.Mail
+			if emailAddress == "" {
+				api.Logger.With(bot.LogContext{"attendee_mm_id": pa}).Warnf("attendee has no email address, skipping")
+				continue
+			}
 		}
 
 		event.Attendees = append(event.Attendees, &remote.Attendee{
@@ -287,7 +476,7 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	event, err := client.CreateEvent(event)
+	event, err := client.CreateEvent(payload.CalendarID, event)
 	if err != nil {
 		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("createEvent, error occurred while creating event")
 		auditRec.AddErrorDesc(fmt.Sprintf("error creating calendar event: %s", err.Error()))
@@ -307,6 +496,17 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	sanitizedSubject := views.MarkdownToHTMLEntities(event.Subject)
+	joinMessage := ""
+	// The warning is only meant for the organizer, never for the linked
+	// channel.
+	warningMessage := ""
+	if payload.OnlineMeeting {
+		if event.Conference != nil && event.Conference.URL != "" {
+			joinMessage = fmt.Sprintf("\nJoin the online meeting: %s", event.Conference.URL)
+		} else {
+			warningMessage = "\nThe online meeting could not be added, please add it from your calendar."
+		}
+	}
 
 	// Event linking
 	if payload.ChannelID != "" {
@@ -326,7 +526,7 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 			}()
 		} else {
 			post := &model.Post{
-				Message:   fmt.Sprintf("The event **%s** was linked to this channel by @%s", sanitizedSubject, user.MattermostUsername),
+				Message:   fmt.Sprintf("The event **%s** was linked to this channel by @%s%s", sanitizedSubject, user.MattermostUsername, joinMessage),
 				ChannelId: payload.ChannelID,
 			}
 			if attachment != nil {
@@ -336,11 +536,14 @@ func (api *api) createEvent(w http.ResponseWriter, r *http.Request) {
 				api.Logger.With(bot.LogContext{"err": err}).Errorf("error sending post to channel about linked event")
 			}
 		}
+		if warningMessage != "" {
+			api.Poster.DM(mattermostUserID, "Your event **%s** was created.%s", sanitizedSubject, warningMessage)
+		}
 	} else {
 		if attachment == nil {
-			api.Poster.DM(mattermostUserID, "Your event: **%s** was created successfully.", sanitizedSubject)
+			api.Poster.DM(mattermostUserID, "Your event: **%s** was created successfully.%s%s", sanitizedSubject, joinMessage, warningMessage)
 		} else {
-			api.Poster.DMWithMessageAndAttachments(mattermostUserID, "Your event was created successfully.", attachment)
+			api.Poster.DMWithMessageAndAttachments(mattermostUserID, "Your event was created successfully."+joinMessage+warningMessage, attachment)
 		}
 	}
 
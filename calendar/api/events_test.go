@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
 
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
@@ -95,6 +97,81 @@ func TestToRemoteEvent(t *testing.T) {
 				assert.NoError(t, err)
 			},
 		},
+		{
+			name: "Weekly recurring event defaults to the day of the event",
+			payload: func() createEventPayload {
+				payload := GetMockCreateEventPayload(false, nil, "2024-10-18", "10:00", "10:15", "", "Standup", "", "")
+				payload.Recurrence = &createEventRecurrence{Frequency: "weekly", Count: 10}
+				return payload
+			}(),
+			assertions: func(t *testing.T, event *remote.Event, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &remote.PatternedRecurrence{
+					Pattern: &remote.RecurrencePattern{
+						Type:           remote.RecurrencePatternWeekly,
+						Interval:       1,
+						DaysOfWeek:     []string{"friday"},
+						FirstDayOfWeek: "sunday",
+					},
+					Range: &remote.RecurrenceRange{
+						Type:                remote.RecurrenceRangeNumbered,
+						StartDate:           "2024-10-18",
+						NumberOfOccurrences: 10,
+					},
+				}, event.Recurrence)
+			},
+		},
+		{
+			name: "Online meeting defaults to Teams",
+			payload: func() createEventPayload {
+				payload := GetMockCreateEventPayload(false, nil, "2024-10-18", "10:00", "10:30", "", "Sync", "", "")
+				payload.OnlineMeeting = true
+				return payload
+			}(),
+			assertions: func(t *testing.T, event *remote.Event, err error) {
+				assert.NoError(t, err)
+				assert.True(t, event.IsOnlineMeeting)
+				assert.Equal(t, remote.OnlineMeetingProviderTeams, event.OnlineMeetingProvider)
+			},
+		},
+		{
+			name: "Rooms are invited as resources",
+			payload: func() createEventPayload {
+				payload := GetMockCreateEventPayload(false, nil, "2024-10-18", "10:00", "10:30", "", "Sync", "", "")
+				payload.Rooms = []string{"room1@example.com"}
+				return payload
+			}(),
+			assertions: func(t *testing.T, event *remote.Event, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []*remote.Attendee{{
+					Type:         remote.AttendeeTypeResource,
+					EmailAddress: &remote.EmailAddress{Address: "room1@example.com"},
+				}}, event.Attendees)
+			},
+		},
+		{
+			name: "Monthly recurring event with an end date",
+			payload: func() createEventPayload {
+				payload := GetMockCreateEventPayload(false, nil, "2024-10-18", "10:00", "11:00", "", "Review", "", "")
+				payload.Recurrence = &createEventRecurrence{Frequency: "monthly", Interval: 2, EndDate: "2025-10-18"}
+				return payload
+			}(),
+			assertions: func(t *testing.T, event *remote.Event, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &remote.PatternedRecurrence{
+					Pattern: &remote.RecurrencePattern{
+						Type:       remote.RecurrencePatternAbsoluteMonthly,
+						Interval:   2,
+						DayOfMonth: 18,
+					},
+					Range: &remote.RecurrenceRange{
+						Type:      remote.RecurrenceRangeEndDate,
+						StartDate: "2024-10-18",
+						EndDate:   "2025-10-18",
+					},
+				}, event.Recurrence)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -186,6 +263,127 @@ func TestIsValid(t *testing.T) {
 				assert.NoError(t, err)
 			},
 		},
+		{
+			name: "Invalid online meeting provider",
+			payload: func() createEventPayload {
+				payload := getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily"})
+				payload.Recurrence = nil
+				payload.OnlineMeeting = true
+				payload.OnlineMeetingProvider = "zoom"
+				return payload
+			}(),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `invalid online meeting provider "zoom"`)
+			},
+		},
+		{
+			name: "Online meeting provider without an online meeting",
+			payload: func() createEventPayload {
+				payload := getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily"})
+				payload.Recurrence = nil
+				payload.OnlineMeetingProvider = remote.OnlineMeetingProviderSkypeForBusiness
+				return payload
+			}(),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "online meeting provider can only be set for online meetings")
+			},
+		},
+		{
+			name: "Invalid room address",
+			payload: func() createEventPayload {
+				payload := getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily"})
+				payload.Recurrence = nil
+				payload.Rooms = []string{"Room 1"}
+				return payload
+			}(),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `invalid room address "Room 1"`)
+			},
+		},
+		{
+			name:    "Invalid recurrence frequency",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "hourly"}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "recurrence frequency must be one of daily, weekly or monthly")
+			},
+		},
+		{
+			name:    "Invalid recurrence interval",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily", Interval: 100}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "recurrence interval must be between 1 and 99, or not set to repeat every period")
+			},
+		},
+		{
+			name:    "Negative recurrence interval",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily", Interval: -1}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "recurrence interval must be between 1 and 99")
+			},
+		},
+		{
+			name:    "Largest recurrence interval and count",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily", Interval: 99, Count: 999}),
+			assertions: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "Recurrence interval and count not set",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily", Interval: 0, Count: 0}),
+			assertions: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:    "Invalid number of occurrences",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily", Count: 1000}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "number of occurrences must be between 1 and 999, or not set")
+			},
+		},
+		{
+			name:    "Negative number of occurrences",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily", Count: -1}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "number of occurrences must be between 1 and 999")
+			},
+		},
+		{
+			name:    "Days of the week on a monthly recurrence",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "monthly", DaysOfWeek: []string{"monday"}}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "days of the week can only be set for weekly recurrences")
+			},
+		},
+		{
+			name:    "Invalid day of the week",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "weekly", DaysOfWeek: []string{"Monday", "someday"}}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, `invalid day of the week "someday"`)
+			},
+		},
+		{
+			name:    "Recurrence with an end date and a count",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily", Count: 5, EndDate: "2099-01-01"}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "recurrence cannot have both an end date and a number of occurrences")
+			},
+		},
+		{
+			name:    "Recurrence ending before the event",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "daily", EndDate: "2020-01-01"}),
+			assertions: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "recurrence end date cannot be earlier than the event date")
+			},
+		},
+		{
+			name:    "Valid weekly recurrence",
+			payload: getMockRecurringEventPayload(createEventRecurrence{Frequency: "weekly", Interval: 2, DaysOfWeek: []string{"Monday", "wednesday"}, Count: 10}),
+			assertions: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -195,6 +393,13 @@ func TestIsValid(t *testing.T) {
 	}
 }
 
+func getMockRecurringEventPayload(recurrence createEventRecurrence) createEventPayload {
+	futureTime := time.Now().UTC().Add(24 * time.Hour)
+	payload := GetMockCreateEventPayload(false, nil, futureTime.Format("2006-01-02"), futureTime.Add(1*time.Hour).Format("15:04"), futureTime.Add(2*time.Hour).Format("15:04"), "mockDescription", "mockSubject", "mockLocation", "")
+	payload.Recurrence = &recurrence
+	return payload
+}
+
 func TestCreateEvent(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -371,7 +576,7 @@ func TestCreateEvent(t *testing.T) {
 				mockPluginAPI.EXPECT().CanLinkEventToChannel(MockChannelID, MockUserID).Return(true).Times(1)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
-				mockRemoteClient.EXPECT().CreateEvent(gomock.Any()).Return(nil, errors.New("failed to create event")).Times(1)
+				mockRemoteClient.EXPECT().CreateEvent("", gomock.Any()).Return(nil, errors.New("failed to create event")).Times(1)
 				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
 				mockLoggerWith.EXPECT().Errorf("createEvent, error occurred while creating event", gomock.Any()).Times(1)
 			},
@@ -393,7 +598,7 @@ func TestCreateEvent(t *testing.T) {
 				mockPluginAPI.EXPECT().CanLinkEventToChannel(MockChannelID, MockUserID).Return(true).Times(1)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
-				mockRemoteClient.EXPECT().CreateEvent(gomock.Any()).Return(mockEvent, nil).Times(1)
+				mockRemoteClient.EXPECT().CreateEvent("", gomock.Any()).Return(mockEvent, nil).Times(1)
 				mockStore.EXPECT().StoreUserLinkedEvent(MockUserID, gomock.Any(), MockChannelID).Return(errors.New("error storing the user linked event")).Times(1)
 				mockPoster.EXPECT().DM(MockUserID, gomock.Any(), gomock.Any()).Times(1)
 				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
@@ -417,7 +622,7 @@ func TestCreateEvent(t *testing.T) {
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
-				mockRemoteClient.EXPECT().CreateEvent(gomock.Any()).Return(mockEvent, nil).Times(1)
+				mockRemoteClient.EXPECT().CreateEvent("", gomock.Any()).Return(mockEvent, nil).Times(1)
 				mockStore.EXPECT().StoreUserLinkedEvent(MockUserID, gomock.Any(), MockChannelID).Return(nil).Times(1)
 				mockStore.EXPECT().AddLinkedChannelToEvent(gomock.Any(), MockChannelID).Return(errors.New("error linking event to channel")).Times(1)
 				mockPoster.EXPECT().DM(MockUserID, gomock.Any(), gomock.Any()).Times(1)
@@ -442,7 +647,7 @@ func TestCreateEvent(t *testing.T) {
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
-				mockRemoteClient.EXPECT().CreateEvent(gomock.Any()).Return(mockEvent, nil).Times(1)
+				mockRemoteClient.EXPECT().CreateEvent("", gomock.Any()).Return(mockEvent, nil).Times(1)
 				mockStore.EXPECT().StoreUserLinkedEvent(MockUserID, gomock.Any(), MockChannelID).Return(nil).Times(1)
 				mockStore.EXPECT().AddLinkedChannelToEvent(gomock.Any(), MockChannelID).Return(nil).Times(1)
 				mockPoster.EXPECT().CreatePost(gomock.Any()).Return(errors.New("error occurred creating post")).Times(1)
@@ -467,7 +672,7 @@ func TestCreateEvent(t *testing.T) {
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
-				mockRemoteClient.EXPECT().CreateEvent(gomock.Any()).Return(mockEvent, nil).Times(1)
+				mockRemoteClient.EXPECT().CreateEvent("", gomock.Any()).Return(mockEvent, nil).Times(1)
 				mockStore.EXPECT().StoreUserLinkedEvent(MockUserID, gomock.Any(), MockChannelID).Return(nil).Times(1)
 				mockStore.EXPECT().AddLinkedChannelToEvent(gomock.Any(), MockChannelID).Return(nil).Times(1)
 				mockPoster.EXPECT().CreatePost(gomock.Any()).Return(nil).Times(1)
@@ -489,7 +694,7 @@ func TestCreateEvent(t *testing.T) {
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
 				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
 				mockEvent := GetMockRemoteEvent()
-				mockRemoteClient.EXPECT().CreateEvent(gomock.Any()).Return(mockEvent, nil).Times(1)
+				mockRemoteClient.EXPECT().CreateEvent("", gomock.Any()).Return(mockEvent, nil).Times(1)
 				mockPoster.EXPECT().DMWithMessageAndAttachments(MockUserID, "Your event was created successfully.", gomock.Any()).Times(1)
 			},
 			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -498,6 +703,70 @@ func TestCreateEvent(t *testing.T) {
 				assert.Contains(t, string(responseBody), "true")
 			},
 		},
+		{
+			name: "Online meetings not supported by the provider",
+			setup: func(req *http.Request, api *api, mockStore *mock_store.MockStore, mockPoster *mock_bot.MockPoster, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, mockLoggerWith *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				api.Config = &config.Config{Provider: config.ProviderConfig{DisplayName: "CalDAV"}}
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				req.Body = io.NopCloser(bytes.NewBufferString(withOnlineMeeting(GetCurrentTimeRequestBodyJSON(""))))
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID}, nil).Times(1)
+				mockLogger.EXPECT().Errorf("createEvent, online meetings are not supported").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+				responseBody, _ := io.ReadAll(rec.Body)
+				assert.Contains(t, string(responseBody), "online meetings are not supported by CalDAV")
+			},
+		},
+		{
+			name: "Event created with an online meeting",
+			setup: func(req *http.Request, api *api, mockStore *mock_store.MockStore, mockPoster *mock_bot.MockPoster, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, mockLoggerWith *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				api.Config = &config.Config{Provider: config.ProviderConfig{Features: config.ProviderFeatures{OnlineMeetings: true}}}
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				req.Body = io.NopCloser(bytes.NewBufferString(withOnlineMeeting(GetCurrentTimeRequestBodyJSON(""))))
+				mockOAauthToken := oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockEvent := GetMockRemoteEvent()
+				mockEvent.Conference = &remote.Conference{Application: "Online Meeting", URL: "https://teams.example.com/join"}
+				mockRemoteClient.EXPECT().CreateEvent("", gomock.Any()).DoAndReturn(func(_ string, event *remote.Event) (*remote.Event, error) {
+					assert.True(t, event.IsOnlineMeeting)
+					assert.Equal(t, remote.OnlineMeetingProviderTeams, event.OnlineMeetingProvider)
+					return mockEvent, nil
+				}).Times(1)
+				mockPoster.EXPECT().DMWithMessageAndAttachments(MockUserID, "Your event was created successfully.\nJoin the online meeting: https://teams.example.com/join", gomock.Any()).Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Missing online meeting is only reported to the organizer",
+			setup: func(req *http.Request, api *api, mockStore *mock_store.MockStore, mockPoster *mock_bot.MockPoster, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, mockLoggerWith *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				api.Config = &config.Config{Provider: config.ProviderConfig{Features: config.ProviderFeatures{OnlineMeetings: true}}}
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				req.Body = io.NopCloser(bytes.NewBufferString(withOnlineMeeting(GetCurrentTimeRequestBodyJSON(MockChannelID))))
+				mockOAauthToken := oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{MattermostUserID: MockUserID, OAuth2Token: &mockOAauthToken, Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(1)
+				mockPluginAPI.EXPECT().CanLinkEventToChannel(MockChannelID, MockUserID).Return(true).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), &mockOAauthToken, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockEvent := GetMockRemoteEvent()
+				mockEvent.Conference = nil
+				mockRemoteClient.EXPECT().CreateEvent("", gomock.Any()).Return(mockEvent, nil).Times(1)
+				mockStore.EXPECT().StoreUserLinkedEvent(MockUserID, gomock.Any(), MockChannelID).Return(nil).Times(1)
+				mockStore.EXPECT().AddLinkedChannelToEvent(gomock.Any(), MockChannelID).Return(nil).Times(1)
+				mockPoster.EXPECT().CreatePost(gomock.Any()).DoAndReturn(func(post *model.Post) error {
+					assert.NotContains(t, post.Message, "online meeting")
+					return nil
+				}).Times(1)
+				mockPoster.EXPECT().DM(MockUserID, "Your event **%s** was created.%s", gomock.Any(), "\nThe online meeting could not be added, please add it from your calendar.").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rec.Result().StatusCode)
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -513,3 +782,8 @@ func TestCreateEvent(t *testing.T) {
 		})
 	}
 }
+
+// withOnlineMeeting adds an online meeting to a create event request body.
+func withOnlineMeeting(body string) string {
+	return strings.Replace(body, "{", `{"online_meeting": true,`, 1)
+}
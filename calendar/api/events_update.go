@@ -0,0 +1,285 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/httputils"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+// updateEventPayload holds the changes to an event: fields left out are not
+// changed. Rescheduling an event takes its new date, with either its start
+// and end times or all_day.
+type updateEventPayload struct {
+	AllDay      bool    `json:"all_day"`
+	Date        string  `json:"date,omitempty"`
+	StartTime   string  `json:"start_time,omitempty"`
+	EndTime     string  `json:"end_time,omitempty"`
+	Subject     *string `json:"subject,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Location    *string `json:"location,omitempty"`
+}
+
+type cancelEventPayload struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+func (uep updateEventPayload) schedule() createEventPayload {
+	return createEventPayload{
+		AllDay:    uep.AllDay,
+		Date:      uep.Date,
+		StartTime: uep.StartTime,
+		EndTime:   uep.EndTime,
+	}
+}
+
+func (uep updateEventPayload) IsValid(loc *time.Location) error {
+	if uep.Subject != nil {
+		if *uep.Subject == "" {
+			return fmt.Errorf("subject must not be empty")
+		}
+		if len(*uep.Subject) > maxSubjectLen {
+			return fmt.Errorf("subject must not exceed %d characters", maxSubjectLen)
+		}
+	}
+	if uep.Description != nil && len(*uep.Description) > maxDescriptionLen {
+		return fmt.Errorf("description must not exceed %d characters", maxDescriptionLen)
+	}
+	if uep.Location != nil && len(*uep.Location) > maxLocationLen {
+		return fmt.Errorf("location must not exceed %d characters", maxLocationLen)
+	}
+
+	if uep.Date == "" {
+		if uep.AllDay || uep.StartTime != "" || uep.EndTime != "" {
+			return fmt.Errorf("date must be set to reschedule the event")
+		}
+		if uep.Subject == nil && uep.Description == nil && uep.Location == nil {
+			return fmt.Errorf("nothing to update")
+		}
+		return nil
+	}
+
+	return uep.schedule().isValidSchedule(loc)
+}
+
+func (uep updateEventPayload) ToRemoteEvent(loc *time.Location) (*remote.Event, error) {
+	evt := &remote.Event{}
+
+	if uep.Date != "" {
+		scheduled, err := uep.schedule().ToRemoteEvent(loc)
+		if err != nil {
+			return nil, err
+		}
+		evt.Start = scheduled.Start
+		evt.End = scheduled.End
+		evt.IsAllDay = scheduled.IsAllDay
+	}
+	if uep.Subject != nil {
+		evt.Subject = *uep.Subject
+	}
+	if uep.Description != nil {
+		evt.Body = &remote.ItemBody{
+			Content:     *uep.Description,
+			ContentType: "text",
+		}
+	}
+	if uep.Location != nil {
+		evt.Location = &remote.Location{
+			DisplayName: *uep.Location,
+		}
+	}
+
+	return evt, nil
+}
+
+func (api *api) updateEvent(w http.ResponseWriter, r *http.Request) {
+	auditRec := plugin.MakeAuditRecord("updateEvent", model.AuditStatusFail)
+	defer api.PluginAPI.LogAuditRec(auditRec)
+
+	mattermostUserID := r.Header.Get("Mattermost-User-Id")
+	if mattermostUserID == "" {
+		api.Logger.Errorf("updateEvent, unauthorized user")
+		auditRec.AddErrorDesc("unauthorized: missing Mattermost-User-Id header")
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	eventID := mux.Vars(r)[config.EventIDVar]
+	if eventID == "" {
+		auditRec.AddErrorDesc("missing event ID")
+		httputils.WriteBadRequestError(w, fmt.Errorf("event ID must not be empty"))
+		return
+	}
+
+	var payload updateEventPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("updateEvent, error occurred while decoding event payload")
+		auditRec.AddErrorDesc(fmt.Sprintf("invalid request body: %s", err.Error()))
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
+
+	auditParams := UpdateEventAuditParams{
+		MattermostUserID: mattermostUserID,
+		EventID:          eventID,
+		Rescheduled:      payload.Date != "",
+	}
+	model.AddEventParameterAuditableToAuditRec(auditRec, "update_event", auditParams)
+
+	eng := engine.New(api.Env, mattermostUserID)
+	user := engine.NewUser(mattermostUserID)
+
+	loc, err := mailboxLocation(eng, user)
+	if err != nil {
+		auditRec.AddErrorDesc(fmt.Sprintf("error loading mailbox timezone: %s", err.Error()))
+		if errors.Is(err, store.ErrNotFound) {
+			api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("updateEvent, user not found in store")
+			httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+			return
+		}
+		api.Logger.With(bot.LogContext{"err": err.Error(), "userID": mattermostUserID}).Errorf("updateEvent, error occurred while loading mailbox timezone location")
+		httputils.WriteInternalServerError(w, err)
+		return
+	}
+
+	if err = payload.IsValid(loc); err != nil {
+		api.Logger.Errorf("updateEvent, invalid payload")
+		auditRec.AddErrorDesc(fmt.Sprintf("invalid payload: %s", err.Error()))
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
+
+	event, err := payload.ToRemoteEvent(loc)
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("updateEvent, error occurred while creating remote event from payload")
+		auditRec.AddErrorDesc(fmt.Sprintf("error building remote event: %s", err.Error()))
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
+
+	event, err = eng.UpdateEvent(user, eventID, event)
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("updateEvent, error occurred while updating event")
+		auditRec.AddErrorDesc(fmt.Sprintf("error updating calendar event: %s", err.Error()))
+		if isNotFoundError(err) {
+			httputils.WriteNotFoundError(w, fmt.Errorf("event not found"))
+			return
+		}
+		httputils.WriteInternalServerError(w, err)
+		return
+	}
+
+	auditRec.AddEventResultState(EventAuditResult{
+		ICalUID:     event.ICalUID,
+		IsOrganizer: event.IsOrganizer,
+	})
+
+	remote.NormalizeDateTimeToRFC3339(event)
+
+	auditRec.Success()
+	httputils.WriteJSONResponse(w, event, http.StatusOK)
+}
+
+func (api *api) cancelEvent(w http.ResponseWriter, r *http.Request) {
+	auditRec := plugin.MakeAuditRecord("cancelEvent", model.AuditStatusFail)
+	defer api.PluginAPI.LogAuditRec(auditRec)
+
+	mattermostUserID := r.Header.Get("Mattermost-User-Id")
+	if mattermostUserID == "" {
+		api.Logger.Errorf("cancelEvent, unauthorized user")
+		auditRec.AddErrorDesc("unauthorized: missing Mattermost-User-Id header")
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	eventID := mux.Vars(r)[config.EventIDVar]
+	if eventID == "" {
+		auditRec.AddErrorDesc("missing event ID")
+		httputils.WriteBadRequestError(w, fmt.Errorf("event ID must not be empty"))
+		return
+	}
+
+	// The body is optional.
+	var payload cancelEventPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("cancelEvent, error occurred while decoding payload")
+		auditRec.AddErrorDesc(fmt.Sprintf("invalid request body: %s", err.Error()))
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
+
+	auditParams := CancelEventAuditParams{
+		MattermostUserID: mattermostUserID,
+		EventID:          eventID,
+		WithComment:      payload.Comment != "",
+	}
+	model.AddEventParameterAuditableToAuditRec(auditRec, "cancel_event", auditParams)
+
+	if len(payload.Comment) > maxDescriptionLen {
+		auditRec.AddErrorDesc("invalid payload: comment too long")
+		httputils.WriteBadRequestError(w, fmt.Errorf("comment must not exceed %d characters", maxDescriptionLen))
+		return
+	}
+
+	eng := engine.New(api.Env, mattermostUserID)
+	event, err := eng.CancelEvent(engine.NewUser(mattermostUserID), eventID, payload.Comment)
+	if err != nil {
+		auditRec.AddErrorDesc(fmt.Sprintf("error canceling calendar event: %s", err.Error()))
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("cancelEvent, user not found in store")
+			httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		case isNotFoundError(err):
+			api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("cancelEvent, event not found")
+			httputils.WriteNotFoundError(w, fmt.Errorf("event not found"))
+		default:
+			api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("cancelEvent, error occurred while canceling event")
+			httputils.WriteInternalServerError(w, err)
+		}
+		return
+	}
+
+	auditRec.AddEventResultState(EventAuditResult{
+		ICalUID:     event.ICalUID,
+		IsOrganizer: event.IsOrganizer,
+	})
+
+	auditRec.Success()
+	httputils.WriteJSONResponse(w, `{"ok": true}`, http.StatusOK)
+}
+
+// mailboxLocation returns the location of the time zone set in the mailbox
+// of the user.
+func mailboxLocation(eng engine.Engine, user *engine.User) (*time.Location, error) {
+	timeZone, err := eng.GetTimezone(user)
+	if err != nil {
+		return nil, err
+	}
+
+	tzName := tz.Go(timeZone)
+	if tzName == "" && timeZone != "" {
+		return nil, fmt.Errorf("unknown time zone %q", timeZone)
+	}
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve mailbox timezone")
+	}
+	return loc, nil
+}
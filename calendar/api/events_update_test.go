@@ -0,0 +1,303 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestUpdateEventPayload(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	tomorrow := time.Now().In(loc).AddDate(0, 0, 1).Format(createEventDateFormat)
+	subject := "Moved meeting"
+	empty := ""
+
+	tests := []struct {
+		name       string
+		payload    updateEventPayload
+		assertions func(t *testing.T, event *remote.Event, err error)
+	}{
+		{
+			name:    "Nothing to update",
+			payload: updateEventPayload{},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				assert.EqualError(t, err, "nothing to update")
+			},
+		},
+		{
+			name:    "Empty subject",
+			payload: updateEventPayload{Subject: &empty},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				assert.EqualError(t, err, "subject must not be empty")
+			},
+		},
+		{
+			name:    "Times without a date",
+			payload: updateEventPayload{StartTime: "10:00", EndTime: "11:00"},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				assert.EqualError(t, err, "date must be set to reschedule the event")
+			},
+		},
+		{
+			name:    "Rescheduled to the past",
+			payload: updateEventPayload{Date: "2020-10-17", StartTime: "10:00", EndTime: "11:00"},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				assert.EqualError(t, err, "please select a start date and time that is not prior to the current time")
+			},
+		},
+		{
+			name:    "Subject only",
+			payload: updateEventPayload{Subject: &subject},
+			assertions: func(t *testing.T, event *remote.Event, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &remote.Event{Subject: subject}, event)
+			},
+		},
+		{
+			name:    "Rescheduled and location cleared",
+			payload: updateEventPayload{Date: tomorrow, StartTime: "10:00", EndTime: "11:00", Location: &empty},
+			assertions: func(t *testing.T, event *remote.Event, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &remote.Event{
+					Start:    &remote.DateTime{DateTime: tomorrow + "T10:00:00", TimeZone: "America/New_York"},
+					End:      &remote.DateTime{DateTime: tomorrow + "T11:00:00", TimeZone: "America/New_York"},
+					Location: &remote.Location{},
+				}, event)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.payload.IsValid(loc); err != nil {
+				tc.assertions(t, nil, err)
+				return
+			}
+			event, err := tc.payload.ToRemoteEvent(loc)
+			tc.assertions(t, event, err)
+		})
+	}
+}
+
+func expectEngineClient(mockStore *mock_store.MockStore, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockRemoteClient *mock_remote.MockClient) {
+	mockOAuthToken := &oauth2.Token{}
+	mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{
+		MattermostUserID: MockUserID,
+		OAuth2Token:      mockOAuthToken,
+		Remote:           &remote.User{ID: MockRemoteUserID},
+	}, nil).Times(2)
+	mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
+	mockRemote.EXPECT().MakeUserClient(gomock.Any(), mockOAuthToken, MockUserID, gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
+}
+
+func TestUpdateEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(*http.Request, *mock_store.MockStore, *mock_remote.MockRemote, *mock_plugin_api.MockPluginAPI, *mock_bot.MockLogger, *mock_bot.MockLogger, *mock_remote.MockClient)
+		assertions func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Missing Mattermost User ID",
+			body: `{"subject": "Moved meeting"}`,
+			setup: func(req *http.Request, _ *mock_store.MockStore, _ *mock_remote.MockRemote, _ *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, _ *mock_bot.MockLogger, _ *mock_remote.MockClient) {
+				req.Header.Del(MMUserIDHeader)
+				mockLogger.EXPECT().Errorf("updateEvent, unauthorized user").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Error decoding the payload",
+			body: `{"subject": }`,
+			setup: func(_ *http.Request, _ *mock_store.MockStore, _ *mock_remote.MockRemote, _ *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, mockLoggerWith *mock_bot.MockLogger, _ *mock_remote.MockClient) {
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
+				mockLoggerWith.EXPECT().Errorf("updateEvent, error occurred while decoding event payload").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "User not found",
+			body: `{"subject": "Moved meeting"}`,
+			setup: func(_ *http.Request, mockStore *mock_store.MockStore, _ *mock_remote.MockRemote, _ *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, mockLoggerWith *mock_bot.MockLogger, _ *mock_remote.MockClient) {
+				mockStore.EXPECT().LoadUser(MockUserID).Return(nil, store.ErrNotFound).Times(1)
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
+				mockLoggerWith.EXPECT().Errorf("updateEvent, user not found in store").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Invalid payload",
+			body: `{"start_time": "10:00"}`,
+			setup: func(_ *http.Request, mockStore *mock_store.MockStore, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, _ *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				mockOAuthToken := &oauth2.Token{}
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{
+					MattermostUserID: MockUserID,
+					OAuth2Token:      mockOAuthToken,
+					Remote:           &remote.User{ID: MockRemoteUserID},
+				}, nil).Times(2)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(1)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), mockOAuthToken, MockUserID, gomock.Any(), gomock.Any()).Return(mockRemoteClient, nil).Times(1)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockLogger.EXPECT().Errorf("updateEvent, invalid payload").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+				body, _ := io.ReadAll(rec.Body)
+				assert.Contains(t, string(body), "date must be set to reschedule the event")
+			},
+		},
+		{
+			name: "Event not found",
+			body: `{"subject": "Moved meeting"}`,
+			setup: func(_ *http.Request, mockStore *mock_store.MockStore, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, mockLoggerWith *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				expectEngineClient(mockStore, mockRemote, mockPluginAPI, mockRemoteClient)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockRemoteClient.EXPECT().UpdateEvent(MockRemoteUserID, MockEventID, &remote.Event{Subject: "Moved meeting"}).Return(nil, errors.New("404 Not Found: the event was not found")).Times(1)
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
+				mockLoggerWith.EXPECT().Errorf("updateEvent, error occurred while updating event").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Event updated successfully",
+			body: `{"subject": "Moved meeting"}`,
+			setup: func(_ *http.Request, mockStore *mock_store.MockStore, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, _ *mock_bot.MockLogger, _ *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				expectEngineClient(mockStore, mockRemote, mockPluginAPI, mockRemoteClient)
+				mockRemoteClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockRemoteClient.EXPECT().UpdateEvent(MockRemoteUserID, MockEventID, &remote.Event{Subject: "Moved meeting"}).Return(&remote.Event{ID: MockEventID, Subject: "Moved meeting"}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				body, _ := io.ReadAll(rec.Body)
+				assert.Contains(t, string(body), "Moved meeting")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, mockStore, _, mockRemote, mockPluginAPI, mockLogger, mockLoggerWith, mockRemoteClient := GetMockSetup(t)
+			a.Config = &config.Config{}
+
+			req := httptest.NewRequest(http.MethodPatch, "/"+MockEventID, bytes.NewBufferString(tc.body))
+			req = mux.SetURLVars(req, map[string]string{config.EventIDVar: MockEventID})
+			req.Header.Set(MMUserIDHeader, MockUserID)
+			rec := httptest.NewRecorder()
+
+			tc.setup(req, mockStore, mockRemote, mockPluginAPI, mockLogger, mockLoggerWith, mockRemoteClient)
+			a.updateEvent(rec, req)
+
+			tc.assertions(t, rec)
+		})
+	}
+}
+
+func TestCancelEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(*http.Request, *mock_store.MockStore, *mock_remote.MockRemote, *mock_plugin_api.MockPluginAPI, *mock_bot.MockLogger, *mock_bot.MockLogger, *mock_remote.MockClient)
+		assertions func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Missing Mattermost User ID",
+			setup: func(req *http.Request, _ *mock_store.MockStore, _ *mock_remote.MockRemote, _ *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, _ *mock_bot.MockLogger, _ *mock_remote.MockClient) {
+				req.Header.Del(MMUserIDHeader)
+				mockLogger.EXPECT().Errorf("cancelEvent, unauthorized user").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Event not found",
+			setup: func(_ *http.Request, mockStore *mock_store.MockStore, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockLogger *mock_bot.MockLogger, mockLoggerWith *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				expectEngineClient(mockStore, mockRemote, mockPluginAPI, mockRemoteClient)
+				mockRemoteClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(nil, errors.New("404 Not Found: the event was not found")).Times(1)
+				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
+				mockLoggerWith.EXPECT().Errorf("cancelEvent, event not found").Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Meeting canceled with a comment",
+			body: `{"comment": "Moved to next week"}`,
+			setup: func(_ *http.Request, mockStore *mock_store.MockStore, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, _ *mock_bot.MockLogger, _ *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				expectEngineClient(mockStore, mockRemote, mockPluginAPI, mockRemoteClient)
+				mockRemoteClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(&remote.Event{
+					ID:          MockEventID,
+					ICalUID:     "iCalUID",
+					IsOrganizer: true,
+					Attendees:   []*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "attendee@example.com"}}},
+				}, nil).Times(1)
+				mockRemoteClient.EXPECT().CancelEvent(MockRemoteUserID, MockEventID, "Moved to next week").Return(nil).Times(1)
+				mockStore.EXPECT().LoadEventMetadata("iCalUID").Return(nil, store.ErrNotFound).Times(1)
+				mockStore.EXPECT().DeleteEventMetadata("iCalUID").Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			},
+		},
+		{
+			name: "Event deleted without a body",
+			setup: func(_ *http.Request, mockStore *mock_store.MockStore, mockRemote *mock_remote.MockRemote, mockPluginAPI *mock_plugin_api.MockPluginAPI, _ *mock_bot.MockLogger, _ *mock_bot.MockLogger, mockRemoteClient *mock_remote.MockClient) {
+				expectEngineClient(mockStore, mockRemote, mockPluginAPI, mockRemoteClient)
+				mockRemoteClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(&remote.Event{ID: MockEventID, ICalUID: "iCalUID"}, nil).Times(1)
+				mockRemoteClient.EXPECT().DeleteEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, mockStore, _, mockRemote, mockPluginAPI, mockLogger, mockLoggerWith, mockRemoteClient := GetMockSetup(t)
+			a.Config = &config.Config{}
+
+			req := httptest.NewRequest(http.MethodDelete, "/"+MockEventID, bytes.NewBufferString(tc.body))
+			req = mux.SetURLVars(req, map[string]string{config.EventIDVar: MockEventID})
+			req.Header.Set(MMUserIDHeader, MockUserID)
+			rec := httptest.NewRecorder()
+
+			tc.setup(req, mockStore, mockRemote, mockPluginAPI, mockLogger, mockLoggerWith, mockRemoteClient)
+			a.cancelEvent(rec, req)
+
+			tc.assertions(t, rec)
+		})
+	}
+}
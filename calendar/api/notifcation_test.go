@@ -5,6 +5,7 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,49 +14,108 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
 )
 
 func TestNotification(t *testing.T) {
-	api, _, _, mockRemote, _, mockLogger, mockLoggerWith, _ := GetMockSetup(t)
-	mockProcessor := &MockNotificationProcessor{}
-	api.NotificationProcessor = mockProcessor
+	subscription := &store.Subscription{Remote: &remote.Subscription{ID: "sub_id", ClientState: "client_state"}}
 
 	tests := []struct {
 		name       string
-		setup      func(*MockNotificationProcessor)
+		setup      func(*MockNotificationProcessor, *mock_store.MockStore)
+		webhook    []*remote.Notification
 		assertions func(*httptest.ResponseRecorder, *MockNotificationProcessor)
 	}{
 		{
 			name: "Error while adding event to notification queue",
-			setup: func(mockProcessor *MockNotificationProcessor) {
+			setup: func(mockProcessor *MockNotificationProcessor, mockStore *mock_store.MockStore) {
 				mockProcessor.err = errors.New("queue error")
-				mockRemote.EXPECT().HandleWebhook(gomock.Any(), gomock.Any()).
-					Return([]*remote.Notification{{}}).Times(1)
-
-				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
-				mockLoggerWith.EXPECT().Errorf("notification, error occurred while adding webhook event to notification queue").Times(1)
+				mockStore.EXPECT().LoadSubscription("sub_id").Return(subscription, nil).Times(1)
 			},
+			webhook: []*remote.Notification{{SubscriptionID: "sub_id", ClientState: "client_state"}},
 			assertions: func(rec *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
 				assert.Equal(t, http.StatusInternalServerError, rec.Result().StatusCode)
 				assert.Equal(t, 0, len(mockProcessor.queue))
 			},
 		},
+		{
+			name: "Saturated notification queue",
+			setup: func(mockProcessor *MockNotificationProcessor, mockStore *mock_store.MockStore) {
+				mockProcessor.err = fmt.Errorf("webhook notification: %w", store.ErrNotificationQueueFull)
+				mockStore.EXPECT().LoadSubscription("sub_id").Return(subscription, nil).Times(1)
+			},
+			webhook: []*remote.Notification{{SubscriptionID: "sub_id", ClientState: "client_state"}},
+			assertions: func(rec *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
+				assert.Equal(t, http.StatusServiceUnavailable, rec.Result().StatusCode)
+				assert.Equal(t, 0, len(mockProcessor.queue))
+			},
+		},
 		{
 			name: "Successful notification processing",
-			setup: func(mockProcessor *MockNotificationProcessor) {
-				mockProcessor.err = nil
-				mockRemote.EXPECT().HandleWebhook(gomock.Any(), gomock.Any()).
-					Return([]*remote.Notification{{}, {}}).Times(1)
+			setup: func(_ *MockNotificationProcessor, mockStore *mock_store.MockStore) {
+				mockStore.EXPECT().LoadSubscription("sub_id").Return(subscription, nil).Times(2)
 			},
+			webhook: []*remote.Notification{{SubscriptionID: "sub_id", ClientState: "client_state"}, {SubscriptionID: "sub_id", ClientState: "client_state"}},
 			assertions: func(rec *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
-				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				assert.Equal(t, http.StatusAccepted, rec.Result().StatusCode)
 				assert.Equal(t, 2, len(mockProcessor.queue))
 			},
 		},
+		{
+			name: "Notification with the wrong client state is dropped",
+			setup: func(_ *MockNotificationProcessor, mockStore *mock_store.MockStore) {
+				mockStore.EXPECT().LoadSubscription("sub_id").Return(subscription, nil).Times(2)
+			},
+			webhook: []*remote.Notification{{SubscriptionID: "sub_id", ClientState: "client_state"}, {SubscriptionID: "sub_id", ClientState: "forged"}},
+			assertions: func(rec *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
+				assert.Equal(t, http.StatusAccepted, rec.Result().StatusCode)
+				assert.Equal(t, 1, len(mockProcessor.queue))
+				assert.Equal(t, "client_state", mockProcessor.queue[0].ClientState)
+			},
+		},
+		{
+			name: "Notifications all with the wrong client state",
+			setup: func(_ *MockNotificationProcessor, mockStore *mock_store.MockStore) {
+				mockStore.EXPECT().LoadSubscription("sub_id").Return(subscription, nil).Times(2)
+			},
+			webhook: []*remote.Notification{{SubscriptionID: "sub_id", ClientState: "forged"}, {SubscriptionID: "sub_id", ClientState: "forged"}},
+			assertions: func(rec *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
+				assert.Equal(t, http.StatusUnauthorized, rec.Result().StatusCode)
+				assert.Equal(t, 0, len(mockProcessor.queue))
+			},
+		},
+		{
+			name: "Notification of an unknown subscription",
+			setup: func(_ *MockNotificationProcessor, mockStore *mock_store.MockStore) {
+				mockStore.EXPECT().LoadSubscription("unknown").Return(nil, store.ErrNotFound).Times(1)
+			},
+			webhook: []*remote.Notification{{SubscriptionID: "unknown"}},
+			assertions: func(rec *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
+				assert.Equal(t, http.StatusAccepted, rec.Result().StatusCode)
+				assert.Equal(t, 0, len(mockProcessor.queue))
+			},
+		},
+		{
+			name:    "Request answered by the remote",
+			setup:   func(*MockNotificationProcessor, *mock_store.MockStore) {},
+			webhook: nil,
+			assertions: func(_ *httptest.ResponseRecorder, mockProcessor *MockNotificationProcessor) {
+				assert.Equal(t, 0, len(mockProcessor.queue))
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			tc.setup(mockProcessor)
+			api, mockStore, _, mockRemote, _, mockLogger, mockLoggerWith, _ := GetMockSetup(t)
+			mockProcessor := &MockNotificationProcessor{}
+			api.NotificationProcessor = mockProcessor
+			mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).AnyTimes()
+			mockLoggerWith.EXPECT().Errorf(gomock.Any()).AnyTimes()
+			mockLoggerWith.EXPECT().Infof(gomock.Any()).AnyTimes()
+			mockLoggerWith.EXPECT().Warnf(gomock.Any()).AnyTimes()
+			mockRemote.EXPECT().HandleWebhook(gomock.Any(), gomock.Any()).Return(tc.webhook).Times(1)
+			tc.setup(mockProcessor, mockStore)
 
 			req := httptest.NewRequest(http.MethodPost, "/notification", nil)
 			rec := httptest.NewRecorder()
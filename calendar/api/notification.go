@@ -4,19 +4,84 @@
 package api
 
 import (
+	"crypto/subtle"
 	"net/http"
 
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/httputils"
 )
 
+var errUnauthorizedNotification = errors.New("notification does not match the client state of its subscription")
+
 func (api *api) notification(w http.ResponseWriter, req *http.Request) {
-	if api.NotificationProcessor != nil {
-		err := api.NotificationProcessor.Enqueue(api.Env.Remote.HandleWebhook(w, req)...)
+	if api.NotificationProcessor == nil {
+		return
+	}
+
+	notifications := api.Env.Remote.HandleWebhook(w, req)
+	if notifications == nil {
+		// The request was answered by the remote.
+		return
+	}
+
+	notifications, err := api.authenticateNotifications(notifications)
+	if errors.Is(err, errUnauthorizedNotification) {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Infof("notification, rejected webhook")
+		httputils.WriteUnauthorizedError(w, err)
+		return
+	}
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("notification, error occurred while authenticating webhook")
+		httputils.WriteInternalServerError(w, err)
+		return
+	}
+
+	err = api.NotificationProcessor.Enqueue(notifications...)
+	if errors.Is(err, store.ErrNotificationQueueFull) {
+		// The remote delivers the notifications again later.
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Warnf("notification, queue saturated, asking for redelivery")
+		httputils.WriteServiceUnavailableError(w, err)
+		return
+	}
+	if err != nil {
+		api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("notification, error occurred while adding webhook event to notification queue")
+		httputils.WriteInternalServerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// authenticateNotifications checks that the notifications carry the client
+// state of their subscription, so that only the remote can send them. The
+// notifications that don't are dropped, so that they don't fail the others,
+// and errUnauthorizedNotification is returned only when none is left.
+// Notifications of unknown subscriptions are dropped.
+func (api *api) authenticateNotifications(notifications []*remote.Notification) ([]*remote.Notification, error) {
+	authenticated := []*remote.Notification{}
+	rejected := 0
+	for _, n := range notifications {
+		sub, err := api.Store.LoadSubscription(n.SubscriptionID)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
 		if err != nil {
-			api.Logger.With(bot.LogContext{"err": err.Error()}).Errorf("notification, error occurred while adding webhook event to notification queue")
-			httputils.WriteInternalServerError(w, err)
-			return
+			return nil, err
 		}
+		if sub.Remote.ClientState != "" && subtle.ConstantTimeCompare([]byte(sub.Remote.ClientState), []byte(n.ClientState)) != 1 {
+			rejected++
+			api.Logger.With(bot.LogContext{
+				"subscriptionID": n.SubscriptionID,
+			}).Warnf("notification, dropped notification not matching the client state of its subscription")
+			continue
+		}
+		authenticated = append(authenticated, n)
+	}
+	if len(authenticated) == 0 && rejected > 0 {
+		return nil, errUnauthorizedNotification
 	}
+	return authenticated, nil
 }
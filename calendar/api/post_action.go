@@ -16,31 +16,42 @@ import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils"
 )
 
-func (api *api) preprocessAction(w http.ResponseWriter, req *http.Request) (mscal engine.Engine, user *engine.User, eventID string, option string, postID string) {
+func (api *api) preprocessAction(w http.ResponseWriter, req *http.Request) (mscal engine.Engine, user *engine.User, eventID string, option string, postID string, principalID string) {
 	mattermostUserID := req.Header.Get("Mattermost-User-ID")
 	if mattermostUserID == "" {
 		utils.SlackAttachmentError(w, "Error: not authorized")
-		return nil, nil, "", "", ""
+		return nil, nil, "", "", "", ""
 	}
 
 	var request model.PostActionIntegrationRequest
 	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
 		utils.SlackAttachmentError(w, "Error: invalid request")
-		return nil, nil, "", "", ""
+		return nil, nil, "", "", "", ""
 	}
 
 	eventID, ok := request.Context[config.EventIDKey].(string)
 	if !ok {
 		utils.SlackAttachmentError(w, "Error: missing event ID")
-		return nil, nil, "", "", ""
+		return nil, nil, "", "", "", ""
 	}
 	option, _ = request.Context["selected_option"].(string)
+	principalID, _ = request.Context[config.PrincipalIDKey].(string)
 	mscal = engine.New(api.Env, mattermostUserID)
 
-	return mscal, engine.NewUser(mattermostUserID), eventID, option, request.PostId
+	return mscal, engine.NewUser(mattermostUserID), eventID, option, request.PostId, principalID
+}
+
+// respondToEvent responds as the user, or on behalf of the principal whose
+// invitation was sent to the user as their delegate.
+func respondToEvent(mscal engine.Engine, user *engine.User, principalID, eventID, option string, response *remote.EventResponse) error {
+	if principalID != "" {
+		return mscal.RespondToEventAs(user, principalID, eventID, option, response)
+	}
+	return mscal.RespondToEvent(user, eventID, option, response)
 }
 
 // authorizePostAction ensures the acting user is allowed to read the channel the
@@ -62,14 +73,14 @@ func (api *api) authorizePostAction(w http.ResponseWriter, postID, mattermostUse
 }
 
 func (api *api) postActionAccept(w http.ResponseWriter, req *http.Request) {
-	localEngine, user, eventID, _, postID := api.preprocessAction(w, req)
+	localEngine, user, eventID, _, postID, _ := api.preprocessAction(w, req)
 	if eventID == "" {
 		return
 	}
 	if _, ok := api.authorizePostAction(w, postID, user.MattermostUserID); !ok {
 		return
 	}
-	err := localEngine.AcceptEvent(user, eventID)
+	err := localEngine.AcceptEvent(user, eventID, nil)
 	if err != nil {
 		api.Logger.Warnf("Failed to accept event. err=%v", err)
 		utils.SlackAttachmentError(w, "Error: Failed to accept event: "+err.Error())
@@ -78,14 +89,14 @@ func (api *api) postActionAccept(w http.ResponseWriter, req *http.Request) {
 }
 
 func (api *api) postActionDecline(w http.ResponseWriter, req *http.Request) {
-	localEngine, user, eventID, _, postID := api.preprocessAction(w, req)
+	localEngine, user, eventID, _, postID, _ := api.preprocessAction(w, req)
 	if eventID == "" {
 		return
 	}
 	if _, ok := api.authorizePostAction(w, postID, user.MattermostUserID); !ok {
 		return
 	}
-	err := localEngine.DeclineEvent(user, eventID)
+	err := localEngine.DeclineEvent(user, eventID, nil)
 	if err != nil {
 		utils.SlackAttachmentError(w, "Error: Failed to decline event: "+err.Error())
 		return
@@ -93,14 +104,14 @@ func (api *api) postActionDecline(w http.ResponseWriter, req *http.Request) {
 }
 
 func (api *api) postActionTentative(w http.ResponseWriter, req *http.Request) {
-	localEngine, user, eventID, _, postID := api.preprocessAction(w, req)
+	localEngine, user, eventID, _, postID, _ := api.preprocessAction(w, req)
 	if eventID == "" {
 		return
 	}
 	if _, ok := api.authorizePostAction(w, postID, user.MattermostUserID); !ok {
 		return
 	}
-	err := localEngine.TentativelyAcceptEvent(user, eventID)
+	err := localEngine.TentativelyAcceptEvent(user, eventID, nil)
 	if err != nil {
 		utils.SlackAttachmentError(w, "Error: Failed to tentatively accept event: "+err.Error())
 		return
@@ -108,7 +119,7 @@ func (api *api) postActionTentative(w http.ResponseWriter, req *http.Request) {
 }
 
 func (api *api) postActionRespond(w http.ResponseWriter, req *http.Request) {
-	calendar, user, eventID, option, postID := api.preprocessAction(w, req)
+	calendar, user, eventID, option, postID, principalID := api.preprocessAction(w, req)
 	if eventID == "" {
 		return
 	}
@@ -118,7 +129,7 @@ func (api *api) postActionRespond(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err := calendar.RespondToEvent(user, eventID, option)
+	err := respondToEvent(calendar, user, principalID, eventID, option, nil)
 	if err != nil && !isAcceptedError(err) && !isNotFoundError(err) && !isCanceledError(err) {
 		utils.SlackAttachmentError(w, "Error: Failed to respond to event: "+err.Error())
 		return
@@ -129,15 +140,35 @@ func (api *api) postActionRespond(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	sas := p.Attachments()
-	if len(sas) == 0 {
+	if !markResponded(p, option, err == nil || isAcceptedError(err)) {
 		utils.SlackAttachmentError(w, "Error: Failed to update the post: No attachments found")
 		return
 	}
 
+	postResponse := model.PostActionIntegrationResponse{}
+	postResponse.Update = p
+
+	if err != nil && isNotFoundError(err) {
+		postResponse.EphemeralText = "Event has changed since this message. Please change your status directly on MS Calendar."
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(postResponse); err != nil {
+		utils.SlackAttachmentError(w, "Error: unable to write response, "+err.Error())
+	}
+}
+
+// markResponded removes the response actions from the notification post,
+// noting the response if it was sent. It returns false if the post has no
+// attachment to update.
+func markResponded(p *model.Post, option string, responded bool) bool {
+	sas := p.Attachments()
+	if len(sas) == 0 {
+		return false
+	}
+
 	sa := sas[0]
 
-	if err == nil || isAcceptedError(err) {
+	if responded {
 		sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
 			Title: "Response",
 			Value: fmt.Sprintf("You have %s this event", prettyOption(option)),
@@ -146,18 +177,8 @@ func (api *api) postActionRespond(w http.ResponseWriter, req *http.Request) {
 	}
 
 	sa.Actions = []*model.PostAction{}
-	postResponse := model.PostActionIntegrationResponse{}
 	model.ParseSlackAttachment(p, []*model.SlackAttachment{sa})
-
-	postResponse.Update = p
-
-	if err != nil && isNotFoundError(err) {
-		postResponse.EphemeralText = "Event has changed since this message. Please change your status directly on MS Calendar."
-	}
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(postResponse); err != nil {
-		utils.SlackAttachmentError(w, "Error: unable to write response, "+err.Error())
-	}
+	return true
 }
 
 func prettyOption(option string) string {
@@ -173,6 +194,141 @@ func prettyOption(option string) string {
 	}
 }
 
+// postActionBookRoom books the room of the button for the user, and removes
+// the buttons from the post so that a single room is booked.
+func (api *api) postActionBookRoom(w http.ResponseWriter, req *http.Request) {
+	mattermostUserID := req.Header.Get("Mattermost-User-ID")
+	if mattermostUserID == "" {
+		utils.SlackAttachmentError(w, "Error: not authorized")
+		return
+	}
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		utils.SlackAttachmentError(w, "Error: invalid request")
+		return
+	}
+
+	room := &remote.Room{}
+	room.EmailAddress, _ = request.Context[config.RoomKey].(string)
+	room.DisplayName, _ = request.Context[config.RoomNameKey].(string)
+	if room.EmailAddress == "" {
+		utils.SlackAttachmentError(w, "Error: missing room")
+		return
+	}
+	start, errStart := parseContextTime(request.Context, config.StartKey)
+	end, errEnd := parseContextTime(request.Context, config.EndKey)
+	if errStart != nil || errEnd != nil {
+		utils.SlackAttachmentError(w, "Error: invalid booking time")
+		return
+	}
+
+	p, ok := api.authorizePostAction(w, request.PostId, mattermostUserID)
+	if !ok {
+		return
+	}
+
+	user := engine.NewUser(mattermostUserID)
+	_, err := engine.New(api.Env, mattermostUserID).BookRoom(user, room, start, end)
+	if err != nil {
+		api.Logger.Warnf("Failed to book room. err=%v", err)
+		utils.SlackAttachmentError(w, "Error: Failed to book the room: "+err.Error())
+		return
+	}
+
+	closeOffer(p, config.RoomKey, room.EmailAddress, &model.SlackAttachmentField{
+		Title: "Booking",
+		Value: "You have invited this room. It accepts or declines the invitation on its own.",
+		Short: false,
+	})
+	postResponse := model.PostActionIntegrationResponse{Update: p}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(postResponse); err != nil {
+		utils.SlackAttachmentError(w, "Error: unable to write response, "+err.Error())
+	}
+}
+
+// postActionScheduleMeeting creates the meeting at the time of the button for
+// the user, and removes the buttons from the post so that a single meeting is
+// created.
+func (api *api) postActionScheduleMeeting(w http.ResponseWriter, req *http.Request) {
+	mattermostUserID := req.Header.Get("Mattermost-User-ID")
+	if mattermostUserID == "" {
+		utils.SlackAttachmentError(w, "Error: not authorized")
+		return
+	}
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		utils.SlackAttachmentError(w, "Error: invalid request")
+		return
+	}
+
+	subject, _ := request.Context[config.SubjectKey].(string)
+	attendees, _ := request.Context[config.AttendeesKey].([]interface{})
+	mails := []string{}
+	for _, a := range attendees {
+		if mail, ok := a.(string); ok && mail != "" {
+			mails = append(mails, mail)
+		}
+	}
+	if len(mails) == 0 {
+		utils.SlackAttachmentError(w, "Error: missing attendees")
+		return
+	}
+	start, errStart := parseContextTime(request.Context, config.StartKey)
+	end, errEnd := parseContextTime(request.Context, config.EndKey)
+	if errStart != nil || errEnd != nil {
+		utils.SlackAttachmentError(w, "Error: invalid meeting time")
+		return
+	}
+
+	p, ok := api.authorizePostAction(w, request.PostId, mattermostUserID)
+	if !ok {
+		return
+	}
+
+	user := engine.NewUser(mattermostUserID)
+	_, err := engine.New(api.Env, mattermostUserID).ScheduleMeeting(user, subject, mails, start, end)
+	if err != nil {
+		api.Logger.Warnf("Failed to schedule meeting. err=%v", err)
+		utils.SlackAttachmentError(w, "Error: Failed to schedule the meeting: "+err.Error())
+		return
+	}
+
+	closeOffer(p, config.StartKey, request.Context[config.StartKey], &model.SlackAttachmentField{
+		Title: "Scheduled",
+		Value: "The meeting has been added to your calendar and the attendees have been invited.",
+		Short: false,
+	})
+	postResponse := model.PostActionIntegrationResponse{Update: p}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(postResponse); err != nil {
+		utils.SlackAttachmentError(w, "Error: unable to write response, "+err.Error())
+	}
+}
+
+func parseContextTime(ctx map[string]interface{}, key string) (time.Time, error) {
+	value, ok := ctx[key].(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("missing %s", key)
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// closeOffer removes the buttons of the choices offered in the post, noting
+// the chosen one, whose button context has the given value for key.
+func closeOffer(p *model.Post, key string, value interface{}, note *model.SlackAttachmentField) {
+	sas := p.Attachments()
+	for _, sa := range sas {
+		if len(sa.Actions) > 0 && sa.Actions[0].Integration != nil && sa.Actions[0].Integration.Context[key] == value {
+			sa.Fields = append(sa.Fields, note)
+		}
+		sa.Actions = []*model.PostAction{}
+	}
+	model.ParseSlackAttachment(p, sas)
+}
+
 func (api *api) postActionConfirmStatusChange(w http.ResponseWriter, req *http.Request) {
 	mattermostUserID := req.Header.Get("Mattermost-User-ID")
 	if mattermostUserID == "" {
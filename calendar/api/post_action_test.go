@@ -33,18 +33,19 @@ func TestPreprocessAction(t *testing.T) {
 	tests := []struct {
 		name       string
 		setup      func(*http.Request)
-		assertions func(*httptest.ResponseRecorder, engine.Engine, *engine.User, string, string, string)
+		assertions func(*httptest.ResponseRecorder, engine.Engine, *engine.User, string, string, string, string)
 	}{
 		{
 			name:  "Missing Mattermost user ID",
 			setup: func(req *http.Request) {},
-			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID string) {
+			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID, principalID string) {
 				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
 				assert.Nil(t, mscal)
 				assert.Nil(t, user)
 				assert.Empty(t, eventID)
 				assert.Empty(t, option)
 				assert.Empty(t, postID)
+				assert.Empty(t, principalID)
 			},
 		},
 		{
@@ -53,13 +54,14 @@ func TestPreprocessAction(t *testing.T) {
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				req.Body = io.NopCloser(bytes.NewBufferString("invalid json"))
 			},
-			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID string) {
+			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID, principalID string) {
 				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
 				assert.Nil(t, mscal)
 				assert.Nil(t, user)
 				assert.Empty(t, eventID)
 				assert.Empty(t, option)
 				assert.Empty(t, postID)
+				assert.Empty(t, principalID)
 			},
 		},
 		{
@@ -73,13 +75,14 @@ func TestPreprocessAction(t *testing.T) {
 				bodyBytes, _ := json.Marshal(requestBody)
 				req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 			},
-			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID string) {
+			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID, principalID string) {
 				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
 				assert.Nil(t, mscal)
 				assert.Nil(t, user)
 				assert.Empty(t, eventID)
 				assert.Empty(t, option)
 				assert.Empty(t, postID)
+				assert.Empty(t, principalID)
 			},
 		},
 		{
@@ -96,7 +99,7 @@ func TestPreprocessAction(t *testing.T) {
 				bodyBytes, _ := json.Marshal(requestBody)
 				req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 			},
-			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID string) {
+			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID, principalID string) {
 				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
 				assert.NotNil(t, mscal)
 				assert.NotNil(t, user)
@@ -104,6 +107,29 @@ func TestPreprocessAction(t *testing.T) {
 				assert.Equal(t, MockEventID, eventID)
 				assert.Equal(t, MockOption, option)
 				assert.Equal(t, MockPostID, postID)
+				assert.Empty(t, principalID)
+			},
+		},
+		{
+			name: "Valid request on behalf of a principal",
+			setup: func(req *http.Request) {
+				req.Header.Set(MMUserIDHeader, MockUserID)
+				requestBody := model.PostActionIntegrationRequest{
+					Context: map[string]interface{}{
+						config.EventIDKey:     MockEventID,
+						config.PrincipalIDKey: "principalUserID",
+						"selected_option":     MockOption,
+					},
+					PostId: MockPostID,
+				}
+				bodyBytes, _ := json.Marshal(requestBody)
+				req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			},
+			assertions: func(rec *httptest.ResponseRecorder, mscal engine.Engine, user *engine.User, eventID, option, postID, principalID string) {
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				assert.NotNil(t, mscal)
+				assert.Equal(t, MockEventID, eventID)
+				assert.Equal(t, "principalUserID", principalID)
 			},
 		},
 	}
@@ -114,9 +140,9 @@ func TestPreprocessAction(t *testing.T) {
 
 			tc.setup(req)
 
-			mscal, user, eventID, option, postID := api.preprocessAction(rec, req)
+			mscal, user, eventID, option, postID, principalID := api.preprocessAction(rec, req)
 
-			tc.assertions(rec, mscal, user, eventID, option, postID)
+			tc.assertions(rec, mscal, user, eventID, option, postID, principalID)
 		})
 	}
 }
@@ -174,7 +200,7 @@ func TestPostActionAccept(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Times(2)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -203,6 +229,185 @@ func TestPostActionAccept(t *testing.T) {
 	}
 }
 
+func TestPostActionBookRoom(t *testing.T) {
+	api, mockStore, _, mockRemote, mockPluginAPI, _, _, mockClient := GetMockSetup(t)
+	bookContext := map[string]interface{}{
+		config.RoomKey:     "room1@example.com",
+		config.RoomNameKey: "Room 1",
+		config.StartKey:    "2024-03-04T14:00:00Z",
+		config.EndKey:      "2024-03-04T14:30:00Z",
+	}
+	offer := func() *model.Post {
+		p := &model.Post{ChannelId: MockChannelID}
+		model.ParseSlackAttachment(p, []*model.SlackAttachment{
+			{Title: "Room 1", Actions: []*model.PostAction{{Name: "Book", Integration: &model.PostActionIntegration{Context: bookContext}}}},
+			{Title: "Room 2", Actions: []*model.PostAction{{Name: "Book", Integration: &model.PostActionIntegration{Context: map[string]interface{}{config.RoomKey: "room2@example.com"}}}}},
+		})
+		return p
+	}
+
+	tests := []struct {
+		name       string
+		context    map[string]interface{}
+		setup      func()
+		assertions func(*httptest.ResponseRecorder)
+	}{
+		{
+			name:    "Missing room",
+			context: map[string]interface{}{},
+			setup:   func() {},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "Error: missing room", response.EphemeralText)
+			},
+		},
+		{
+			name:    "User not authorized to read the post's channel",
+			context: bookContext,
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost(MockPostID).Return(offer(), nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(false)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "Error: not authorized", response.EphemeralText)
+			},
+		},
+		{
+			name:    "Book room successfully",
+			context: bookContext,
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost(MockPostID).Return(offer(), nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true)
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Times(2)
+				mockClient.EXPECT().CreateEvent("", gomock.Any()).DoAndReturn(func(_ string, event *remote.Event) (*remote.Event, error) {
+					assert.Equal(t, "room1@example.com", event.Attendees[0].EmailAddress.Address)
+					assert.Equal(t, time.Date(2024, 3, 4, 14, 30, 0, 0, time.UTC), event.End.Time())
+					return &remote.Event{ID: MockEventID}, nil
+				})
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				attachments := response.Update.Attachments()
+				assert.Len(t, attachments, 2)
+				assert.Empty(t, attachments[0].Actions)
+				assert.Len(t, attachments[0].Fields, 1)
+				assert.Empty(t, attachments[1].Actions)
+				assert.Empty(t, attachments[1].Fields)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bodyBytes, _ := json.Marshal(model.PostActionIntegrationRequest{Context: tc.context, PostId: MockPostID})
+			req := httptest.NewRequest(http.MethodPost, "/postActionBookRoom", bytes.NewBuffer(bodyBytes))
+			req.Header.Set(MMUserIDHeader, MockUserID)
+			rec := httptest.NewRecorder()
+
+			tc.setup()
+			api.postActionBookRoom(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			tc.assertions(rec)
+		})
+	}
+}
+
+func TestPostActionScheduleMeeting(t *testing.T) {
+	api, mockStore, _, mockRemote, mockPluginAPI, _, _, mockClient := GetMockSetup(t)
+	scheduleContext := map[string]interface{}{
+		config.SubjectKey:   "Meeting with Alice",
+		config.AttendeesKey: []interface{}{"alice@example.com"},
+		config.StartKey:     "2024-03-04T14:00:00Z",
+		config.EndKey:       "2024-03-04T14:45:00Z",
+	}
+	offer := func() *model.Post {
+		p := &model.Post{ChannelId: MockChannelID}
+		model.ParseSlackAttachment(p, []*model.SlackAttachment{
+			{Title: "2:00PM", Actions: []*model.PostAction{{Name: "Schedule this", Integration: &model.PostActionIntegration{Context: scheduleContext}}}},
+			{Title: "3:00PM", Actions: []*model.PostAction{{Name: "Schedule this", Integration: &model.PostActionIntegration{Context: map[string]interface{}{config.StartKey: "2024-03-04T15:00:00Z"}}}}},
+		})
+		return p
+	}
+
+	tests := []struct {
+		name       string
+		context    map[string]interface{}
+		setup      func()
+		assertions func(*httptest.ResponseRecorder)
+	}{
+		{
+			name:    "Missing attendees",
+			context: map[string]interface{}{config.StartKey: "2024-03-04T14:00:00Z"},
+			setup:   func() {},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "Error: missing attendees", response.EphemeralText)
+			},
+		},
+		{
+			name:    "User not authorized to read the post's channel",
+			context: scheduleContext,
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost(MockPostID).Return(offer(), nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(false)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "Error: not authorized", response.EphemeralText)
+			},
+		},
+		{
+			name:    "Schedule meeting successfully",
+			context: scheduleContext,
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost(MockPostID).Return(offer(), nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true)
+				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Times(2)
+				mockClient.EXPECT().CreateEvent("", gomock.Any()).DoAndReturn(func(_ string, event *remote.Event) (*remote.Event, error) {
+					assert.Equal(t, "Meeting with Alice", event.Subject)
+					assert.Equal(t, "alice@example.com", event.Attendees[0].EmailAddress.Address)
+					assert.Equal(t, time.Date(2024, 3, 4, 14, 45, 0, 0, time.UTC), event.End.Time())
+					return &remote.Event{ID: MockEventID}, nil
+				})
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				attachments := response.Update.Attachments()
+				assert.Len(t, attachments, 2)
+				assert.Empty(t, attachments[0].Actions)
+				assert.Len(t, attachments[0].Fields, 1)
+				assert.Empty(t, attachments[1].Actions)
+				assert.Empty(t, attachments[1].Fields)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bodyBytes, _ := json.Marshal(model.PostActionIntegrationRequest{Context: tc.context, PostId: MockPostID})
+			req := httptest.NewRequest(http.MethodPost, "/postActionScheduleMeeting", bytes.NewBuffer(bodyBytes))
+			req.Header.Set(MMUserIDHeader, MockUserID)
+			rec := httptest.NewRecorder()
+
+			tc.setup()
+			api.postActionScheduleMeeting(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			tc.assertions(rec)
+		})
+	}
+}
+
 func TestPostDeclineAccept(t *testing.T) {
 	api, mockStore, _, mockRemote, mockPluginAPI, _, _, mockClient := GetMockSetup(t)
 
@@ -256,7 +461,7 @@ func TestPostDeclineAccept(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Times(2)
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -338,7 +543,7 @@ func TestPostActionTentative(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Times(2)
-				mockClient.EXPECT().TentativelyAcceptEvent(MockEventID).Return(nil)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -395,7 +600,7 @@ func TestPostActionRespond(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(errors.New("failed to decline event"))
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(errors.New("failed to decline event"))
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -482,7 +687,7 @@ func TestPostActionRespond(t *testing.T) {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
 				mockPluginAPI.EXPECT().GetPost("").Return(&model.Post{ChannelId: MockChannelID}, nil)
 				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil)
 
 				req.Header.Set(MMUserIDHeader, MockUserID)
 				requestBody := model.PostActionIntegrationRequest{
@@ -508,7 +713,7 @@ func TestPostActionRespond(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{Remote: &remote.User{ID: MockRemoteUserID}}, nil).Times(2)
 				mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).Times(2)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil)
 				attachment := model.SlackAttachment{
 					Title: "Example Title",
 					Text:  "This is an example attachment.",
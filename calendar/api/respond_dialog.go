@@ -0,0 +1,266 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/httputils"
+)
+
+const (
+	respondDialogResponse     = "response"
+	respondDialogComment      = "comment"
+	respondDialogSendResponse = "send_response"
+	respondDialogDate         = "proposed_date"
+	respondDialogStartTime    = "proposed_start_time"
+	respondDialogEndTime      = "proposed_end_time"
+
+	// maxResponseCommentLen is the longest text a dialog textarea accepts.
+	maxResponseCommentLen = 3000
+)
+
+// respondDialogState is passed through the dialog, to find the event to
+// respond to and the notification post to update.
+type respondDialogState struct {
+	EventID string `json:"event_id"`
+	PostID  string `json:"post_id"`
+	// PrincipalID is set when responding on behalf of a principal, as their
+	// delegate.
+	PrincipalID string `json:"principal_id,omitempty"`
+}
+
+// postActionRespondWithComment opens the dialog to respond to an event with a
+// comment, or to propose a new time to the organizer.
+func (api *api) postActionRespondWithComment(w http.ResponseWriter, req *http.Request) {
+	mattermostUserID := req.Header.Get("Mattermost-User-ID")
+	if mattermostUserID == "" {
+		utils.SlackAttachmentError(w, "Error: not authorized")
+		return
+	}
+
+	var request model.PostActionIntegrationRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		utils.SlackAttachmentError(w, "Error: invalid request")
+		return
+	}
+
+	eventID, ok := request.Context[config.EventIDKey].(string)
+	if !ok {
+		utils.SlackAttachmentError(w, "Error: missing event ID")
+		return
+	}
+	if _, ok = api.authorizePostAction(w, request.PostId, mattermostUserID); !ok {
+		return
+	}
+
+	principalID, _ := request.Context[config.PrincipalIDKey].(string)
+	state, err := json.Marshal(respondDialogState{EventID: eventID, PostID: request.PostId, PrincipalID: principalID})
+	if err != nil {
+		utils.SlackAttachmentError(w, "Error: invalid request")
+		return
+	}
+
+	err = api.PluginAPI.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: request.TriggerId,
+		URL:       api.Config.PluginURLPath + config.PathDialogs + config.PathRespond,
+		Dialog:    newRespondDialog(string(state)),
+	})
+	if err != nil {
+		api.Logger.Warnf("Failed to open the respond dialog. err=%v", err)
+		utils.SlackAttachmentError(w, "Error: Failed to open the dialog: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(model.PostActionIntegrationResponse{}); err != nil {
+		utils.SlackAttachmentError(w, "Error: unable to write response, "+err.Error())
+	}
+}
+
+func newRespondDialog(state string) model.Dialog {
+	return model.Dialog{
+		Title:       "Respond to event",
+		SubmitLabel: "Respond",
+		State:       state,
+		Elements: []model.DialogElement{
+			{
+				DisplayName: "Response",
+				Name:        respondDialogResponse,
+				Type:        "select",
+				Options: []*model.PostActionOptions{
+					{Text: "Accept", Value: engine.OptionYes},
+					{Text: "Tentative", Value: engine.OptionMaybe},
+					{Text: "Decline", Value: engine.OptionNo},
+				},
+			},
+			{
+				DisplayName: "Comment",
+				Name:        respondDialogComment,
+				Type:        "textarea",
+				Optional:    true,
+				MaxLength:   maxResponseCommentLen,
+			},
+			{
+				DisplayName: "Send the response to the organizer",
+				Name:        respondDialogSendResponse,
+				Type:        "bool",
+				Default:     "true",
+				Optional:    true,
+			},
+			{
+				DisplayName: "Propose a new date",
+				Name:        respondDialogDate,
+				Type:        "text",
+				Placeholder: "YYYY-MM-DD",
+				HelpText:    "To propose a new time, set its date, start and end times. Only tentative and decline responses sent to the organizer can propose one.",
+				Optional:    true,
+			},
+			{
+				DisplayName: "Proposed start time",
+				Name:        respondDialogStartTime,
+				Type:        "text",
+				Placeholder: "HH:MM",
+				Optional:    true,
+			},
+			{
+				DisplayName: "Proposed end time",
+				Name:        respondDialogEndTime,
+				Type:        "text",
+				Placeholder: "HH:MM",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// submitRespondDialog sends the response filled in the dialog, and removes
+// the response actions from the notification post.
+func (api *api) submitRespondDialog(w http.ResponseWriter, req *http.Request) {
+	mattermostUserID := req.Header.Get("Mattermost-User-ID")
+	if mattermostUserID == "" {
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	var request model.SubmitDialogRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		httputils.WriteBadRequestError(w, err)
+		return
+	}
+	if request.Cancelled {
+		return
+	}
+
+	var state respondDialogState
+	if err := json.Unmarshal([]byte(request.State), &state); err != nil || state.EventID == "" {
+		httputils.WriteBadRequestError(w, fmt.Errorf("invalid dialog state"))
+		return
+	}
+
+	post, err := api.PluginAPI.GetPost(state.PostID)
+	if err != nil {
+		writeDialogError(w, "Failed to get the post: "+err.Error(), nil)
+		return
+	}
+	if !api.PluginAPI.CanReadChannel(post.ChannelId, mattermostUserID) {
+		httputils.WriteUnauthorizedError(w, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	option, _ := request.Submission[respondDialogResponse].(string)
+	comment, _ := request.Submission[respondDialogComment].(string)
+	response := &remote.EventResponse{
+		Comment:      comment,
+		SendResponse: submittedBool(request.Submission[respondDialogSendResponse]),
+	}
+
+	eng := engine.New(api.Env, mattermostUserID)
+	user := engine.NewUser(mattermostUserID)
+
+	schedule := createEventPayload{}
+	schedule.Date, _ = request.Submission[respondDialogDate].(string)
+	schedule.StartTime, _ = request.Submission[respondDialogStartTime].(string)
+	schedule.EndTime, _ = request.Submission[respondDialogEndTime].(string)
+	if schedule.Date != "" || schedule.StartTime != "" || schedule.EndTime != "" {
+		slot, fieldErrors, err := proposedTimeSlot(eng, user, schedule)
+		if err != nil {
+			writeDialogError(w, "Failed to propose a new time: "+err.Error(), fieldErrors)
+			return
+		}
+		response.ProposedNewTime = slot
+	}
+
+	err = respondToEvent(eng, user, state.PrincipalID, state.EventID, option, response)
+	switch {
+	case err != nil && isCanceledError(err):
+		writeDialogError(w, "Cannot respond to the event because it is already canceled.", nil)
+		return
+	case err != nil && isNotFoundError(err):
+		writeDialogError(w, "Event has changed since this message. Please change your status directly on MS Calendar.", nil)
+		return
+	case err != nil && !isAcceptedError(err):
+		writeDialogError(w, "Failed to respond to event: "+err.Error(), nil)
+		return
+	}
+
+	if markResponded(post, option, true) {
+		if err = api.Poster.UpdatePost(post); err != nil {
+			api.Logger.Warnf("Failed to update the post after responding to event. err=%v", err)
+		}
+	}
+}
+
+// proposedTimeSlot returns the time slot filled in the dialog, in the time
+// zone of the mailbox of the user. Errors on a single field are returned
+// by field name.
+func proposedTimeSlot(eng engine.Engine, user *engine.User, schedule createEventPayload) (*remote.TimeSlot, map[string]string, error) {
+	if schedule.Date == "" {
+		return nil, map[string]string{respondDialogDate: "Please set the date of the new time."}, fmt.Errorf("missing date")
+	}
+	if schedule.StartTime == "" || schedule.EndTime == "" {
+		return nil, map[string]string{respondDialogStartTime: "Please set the start and end times of the new time."}, fmt.Errorf("missing time")
+	}
+
+	loc, err := mailboxLocation(eng, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = schedule.isValidSchedule(loc); err != nil {
+		return nil, nil, err
+	}
+	event, err := schedule.ToRemoteEvent(loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &remote.TimeSlot{Start: event.Start, End: event.End}, nil, nil
+}
+
+// submittedBool reads a bool dialog element, submitted as a bool or as a
+// string depending on the client.
+func submittedBool(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	default:
+		return false
+	}
+}
+
+func writeDialogError(w http.ResponseWriter, message string, fieldErrors map[string]string) {
+	httputils.WriteJSONResponse(w, model.SubmitDialogResponse{
+		Error:  message,
+		Errors: fieldErrors,
+	}, http.StatusOK)
+}
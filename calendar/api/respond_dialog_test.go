@@ -0,0 +1,201 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestPostActionRespondWithComment(t *testing.T) {
+	api, _, _, _, mockPluginAPI, _, _, _ := GetMockSetup(t)
+	api.Config = &config.Config{PluginURLPath: "/plugins/mscalendar"}
+
+	tests := []struct {
+		name       string
+		setup      func()
+		assertions func(*httptest.ResponseRecorder)
+	}{
+		{
+			name: "User not authorized to read the post's channel",
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost("post_id").Return(&model.Post{ChannelId: MockChannelID}, nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(false)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "Error: not authorized", response.EphemeralText)
+			},
+		},
+		{
+			name: "Dialog opened",
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost("post_id").Return(&model.Post{ChannelId: MockChannelID}, nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true)
+				mockPluginAPI.EXPECT().OpenInteractiveDialog(gomock.Any()).DoAndReturn(func(dialog model.OpenDialogRequest) error {
+					assert.Equal(t, "trigger_id", dialog.TriggerId)
+					assert.Equal(t, "/plugins/mscalendar/dialogs/respond", dialog.URL)
+					assert.JSONEq(t, `{"event_id": "mockEventID", "post_id": "post_id"}`, dialog.Dialog.State)
+					return nil
+				})
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.PostActionIntegrationResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Empty(t, response.EphemeralText)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _ := json.Marshal(model.PostActionIntegrationRequest{
+				PostId:    "post_id",
+				TriggerId: "trigger_id",
+				Context: map[string]interface{}{
+					config.EventIDKey: MockEventID,
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, config.PathRespondWithComment, bytes.NewBuffer(body))
+			req.Header.Set(MMUserIDHeader, MockUserID)
+			rec := httptest.NewRecorder()
+
+			tc.setup()
+			api.postActionRespondWithComment(rec, req)
+
+			tc.assertions(rec)
+		})
+	}
+}
+
+func TestSubmitRespondDialog(t *testing.T) {
+	api, mockStore, mockPoster, mockRemote, mockPluginAPI, _, _, mockClient := GetMockSetup(t)
+	api.Config = &config.Config{}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	date := tomorrow.Format(createEventDateFormat)
+	notification := func() *model.Post {
+		post := &model.Post{ChannelId: MockChannelID}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{{Title: "Planning"}})
+		return post
+	}
+	expectUser := func() {
+		mockStore.EXPECT().LoadUser(MockUserID).Return(&store.User{
+			MattermostUserID: MockUserID,
+			Remote:           &remote.User{ID: MockRemoteUserID},
+		}, nil).AnyTimes()
+		mockPluginAPI.EXPECT().GetMattermostUser(MockUserID).Return(&model.User{Id: MockUserID}, nil).AnyTimes()
+		mockRemote.EXPECT().MakeUserClient(gomock.Any(), gomock.Any(), MockUserID, gomock.Any(), gomock.Any()).Return(mockClient, nil)
+	}
+
+	tests := []struct {
+		name       string
+		submission map[string]interface{}
+		setup      func()
+		assertions func(*httptest.ResponseRecorder)
+	}{
+		{
+			name: "Declined with a comment and a proposed new time",
+			submission: map[string]interface{}{
+				respondDialogResponse:     engine.OptionNo,
+				respondDialogComment:      "I'm out of office in the morning",
+				respondDialogSendResponse: true,
+				respondDialogDate:         date,
+				respondDialogStartTime:    "14:00",
+				respondDialogEndTime:      "15:00",
+			},
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost("post_id").Return(notification(), nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true)
+				expectUser()
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{
+					Comment:      "I'm out of office in the morning",
+					SendResponse: true,
+					ProposedNewTime: &remote.TimeSlot{
+						Start: &remote.DateTime{DateTime: date + "T14:00:00", TimeZone: "UTC"},
+						End:   &remote.DateTime{DateTime: date + "T15:00:00", TimeZone: "UTC"},
+					},
+				}).Return(nil)
+				mockPoster.EXPECT().UpdatePost(gomock.Any()).DoAndReturn(func(post *model.Post) error {
+					sa := post.Attachments()[0]
+					assert.Empty(t, sa.Actions)
+					assert.Equal(t, "You have declined this event", sa.Fields[0].Value)
+					return nil
+				})
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				assert.Empty(t, rec.Body.String())
+			},
+		},
+		{
+			name: "Proposed new time missing its times",
+			submission: map[string]interface{}{
+				respondDialogResponse: engine.OptionMaybe,
+				respondDialogDate:     date,
+			},
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost("post_id").Return(notification(), nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true)
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Contains(t, response.Errors, respondDialogStartTime)
+			},
+		},
+		{
+			name: "Event already canceled",
+			submission: map[string]interface{}{
+				respondDialogResponse:     engine.OptionYes,
+				respondDialogSendResponse: "false",
+			},
+			setup: func() {
+				mockPluginAPI.EXPECT().GetPost("post_id").Return(notification(), nil)
+				mockPluginAPI.EXPECT().CanReadChannel(MockChannelID, MockUserID).Return(true)
+				expectUser()
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{}).Return(errors.New("You can't respond to a meeting that's been canceled."))
+			},
+			assertions: func(rec *httptest.ResponseRecorder) {
+				var response model.SubmitDialogResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+				assert.Equal(t, "Cannot respond to the event because it is already canceled.", response.Error)
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body, _ := json.Marshal(model.SubmitDialogRequest{
+				UserId:     MockUserID,
+				State:      `{"event_id": "mockEventID", "post_id": "post_id"}`,
+				Submission: tc.submission,
+			})
+			req := httptest.NewRequest(http.MethodPost, config.PathDialogs+config.PathRespond, bytes.NewBuffer(body))
+			req.Header.Set(MMUserIDHeader, MockUserID)
+			rec := httptest.NewRecorder()
+
+			tc.setup()
+			api.submitRespondDialog(rec, req)
+
+			tc.assertions(rec)
+		})
+	}
+}
@@ -83,7 +83,7 @@ func GetMockSetup(t *testing.T) (*api, *mock_store.MockStore, *mock_bot.MockPost
 
 	api := &api{
 		Env:                   env,
-		NotificationProcessor: engine.NewNotificationProcessor(env),
+		NotificationProcessor: &MockNotificationProcessor{},
 	}
 
 	return api, mockStore, mockPoster, mockRemote, mockPluginAPI, mockLogger, mockLoggerWith, mockClient
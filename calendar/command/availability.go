@@ -3,6 +3,17 @@
 
 package command
 
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
 func (c *Command) debugAvailability(parameters ...string) (string, bool, error) {
 	switch {
 	case len(parameters) == 0:
@@ -23,3 +34,88 @@ func (c *Command) debugAvailability(parameters ...string) (string, bool, error)
 
 	return "bad syntax", false, nil
 }
+
+const (
+	availabilityStartHour    = 8
+	availabilityHours        = 10
+	availabilityViewInterval = 30
+	availabilityDateFormat   = "2006-01-02"
+)
+
+func getAvailabilityUsage() string {
+	return fmt.Sprintf("Please enter one or more users and an optional date, for example:\n`/%s availability @alice @bob %s`", config.Provider.CommandTrigger, time.Now().Format(availabilityDateFormat))
+}
+
+// availability renders the free/busy grid of the given users during the
+// working day, in the time zone of the acting user.
+func (c *Command) availability(parameters ...string) (string, bool, error) {
+	usernames := []string{}
+	day := ""
+	for _, p := range parameters {
+		if strings.HasPrefix(p, "@") {
+			usernames = append(usernames, p)
+			continue
+		}
+		if day != "" {
+			return getAvailabilityUsage(), false, nil
+		}
+		day = p
+	}
+	if len(usernames) == 0 {
+		return getAvailabilityUsage(), false, nil
+	}
+
+	timezone, err := c.Engine.GetTimezone(c.user())
+	if err != nil {
+		if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
+			return store.ErrorUserInactive, false, nil
+		}
+
+		return "Error: No timezone found", false, err
+	}
+	loc, err := time.LoadLocation(tz.Go(timezone))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	date, ok := parseAvailabilityDate(day, time.Now().In(loc))
+	if !ok {
+		return getAvailabilityUsage(), false, nil
+	}
+	start := time.Date(date.Year(), date.Month(), date.Day(), availabilityStartHour, 0, 0, 0, loc)
+	end := start.Add(availabilityHours * time.Hour)
+
+	availability, err := c.Engine.GetAvailability(c.user(), usernames, start, end, availabilityViewInterval)
+	if err != nil {
+		return "", false, err
+	}
+
+	rows := []*views.AvailabilityRow{}
+	for _, a := range availability {
+		row := &views.AvailabilityRow{
+			Username: a.MattermostUsername,
+			Error:    a.Error,
+		}
+		if a.Schedule != nil {
+			row.AvailabilityView = a.Schedule.AvailabilityView
+		}
+		rows = append(rows, row)
+	}
+
+	return views.RenderAvailabilityGrid(rows, start, availabilityHours, availabilityViewInterval), false, nil
+}
+
+func parseAvailabilityDate(day string, now time.Time) (time.Time, bool) {
+	switch strings.ToLower(day) {
+	case "", "today":
+		return now, true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), true
+	}
+
+	date, err := time.ParseInLocation(availabilityDateFormat, day, now.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
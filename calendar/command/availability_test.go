@@ -0,0 +1,157 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestAvailability(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2024, 1, 8, availabilityStartHour, 0, 0, 0, berlin)
+
+	testcase := []struct {
+		name       string
+		parameters []string
+		setup      func(engine.Engine)
+		assertions func(t *testing.T, output string, err error)
+	}{
+		{
+			name:       "no users",
+			parameters: []string{"tomorrow"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, getAvailabilityUsage(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "more than one date",
+			parameters: []string{"@alice", "today", "tomorrow"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, getAvailabilityUsage(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "invalid date",
+			parameters: []string{"@alice", "08/01/2024"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("Europe/Berlin", nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, getAvailabilityUsage(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "inactive user",
+			parameters: []string{"@alice"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("", errors.New(store.ErrorUserInactive)).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, store.ErrorUserInactive, output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "render availability",
+			parameters: []string{"@alice", "2024-01-08", "@bob"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("Europe/Berlin", nil).Times(1)
+				mscal.EXPECT().GetAvailability(gomock.Any(), []string{"@alice", "@bob"}, start, start.Add(availabilityHours*time.Hour), availabilityViewInterval).Return([]*engine.UserAvailability{
+					{MattermostUsername: "alice", Schedule: &remote.ScheduleInformation{AvailabilityView: "0022"}},
+					{MattermostUsername: "bob", Error: "User not found."},
+				}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, views.RenderAvailabilityGrid([]*views.AvailabilityRow{
+					{Username: "alice", AvailabilityView: "0022"},
+					{Username: "bob", Error: "User not found."},
+				}, start, availabilityHours, availabilityViewInterval), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "error getting availability",
+			parameters: []string{"@alice"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("Europe/Berlin", nil).Times(1)
+				mscal.EXPECT().GetAvailability(gomock.Any(), []string{"@alice"}, gomock.Any(), gomock.Any(), availabilityViewInterval).Return(nil, errors.New("schedule error")).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, "", output)
+				require.Equal(t, "schedule error", err.Error())
+			},
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s availability", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, _, err := command.availability(tt.parameters...)
+
+			tt.assertions(t, out, err)
+		})
+	}
+}
+
+func TestParseAvailabilityDate(t *testing.T) {
+	now := time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		in       string
+		expected time.Time
+		ok       bool
+	}{
+		{in: "", expected: now, ok: true},
+		{in: "Today", expected: now, ok: true},
+		{in: "tomorrow", expected: now.AddDate(0, 0, 1), ok: true},
+		{in: "2024-02-29", expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), ok: true},
+		{in: "2024-02-30"},
+		{in: "next week"},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			date, ok := parseAvailabilityDate(tc.in, now)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, date)
+		})
+	}
+}
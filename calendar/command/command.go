@@ -28,6 +28,13 @@ type Command struct {
 }
 
 func getNotConnectedText(pluginURL string) string {
+	if config.Provider.Features.CredentialsConnect {
+		return fmt.Sprintf(
+			"It looks like your Mattermost account is not connected to a %s account. Use `/%s connect` to connect your account.",
+			config.Provider.DisplayName,
+			config.Provider.CommandTrigger,
+		)
+	}
 	return fmt.Sprintf(
 		"It looks like your Mattermost account is not connected to a %s account. [Click here to connect your account](%s/oauth2/connect) or use `/%s connect`.",
 		config.Provider.DisplayName,
@@ -40,7 +47,7 @@ type handleFunc func(parameters ...string) (string, bool, error)
 
 func getCommands() []*model.AutocompleteData {
 	cmds := []*model.AutocompleteData{
-		model.NewAutocompleteData("connect", "", fmt.Sprintf("Connect to your %s account", config.Provider.DisplayName)),
+		model.NewAutocompleteData("connect", "[ics <url>]", fmt.Sprintf("Connect to your %s account, or to the read-only ICS feed of your calendar", config.Provider.DisplayName)),
 		model.NewAutocompleteData("disconnect", "", fmt.Sprintf("Disconnect from your %s account", config.Provider.DisplayName)),
 		{ // Summary
 			Trigger:  "summary",
@@ -55,7 +62,18 @@ func getCommands() []*model.AutocompleteData {
 				model.NewAutocompleteData("disable", "", "Disable your daily summary."),
 			},
 		},
-		model.NewAutocompleteData("viewcal", "", "View your events for the upcoming 14 days, including today."),
+		model.NewAutocompleteData("viewcal", "[--as @user]", "View your events for the upcoming 14 days, including today, or those of a calendar shared with you."),
+		{
+			Trigger:  "delegate",
+			HelpText: "Receive and respond to the new invitations of a user whose calendar is shared with you.",
+			SubCommands: []*model.AutocompleteData{
+				model.NewAutocompleteData("add", "@user", "Receive the new invitations of the user."),
+				model.NewAutocompleteData("remove", "@user", "Stop receiving the new invitations of the user."),
+			},
+		},
+		model.NewAutocompleteData("availability", "@user1 @user2 [today|tomorrow|YYYY-MM-DD]", "View when other users are free or busy during the day."),
+		model.NewAutocompleteData("rooms", "[building] [time] [duration]", "Find the meeting rooms free now or later today, and book one."),
+		model.NewAutocompleteData("findtime", "@user1 @user2 [duration] [within N days]", "Find times at which you and other users can meet, and schedule one."),
 	}
 
 	cmds = append(cmds, &model.AutocompleteData{
@@ -127,6 +145,14 @@ func (c *Command) Handle() (string, bool, error) {
 		handler = c.requireConnectedUser(c.dailySummary)
 	case "viewcal":
 		handler = c.requireConnectedUser(c.viewCalendar)
+	case "delegate":
+		handler = c.requireConnectedUser(c.delegate)
+	case "availability":
+		handler = c.requireConnectedUser(c.availability)
+	case "rooms":
+		handler = c.requireConnectedUser(c.rooms)
+	case "findtime":
+		handler = c.requireConnectedUser(c.findTime)
 	case "settings":
 		handler = c.requireConnectedUser(c.settings)
 	case "event":
@@ -140,6 +166,8 @@ func (c *Command) Handle() (string, bool, error) {
 		handler = c.requireConnectedUser(c.requireAdminUser(c.subscribe))
 	case "unsubscribe":
 		handler = c.requireConnectedUser(c.requireAdminUser(c.unsubscribe))
+	case "notifications":
+		handler = c.requireAdminUser(c.notifications)
 	// Aliases
 	case "today":
 		parameters = []string{"today"}
@@ -5,6 +5,7 @@ package command
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 )
@@ -14,12 +15,26 @@ const (
 	ConnectBotSuccessTemplate          = "[Click here to link the bot's %s account.](%s/oauth2/connect_bot)"
 	ConnectAlreadyConnectedTemplate    = "Your Mattermost account is already connected to %s account `%s`. To connect to a different account, first run `/%s disconnect`."
 	ConnectErrorMessage                = "There has been a problem while trying to connect. err="
+	ConnectCredentialsInCommandMessage = "For your security, don't type your password in a command. Run `/%s connect` and enter your credentials in the dialog."
 )
 
-func (c *Command) connect(_ ...string) (string, bool, error) {
+func (c *Command) connect(parameters ...string) (string, bool, error) {
 	ru, err := c.Engine.GetRemoteUser(c.Args.UserId)
 	if err == nil {
-		return fmt.Sprintf(ConnectAlreadyConnectedTemplate, config.Provider.DisplayName, ru.Mail, config.Provider.CommandTrigger), false, nil
+		return fmt.Sprintf(ConnectAlreadyConnectedTemplate, config.Provider.DisplayName, ru.Name(), config.Provider.CommandTrigger), false, nil
+	}
+
+	// Arguments connect with credentials, e.g. `connect ics <url>` for an ICS
+	// feed, whatever the provider.
+	if len(parameters) > 0 {
+		return c.connectWithCredentials(parameters...)
+	}
+	if config.Provider.Features.CredentialsConnect {
+		err = c.Engine.OpenConnectDialog(c.Args.UserId, c.Args.TriggerId)
+		if err != nil {
+			return ConnectErrorMessage + err.Error(), false, nil
+		}
+		return "", false, nil
 	}
 
 	out := ""
@@ -31,3 +46,17 @@ func (c *Command) connect(_ ...string) (string, bool, error) {
 
 	return out, true, nil
 }
+
+func (c *Command) connectWithCredentials(parameters ...string) (string, bool, error) {
+	// Only feed URLs are passed in the command, the credentials of the
+	// provider are entered in the connect dialog.
+	if config.Provider.Features.CredentialsConnect && !strings.EqualFold(parameters[0], "ics") {
+		return fmt.Sprintf(ConnectCredentialsInCommandMessage, config.Provider.CommandTrigger), false, nil
+	}
+
+	err := c.Engine.ConnectWithCredentials(c.Args.UserId, parameters)
+	if err != nil {
+		return ConnectErrorMessage + err.Error(), false, nil
+	}
+	return "", true, nil
+}
@@ -23,6 +23,7 @@ func TestConnect(t *testing.T) {
 	tcs := []struct {
 		name           string
 		command        string
+		credentials    bool
 		setup          func(m engine.Engine)
 		expectedOutput string
 		expectedError  string
@@ -51,6 +52,53 @@ func TestConnect(t *testing.T) {
 			expectedOutput: "",
 			expectedError:  "",
 		},
+		{
+			name:    "ICS feed",
+			command: "connect ics https://example.com/calendar.ics",
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetRemoteUser("user_id").Return(nil, errors.New("remote user not found")).Times(1)
+				mscal.EXPECT().ConnectWithCredentials("user_id", []string{"ics", "https://example.com/calendar.ics"}).Return(nil).Times(1)
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name:        "credentials provider, no arguments",
+			command:     "connect",
+			credentials: true,
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetRemoteUser("user_id").Return(nil, errors.New("remote user not found")).Times(1)
+				mscal.EXPECT().OpenConnectDialog("user_id", "trigger_id").Return(nil).Times(1)
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
+		{
+			name:        "credentials provider, credentials in the command",
+			command:     "connect alice secret",
+			credentials: true,
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetRemoteUser("user_id").Return(nil, errors.New("remote user not found")).Times(1)
+				mscal.EXPECT().ConnectWithCredentials(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedOutput: fmt.Sprintf(ConnectCredentialsInCommandMessage, config.Provider.CommandTrigger),
+			expectedError:  "",
+		},
+		{
+			name:        "credentials provider, ICS feed",
+			command:     "connect ics https://example.com/calendar.ics",
+			credentials: true,
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetRemoteUser("user_id").Return(nil, errors.New("remote user not found")).Times(1)
+				mscal.EXPECT().ConnectWithCredentials("user_id", []string{"ics", "https://example.com/calendar.ics"}).Return(nil).Times(1)
+			},
+			expectedOutput: "",
+			expectedError:  "",
+		},
 	}
 
 	for _, tc := range tcs {
@@ -62,12 +110,19 @@ func TestConnect(t *testing.T) {
 				PluginURL: "http://localhost",
 			}
 
+			if tc.credentials {
+				provider := config.Provider
+				config.Provider.Features.CredentialsConnect = true
+				defer func() { config.Provider = provider }()
+			}
+
 			mscal := mock_engine.NewMockEngine(ctrl)
 			command := Command{
 				Context: &plugin.Context{},
 				Args: &model.CommandArgs{
-					Command: fmt.Sprintf("/%s %s", config.Provider.CommandTrigger, tc.command),
-					UserId:  "user_id",
+					Command:   fmt.Sprintf("/%s %s", config.Provider.CommandTrigger, tc.command),
+					UserId:    "user_id",
+					TriggerId: "trigger_id",
 				},
 				ChannelID: "channel_id",
 				Config:    conf,
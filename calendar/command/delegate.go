@@ -0,0 +1,40 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+)
+
+func getDelegateHelp() string {
+	return "### Delegate commands:\n" +
+		fmt.Sprintf("`/%s delegate add @user` - Receive the new invitations of a user whose calendar is shared with you, and respond on their behalf\n", config.Provider.CommandTrigger) +
+		fmt.Sprintf("`/%s delegate remove @user` - Stop receiving the new invitations of the user", config.Provider.CommandTrigger)
+}
+
+// delegate makes the user a delegate of a principal, whose calendar is
+// shared with or delegated to the user, or stops it.
+func (c *Command) delegate(parameters ...string) (string, bool, error) {
+	if len(parameters) != 2 || !strings.HasPrefix(parameters[1], "@") {
+		return getDelegateHelp(), false, nil
+	}
+	principal := parameters[1]
+
+	switch parameters[0] {
+	case "add":
+		if err := c.Engine.AddDelegate(c.user(), principal); err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("You will receive the new invitations of %s.", principal), false, nil
+	case "remove":
+		if err := c.Engine.RemoveDelegate(c.user(), principal); err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("You will no longer receive the new invitations of %s.", principal), false, nil
+	}
+	return getDelegateHelp(), false, nil
+}
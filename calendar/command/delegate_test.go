@@ -0,0 +1,108 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+)
+
+func TestDelegate(t *testing.T) {
+	testcase := []struct {
+		name       string
+		parameters []string
+		setup      func(engine.Engine)
+		assertions func(t *testing.T, output string, err error)
+	}{
+		{
+			name:       "missing principal",
+			parameters: []string{"add"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, getDelegateHelp(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "unknown subcommand",
+			parameters: []string{"list", "@manager"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, getDelegateHelp(), output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "add",
+			parameters: []string{"add", "@manager"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().AddDelegate(gomock.Any(), "@manager").Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, "You will receive the new invitations of @manager.", output)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "add without access to the calendar",
+			parameters: []string{"add", "@manager"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().AddDelegate(gomock.Any(), "@manager").Return(errors.New("the calendar of @manager is not shared with you")).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, "", output)
+				require.EqualError(t, err, "the calendar of @manager is not shared with you")
+			},
+		},
+		{
+			name:       "remove",
+			parameters: []string{"remove", "@manager"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().RemoveDelegate(gomock.Any(), "@manager").Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, err error) {
+				require.Equal(t, "You will no longer receive the new invitations of @manager.", output)
+				require.Nil(t, err)
+			},
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s delegate", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, redirect, err := command.delegate(tt.parameters...)
+
+			require.False(t, redirect)
+			tt.assertions(t, out, err)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+const (
+	defaultMeetingDuration = 30 * time.Minute
+	maxMeetingDuration     = 8 * time.Hour
+	defaultFindTimeWindow  = 7 * 24 * time.Hour
+	maxFindTimeWindowDays  = 30
+)
+
+func getFindTimeUsage() string {
+	return fmt.Sprintf("Please enter the users to meet, and an optional duration and time window, for example:\n`/%s findtime @alice @bob 45m within 3 days`", config.Provider.CommandTrigger)
+}
+
+// findTime sends the user the times at which they can meet the given users,
+// with a button to schedule the meeting.
+func (c *Command) findTime(parameters ...string) (string, bool, error) {
+	usernames := []string{}
+	duration := defaultMeetingDuration
+	window := defaultFindTimeWindow
+	for i := 0; i < len(parameters); i++ {
+		p := parameters[i]
+		switch {
+		case strings.HasPrefix(p, "@") && len(p) > 1:
+			usernames = append(usernames, p)
+		case strings.EqualFold(p, "within"):
+			if i+2 >= len(parameters) {
+				return getFindTimeUsage(), false, nil
+			}
+			days, err := strconv.Atoi(parameters[i+1])
+			unit := strings.ToLower(parameters[i+2])
+			if err != nil || days <= 0 || days > maxFindTimeWindowDays || (unit != "day" && unit != "days") {
+				return getFindTimeUsage(), false, nil
+			}
+			window = time.Duration(days) * 24 * time.Hour
+			i += 2
+		default:
+			d, err := time.ParseDuration(p)
+			if err != nil || d <= 0 || d > maxMeetingDuration {
+				return getFindTimeUsage(), false, nil
+			}
+			duration = d
+		}
+	}
+	if len(usernames) == 0 {
+		return getFindTimeUsage(), false, nil
+	}
+
+	timezone, err := c.Engine.GetTimezone(c.user())
+	if err != nil {
+		if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
+			return store.ErrorUserInactive, false, nil
+		}
+
+		return "Error: No timezone found", false, err
+	}
+
+	start := time.Now().Truncate(time.Minute)
+	suggestions, err := c.Engine.SuggestMeetingTimes(c.user(), usernames, duration, start, start.Add(window))
+	if err != nil {
+		return "", false, err
+	}
+	if len(suggestions.Suggestions) == 0 {
+		out := "No times were found at which everyone can meet."
+		if suggestions.EmptySuggestionReason != "" {
+			out += fmt.Sprintf(" Reason: %s.", suggestions.EmptySuggestionReason)
+		}
+		return out, false, nil
+	}
+
+	if err := c.Engine.OfferMeetingTimes(c.user(), suggestions, timezone); err != nil {
+		return "", false, err
+	}
+	return "", true, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestFindTime(t *testing.T) {
+	suggestions := &engine.MeetingSuggestions{
+		Suggestions: []*remote.MeetingTimeSuggestion{{Order: 1}},
+	}
+
+	testcase := []struct {
+		name       string
+		parameters []string
+		setup      func(engine.Engine)
+		assertions func(t *testing.T, output string, redirect bool, err error)
+	}{
+		{
+			name:       "no users",
+			parameters: []string{"45m"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, getFindTimeUsage(), output)
+				require.False(t, redirect)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "invalid window",
+			parameters: []string{"@alice", "within", "3", "weeks"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, getFindTimeUsage(), output)
+				require.False(t, redirect)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "users, duration and window",
+			parameters: []string{"@alice", "@bob", "45m", "within", "3", "days"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				mscal.EXPECT().SuggestMeetingTimes(gomock.Any(), []string{"@alice", "@bob"}, 45*time.Minute, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *engine.User, _ []string, _ time.Duration, start, end time.Time) (*engine.MeetingSuggestions, error) {
+					require.WithinDuration(t, time.Now(), start, time.Minute)
+					require.Equal(t, 72*time.Hour, end.Sub(start))
+					return suggestions, nil
+				}).Times(1)
+				mscal.EXPECT().OfferMeetingTimes(gomock.Any(), suggestions, "UTC").Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, "", output)
+				require.True(t, redirect)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "no suggestions",
+			parameters: []string{"@alice"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				mscal.EXPECT().SuggestMeetingTimes(gomock.Any(), []string{"@alice"}, defaultMeetingDuration, gomock.Any(), gomock.Any()).Return(&engine.MeetingSuggestions{EmptySuggestionReason: "AttendeesUnavailable"}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, "No times were found at which everyone can meet. Reason: AttendeesUnavailable.", output)
+				require.False(t, redirect)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "unknown user",
+			parameters: []string{"@nobody"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				mscal.EXPECT().SuggestMeetingTimes(gomock.Any(), []string{"@nobody"}, defaultMeetingDuration, gomock.Any(), gomock.Any()).Return(nil, errors.New("user @nobody not found")).Times(1)
+			},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, "", output)
+				require.EqualError(t, err, "user @nobody not found")
+			},
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s findtime", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, redirect, err := command.findTime(tt.parameters...)
+
+			tt.assertions(t, out, redirect, err)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+)
+
+func (c *Command) notifications(parameters ...string) (string, bool, error) {
+	if len(parameters) == 0 {
+		return c.failedNotifications()
+	}
+
+	switch parameters[0] {
+	case "failed":
+		return c.failedNotifications()
+	case "replay":
+		return c.replayNotifications(parameters[1:]...)
+	}
+	return fmt.Sprintf("Usage: `/%s notifications [failed|replay [id...]]`", config.Provider.CommandTrigger), false, nil
+}
+
+func (c *Command) failedNotifications() (string, bool, error) {
+	items, err := c.Engine.ListFailedNotifications()
+	if err != nil {
+		return "", false, err
+	}
+	if len(items) == 0 {
+		return "There are no failed notifications.", false, nil
+	}
+
+	resp := "| ID | Subscription | Attempts | Failed at | Error |\n|---|---|---|---|---|\n"
+	for _, item := range items {
+		resp += fmt.Sprintf("| %s | %s | %d | %s | %s |\n",
+			item.ID,
+			item.Notification.SubscriptionID,
+			item.Attempts,
+			item.FailedAt.UTC().Format(time.RFC3339),
+			strings.ReplaceAll(item.LastError, "|", "\\|"),
+		)
+	}
+	resp += fmt.Sprintf("\nUse `/%s notifications replay [id...]` to process them again.", config.Provider.CommandTrigger)
+	return resp, false, nil
+}
+
+func (c *Command) replayNotifications(ids ...string) (string, bool, error) {
+	replayed, err := c.Engine.ReplayFailedNotifications(ids...)
+	if err != nil {
+		return "", false, err
+	}
+	return fmt.Sprintf("%d failed notification(s) queued again.", replayed), false, nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+)
+
+func TestNotifications(t *testing.T) {
+	failed := &store.QueuedNotification{
+		ID:           "notification_id",
+		Notification: &remote.Notification{SubscriptionID: "subscription_id"},
+		Attempts:     8,
+		FailedAt:     time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+		LastError:    "some error",
+	}
+
+	tcs := []struct {
+		name           string
+		command        string
+		setup          func(*mock_engine.MockEngine)
+		expectedOutput string
+	}{
+		{
+			name:    "not an admin",
+			command: "notifications",
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().IsAuthorizedAdmin("user_id").Return(false, nil)
+			},
+			expectedOutput: "Not authorized",
+		},
+		{
+			name:    "no failed notifications",
+			command: "notifications failed",
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().IsAuthorizedAdmin("user_id").Return(true, nil)
+				m.EXPECT().ListFailedNotifications().Return([]*store.QueuedNotification{}, nil)
+			},
+			expectedOutput: "There are no failed notifications.",
+		},
+		{
+			name:    "failed notifications",
+			command: "notifications",
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().IsAuthorizedAdmin("user_id").Return(true, nil)
+				m.EXPECT().ListFailedNotifications().Return([]*store.QueuedNotification{failed}, nil)
+			},
+			expectedOutput: "| ID | Subscription | Attempts | Failed at | Error |\n|---|---|---|---|---|\n" +
+				"| notification_id | subscription_id | 8 | 2026-10-01T09:00:00Z | some error |\n" +
+				fmt.Sprintf("\nUse `/%s notifications replay [id...]` to process them again.", config.Provider.CommandTrigger),
+		},
+		{
+			name:    "replay all",
+			command: "notifications replay",
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().IsAuthorizedAdmin("user_id").Return(true, nil)
+				m.EXPECT().ReplayFailedNotifications().Return(3, nil)
+			},
+			expectedOutput: "3 failed notification(s) queued again.",
+		},
+		{
+			name:    "replay some",
+			command: "notifications replay id1 id2",
+			setup: func(m *mock_engine.MockEngine) {
+				m.EXPECT().IsAuthorizedAdmin("user_id").Return(true, nil)
+				m.EXPECT().ReplayFailedNotifications("id1", "id2").Return(2, nil)
+			},
+			expectedOutput: "2 failed notification(s) queued again.",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s %s", config.Provider.CommandTrigger, tc.command),
+					UserId:  "user_id",
+				},
+				ChannelID: "channel_id",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+			tc.setup(mscal)
+
+			out, _, err := command.Handle()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedOutput, out)
+		})
+	}
+}
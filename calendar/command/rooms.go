@@ -0,0 +1,91 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const (
+	defaultRoomBookingDuration = 30 * time.Minute
+	maxRoomBookingDuration     = 8 * time.Hour
+)
+
+var roomTimeFormats = []string{"15:04", "3:04PM", "3PM"}
+
+func getRoomsUsage() string {
+	return fmt.Sprintf("Please enter an optional building, start time and duration, for example:\n`/%s rooms HQ 14:30 1h`", config.Provider.CommandTrigger)
+}
+
+// rooms sends the user the rooms free from the given time today, now by
+// default, with a button to book them.
+func (c *Command) rooms(parameters ...string) (string, bool, error) {
+	duration := defaultRoomBookingDuration
+	if n := len(parameters); n > 0 {
+		if d, err := time.ParseDuration(parameters[n-1]); err == nil {
+			if d <= 0 || d > maxRoomBookingDuration {
+				return getRoomsUsage(), false, nil
+			}
+			duration = d
+			parameters = parameters[:n-1]
+		}
+	}
+
+	timezone, err := c.Engine.GetTimezone(c.user())
+	if err != nil {
+		if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
+			return store.ErrorUserInactive, false, nil
+		}
+
+		return "Error: No timezone found", false, err
+	}
+	loc, err := time.LoadLocation(tz.Go(timezone))
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc).Truncate(time.Minute)
+
+	start := now
+	if n := len(parameters); n > 0 {
+		if t, ok := parseRoomTime(parameters[n-1], now); ok {
+			if t.Before(now) {
+				return getRoomsUsage(), false, nil
+			}
+			start = t
+			parameters = parameters[:n-1]
+		}
+	}
+	end := start.Add(duration)
+	building := strings.Join(parameters, " ")
+
+	rooms, err := c.Engine.FindFreeRooms(c.user(), building, start, end)
+	if err != nil {
+		return "", false, err
+	}
+	if len(rooms) == 0 {
+		return fmt.Sprintf("No rooms are free from %s to %s.", start.Format(time.Kitchen), end.Format(time.Kitchen)), false, nil
+	}
+
+	if err := c.Engine.OfferRooms(c.user(), rooms, start, end); err != nil {
+		return "", false, err
+	}
+	return "", true, nil
+}
+
+// parseRoomTime reads a time of the day of now.
+func parseRoomTime(s string, now time.Time) (time.Time, bool) {
+	for _, format := range roomTimeFormats {
+		t, err := time.Parse(format, strings.ToUpper(s))
+		if err == nil {
+			return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location()), true
+		}
+	}
+	return time.Time{}, false
+}
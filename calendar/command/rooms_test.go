@@ -0,0 +1,139 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package command
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/mattermost/mattermost/server/public/plugin"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_engine"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestRooms(t *testing.T) {
+	rooms := []*remote.Room{{DisplayName: "Room 1", EmailAddress: "room1@example.com"}}
+
+	testcase := []struct {
+		name       string
+		parameters []string
+		setup      func(engine.Engine)
+		assertions func(t *testing.T, output string, redirect bool, err error)
+	}{
+		{
+			name:       "invalid duration",
+			parameters: []string{"HQ", "10h"},
+			setup:      func(_ engine.Engine) {},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, getRoomsUsage(), output)
+				require.False(t, redirect)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "free rooms now by default",
+			parameters: []string{},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				mscal.EXPECT().FindFreeRooms(gomock.Any(), "", gomock.Any(), gomock.Any()).DoAndReturn(func(_ *engine.User, _ string, start, end time.Time) ([]*remote.Room, error) {
+					require.WithinDuration(t, time.Now(), start, time.Minute)
+					require.Equal(t, defaultRoomBookingDuration, end.Sub(start))
+					return rooms, nil
+				}).Times(1)
+				mscal.EXPECT().OfferRooms(gomock.Any(), rooms, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, "", output)
+				require.True(t, redirect)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "building, time and duration",
+			parameters: []string{"North", "Tower", "11:59pm", "1m"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				mscal.EXPECT().FindFreeRooms(gomock.Any(), "North Tower", gomock.Any(), gomock.Any()).DoAndReturn(func(_ *engine.User, _ string, start, end time.Time) ([]*remote.Room, error) {
+					require.Equal(t, 23, start.Hour())
+					require.Equal(t, 59, start.Minute())
+					require.Equal(t, time.Minute, end.Sub(start))
+					return []*remote.Room{}, nil
+				}).Times(1)
+			},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, "No rooms are free from 11:59PM to 12:00AM.", output)
+				require.False(t, redirect)
+				require.Nil(t, err)
+			},
+		},
+		{
+			name:       "error finding rooms",
+			parameters: []string{"HQ"},
+			setup: func(m engine.Engine) {
+				mscal := m.(*mock_engine.MockEngine)
+				mscal.EXPECT().GetTimezone(gomock.Any()).Return("UTC", nil).Times(1)
+				mscal.EXPECT().FindFreeRooms(gomock.Any(), "HQ", gomock.Any(), gomock.Any()).Return(nil, errors.New("rooms are not supported by Google Calendar")).Times(1)
+			},
+			assertions: func(t *testing.T, output string, redirect bool, err error) {
+				require.Equal(t, "", output)
+				require.EqualError(t, err, "rooms are not supported by Google Calendar")
+			},
+		},
+	}
+	for _, tt := range testcase {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mscal := mock_engine.NewMockEngine(ctrl)
+			command := Command{
+				Context: &plugin.Context{},
+				Args: &model.CommandArgs{
+					Command: fmt.Sprintf("/%s rooms", config.Provider.CommandTrigger),
+					UserId:  "mockUserID",
+				},
+				ChannelID: "mockChannelID",
+				Config:    &config.Config{PluginURL: "http://localhost"},
+				Engine:    mscal,
+			}
+
+			tt.setup(mscal)
+
+			out, redirect, err := command.rooms(tt.parameters...)
+
+			tt.assertions(t, out, redirect, err)
+		})
+	}
+}
+
+func TestParseRoomTime(t *testing.T) {
+	now := time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		in       string
+		expected time.Time
+		ok       bool
+	}{
+		{in: "14:30", expected: time.Date(2024, 1, 8, 14, 30, 0, 0, time.UTC), ok: true},
+		{in: "2:30pm", expected: time.Date(2024, 1, 8, 14, 30, 0, 0, time.UTC), ok: true},
+		{in: "3PM", expected: time.Date(2024, 1, 8, 15, 0, 0, 0, time.UTC), ok: true},
+		{in: "HQ"},
+		{in: "25:00"},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			start, ok := parseRoomTime(tc.in, now)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, start)
+		})
+	}
+}
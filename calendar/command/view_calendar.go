@@ -4,14 +4,31 @@
 package command
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 )
 
-func (c *Command) viewCalendar(_ ...string) (string, bool, error) {
+func getViewCalendarUsage() string {
+	return fmt.Sprintf("Please use `/%s viewcal`, or `/%s viewcal --as @user` to view a calendar shared with you.", config.Provider.CommandTrigger, config.Provider.CommandTrigger)
+}
+
+// viewCalendar renders the events of the user, or of the principal given
+// with --as, whose calendar is shared with or delegated to the user.
+func (c *Command) viewCalendar(parameters ...string) (string, bool, error) {
+	principal := ""
+	switch {
+	case len(parameters) == 0:
+	case len(parameters) == 2 && parameters[0] == "--as" && strings.HasPrefix(parameters[1], "@"):
+		principal = parameters[1]
+	default:
+		return getViewCalendarUsage(), false, nil
+	}
+
 	tz, err := c.Engine.GetTimezone(c.user())
 	if err != nil {
 		if strings.Contains(err.Error(), store.ErrorRefreshTokenNotSet) || strings.Contains(err.Error(), store.ErrorUserInactive) {
@@ -22,7 +39,17 @@ func (c *Command) viewCalendar(_ ...string) (string, bool, error) {
 	}
 
 	startOfCurrentDay := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 0, 0, 0, 0, time.Now().Location())
-	events, err := c.Engine.ViewCalendar(c.user(), startOfCurrentDay, time.Now().Add(14*24*time.Hour))
+	end := time.Now().Add(14 * 24 * time.Hour)
+	if principal != "" {
+		events, err := c.Engine.ViewCalendarAs(c.user(), principal, startOfCurrentDay, end)
+		if err != nil {
+			return "", false, err
+		}
+		out, err := views.RenderCalendarView(events, tz)
+		return fmt.Sprintf("Calendar of %s. Use `/%s delegate add %s` to also receive their new invitations.\n%s", principal, config.Provider.CommandTrigger, principal, out), false, err
+	}
+
+	events, err := c.Engine.ViewCalendar(c.user(), startOfCurrentDay, end)
 	if err != nil {
 		return "", false, err
 	}
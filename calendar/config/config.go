@@ -3,7 +3,10 @@
 
 package config
 
-import "github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+import (
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
+)
 
 var Provider ProviderConfig
 
@@ -14,10 +17,16 @@ type StoredConfig struct {
 	OAuth2ClientID     string
 	OAuth2ClientSecret string
 	OAuth2ForceConsent bool
+	CalDAVServerURL    string
 	bot.Config
 	EnableStatusSync     bool
 	EnableDailySummary   bool
 	EnableExperimentalUI bool
+	EnableEventMirror    bool
+	EnableRooms          bool
+
+	EnableRichNotifications bool
+	NotificationWorkers     int
 
 	EncryptionKey string
 }
@@ -35,6 +44,15 @@ type ProviderFeatures struct {
 	// regardless of the OAuth2ForceConsent admin setting. Use for providers that
 	// require user consent to issue a refresh token (e.g. Google OAuth2).
 	ForceOAuth2Consent bool
+
+	// CredentialsConnect makes users connect by entering their credentials in
+	// a dialog opened by `connect`, instead of the OAuth2 flow. The remote must implement
+	// remote.CredentialsConnector.
+	CredentialsConnect bool
+
+	// OnlineMeetings lets users add an online meeting to the events they
+	// create, with the join URL returned by the remote.
+	OnlineMeetings bool
 }
 
 // ProviderConfig represents the specific configuration that changes when building for different
@@ -64,8 +82,16 @@ type Config struct {
 	PluginVersion          string
 	StoredConfig
 	Provider ProviderConfig
+
+	// NotificationCertificate is set when rich notifications are enabled.
+	// The remote encrypts the resource data of notifications with it.
+	NotificationCertificate *certificate.Certificate
 }
 
 func (c *Config) GetNotificationURL() string {
 	return c.PluginURL + FullPathEventNotification
 }
+
+func (c *Config) GetLifecycleNotificationURL() string {
+	return c.PluginURL + FullPathLifecycleNotification
+}
@@ -13,12 +13,17 @@ const (
 	PathSetAutoRespondMessage = "/set-auto-respond-message"
 	PathPostAction            = "/action"
 	PathRespond               = "/respond"
+	PathRespondWithComment    = "/respond-with-comment"
+	PathConnect               = "/connect"
 	PathAccept                = "/accept"
 	PathDecline               = "/decline"
 	PathTentative             = "/tentative"
 	PathConfirmStatusChange   = "/confirm"
+	PathBookRoom              = "/book-room"
+	PathScheduleMeeting       = "/schedule-meeting"
 	PathNotification          = "/notification/v1"
 	PathEvent                 = "/event"
+	PathLifecycle             = "/lifecycle"
 	PathVerifyDomain          = "/verify"
 
 	PathAutocomplete = "/autocomplete"
@@ -29,11 +34,21 @@ const (
 	PathEvents        = "/events"
 	PathCreate        = "/create"
 	PathView          = "/view"
+	PathEventID       = "/{" + EventIDVar + "}"
 	PathProvider      = "/provider"
 	PathConnectedUser = "/me"
 
-	FullPathEventNotification = PathNotification + PathEvent
-	FullPathOAuth2Redirect    = PathOAuth2 + PathComplete
+	FullPathEventNotification     = PathNotification + PathEvent
+	FullPathLifecycleNotification = PathNotification + PathLifecycle
+	FullPathOAuth2Redirect        = PathOAuth2 + PathComplete
 
-	EventIDKey = "EventID"
+	EventIDKey     = "EventID"
+	PrincipalIDKey = "PrincipalID"
+	RoomKey        = "Room"
+	RoomNameKey    = "RoomName"
+	StartKey       = "Start"
+	EndKey         = "End"
+	SubjectKey     = "Subject"
+	AttendeesKey   = "Attendees"
+	EventIDVar     = "eventID"
 )
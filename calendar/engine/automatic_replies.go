@@ -0,0 +1,94 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const (
+	outOfOfficeEmoji = "palm_tree"
+	outOfOfficeText  = "Out of office"
+)
+
+var (
+	htmlBreakRegexp = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/tr)\b[^>]*>`)
+	htmlTagRegexp   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// setOutOfOfficeCustomStatus sets the custom status of the user while their
+// automatic replies are sent. The status is removed as any other custom status
+// set by the plugin once they are not.
+func (m *mscalendar) setOutOfOfficeCustomStatus(user *store.User, settings *remote.MailboxSettings) (string, bool, error) {
+	currentUser, err := m.PluginAPI.GetMattermostUser(user.MattermostUserID)
+	if err != nil {
+		return "", false, err
+	}
+
+	currentCustomStatus := currentUser.GetCustomStatus()
+	if currentCustomStatus != nil && !user.IsCustomStatusSet {
+		return "User already has a custom status set, ignoring custom status change", false, nil
+	}
+
+	customStatus := outOfOfficeCustomStatus(settings, user.Settings.ShowAutomaticReply)
+	if currentCustomStatus != nil && currentCustomStatus.Emoji == customStatus.Emoji && currentCustomStatus.Text == customStatus.Text {
+		return "User already has the out of office custom status", false, nil
+	}
+
+	if appErr := m.PluginAPI.UpdateMattermostUserCustomStatus(user.MattermostUserID, customStatus); appErr != nil {
+		return "", false, appErr
+	}
+	if err := m.Store.StoreUserCustomStatusUpdates(user.MattermostUserID, true); err != nil {
+		return "", true, err
+	}
+	return "", true, nil
+}
+
+// outOfOfficeCustomStatus returns the custom status showing the end of the
+// automatic replies, and the internal reply when the user wants to share it.
+// The external reply is never shown.
+func outOfOfficeCustomStatus(settings *remote.MailboxSettings, showReply bool) *model.CustomStatus {
+	replies := settings.AutomaticRepliesSetting
+	customStatus := &model.CustomStatus{
+		Emoji: outOfOfficeEmoji,
+		Text:  outOfOfficeText,
+	}
+
+	if replies.Status == remote.AutomaticRepliesScheduled && replies.ScheduledEndDateTime != nil {
+		loc, err := time.LoadLocation(tz.Go(settings.TimeZone))
+		if err != nil {
+			loc = time.UTC
+		}
+		end := replies.ScheduledEndDateTime.Time()
+		customStatus.Text += " until " + end.In(loc).Format("Jan 2")
+		customStatus.ExpiresAt = end
+		customStatus.Duration = "date_and_time"
+	}
+
+	if showReply {
+		if reply := plainText(replies.InternalReplyMessage); reply != "" {
+			customStatus.Text += ": " + reply
+		}
+	}
+	if runes := []rune(customStatus.Text); len(runes) > model.CustomStatusTextMaxRunes {
+		customStatus.Text = string(runes[:model.CustomStatusTextMaxRunes-1]) + "…"
+	}
+	return customStatus
+}
+
+// plainText returns the text of an HTML message on a single line.
+func plainText(message string) string {
+	text := htmlBreakRegexp.ReplaceAllString(message, " ")
+	text = html.UnescapeString(htmlTagRegexp.ReplaceAllString(text, ""))
+	return strings.Join(strings.Fields(text), " ")
+}
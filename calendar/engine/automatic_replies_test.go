@@ -0,0 +1,134 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+)
+
+func TestOutOfOfficeCustomStatus(t *testing.T) {
+	end := time.Date(2024, 3, 8, 2, 0, 0, 0, time.UTC)
+	scheduled := remote.AutomaticRepliesSetting{
+		Status:               remote.AutomaticRepliesScheduled,
+		ScheduledEndDateTime: remote.NewDateTime(end, "UTC"),
+		InternalReplyMessage: "<html><body><p>I&#39;m on leave.<br/>Ask  <b>@bob</b>.</p></body></html>",
+	}
+
+	for name, tc := range map[string]struct {
+		settings  *remote.MailboxSettings
+		showReply bool
+		expected  *model.CustomStatus
+	}{
+		"always enabled": {
+			settings: &remote.MailboxSettings{AutomaticRepliesSetting: remote.AutomaticRepliesSetting{Status: remote.AutomaticRepliesAlwaysEnabled}},
+			expected: &model.CustomStatus{Emoji: "palm_tree", Text: "Out of office"},
+		},
+		"scheduled, end date in the mailbox timezone": {
+			settings: &remote.MailboxSettings{TimeZone: "Pacific Standard Time", AutomaticRepliesSetting: scheduled},
+			expected: &model.CustomStatus{Emoji: "palm_tree", Text: "Out of office until Mar 7", ExpiresAt: end, Duration: "date_and_time"},
+		},
+		"scheduled, with the internal reply": {
+			settings:  &remote.MailboxSettings{TimeZone: "UTC", AutomaticRepliesSetting: scheduled},
+			showReply: true,
+			expected:  &model.CustomStatus{Emoji: "palm_tree", Text: "Out of office until Mar 8: I'm on leave. Ask @bob.", ExpiresAt: end, Duration: "date_and_time"},
+		},
+		"long internal reply": {
+			settings: &remote.MailboxSettings{AutomaticRepliesSetting: remote.AutomaticRepliesSetting{
+				Status:               remote.AutomaticRepliesAlwaysEnabled,
+				InternalReplyMessage: strings.Repeat("a", 200),
+			}},
+			showReply: true,
+			expected:  &model.CustomStatus{Emoji: "palm_tree", Text: "Out of office: " + strings.Repeat("a", 84) + "…"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, outOfOfficeCustomStatus(tc.settings, tc.showReply))
+		})
+	}
+}
+
+func TestSyncOutOfOfficeCustomStatus(t *testing.T) {
+	outOfOffice := &remote.MailboxSettings{AutomaticRepliesSetting: remote.AutomaticRepliesSetting{Status: remote.AutomaticRepliesAlwaysEnabled}}
+	busyEvent := &remote.Event{
+		ICalUID:   "event_id",
+		Start:     remote.NewDateTime(time.Now().UTC(), "UTC"),
+		End:       remote.NewDateTime(time.Now().Add(time.Hour).UTC(), "UTC"),
+		ShowAs:    "busy",
+		Attendees: []*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "mock-attendee@example.com"}}},
+	}
+
+	for name, tc := range map[string]struct {
+		isCustomStatusSet   bool
+		currentCustomStatus *model.CustomStatus
+		shouldUpdate        bool
+	}{
+		"Out of office during a meeting. Set the out of office custom status.": {
+			shouldUpdate: true,
+		},
+		"Custom status set by the user. No custom status change.": {
+			currentCustomStatus: &model.CustomStatus{Emoji: "coffee", Text: "Break"},
+		},
+		"Out of office custom status already set. No custom status change.": {
+			isCustomStatusSet:   true,
+			currentCustomStatus: &model.CustomStatus{Emoji: "palm_tree", Text: "Out of office"},
+		},
+		"Meeting custom status set by the plugin. Replace it.": {
+			isCustomStatusSet:   true,
+			currentCustomStatus: &model.CustomStatus{Emoji: "calendar", Text: "In a meeting"},
+			shouldUpdate:        true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			env, client := makeStatusSyncTestEnv(ctrl)
+			deps := env.Dependencies
+
+			c, r, papi, s := client.(*mock_remote.MockClient), env.Remote.(*mock_remote.MockRemote), deps.PluginAPI.(*mock_plugin_api.MockPluginAPI), deps.Store.(*mock_store.MockStore)
+			s.EXPECT().LoadUserIndex().Return(store.UserIndex{{MattermostUserID: "user_mm_id", RemoteID: "user_remote_id"}}, nil)
+			r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
+			s.EXPECT().LoadUser("user_mm_id").Return(&store.User{
+				MattermostUserID:         "user_mm_id",
+				Remote:                   &remote.User{ID: "user_remote_id", Mail: "user_email@example.com"},
+				Settings:                 store.Settings{SetCustomStatus: true},
+				IsCustomStatusSet:        tc.isCustomStatusSet,
+				MailboxSettings:          outOfOffice,
+				MailboxSettingsUpdatedAt: time.Now().Unix(),
+			}, nil)
+			c.EXPECT().DoBatchViewCalendarRequests(gomock.Any()).Return([]*remote.ViewCalendarResponse{
+				{Events: []*remote.Event{busyEvent}, RemoteUserID: "user_remote_id"},
+			}, nil)
+			papi.EXPECT().GetMattermostUserStatusesByIds([]string{"user_mm_id"}).Return([]*model.Status{{Status: model.StatusOnline, UserId: "user_mm_id"}}, nil)
+
+			user := &model.User{Id: "user_mm_id"}
+			if tc.currentCustomStatus != nil {
+				require.NoError(t, user.SetCustomStatus(tc.currentCustomStatus))
+			}
+			papi.EXPECT().GetMattermostUser("user_mm_id").Return(user, nil)
+
+			if tc.shouldUpdate {
+				papi.EXPECT().UpdateMattermostUserCustomStatus("user_mm_id", &model.CustomStatus{Emoji: "palm_tree", Text: "Out of office"}).Return(nil)
+				s.EXPECT().StoreUserCustomStatusUpdates("user_mm_id", true).Return(nil)
+			} else {
+				papi.EXPECT().UpdateMattermostUserCustomStatus(gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			_, _, err := New(env, "").SyncAll()
+			require.NoError(t, err)
+		})
+	}
+}
@@ -6,6 +6,7 @@ package engine
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/mattermost/mattermost/server/public/model"
@@ -37,14 +38,111 @@ type StatusSyncJobSummary struct {
 	NumberOfUsersFailedStatusChanged int
 	NumberOfUsersStatusChanged       int
 	NumberOfUsersProcessed           int
+	// NumberOfThrottledRequests counts the requests throttled by the remote
+	// during the sync, including the ones retried successfully.
+	NumberOfThrottledRequests int64
 }
 
 type Availability interface {
+	GetAvailability(user *User, mattermostUsernames []string, start, end time.Time, availabilityViewInterval int) ([]*UserAvailability, error)
 	GetCalendarViews(users []*store.User) ([]*remote.ViewCalendarResponse, error)
 	Sync(mattermostUserID string) (string, *StatusSyncJobSummary, error)
 	SyncAll() (string, *StatusSyncJobSummary, error)
 }
 
+// UserAvailability is the free/busy schedule of a Mattermost user, or the
+// reason it couldn't be read.
+type UserAvailability struct {
+	MattermostUsername string
+	Schedule           *remote.ScheduleInformation
+	Error              string
+}
+
+// GetAvailability reads the free/busy schedule of the given users between
+// start and end. Schedules are read with the superuser client when the remote
+// supports it, and with each user's own client otherwise.
+func (m *mscalendar) GetAvailability(user *User, mattermostUsernames []string, start, end time.Time, availabilityViewInterval int) ([]*UserAvailability, error) {
+	err := m.Filter(withUserExpanded(user))
+	if err != nil {
+		return nil, err
+	}
+
+	superuser, err := m.FilterCopy(withActingUser(user.MattermostUserID), withSuperuserClient)
+	if err != nil && !errors.Is(err, remote.ErrSuperUserClientNotSupported) {
+		return nil, errors.Wrap(err, "not able to filter the super user client")
+	}
+	fetchIndividually := superuser == nil
+
+	startTime, endTime := remote.NewDateTime(start.UTC(), "UTC"), remote.NewDateTime(end.UTC(), "UTC")
+	result := []*UserAvailability{}
+	byMail := map[string]*UserAvailability{}
+	requests := []*remote.ScheduleUserInfo{}
+	for _, username := range mattermostUsernames {
+		res := &UserAvailability{MattermostUsername: strings.TrimPrefix(username, "@")}
+		result = append(result, res)
+
+		mattermostUser, err := m.PluginAPI.GetMattermostUserByUsername(res.MattermostUsername)
+		if err != nil {
+			res.Error = "User not found."
+			continue
+		}
+		storedUser, err := m.Store.LoadUser(mattermostUser.Id)
+		if err != nil {
+			res.Error = fmt.Sprintf("Not connected to %s.", m.Provider.DisplayName)
+			continue
+		}
+
+		req := &remote.ScheduleUserInfo{
+			RemoteUserID: storedUser.Remote.ID,
+			Mail:         storedUser.Remote.Mail,
+		}
+		// Batched schedules are matched by mail
+		if req.Mail != "" && !fetchIndividually && !m.requiresUserClient(storedUser) {
+			byMail[strings.ToLower(req.Mail)] = res
+			requests = append(requests, req)
+			continue
+		}
+
+		engine, err := m.FilterCopy(withActingUser(storedUser.MattermostUserID), withClient)
+		if err != nil {
+			res.Error = "Unable to read the calendar."
+			m.Logger.With(bot.LogContext{"mm_user_id": storedUser.MattermostUserID, "err": err}).Warnf("could not make client to get availability")
+			continue
+		}
+		schedules, err := engine.client.GetSchedule([]*remote.ScheduleUserInfo{req}, startTime, endTime, availabilityViewInterval)
+		if err != nil || len(schedules) == 0 {
+			res.Error = "Unable to read the calendar."
+			m.Logger.With(bot.LogContext{"mm_user_id": storedUser.MattermostUserID, "err": err}).Warnf("could not get availability")
+			continue
+		}
+		res.Schedule = schedules[0]
+	}
+
+	if len(requests) > 0 {
+		schedules, err := superuser.client.GetSchedule(requests, startTime, endTime, availabilityViewInterval)
+		if err != nil {
+			return nil, errors.Wrap(err, "not able to get the schedules")
+		}
+		for _, schedule := range schedules {
+			if res, ok := byMail[strings.ToLower(schedule.ScheduleID)]; ok {
+				res.Schedule = schedule
+			}
+		}
+	}
+
+	for _, res := range result {
+		switch {
+		case res.Error != "":
+		case res.Schedule == nil:
+			res.Error = "Unable to read the calendar."
+		case res.Schedule.Error != nil:
+			res.Error = res.Schedule.Error.Message
+			res.Schedule = nil
+		}
+	}
+	return result, nil
+}
+
 func (m *mscalendar) Sync(mattermostUserID string) (string, *StatusSyncJobSummary, error) {
 	user, err := m.Store.LoadUserFromIndex(mattermostUserID)
 	if err != nil {
@@ -85,13 +183,15 @@ func (m *mscalendar) SyncAll() (string, *StatusSyncJobSummary, error) {
 
 // retrieveUsersToSync retrieves the users and their calendar data to sync up and send notifications
 // The parameter fetchIndividually determines if the calendar data should be fetched while we loop the
-// users (using individual credentials) or on a batch after the loop.
+// users (using individual credentials) or on a batch after the loop. Users whose calendar can only be
+// read with their own client are always fetched individually.
 func (m *mscalendar) retrieveUsersToSync(userIndex store.UserIndex, syncJobSummary *StatusSyncJobSummary, fetchIndividually bool) ([]*store.User, []*remote.ViewCalendarResponse, error) {
 	start := time.Now().UTC()
 	end := time.Now().UTC().Add(calendarViewTimeWindowSize)
 
 	numberOfLogs := 0
 	users := []*store.User{}
+	batchUsers := []*store.User{}
 	calendarViews := []*remote.ViewCalendarResponse{}
 	for _, u := range userIndex {
 		// TODO fetch users from kvstore in batches, and process in batches instead of all at once
@@ -110,11 +210,11 @@ func (m *mscalendar) retrieveUsersToSync(userIndex store.UserIndex, syncJobSumma
 		}
 
 		// If user does not have the proper features enabled, just go to the next one
-		if !(user.IsConfiguredForStatusUpdates() || user.IsConfiguredForCustomStatusUpdates() || user.Settings.ReceiveReminders) {
+		if !(user.IsConfiguredForStatusUpdates() || user.IsConfiguredForCustomStatusUpdates() || user.Settings.ReceiveReminders || user.IsConfiguredForWorkingHours()) {
 			continue
 		}
 
-		if fetchIndividually {
+		if fetchIndividually || m.requiresUserClient(user) {
 			engine, err := m.FilterCopy(withActingUser(user.MattermostUserID))
 			if err != nil {
 				m.Logger.Warnf("Not able to enable active user %s from user index. err=%v", user.MattermostUserID, err)
@@ -133,6 +233,8 @@ func (m *mscalendar) retrieveUsersToSync(userIndex store.UserIndex, syncJobSumma
 			}
 
 			calendarViews = append(calendarViews, calendarEvents)
+		} else {
+			batchUsers = append(batchUsers, user)
 		}
 
 		users = append(users, user)
@@ -142,12 +244,12 @@ func (m *mscalendar) retrieveUsersToSync(userIndex store.UserIndex, syncJobSumma
 		return users, calendarViews, errNoUsersNeedToBeSynced
 	}
 
-	if !fetchIndividually {
-		var err error
-		calendarViews, err = m.GetCalendarViews(users)
+	if len(batchUsers) > 0 {
+		batchViews, err := m.GetCalendarViews(batchUsers)
 		if err != nil {
 			return users, calendarViews, errors.Wrap(err, "not able to get calendar views for connected users")
 		}
+		calendarViews = append(calendarViews, batchViews...)
 	}
 
 	if len(calendarViews) == 0 {
@@ -167,6 +269,10 @@ func (m *mscalendar) retrieveUsersToSync(userIndex store.UserIndex, syncJobSumma
 
 func (m *mscalendar) syncUsers(userIndex store.UserIndex, fetchIndividually bool) (string, *StatusSyncJobSummary, error) {
 	syncJobSummary := &StatusSyncJobSummary{}
+	throttledBefore := m.ThrottledRequests()
+	defer func() {
+		syncJobSummary.NumberOfThrottledRequests = m.ThrottledRequests() - throttledBefore
+	}()
 	if len(userIndex) == 0 {
 		return "No connected users found", syncJobSummary, nil
 	}
@@ -177,8 +283,9 @@ func (m *mscalendar) syncUsers(userIndex store.UserIndex, fetchIndividually bool
 		return err.Error(), syncJobSummary, errors.Wrapf(err, "error retrieving users to sync (individually=%v)", fetchIndividually)
 	}
 
-	m.deliverReminders(users, calendarViews, fetchIndividually)
-	out, numberOfUsersStatusChanged, numberOfUsersFailedStatusChanged, err := m.setUserStatuses(users, calendarViews)
+	outsideWorkingHours := m.usersOutsideWorkingHours(users, time.Now())
+	m.deliverReminders(users, calendarViews, fetchIndividually, outsideWorkingHours)
+	out, numberOfUsersStatusChanged, numberOfUsersFailedStatusChanged, err := m.setUserStatuses(users, calendarViews, outsideWorkingHours)
 	if err != nil {
 		return "", syncJobSummary, errors.Wrap(err, "error setting the user statuses")
 	}
@@ -189,11 +296,13 @@ func (m *mscalendar) syncUsers(userIndex store.UserIndex, fetchIndividually bool
 	return out, syncJobSummary, nil
 }
 
-func (m *mscalendar) deliverReminders(users []*store.User, calendarViews []*remote.ViewCalendarResponse, fetchIndividually bool) {
+// deliverReminders sends the reminders of the upcoming events, except to the
+// users outside their working hours.
+func (m *mscalendar) deliverReminders(users []*store.User, calendarViews []*remote.ViewCalendarResponse, fetchIndividually bool, outsideWorkingHours map[string]bool) {
 	numberOfLogs := 0
 	toNotify := []*store.User{}
 	for _, u := range users {
-		if u.Settings.ReceiveReminders {
+		if u.Settings.ReceiveReminders && !outsideWorkingHours[u.MattermostUserID] {
 			toNotify = append(toNotify, u)
 		}
 	}
@@ -222,7 +331,7 @@ func (m *mscalendar) deliverReminders(users []*store.User, calendarViews []*remo
 		}
 
 		mattermostUserID := usersByRemoteID[view.RemoteUserID].MattermostUserID
-		if fetchIndividually {
+		if fetchIndividually || m.requiresUserClient(user) {
 			engine, err := m.FilterCopy(withActingUser(user.MattermostUserID))
 			if err != nil {
 				m.Logger.With(bot.LogContext{"err": err}).Errorf("error getting engine for user")
@@ -235,11 +344,15 @@ func (m *mscalendar) deliverReminders(users []*store.User, calendarViews []*remo
 	}
 }
 
-func (m *mscalendar) setUserStatuses(users []*store.User, calendarViews []*remote.ViewCalendarResponse) (string, int, int, error) {
+// setUserStatuses sets the status of the users from their calendar events.
+// Outside their working hours, users who opted into working hours get the
+// status they chose instead of the one of their meetings. Users out of office
+// get a custom status saying so instead of the one of their meetings.
+func (m *mscalendar) setUserStatuses(users []*store.User, calendarViews []*remote.ViewCalendarResponse, outsideWorkingHours map[string]bool) (string, int, int, error) {
 	numberOfLogs, numberOfUserStatusChange, numberOfUserErrorInStatusChange := 0, 0, 0
 	toUpdate := []*store.User{}
 	for _, u := range users {
-		if u.IsConfiguredForStatusUpdates() || u.IsConfiguredForCustomStatusUpdates() {
+		if u.IsConfiguredForStatusUpdates() || u.IsConfiguredForCustomStatusUpdates() || u.IsConfiguredForWorkingHours() {
 			toUpdate = append(toUpdate, u)
 		}
 	}
@@ -264,6 +377,7 @@ func (m *mscalendar) setUserStatuses(users []*store.User, calendarViews []*remot
 	}
 
 	var res string
+	now := time.Now()
 	for _, view := range calendarViews {
 		isStatusChanged := false
 		user, ok := usersByRemoteID[view.RemoteUserID]
@@ -291,7 +405,23 @@ func (m *mscalendar) setUserStatuses(users []*store.User, calendarViews []*remot
 		events = getMergedEvents(events)
 
 		var err error
-		if user.IsConfiguredForStatusUpdates() {
+		if user.IsConfiguredForWorkingHours() {
+			isStatusChanged, err = m.setWorkingHoursStatus(user, status, outsideWorkingHours[mattermostUserID])
+			if err != nil {
+				if numberOfLogs < logTruncateLimit {
+					m.Logger.Warnf("Error setting user %s working hours status. err=%v", user.MattermostUserID, err)
+				} else if numberOfLogs == logTruncateLimit {
+					m.Logger.Warnf(logTruncateMsg)
+				}
+				numberOfLogs++
+				numberOfUserErrorInStatusChange++
+			}
+			if isStatusChanged {
+				numberOfUserStatusChange++
+			}
+		}
+
+		if user.IsConfiguredForStatusUpdates() && !outsideWorkingHours[mattermostUserID] {
 			res, isStatusChanged, err = m.setStatusFromCalendarView(user, status, events)
 			if err != nil {
 				if numberOfLogs < logTruncateLimit {
@@ -308,7 +438,11 @@ func (m *mscalendar) setUserStatuses(users []*store.User, calendarViews []*remot
 		}
 
 		if user.IsConfiguredForCustomStatusUpdates() {
-			res, isStatusChanged, err = m.setCustomStatusFromCalendarView(user, events)
+			if settings := m.mailboxSettingsOf(user, now); settings != nil && settings.AutomaticRepliesSetting.IsActive(now) {
+				res, isStatusChanged, err = m.setOutOfOfficeCustomStatus(user, settings)
+			} else {
+				res, isStatusChanged, err = m.setCustomStatusFromCalendarView(user, events)
+			}
 			if err != nil {
 				if numberOfLogs < logTruncateLimit {
 					m.Logger.Warnf("Error setting user %s custom status. err=%v", user.MattermostUserID, err)
@@ -363,6 +497,8 @@ func (m *mscalendar) setCustomStatusFromCalendarView(user *store.User, events []
 		return "User already has a custom status set, ignoring custom status change", isStatusChanged, nil
 	}
 
+	// The custom status is seen by everyone, so it never shows the details
+	// of the event, which may be private.
 	if appErr := m.PluginAPI.UpdateMattermostUserCustomStatus(user.MattermostUserID, &model.CustomStatus{
 		Emoji:     "calendar",
 		Text:      "In a meeting",
@@ -545,7 +681,12 @@ func (m *mscalendar) GetCalendarEvents(user *User, start, end time.Time, exclude
 		return nil, errors.Wrap(err, "error withClient in GetCalendarEvents")
 	}
 
-	events, err := m.client.GetEventsBetweenDates(user.Remote.ID, start, end)
+	var events []*remote.Event
+	if user.User != nil && len(user.Settings.CalendarIDs) > 0 {
+		events, err = m.getCalendarsEvents(user.Remote.ID, user.Settings.CalendarIDs, start, end)
+	} else {
+		events, err = m.client.GetEventsBetweenDates(user.Remote.ID, start, end)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "error getting events for user %s", user.MattermostUserID)
 	}
@@ -571,14 +712,14 @@ func (m *mscalendar) GetCalendarViews(users []*store.User) ([]*remote.ViewCalend
 
 	params := []*remote.ViewCalendarParams{}
 	for _, u := range users {
-		params = append(params, &remote.ViewCalendarParams{
-			RemoteUserID: u.Remote.ID,
-			StartTime:    start,
-			EndTime:      end,
-		})
+		params = append(params, calendarViewParams(u, start, end)...)
 	}
 
-	return m.client.DoBatchViewCalendarRequests(params)
+	views, err := m.client.DoBatchViewCalendarRequests(params)
+	if err != nil {
+		return nil, err
+	}
+	return mergeCalendarViews(views), nil
 }
 
 func (m *mscalendar) notifyUpcomingEvents(mattermostUserID string, events []*remote.Event) {
@@ -624,12 +765,14 @@ func (m *mscalendar) notifyUpcomingEvents(mattermostUserID string, events []*rem
 			}
 
 			if eventMetadata != nil {
+				// Channel members only see private events as busy time.
+				channelEvent := views.ForOthers(event)
 				for channelID := range eventMetadata.LinkedChannelIDs {
 					post := &model.Post{
 						ChannelId: channelID,
 						Message:   "Upcoming event",
 					}
-					attachment, errRender := views.RenderEventAsAttachment(event, timezone, views.ShowTimezoneOption(timezone))
+					attachment, errRender := views.RenderEventAsAttachment(channelEvent, timezone, views.ShowTimezoneOption(timezone))
 					if errRender != nil {
 						m.Logger.With(bot.LogContext{"err": errRender}).Errorf("notifyUpcomingEvents error rendering channel post")
 						continue
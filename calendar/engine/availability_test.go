@@ -4,14 +4,15 @@
 package engine
 
 import (
-	"context"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
@@ -130,7 +131,7 @@ func TestSyncStatusAll(t *testing.T) {
 					Email:            "user_email@example.com",
 				},
 			}, nil).Times(1)
-			r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+			r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 
 			mockUser := &store.User{
 				MattermostUserID: "user_mm_id",
@@ -208,7 +209,7 @@ func TestSyncStatusUserConfig(t *testing.T) {
 			},
 			runAssertions: func(deps *Dependencies, client remote.Client) {
 				c, r, papi, poster, s := client.(*mock_remote.MockClient), deps.Remote.(*mock_remote.MockRemote), deps.PluginAPI.(*mock_plugin_api.MockPluginAPI), deps.Poster.(*mock_bot.MockPoster), deps.Store.(*mock_store.MockStore)
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 				moment := time.Now().UTC()
 				busyEvent := &remote.Event{ICalUID: "event_id", Start: remote.NewDateTime(moment, "UTC"), ShowAs: "busy", Attendees: []*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "mock-attendee@gmail.com"}}}}
 
@@ -240,7 +241,7 @@ func TestSyncStatusUserConfig(t *testing.T) {
 
 				papi.EXPECT().UpdateMattermostUserStatus("user_mm_id", gomock.Any()).Times(0)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 		"UpdateStatusFromOptions do not disturb, GetConfirmation enabled and overlapping events present": {
@@ -261,7 +262,7 @@ func TestSyncStatusUserConfig(t *testing.T) {
 
 				papi.EXPECT().UpdateMattermostUserStatus("user_mm_id", gomock.Any()).Times(0)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 		"Update status to away using legacy settings, GetConfirmation enabled and overlapping events present": {
@@ -283,7 +284,7 @@ func TestSyncStatusUserConfig(t *testing.T) {
 
 				papi.EXPECT().UpdateMattermostUserStatus("user_mm_id", gomock.Any()).Times(0)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 		"Update status to dnd using legacy settings, GetConfirmation enabled and overlapping events present": {
@@ -305,7 +306,7 @@ func TestSyncStatusUserConfig(t *testing.T) {
 
 				papi.EXPECT().UpdateMattermostUserStatus("user_mm_id", gomock.Any()).Times(0)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 	} {
@@ -353,7 +354,7 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 			runAssertions: func(deps *Dependencies, client remote.Client) {
 				_, _, _, _, r := client.(*mock_remote.MockClient), deps.PluginAPI.(*mock_plugin_api.MockPluginAPI), deps.Poster.(*mock_bot.MockPoster), deps.Store.(*mock_store.MockStore), deps.Remote.(*mock_remote.MockRemote)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 		"SetCustomStatus enabled but no event present": {
@@ -370,7 +371,7 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 				papi.EXPECT().RemoveMattermostUserCustomStatus("user_mm_id").Return(nil)
 				s.EXPECT().StoreUserCustomStatusUpdates("user_mm_id", false).Return(nil)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 		"SetCustomStatus enabled but overlapping events": {
@@ -388,7 +389,7 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 				}, nil)
 				papi.EXPECT().GetMattermostUserStatusesByIds([]string{"user_mm_id"}).Return([]*model.Status{{Status: "online", Manual: true, UserId: "user_mm_id"}}, nil)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 
 				papi.EXPECT().GetMattermostUser("user_mm_id").Return(&model.User{
 					Id: "user_mm_id",
@@ -418,7 +419,7 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 				}, nil)
 				papi.EXPECT().GetMattermostUserStatusesByIds([]string{"user_mm_id"}).Return([]*model.Status{{Status: "online", Manual: true, UserId: "user_mm_id"}}, nil)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 
 				papi.EXPECT().GetMattermostUser("user_mm_id").Return(&model.User{
 					Id: "user_mm_id",
@@ -448,7 +449,7 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 				}, nil)
 				papi.EXPECT().GetMattermostUserStatusesByIds([]string{"user_mm_id"}).Return([]*model.Status{{Status: "online", Manual: true, UserId: "user_mm_id"}}, nil)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 
 				papi.EXPECT().GetMattermostUser("user_mm_id").Return(&model.User{
 					Id: "user_mm_id",
@@ -480,7 +481,7 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 				papi.EXPECT().RemoveMattermostUserCustomStatus("user_mm_id").Return(nil)
 				s.EXPECT().StoreUserCustomStatusUpdates("user_mm_id", false).Return(nil)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 		"SetCustomStatus enabled but no attendee present": {
@@ -498,7 +499,7 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 				papi.EXPECT().GetMattermostUserStatusesByIds([]string{"user_mm_id"}).Return([]*model.Status{{Status: "online", Manual: true, UserId: "user_mm_id"}}, nil)
 				papi.EXPECT().RemoveMattermostUserCustomStatus("user_mm_id").Return(nil)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 
 				s.EXPECT().StoreUserCustomStatusUpdates("user_mm_id", false).Return(nil)
 			},
@@ -528,7 +529,7 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 
 				s.EXPECT().StoreUserCustomStatusUpdates("user_mm_id", true).Return(nil)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 	} {
@@ -552,8 +553,10 @@ func TestSyncCustomStatusUserConfig(t *testing.T) {
 					ID:   "user_remote_id",
 					Mail: "user_email@example.com",
 				},
-				IsCustomStatusSet: true,
-				Settings:          tc.settings,
+				IsCustomStatusSet:        true,
+				Settings:                 tc.settings,
+				MailboxSettings:          &remote.MailboxSettings{},
+				MailboxSettingsUpdatedAt: time.Now().Unix(),
 			}, nil).Times(1)
 
 			tc.runAssertions(env.Dependencies, client)
@@ -596,7 +599,7 @@ func TestSyncCustomStatusAndUserStatusConfig(t *testing.T) {
 				}).Return(nil)
 
 				s.EXPECT().StoreUserCustomStatusUpdates("user_mm_id", true).Return(nil)
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 
 				s.EXPECT().StoreUserActiveEvents("user_mm_id", []string{"event_id " + moment.Format(time.RFC3339)})
 				s.EXPECT().StoreUser(gomock.Any()).Return(nil).Times(1)
@@ -631,7 +634,7 @@ func TestSyncCustomStatusAndUserStatusConfig(t *testing.T) {
 				}).Return(nil)
 
 				s.EXPECT().StoreUserCustomStatusUpdates("user_mm_id", true).Return(nil)
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 
 				s.EXPECT().StoreUserActiveEvents("user_mm_id", []string{"event_id " + moment.Format(time.RFC3339)})
 				s.EXPECT().StoreUser(gomock.Any()).Return(nil).Times(1)
@@ -644,7 +647,7 @@ func TestSyncCustomStatusAndUserStatusConfig(t *testing.T) {
 			runAssertions: func(deps *Dependencies, client remote.Client) {
 				_, _, _, _, r := client.(*mock_remote.MockClient), deps.PluginAPI.(*mock_plugin_api.MockPluginAPI), deps.Poster.(*mock_bot.MockPoster), deps.Store.(*mock_store.MockStore), deps.Remote.(*mock_remote.MockRemote)
 
-				r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+				r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 			},
 		},
 	} {
@@ -668,8 +671,10 @@ func TestSyncCustomStatusAndUserStatusConfig(t *testing.T) {
 					ID:   "user_remote_id",
 					Mail: "user_email@example.com",
 				},
-				IsCustomStatusSet: true,
-				Settings:          tc.settings,
+				IsCustomStatusSet:        true,
+				Settings:                 tc.settings,
+				MailboxSettings:          &remote.MailboxSettings{},
+				MailboxSettingsUpdatedAt: time.Now().Unix(),
 			}, nil).Times(1)
 
 			tc.runAssertions(env.Dependencies, client)
@@ -683,11 +688,12 @@ func TestSyncCustomStatusAndUserStatusConfig(t *testing.T) {
 
 func TestReminders(t *testing.T) {
 	for name, tc := range map[string]struct {
-		apiError       *remote.APIError
-		remoteEvents   []*remote.Event
-		eventMetadata  map[string]*store.EventMetadata
-		numReminders   int
-		shouldLogError bool
+		apiError             *remote.APIError
+		remoteEvents         []*remote.Event
+		eventMetadata        map[string]*store.EventMetadata
+		expectedChannelTitle string
+		numReminders         int
+		shouldLogError       bool
 	}{
 		"Most common case, no remote events. No reminder.": {
 			remoteEvents:   []*remote.Event{},
@@ -754,6 +760,19 @@ func TestReminders(t *testing.T) {
 			numReminders:   1,
 			shouldLogError: false,
 		},
+		"Private remote event linked to channel in the range for the reminder. Channel reminder should show it as busy.": {
+			remoteEvents: []*remote.Event{
+				{ID: "event_id_1", ICalUID: "event_id_1", Subject: "Doctor", Sensitivity: remote.SensitivityPrivate, Location: &remote.Location{DisplayName: "Clinic"}, Start: remote.NewDateTime(time.Now().Add(7*time.Minute).UTC(), "UTC"), End: remote.NewDateTime(time.Now().Add(45*time.Minute).UTC(), "UTC")},
+			},
+			eventMetadata: map[string]*store.EventMetadata{
+				"event_id_1": {
+					LinkedChannelIDs: map[string]struct{}{"some_channel_id": {}},
+				},
+			},
+			expectedChannelTitle: "Busy",
+			numReminders:         1,
+			shouldLogError:       false,
+		},
 		"Remote API Error. Error should be logged.": {
 			remoteEvents:   []*remote.Event{},
 			numReminders:   0,
@@ -776,7 +795,7 @@ func TestReminders(t *testing.T) {
 					Email:            "user_email@example.com",
 				},
 			}, nil).Times(1)
-			r.EXPECT().MakeSuperuserClient(context.Background()).Return(client, nil)
+			r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
 
 			loadUser := s.EXPECT().LoadUser("user_mm_id").Return(&store.User{
 				MattermostUserID: "user_mm_id",
@@ -800,6 +819,12 @@ func TestReminders(t *testing.T) {
 					s.EXPECT().LoadEventMetadata(eventID).Return(metadata, nil).Times(1)
 					for channelID := range metadata.LinkedChannelIDs {
 						poster.EXPECT().CreatePost(test.DoMatch(func(v *model.Post) bool {
+							if tc.expectedChannelTitle != "" {
+								attachments := v.Attachments()
+								if len(attachments) != 1 || attachments[0].Title != tc.expectedChannelTitle || len(attachments[0].Fields) != 0 {
+									return false
+								}
+							}
 							return v.ChannelId == channelID
 						})).Return(nil)
 					}
@@ -888,7 +913,7 @@ func TestRetrieveUsersToSyncIndividually(t *testing.T) {
 
 		events := []*remote.Event{newTestEvent("1", "", "test")}
 		papi.EXPECT().GetMattermostUser(testUser.MattermostUserID)
-		r.EXPECT().MakeUserClient(context.Background(), testUser.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(client, nil)
+		r.EXPECT().MakeUserClient(gomock.Any(), testUser.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(client, nil)
 		c.EXPECT().GetEventsBetweenDates(testUser.Remote.ID, gomock.Any(), gomock.Any()).Return(events, nil)
 
 		m := New(e, "").(*mscalendar)
@@ -934,7 +959,7 @@ func TestRetrieveUsersToSyncIndividually(t *testing.T) {
 
 		events := []*remote.Event{newTestEvent("1", "", "test")}
 		papi.EXPECT().GetMattermostUser(testUser.MattermostUserID)
-		r.EXPECT().MakeUserClient(context.Background(), testUser.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(client, nil)
+		r.EXPECT().MakeUserClient(gomock.Any(), testUser.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(client, nil)
 		c.EXPECT().GetEventsBetweenDates(testUser.Remote.ID, gomock.Any(), gomock.Any()).Return(events, nil)
 
 		m := New(e, "").(*mscalendar)
@@ -983,8 +1008,8 @@ func TestRetrieveUsersToSyncIndividually(t *testing.T) {
 		eventsUser2 := []*remote.Event{newTestEvent("2", "", "test2")}
 		papi.EXPECT().GetMattermostUser(testUser.MattermostUserID)
 		papi.EXPECT().GetMattermostUser(testUser2.MattermostUserID)
-		r.EXPECT().MakeUserClient(context.Background(), testUser.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(client, nil)
-		r.EXPECT().MakeUserClient(context.Background(), testUser2.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(client, nil)
+		r.EXPECT().MakeUserClient(gomock.Any(), testUser.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(client, nil)
+		r.EXPECT().MakeUserClient(gomock.Any(), testUser2.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(client, nil)
 		c.EXPECT().GetEventsBetweenDates(testUser.Remote.ID, gomock.Any(), gomock.Any()).Return(eventsUser1, nil)
 		c.EXPECT().GetEventsBetweenDates(testUser2.Remote.ID, gomock.Any(), gomock.Any()).Return(eventsUser2, nil)
 
@@ -1004,6 +1029,66 @@ func TestRetrieveUsersToSyncIndividually(t *testing.T) {
 	})
 }
 
+// feedRemote serves the users holding a "feed" token with their own client only.
+type feedRemote struct {
+	*mock_remote.MockRemote
+}
+
+func (feedRemote) RequiresUserClient(token *oauth2.Token) bool {
+	return token != nil && token.TokenType == "feed"
+}
+
+func TestRetrieveUsersToSyncMixed(t *testing.T) {
+	testUser := newTestUserNumbered(1)
+	testUser.Settings.UpdateStatusFromOptions = store.AwayStatusOption
+	testUser.Settings.ReceiveReminders = true
+
+	feedUser := newTestUserNumbered(2)
+	feedUser.OAuth2Token.TokenType = "feed"
+	feedUser.Settings.ReceiveReminders = true
+
+	userIndex := []*store.UserShort{
+		{MattermostUserID: testUser.MattermostUserID, RemoteID: testUser.Remote.ID},
+		{MattermostUserID: feedUser.MattermostUserID, RemoteID: feedUser.Remote.ID},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	e, superuserClient := makeStatusSyncTestEnv(ctrl)
+	r := e.Remote.(*mock_remote.MockRemote)
+	e.Remote = feedRemote{r}
+	feedClient := mock_remote.NewMockClient(ctrl)
+
+	s, papi := e.Store.(*mock_store.MockStore), e.PluginAPI.(*mock_plugin_api.MockPluginAPI)
+	s.EXPECT().LoadUser(testUser.MattermostUserID).Return(testUser, nil)
+	s.EXPECT().LoadUser(feedUser.MattermostUserID).Return(feedUser, nil).Times(2)
+	papi.EXPECT().GetMattermostUser(feedUser.MattermostUserID)
+
+	feedEvents := []*remote.Event{newTestEvent("1", "", "feed")}
+	r.EXPECT().MakeUserClient(gomock.Any(), feedUser.OAuth2Token, gomock.Any(), gomock.Any(), gomock.Any()).Return(feedClient, nil)
+	feedClient.EXPECT().GetEventsBetweenDates(feedUser.Remote.ID, gomock.Any(), gomock.Any()).Return(feedEvents, nil)
+
+	batchView := &remote.ViewCalendarResponse{RemoteUserID: testUser.Remote.ID, Events: []*remote.Event{newTestEvent("2", "", "batch")}}
+	superuserClient.(*mock_remote.MockClient).EXPECT().DoBatchViewCalendarRequests(gomock.Any()).DoAndReturn(
+		func(params []*remote.ViewCalendarParams) ([]*remote.ViewCalendarResponse, error) {
+			require.Len(t, params, 1)
+			require.Equal(t, testUser.Remote.ID, params[0].RemoteUserID)
+			return []*remote.ViewCalendarResponse{batchView}, nil
+		})
+
+	m := New(e, "").(*mscalendar)
+	m.client = superuserClient
+
+	users, responses, err := m.retrieveUsersToSync(userIndex, &StatusSyncJobSummary{}, false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []*store.User{testUser, feedUser}, users)
+	require.ElementsMatch(t, []*remote.ViewCalendarResponse{batchView, {
+		RemoteUserID: feedUser.Remote.ID,
+		Events:       feedEvents,
+	}}, responses)
+}
+
 func makeStatusSyncTestEnv(ctrl *gomock.Controller) (Env, remote.Client) {
 	s := mock_store.NewMockStore(ctrl)
 	poster := mock_bot.NewMockPoster(ctrl)
@@ -1183,3 +1268,102 @@ func TestGetMergedEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAvailability(t *testing.T) {
+	start := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	actingUser := &User{
+		User:             newTestUserNumbered(0),
+		MattermostUser:   &model.User{Id: "creator_mm_id_0"},
+		MattermostUserID: "creator_mm_id_0",
+	}
+	alice := newTestUserNumbered(1)
+	alice.Remote.Mail = "Alice@example.com"
+	feedUser := newTestUserNumbered(2)
+	feedUser.OAuth2Token.TokenType = "feed"
+	feedUser.Remote.Mail = "feed.example.com"
+
+	setupUsers := func(s *mock_store.MockStore, papi *mock_plugin_api.MockPluginAPI) {
+		papi.EXPECT().GetMattermostUserByUsername("alice").Return(&model.User{Id: alice.MattermostUserID}, nil)
+		papi.EXPECT().GetMattermostUserByUsername("feed").Return(&model.User{Id: feedUser.MattermostUserID}, nil)
+		papi.EXPECT().GetMattermostUserByUsername("carol").Return(&model.User{Id: "carol_mm_id"}, nil)
+		papi.EXPECT().GetMattermostUserByUsername("nobody").Return(nil, errors.New("not found"))
+		s.EXPECT().LoadUser(alice.MattermostUserID).Return(alice, nil).AnyTimes()
+		s.EXPECT().LoadUser(feedUser.MattermostUserID).Return(feedUser, nil).AnyTimes()
+		s.EXPECT().LoadUser("carol_mm_id").Return(nil, store.ErrNotFound)
+	}
+	usernames := []string{"@alice", "@feed", "@carol", "nobody"}
+
+	t.Run("superuser client with users requiring their own client", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		e, superuserClient := makeStatusSyncTestEnv(ctrl)
+		e.Config.Provider.DisplayName = "Calendar"
+		r := e.Remote.(*mock_remote.MockRemote)
+		e.Remote = feedRemote{r}
+		s, papi := e.Store.(*mock_store.MockStore), e.PluginAPI.(*mock_plugin_api.MockPluginAPI)
+		setupUsers(s, papi)
+		papi.EXPECT().GetMattermostUser(feedUser.MattermostUserID).Return(&model.User{Id: feedUser.MattermostUserID}, nil)
+
+		aliceSchedule := &remote.ScheduleInformation{ScheduleID: "alice@example.com", AvailabilityView: "0220"}
+		r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(superuserClient, nil)
+		superuserClient.(*mock_remote.MockClient).EXPECT().GetSchedule(gomock.Any(), gomock.Any(), gomock.Any(), 30).DoAndReturn(
+			func(requests []*remote.ScheduleUserInfo, startTime, endTime *remote.DateTime, _ int) ([]*remote.ScheduleInformation, error) {
+				require.Equal(t, []*remote.ScheduleUserInfo{{RemoteUserID: alice.Remote.ID, Mail: alice.Remote.Mail}}, requests)
+				require.Equal(t, start, startTime.Time())
+				require.Equal(t, end, endTime.Time())
+				return []*remote.ScheduleInformation{aliceSchedule}, nil
+			})
+
+		feedClient := mock_remote.NewMockClient(ctrl)
+		feedSchedule := &remote.ScheduleInformation{ScheduleID: feedUser.Remote.Mail, AvailabilityView: "1000"}
+		r.EXPECT().MakeUserClient(gomock.Any(), feedUser.OAuth2Token, feedUser.MattermostUserID, gomock.Any(), gomock.Any()).Return(feedClient, nil)
+		feedClient.EXPECT().GetSchedule([]*remote.ScheduleUserInfo{{RemoteUserID: feedUser.Remote.ID, Mail: feedUser.Remote.Mail}}, gomock.Any(), gomock.Any(), 30).Return([]*remote.ScheduleInformation{feedSchedule}, nil)
+
+		m := New(e, "").(*mscalendar)
+		availability, err := m.GetAvailability(actingUser, usernames, start, end, 30)
+		require.NoError(t, err)
+		require.Equal(t, []*UserAvailability{
+			{MattermostUsername: "alice", Schedule: aliceSchedule},
+			{MattermostUsername: "feed", Schedule: feedSchedule},
+			{MattermostUsername: "carol", Error: "Not connected to Calendar."},
+			{MattermostUsername: "nobody", Error: "User not found."},
+		}, availability)
+	})
+
+	t.Run("user clients when the superuser client is not supported", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		e, _ := makeStatusSyncTestEnv(ctrl)
+		e.Config.Provider.DisplayName = "Calendar"
+		r := e.Remote.(*mock_remote.MockRemote)
+		s, papi := e.Store.(*mock_store.MockStore), e.PluginAPI.(*mock_plugin_api.MockPluginAPI)
+		setupUsers(s, papi)
+		papi.EXPECT().GetMattermostUser(gomock.Any()).Return(&model.User{}, nil).Times(2)
+
+		r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(nil, remote.ErrSuperUserClientNotSupported)
+
+		aliceClient, feedClient := mock_remote.NewMockClient(ctrl), mock_remote.NewMockClient(ctrl)
+		r.EXPECT().MakeUserClient(gomock.Any(), alice.OAuth2Token, alice.MattermostUserID, gomock.Any(), gomock.Any()).Return(aliceClient, nil)
+		r.EXPECT().MakeUserClient(gomock.Any(), feedUser.OAuth2Token, feedUser.MattermostUserID, gomock.Any(), gomock.Any()).Return(feedClient, nil)
+		aliceSchedule := &remote.ScheduleInformation{ScheduleID: alice.Remote.Mail, AvailabilityView: "0220"}
+		aliceClient.EXPECT().GetSchedule(gomock.Any(), gomock.Any(), gomock.Any(), 30).Return([]*remote.ScheduleInformation{aliceSchedule}, nil)
+		feedClient.EXPECT().GetSchedule(gomock.Any(), gomock.Any(), gomock.Any(), 30).Return([]*remote.ScheduleInformation{{
+			ScheduleID: feedUser.Remote.Mail,
+			Error:      &remote.ScheduleInformationError{Message: "access denied"},
+		}}, nil)
+
+		m := New(e, "").(*mscalendar)
+		availability, err := m.GetAvailability(actingUser, usernames, start, end, 30)
+		require.NoError(t, err)
+		require.Equal(t, []*UserAvailability{
+			{MattermostUsername: "alice", Schedule: aliceSchedule},
+			{MattermostUsername: "feed", Error: "access denied"},
+			{MattermostUsername: "carol", Error: "Not connected to Calendar."},
+			{MattermostUsername: "nobody", Error: "User not found."},
+		}, availability)
+	})
+}
@@ -4,18 +4,31 @@
 package engine
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
 )
 
 type Calendar interface {
 	CreateCalendar(user *User, calendar *remote.Calendar) (*remote.Calendar, error)
 	CreateEvent(user *User, event *remote.Event, mattermostUserIDs []string) (*remote.Event, error)
+	UpdateEvent(user *User, eventID string, event *remote.Event) (*remote.Event, error)
+	CancelEvent(user *User, eventID, comment string) (*remote.Event, error)
 	DeleteCalendar(user *User, calendarID string) error
 	FindMeetingTimes(user *User, meetingParams *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error)
 	GetCalendars(user *User) ([]*remote.Calendar, error)
 	ViewCalendar(user *User, from, to time.Time) ([]*remote.Event, error)
+	ViewCalendarAs(user *User, principalUsername string, from, to time.Time) ([]*remote.Event, error)
+	AddDelegate(user *User, principalUsername string) error
+	RemoveDelegate(user *User, principalUsername string) error
 }
 
 func (m *mscalendar) ViewCalendar(user *User, from, to time.Time) ([]*remote.Event, error) {
@@ -43,9 +56,97 @@ func (m *mscalendar) getTodayCalendarEvents(user *User, now time.Time, timezone
 	}
 
 	from, to := getTodayHoursForTimezone(now, timezone)
+	if len(user.Settings.CalendarIDs) > 0 {
+		return m.getCalendarsEvents(user.Remote.ID, user.Settings.CalendarIDs, from, to)
+	}
 	return m.client.GetDefaultCalendarView(user.Remote.ID, from, to)
 }
 
+// getCalendarsEvents reads the events of the given calendars of the user,
+// sorted by start time. Calendars that cannot be read are skipped, unless
+// none of them can.
+func (m *mscalendar) getCalendarsEvents(remoteUserID string, calendarIDs []string, start, end time.Time) ([]*remote.Event, error) {
+	events := []*remote.Event{}
+	read := 0
+	var lastErr error
+	for _, calendarID := range calendarIDs {
+		calendarEvents, err := m.client.GetCalendarView(remoteUserID, calendarID, start, end)
+		if err != nil {
+			m.Logger.Warnf("Failed to get the events of calendar %s of remote user %s. err=%v", calendarID, remoteUserID, err)
+			lastErr = err
+			continue
+		}
+		read++
+		events = append(events, calendarEvents...)
+	}
+	if read == 0 && lastErr != nil {
+		return nil, errors.Wrap(lastErr, "error getting the events of the selected calendars")
+	}
+
+	sortEventsByStart(events)
+	return events, nil
+}
+
+// calendarViewParams returns the parameters to view the calendars selected
+// by the user, or their default calendar.
+func calendarViewParams(user *store.User, start, end time.Time) []*remote.ViewCalendarParams {
+	if len(user.Settings.CalendarIDs) == 0 {
+		return []*remote.ViewCalendarParams{{
+			RemoteUserID: user.Remote.ID,
+			StartTime:    start,
+			EndTime:      end,
+		}}
+	}
+
+	params := []*remote.ViewCalendarParams{}
+	for _, calendarID := range user.Settings.CalendarIDs {
+		params = append(params, &remote.ViewCalendarParams{
+			RemoteUserID: user.Remote.ID,
+			CalendarID:   calendarID,
+			StartTime:    start,
+			EndTime:      end,
+		})
+	}
+	return params
+}
+
+// mergeCalendarViews merges the views of the calendars of each user into a
+// single view, keeping the order of the users. A view only holds an error
+// when none of the calendars of the user could be read.
+func mergeCalendarViews(views []*remote.ViewCalendarResponse) []*remote.ViewCalendarResponse {
+	result := []*remote.ViewCalendarResponse{}
+	byUser := map[string]*remote.ViewCalendarResponse{}
+	read := map[string]bool{}
+	for _, view := range views {
+		merged, ok := byUser[view.RemoteUserID]
+		if !ok {
+			merged = &remote.ViewCalendarResponse{RemoteUserID: view.RemoteUserID}
+			byUser[view.RemoteUserID] = merged
+			result = append(result, merged)
+		}
+		if view.Error != nil {
+			if !read[view.RemoteUserID] {
+				merged.Error = view.Error
+			}
+			continue
+		}
+		read[view.RemoteUserID] = true
+		merged.Error = nil
+		merged.Events = append(merged.Events, view.Events...)
+	}
+
+	for _, view := range result {
+		sortEventsByStart(view.Events)
+	}
+	return result
+}
+
+func sortEventsByStart(events []*remote.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Time().Before(events[j].Start.Time())
+	})
+}
+
 func (m *mscalendar) excludeDeclinedEvents(events []*remote.Event) (result []*remote.Event) {
 	for ix, evt := range events {
 		if evt.ResponseStatus == nil || evt.ResponseStatus.Response != remote.EventResponseStatusDeclined {
@@ -91,7 +192,125 @@ func (m *mscalendar) CreateEvent(user *User, event *remote.Event, mattermostUser
 		}
 	}
 
-	return m.client.CreateEvent(event)
+	if event.IsOnlineMeeting && event.OnlineMeetingProvider == "" {
+		event.OnlineMeetingProvider = remote.OnlineMeetingProviderTeams
+	}
+
+	return m.client.CreateEvent("", event)
+}
+
+// UpdateEvent changes the fields set on the given event. When the user
+// organizes the event, the channels linked to it are told about the change.
+func (m *mscalendar) UpdateEvent(user *User, eventID string, event *remote.Event) (*remote.Event, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := m.client.UpdateEvent(user.Remote.ID, eventID, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if updated.IsOrganizer {
+		m.notifyLinkedChannels(user, updated, "The event **%s** was updated by %s")
+	}
+	return updated, nil
+}
+
+// CancelEvent removes the event from the calendar of the user. Meetings
+// organized by the user are cancelled for every attendee, with the comment,
+// and the channels linked to them are told about the cancellation. It returns
+// the event as it was before being cancelled.
+func (m *mscalendar) CancelEvent(user *User, eventID, comment string) (*remote.Event, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := m.client.GetEvent(user.Remote.ID, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.IsOrganizer && len(event.Attendees) > 0 {
+		err = m.client.CancelEvent(user.Remote.ID, eventID, comment)
+	} else {
+		err = m.client.DeleteEvent(user.Remote.ID, eventID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Attendees share the iCalUID of the event, so only its organizer may
+	// speak for the linked channels.
+	if event.IsOrganizer {
+		m.notifyLinkedChannels(user, event, "The event **%s** was canceled by %s")
+		err = m.Store.DeleteEventMetadata(event.ICalUID)
+		if err != nil {
+			m.Logger.With(bot.LogContext{
+				"eventID": event.ID,
+				"err":     err.Error(),
+			}).Warnf("CancelEvent error deleting event metadata")
+		}
+	}
+	return event, nil
+}
+
+// notifyLinkedChannels posts the event to every channel linked to it. The
+// message is formatted with the subject of the event and the user. Private
+// events are posted as busy time.
+func (m *mscalendar) notifyLinkedChannels(user *User, event *remote.Event, format string) {
+	eventMetadata, err := m.Store.LoadEventMetadata(event.ICalUID)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			m.Logger.With(bot.LogContext{
+				"eventID": event.ID,
+				"err":     err.Error(),
+			}).Warnf("notifyLinkedChannels error loading event metadata")
+		}
+		return
+	}
+	if len(eventMetadata.LinkedChannelIDs) == 0 {
+		return
+	}
+
+	timezone, err := m.GetTimezone(user)
+	if err != nil {
+		m.Logger.Warnf("notifyLinkedChannels error getting timezone. err=%v", err)
+		return
+	}
+	postToLinkedChannels(m.Env, eventMetadata, user, event, timezone, format)
+}
+
+// postToLinkedChannels posts the event to the linked channels, with the
+// message formatted as for notifyLinkedChannels.
+func postToLinkedChannels(env Env, eventMetadata *store.EventMetadata, user *User, event *remote.Event, timezone, format string) {
+	event = views.ForOthers(event)
+	attachment, err := views.RenderEventAsAttachment(event, timezone, views.ShowTimezoneOption(timezone))
+	if err != nil {
+		env.Logger.With(bot.LogContext{"err": err}).Errorf("notifyLinkedChannels error rendering channel post")
+		return
+	}
+
+	message := fmt.Sprintf(format, views.MarkdownToHTMLEntities(event.Subject), user.Markdown())
+	for channelID := range eventMetadata.LinkedChannelIDs {
+		post := &model.Post{
+			ChannelId: channelID,
+			Message:   message,
+		}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{attachment})
+		err = env.Poster.CreatePost(post)
+		if err != nil {
+			env.Logger.With(bot.LogContext{"err": err}).Warnf("notifyLinkedChannels error creating post in channel")
+		}
+	}
 }
 
 func (m *mscalendar) DeleteCalendar(user *User, calendarID string) error {
@@ -76,7 +76,7 @@ func TestViewCalendar(t *testing.T) {
 }
 
 func TestGetTodayCalendarEvents(t *testing.T) {
-	mscalendar, mockStore, _, _, _, mockClient, _ := GetMockSetup(t)
+	mscalendar, mockStore, _, _, _, mockClient, mockLogger := GetMockSetup(t)
 	now := time.Now()
 	timezone := "America/Los_Angeles"
 	from, to := getTodayHoursForTimezone(now, timezone)
@@ -120,6 +120,33 @@ func TestGetTodayCalendarEvents(t *testing.T) {
 				require.Equal(t, "Today's Test Event", events[0].Subject, "Expected first event's subject to be %s, but got %s", "Today's Test Event", events[0].Subject)
 			},
 		},
+		{
+			name: "selected calendars are merged, skipping the failing ones",
+			user: GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, &store.Settings{CalendarIDs: []string{"work", "team", "broken"}}),
+			setupMock: func() {
+				mockClient.EXPECT().GetCalendarView(MockRemoteUserID, "work", from, to).Return([]*remote.Event{{Subject: "Review", Start: remote.NewDateTime(from.Add(2*time.Hour), "UTC")}}, nil).Times(1)
+				mockClient.EXPECT().GetCalendarView(MockRemoteUserID, "team", from, to).Return([]*remote.Event{{Subject: "Standup", Start: remote.NewDateTime(from.Add(time.Hour), "UTC")}}, nil).Times(1)
+				mockClient.EXPECT().GetCalendarView(MockRemoteUserID, "broken", from, to).Return(nil, fmt.Errorf("404 Not Found")).Times(1)
+				mockLogger.EXPECT().Warnf("Failed to get the events of calendar %s of remote user %s. err=%v", "broken", MockRemoteUserID, gomock.Any()).Times(1)
+			},
+			assertions: func(t *testing.T, events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, events, 2)
+				require.Equal(t, "Standup", events[0].Subject)
+				require.Equal(t, "Review", events[1].Subject)
+			},
+		},
+		{
+			name: "none of the selected calendars can be read",
+			user: GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, &store.Settings{CalendarIDs: []string{"broken"}}),
+			setupMock: func() {
+				mockClient.EXPECT().GetCalendarView(MockRemoteUserID, "broken", from, to).Return(nil, fmt.Errorf("404 Not Found")).Times(1)
+				mockLogger.EXPECT().Warnf("Failed to get the events of calendar %s of remote user %s. err=%v", "broken", MockRemoteUserID, gomock.Any()).Times(1)
+			},
+			assertions: func(t *testing.T, _ []*remote.Event, err error) {
+				require.EqualError(t, err, "error getting the events of the selected calendars: 404 Not Found")
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -132,6 +159,30 @@ func TestGetTodayCalendarEvents(t *testing.T) {
 	}
 }
 
+func TestMergeCalendarViews(t *testing.T) {
+	at := func(hour int) *remote.DateTime {
+		return remote.NewDateTime(time.Date(2024, 3, 4, hour, 0, 0, 0, time.UTC), "UTC")
+	}
+	apiError := &remote.APIError{Code: "ErrorItemNotFound"}
+
+	merged := mergeCalendarViews([]*remote.ViewCalendarResponse{
+		{RemoteUserID: "a", Events: []*remote.Event{{ID: "a-work", Start: at(11)}}},
+		{RemoteUserID: "b", Error: apiError},
+		{RemoteUserID: "a", Error: apiError},
+		{RemoteUserID: "a", Events: []*remote.Event{{ID: "a-team", Start: at(9)}}},
+		{RemoteUserID: "c", Events: []*remote.Event{}},
+	})
+
+	require.Len(t, merged, 3)
+	require.Equal(t, "a", merged[0].RemoteUserID)
+	require.Nil(t, merged[0].Error)
+	require.Equal(t, []*remote.Event{{ID: "a-team", Start: at(9)}, {ID: "a-work", Start: at(11)}}, merged[0].Events)
+	require.Equal(t, "b", merged[1].RemoteUserID)
+	require.Equal(t, apiError, merged[1].Error)
+	require.Equal(t, "c", merged[2].RemoteUserID)
+	require.Nil(t, merged[2].Error)
+}
+
 func TestCreateCalendar(t *testing.T) {
 	mscalendar, mockStore, _, _, _, mockClient, _ := GetMockSetup(t)
 
@@ -218,7 +269,7 @@ func TestCreateEvent(t *testing.T) {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
 				mockPoster.EXPECT().DM(MockMMUserID, gomock.AssignableToTypeOf(""), "testDisplayName", "testDisplayName", "testCommandTrigger").Return("", fmt.Errorf("error creating DM")).Times(1)
 				mockLogger.EXPECT().Warnf("CreateEvent error creating DM. err=%v", gomock.Any())
-				mockClient.EXPECT().CreateEvent(gomock.Any()).Return(&remote.Event{}, nil).Times(1)
+				mockClient.EXPECT().CreateEvent("", gomock.Any()).Return(&remote.Event{}, nil).Times(1)
 			},
 			assertions: func(t *testing.T, createdEvent *remote.Event, err error) {
 				require.NoError(t, err)
@@ -234,7 +285,7 @@ func TestCreateEvent(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockMMUserID).Return(nil, errors.New("not found")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
 				mockPoster.EXPECT().DM(MockMMUserID, gomock.AssignableToTypeOf(""), "testDisplayName", "testDisplayName", "testCommandTrigger").Return("", fmt.Errorf("error creating DM")).Times(1).Return("", nil)
-				mockClient.EXPECT().CreateEvent(&remote.Event{Subject: MockEventName}).Return(nil, fmt.Errorf("error creating event")).Times(1)
+				mockClient.EXPECT().CreateEvent("", &remote.Event{Subject: MockEventName}).Return(nil, fmt.Errorf("error creating event")).Times(1)
 			},
 			assertions: func(t *testing.T, createdEvent *remote.Event, err error) {
 				require.EqualError(t, err, "error creating event")
@@ -254,7 +305,7 @@ func TestCreateEvent(t *testing.T) {
 				mockStore.EXPECT().LoadUser(MockMMUserID).Return(nil, errors.New("not found")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
 				mockPoster.EXPECT().DM(MockMMUserID, gomock.AssignableToTypeOf(""), "testDisplayName", "testDisplayName", "testCommandTrigger").Return("", fmt.Errorf("error creating DM")).Times(1).Return("", nil)
-				mockClient.EXPECT().CreateEvent(&remote.Event{
+				mockClient.EXPECT().CreateEvent("", &remote.Event{
 					Subject:   MockEventName,
 					Location:  &remote.Location{DisplayName: "Test Location"},
 					Start:     &remote.DateTime{DateTime: "2024-10-01T09:00:00", TimeZone: "UTC"},
@@ -268,6 +319,23 @@ func TestCreateEvent(t *testing.T) {
 				require.Equal(t, &remote.Event{Subject: "Created Test Event", ID: "123"}, createdEvent)
 			},
 		},
+		{
+			name:  "online meeting defaults to Teams",
+			user:  GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, nil),
+			event: &remote.Event{Subject: MockEventName, IsOnlineMeeting: true},
+			setupMock: func() {
+				mockStore.EXPECT().LoadUser(MockMMUserID).Return(&store.User{}, nil).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
+				mockClient.EXPECT().CreateEvent("", &remote.Event{
+					Subject:               MockEventName,
+					IsOnlineMeeting:       true,
+					OnlineMeetingProvider: remote.OnlineMeetingProviderTeams,
+				}).Return(&remote.Event{ID: "123"}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, createdEvent *remote.Event, err error) {
+				require.NoError(t, err)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -278,6 +346,144 @@ func TestCreateEvent(t *testing.T) {
 	}
 }
 
+func TestUpdateEvent(t *testing.T) {
+	mscalendar, mockStore, mockPoster, _, _, mockClient, _ := GetMockSetup(t)
+	update := &remote.Event{Subject: "Moved event"}
+	start := &remote.DateTime{DateTime: "2024-10-01T09:00:00", TimeZone: "UTC"}
+	end := &remote.DateTime{DateTime: "2024-10-01T10:00:00", TimeZone: "UTC"}
+
+	tests := []struct {
+		name       string
+		setupMock  func()
+		assertions func(t *testing.T, updatedEvent *remote.Event, err error)
+	}{
+		{
+			name: "error updating event",
+			setupMock: func() {
+				mockClient.EXPECT().UpdateEvent(MockRemoteUserID, MockEventID, update).Return(nil, fmt.Errorf("error updating event")).Times(1)
+			},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				require.EqualError(t, err, "error updating event")
+			},
+		},
+		{
+			name: "attendee update doesn't notify linked channels",
+			setupMock: func() {
+				mockClient.EXPECT().UpdateEvent(MockRemoteUserID, MockEventID, update).Return(&remote.Event{ID: MockEventID, ICalUID: MockICalUID, Subject: "Moved event"}, nil).Times(1)
+			},
+			assertions: func(t *testing.T, updatedEvent *remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "Moved event", updatedEvent.Subject)
+			},
+		},
+		{
+			name: "organizer update notifies linked channels",
+			setupMock: func() {
+				mockClient.EXPECT().UpdateEvent(MockRemoteUserID, MockEventID, update).Return(&remote.Event{ID: MockEventID, ICalUID: MockICalUID, Subject: "Moved event", IsOrganizer: true, Start: start, End: end}, nil).Times(1)
+				mockStore.EXPECT().LoadEventMetadata(MockICalUID).Return(&store.EventMetadata{LinkedChannelIDs: map[string]struct{}{mockChannelID: {}}}, nil).Times(1)
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockPoster.EXPECT().CreatePost(gomock.Any()).DoAndReturn(func(post *model.Post) error {
+					require.Equal(t, mockChannelID, post.ChannelId)
+					require.Equal(t, "The event **Moved event** was updated by @"+MockMMUsername, post.Message)
+					return nil
+				}).Times(1)
+			},
+			assertions: func(t *testing.T, updatedEvent *remote.Event, err error) {
+				require.NoError(t, err)
+				require.True(t, updatedEvent.IsOrganizer)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			updatedEvent, err := mscalendar.UpdateEvent(GetMockUserWithDefaultDailySummaryUserSettings(), MockEventID, update)
+			tt.assertions(t, updatedEvent, err)
+		})
+	}
+}
+
+func TestCancelEvent(t *testing.T) {
+	mscalendar, mockStore, mockPoster, _, _, mockClient, _ := GetMockSetup(t)
+	attendees := []*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "attendee1@example.com"}}}
+	start := &remote.DateTime{DateTime: "2024-10-01T09:00:00", TimeZone: "UTC"}
+	end := &remote.DateTime{DateTime: "2024-10-01T10:00:00", TimeZone: "UTC"}
+
+	tests := []struct {
+		name       string
+		setupMock  func()
+		assertions func(t *testing.T, canceledEvent *remote.Event, err error)
+	}{
+		{
+			name: "error getting event",
+			setupMock: func() {
+				mockClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(nil, fmt.Errorf("404 Not Found")).Times(1)
+			},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				require.EqualError(t, err, "404 Not Found")
+			},
+		},
+		{
+			name: "organizer cancels meeting",
+			setupMock: func() {
+				mockClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(&remote.Event{ID: MockEventID, ICalUID: MockICalUID, IsOrganizer: true, Attendees: attendees}, nil).Times(1)
+				mockClient.EXPECT().CancelEvent(MockRemoteUserID, MockEventID, "See you next week").Return(nil).Times(1)
+				mockStore.EXPECT().LoadEventMetadata(MockICalUID).Return(nil, store.ErrNotFound).Times(1)
+				mockStore.EXPECT().DeleteEventMetadata(MockICalUID).Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, canceledEvent *remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, MockEventID, canceledEvent.ID)
+			},
+		},
+		{
+			name: "error cancelling meeting",
+			setupMock: func() {
+				mockClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(&remote.Event{ID: MockEventID, ICalUID: MockICalUID, IsOrganizer: true, Attendees: attendees}, nil).Times(1)
+				mockClient.EXPECT().CancelEvent(MockRemoteUserID, MockEventID, "See you next week").Return(fmt.Errorf("error cancelling event")).Times(1)
+			},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				require.EqualError(t, err, "error cancelling event")
+			},
+		},
+		{
+			name: "attendee deletes meeting",
+			setupMock: func() {
+				mockClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(&remote.Event{ID: MockEventID, ICalUID: MockICalUID, Attendees: attendees}, nil).Times(1)
+				mockClient.EXPECT().DeleteEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "organizer deletes event linked to a channel",
+			setupMock: func() {
+				mockClient.EXPECT().GetEvent(MockRemoteUserID, MockEventID).Return(&remote.Event{ID: MockEventID, ICalUID: MockICalUID, Subject: MockEventName, IsOrganizer: true, Start: start, End: end}, nil).Times(1)
+				mockClient.EXPECT().DeleteEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockStore.EXPECT().LoadEventMetadata(MockICalUID).Return(&store.EventMetadata{LinkedChannelIDs: map[string]struct{}{mockChannelID: {}}}, nil).Times(1)
+				mockClient.EXPECT().GetMailboxSettings(MockRemoteUserID).Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil).Times(1)
+				mockPoster.EXPECT().CreatePost(gomock.Any()).DoAndReturn(func(post *model.Post) error {
+					require.Equal(t, mockChannelID, post.ChannelId)
+					require.Equal(t, "The event **"+MockEventName+"** was canceled by @"+MockMMUsername, post.Message)
+					return nil
+				}).Times(1)
+				mockStore.EXPECT().DeleteEventMetadata(MockICalUID).Return(nil).Times(1)
+			},
+			assertions: func(t *testing.T, _ *remote.Event, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			canceledEvent, err := mscalendar.CancelEvent(GetMockUserWithDefaultDailySummaryUserSettings(), MockEventID, "See you next week")
+			tt.assertions(t, canceledEvent, err)
+		})
+	}
+}
+
 func TestDeleteCalendar(t *testing.T) {
 	mscalendar, mockStore, _, _, mockPluginAPI, mockClient, _ := GetMockSetup(t)
 	user := GetMockUser(nil, nil, MockMMUserID, nil)
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 )
 
 type Client interface {
@@ -20,9 +21,51 @@ func (m *mscalendar) MakeClient() (remote.Client, error) {
 		return nil, err
 	}
 
-	return m.Remote.MakeUserClient(context.Background(), m.actingUser.OAuth2Token, m.actingUser.MattermostUserID, m.Poster, m.Store)
+	client, err := m.Remote.MakeUserClient(m.clientContext(), m.actingUser.OAuth2Token, m.actingUser.MattermostUserID, m.Poster, m.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteUserID := ""
+	if m.actingUser.Remote != nil {
+		remoteUserID = m.actingUser.Remote.ID
+	}
+	return m.withEventMirror(client, remoteUserID), nil
 }
 
 func (m *mscalendar) MakeSuperuserClient() (remote.Client, error) {
-	return m.Remote.MakeSuperuserClient(context.Background())
+	client, err := m.Remote.MakeSuperuserClient(m.clientContext())
+	if err != nil {
+		return nil, err
+	}
+	return m.withEventMirror(client, ""), nil
+}
+
+// clientContext returns the context of the clients made by the engine,
+// which count their throttled responses in the counter of the engine.
+func (m *mscalendar) clientContext() context.Context {
+	ctx := context.Background()
+	if m.throttled != nil {
+		ctx = remote.WithThrottleCounter(ctx, m.throttled)
+	}
+	if m.background {
+		return remote.BackgroundContext(ctx)
+	}
+	return ctx
+}
+
+// requiresUserClient tells whether the calendar of the user can only be read
+// with their own client, even when a superuser client is available.
+func (m *mscalendar) requiresUserClient(user *store.User) bool {
+	r, ok := m.Remote.(remote.UserClientRequirer)
+	return ok && r.RequiresUserClient(user.OAuth2Token)
+}
+
+// ThrottledRequests returns the number of responses throttled by the remote
+// for the clients made by the engine so far.
+func (m *mscalendar) ThrottledRequests() int64 {
+	if m.throttled == nil {
+		return 0
+	}
+	return m.throttled.Load()
 }
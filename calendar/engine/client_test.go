@@ -0,0 +1,53 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestMakeSuperuserClientContext(t *testing.T) {
+	m, _, _, mockRemote, _, mockClient, _ := GetMockSetup(t)
+
+	mockRemote.EXPECT().MakeSuperuserClient(gomock.Any()).DoAndReturn(func(ctx context.Context) (remote.Client, error) {
+		require.False(t, remote.IsBackground(ctx))
+		return mockClient, nil
+	})
+	_, err := m.MakeSuperuserClient()
+	require.NoError(t, err)
+
+	// Jobs can wait for a throttled remote
+	job := NewBackground(m.Env, "").(*mscalendar)
+	mockRemote.EXPECT().MakeSuperuserClient(gomock.Any()).DoAndReturn(func(ctx context.Context) (remote.Client, error) {
+		require.True(t, remote.IsBackground(ctx))
+		return mockClient, nil
+	})
+	_, err = job.MakeSuperuserClient()
+	require.NoError(t, err)
+}
+
+func TestThrottledRequests(t *testing.T) {
+	m, _, _, mockRemote, _, mockClient, _ := GetMockSetup(t)
+
+	// Each job counts the responses throttled for its own clients
+	job := NewBackground(m.Env, "").(*mscalendar)
+	other := NewBackground(m.Env, "").(*mscalendar)
+	mockRemote.EXPECT().MakeSuperuserClient(gomock.Any()).DoAndReturn(func(ctx context.Context) (remote.Client, error) {
+		remote.CountThrottled(ctx)
+		remote.CountThrottled(ctx)
+		return mockClient, nil
+	})
+	_, err := job.MakeSuperuserClient()
+	require.NoError(t, err)
+
+	require.EqualValues(t, 2, job.ThrottledRequests())
+	require.EqualValues(t, 0, other.ThrottledRequests())
+	require.EqualValues(t, 0, m.ThrottledRequests())
+}
@@ -138,7 +138,7 @@ func (m *mscalendar) ProcessAllDailySummary(now time.Time) error {
 			continue
 		}
 
-		if fetchIndividually {
+		if fetchIndividually || m.requiresUserClient(storeUser) {
 			u := NewUser(storeUser.MattermostUserID)
 			if err := m.ExpandUser(u); err != nil {
 				m.Logger.With(bot.LogContext{
@@ -179,21 +179,16 @@ func (m *mscalendar) ProcessAllDailySummary(now time.Time) error {
 			})
 		} else {
 			start, end := getTodayHoursForTimezone(now, dsum.Timezone)
-			req := &remote.ViewCalendarParams{
-				RemoteUserID: storeUser.Remote.ID,
-				StartTime:    start,
-				EndTime:      end,
-			}
-			requests = append(requests, req)
+			requests = append(requests, calendarViewParams(storeUser, start, end)...)
 		}
 	}
 
 	if !fetchIndividually {
-		var err error
-		calendarViews, err = m.client.DoBatchViewCalendarRequests(requests)
+		batchViews, err := m.client.DoBatchViewCalendarRequests(requests)
 		if err != nil {
 			return err
 		}
+		calendarViews = append(calendarViews, mergeCalendarViews(batchViews)...)
 	}
 
 	for _, res := range calendarViews {
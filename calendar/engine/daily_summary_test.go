@@ -4,7 +4,6 @@
 package engine
 
 import (
-	"context"
 	"errors"
 	"testing"
 	"time"
@@ -69,7 +68,7 @@ func TestGetDaySummaryForUser(t *testing.T) {
 
 		mockPluginAPI.EXPECT().GetMattermostUser(user.MattermostUserID)
 
-		mockRemote.EXPECT().MakeUserClient(context.Background(), nil, gomock.Any(), poster, gomock.Any()).Return(mockClient, nil)
+		mockRemote.EXPECT().MakeUserClient(gomock.Any(), nil, gomock.Any(), poster, gomock.Any()).Return(mockClient, nil)
 
 		mockClient.EXPECT().GetMailboxSettings("user1_remote_id").Return(&remote.MailboxSettings{
 			TimeZone: "Pacific Standard Time",
@@ -151,7 +150,7 @@ func TestProcessAllDailySummary(t *testing.T) {
 
 				mockClient := client.(*mock_remote.MockClient)
 				mockRemote := deps.Remote.(*mock_remote.MockRemote)
-				mockRemote.EXPECT().MakeSuperuserClient(context.Background()).Return(mockClient, nil).Times(1)
+				mockRemote.EXPECT().MakeSuperuserClient(gomock.Any()).Return(mockClient, nil).Times(1)
 
 				mockClient.EXPECT().DoBatchViewCalendarRequests(gomock.Any()).Return([]*remote.ViewCalendarResponse{}, errors.New("error fetching events"))
 			},
@@ -230,7 +229,7 @@ func TestProcessAllDailySummary(t *testing.T) {
 					}},
 				}, nil)
 				mockRemote := deps.Remote.(*mock_remote.MockRemote)
-				mockRemote.EXPECT().MakeSuperuserClient(context.Background()).Return(mockClient, nil).Times(1)
+				mockRemote.EXPECT().MakeSuperuserClient(gomock.Any()).Return(mockClient, nil).Times(1)
 
 				mockPoster := deps.Poster.(*mock_bot.MockPoster)
 				gomock.InOrder(
@@ -277,7 +276,7 @@ Wednesday February 12, 2020
 					RemoteID:         "user3_remote_id",
 				}}, nil)
 
-				mockRemote.EXPECT().MakeSuperuserClient(context.Background()).Return(nil, remote.ErrSuperUserClientNotSupported).Times(1)
+				mockRemote.EXPECT().MakeSuperuserClient(gomock.Any()).Return(nil, remote.ErrSuperUserClientNotSupported).Times(1)
 
 				s.EXPECT().LoadUser("user1_mm_id").Return(&store.User{
 					MattermostUserID: "user1_mm_id",
@@ -328,7 +327,7 @@ Wednesday February 12, 2020
 					TimeZone: "Pacific Standard Time",
 				}, nil)
 
-				mockRemote.EXPECT().MakeUserClient(context.Background(), nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil).Times(2)
+				mockRemote.EXPECT().MakeUserClient(gomock.Any(), nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(mockClient, nil).Times(2)
 
 				mockClient.EXPECT().GetDefaultCalendarView("user1_remote_id", gomock.Any(), gomock.Any()).Return([]*remote.Event{}, nil)
 				mockClient.EXPECT().GetDefaultCalendarView("user2_remote_id", gomock.Any(), gomock.Any()).Return([]*remote.Event{
@@ -0,0 +1,203 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+var errNotDelegate = errors.New("the calendar is not shared with the user")
+
+// ViewCalendarAs returns the events of the calendar of the principal, shared
+// with or delegated to the user. Private events of the principal are
+// returned as busy time.
+func (m *mscalendar) ViewCalendarAs(user *User, principalUsername string, from, to time.Time) ([]*remote.Event, error) {
+	_, cal, err := m.sharedCalendar(user, principalUsername)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := m.client.GetCalendarView(user.Remote.ID, cal.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i, event := range events {
+		events[i] = views.ForOthers(event)
+	}
+	return events, nil
+}
+
+// AddDelegate makes the user a delegate of the principal, who then receives
+// their new invitations and can respond to them on their behalf. The
+// calendar of the principal must be shared with or delegated to the user.
+func (m *mscalendar) AddDelegate(user *User, principalUsername string) error {
+	principal, _, err := m.sharedCalendar(user, principalUsername)
+	if err != nil {
+		return err
+	}
+
+	if !principal.AddDelegate(user.MattermostUserID) {
+		return nil
+	}
+	return m.Store.StoreUser(principal)
+}
+
+// RemoveDelegate stops sending the new invitations of the principal to the
+// user. It needs no access to the calendar of the principal, which may
+// already have been revoked.
+func (m *mscalendar) RemoveDelegate(user *User, principalUsername string) error {
+	principal, err := m.loadPrincipal(principalUsername)
+	if err != nil {
+		return err
+	}
+
+	if !principal.RemoveDelegate(user.MattermostUserID) {
+		return errors.Errorf("you are not a delegate of @%s", strings.TrimPrefix(principalUsername, "@"))
+	}
+	return m.Store.StoreUser(principal)
+}
+
+// sharedCalendar returns the principal and their calendar shared with or
+// delegated to the user.
+func (m *mscalendar) sharedCalendar(user *User, principalUsername string) (*store.User, *remote.SharedCalendar, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	principal, err := m.loadPrincipal(principalUsername)
+	if err != nil {
+		return nil, nil, err
+	}
+	calendars, err := m.client.GetSharedCalendars(user.Remote.ID)
+	if errors.Is(err, remote.ErrNotImplemented) {
+		return nil, nil, errors.Errorf("shared calendars are not supported by %s", m.Provider.DisplayName)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	cal := sharedCalendarOf(calendars, principal.Remote)
+	if cal == nil {
+		return nil, nil, errors.Errorf("the calendar of @%s is not shared with you", strings.TrimPrefix(principalUsername, "@"))
+	}
+	return principal, cal, nil
+}
+
+func (m *mscalendar) loadPrincipal(principalUsername string) (*store.User, error) {
+	username := strings.TrimPrefix(principalUsername, "@")
+	mattermostUser, err := m.PluginAPI.GetMattermostUserByUsername(username)
+	if err != nil {
+		return nil, errors.Errorf("user @%s not found", username)
+	}
+	principal, err := m.Store.LoadUser(mattermostUser.Id)
+	if err != nil {
+		return nil, errors.Errorf("@%s is not connected to %s", username, m.Provider.DisplayName)
+	}
+	return principal, nil
+}
+
+// sharedCalendarOf returns the calendar owned by the given user, if any.
+func sharedCalendarOf(calendars []*remote.SharedCalendar, owner *remote.User) *remote.SharedCalendar {
+	if owner == nil || owner.Mail == "" && owner.UserPrincipalName == "" {
+		return nil
+	}
+	for _, cal := range calendars {
+		if cal.OwnerMail == "" {
+			continue
+		}
+		if strings.EqualFold(cal.OwnerMail, owner.Mail) || strings.EqualFold(cal.OwnerMail, owner.UserPrincipalName) {
+			return cal
+		}
+	}
+	return nil
+}
+
+// notifyDelegates sends a new invitation of the principal to their
+// delegates, with response actions acting on behalf of the principal.
+// Delegates who lost access to the calendar of the principal are forgotten.
+func (processor *notificationProcessor) notifyDelegates(principal *store.User, n *remote.Notification) {
+	removed := false
+	for _, delegateID := range append([]string{}, principal.Delegates...) {
+		err := processor.notifyDelegate(principal, delegateID, n)
+		if errors.Is(err, errNotDelegate) {
+			principal.RemoveDelegate(delegateID)
+			removed = true
+			continue
+		}
+		if err != nil {
+			processor.Logger.With(bot.LogContext{
+				"MattermostUserID": principal.MattermostUserID,
+				"DelegateID":       delegateID,
+				"err":              err,
+			}).Warnf("webhook notification: failed to notify delegate.")
+		}
+	}
+
+	if removed {
+		if err := processor.Store.StoreUser(principal); err != nil {
+			processor.Logger.Warnf("Failed to store the delegates of user %s. err=%v", principal.MattermostUserID, err)
+		}
+	}
+}
+
+func (processor *notificationProcessor) notifyDelegate(principal *store.User, delegateID string, n *remote.Notification) error {
+	delegate, err := processor.Store.LoadUser(delegateID)
+	if errors.Is(err, store.ErrNotFound) {
+		return errNotDelegate
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := processor.Remote.MakeUserClient(remote.BackgroundContext(context.Background()), delegate.OAuth2Token, delegateID, processor.Poster, processor.Store)
+	if err != nil {
+		return errors.Wrap(err, "unable to build delegate client")
+	}
+	calendars, err := client.GetSharedCalendars(delegate.Remote.ID)
+	if err != nil {
+		return err
+	}
+	if sharedCalendarOf(calendars, principal.Remote) == nil {
+		return errNotDelegate
+	}
+	mailSettings, err := client.GetMailboxSettings(delegate.Remote.ID)
+	if err != nil {
+		return err
+	}
+
+	masked := *n
+	masked.Event = views.ForOthers(n.Event)
+	sa := processor.newEventSlackAttachment(&masked, mailSettings.TimeZone)
+	sa.Pretext = fmt.Sprintf("New invitation for %s", principalName(principal))
+	for _, action := range sa.Actions {
+		action.Integration.Context[config.PrincipalIDKey] = principal.MattermostUserID
+	}
+
+	_, err = processor.Poster.DMWithAttachments(delegateID, sa)
+	return err
+}
+
+func principalName(principal *store.User) string {
+	if principal.MattermostUsername != "" {
+		return "@" + principal.MattermostUsername
+	}
+	if principal.Remote != nil && principal.Remote.DisplayName != "" {
+		return principal.Remote.DisplayName
+	}
+	return "your principal"
+}
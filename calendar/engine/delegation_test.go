@@ -0,0 +1,243 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+)
+
+func TestViewCalendarAs(t *testing.T) {
+	from := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 14)
+	principal := func(delegates ...string) *store.User {
+		return &store.User{
+			MattermostUserID: "principalID",
+			Remote:           &remote.User{ID: "principalRemoteID", Mail: "Manager@example.com"},
+			Delegates:        delegates,
+		}
+	}
+	shared := []*remote.SharedCalendar{
+		{ID: "other", OwnerMail: "other@example.com"},
+		{ID: "managerCalendar", OwnerMail: "manager@example.com"},
+	}
+
+	tests := []struct {
+		name      string
+		setupMock func(*mock_store.MockStore, *mock_plugin_api.MockPluginAPI, *mock_remote.MockClient)
+		assertion func([]*remote.Event, error)
+	}{
+		{
+			name: "unknown principal",
+			setupMock: func(mockStore *mock_store.MockStore, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockClient *mock_remote.MockClient) {
+				mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(nil, store.ErrNotFound)
+			},
+			assertion: func(events []*remote.Event, err error) {
+				require.EqualError(t, err, "user @manager not found")
+			},
+		},
+		{
+			name: "remote without shared calendars",
+			setupMock: func(mockStore *mock_store.MockStore, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockClient *mock_remote.MockClient) {
+				mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+				mockStore.EXPECT().LoadUser("principalID").Return(principal(), nil)
+				mockClient.EXPECT().GetSharedCalendars(MockRemoteUserID).Return(nil, remote.ErrNotImplemented)
+			},
+			assertion: func(events []*remote.Event, err error) {
+				require.EqualError(t, err, "shared calendars are not supported by testDisplayName")
+			},
+		},
+		{
+			name: "calendar not shared",
+			setupMock: func(mockStore *mock_store.MockStore, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockClient *mock_remote.MockClient) {
+				mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+				mockStore.EXPECT().LoadUser("principalID").Return(principal(), nil)
+				mockClient.EXPECT().GetSharedCalendars(MockRemoteUserID).Return(shared[:1], nil)
+			},
+			assertion: func(events []*remote.Event, err error) {
+				require.EqualError(t, err, "the calendar of @manager is not shared with you")
+			},
+		},
+		{
+			name: "shared calendar does not make the user a delegate",
+			setupMock: func(mockStore *mock_store.MockStore, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockClient *mock_remote.MockClient) {
+				mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+				mockStore.EXPECT().LoadUser("principalID").Return(principal(), nil)
+				mockClient.EXPECT().GetSharedCalendars(MockRemoteUserID).Return(shared, nil)
+				mockClient.EXPECT().GetCalendarView(MockRemoteUserID, "managerCalendar", from, to).Return([]*remote.Event{{ID: MockEventID}}, nil)
+			},
+			assertion: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*remote.Event{{ID: MockEventID}}, events)
+			},
+		},
+		{
+			name: "private events are shown as busy",
+			setupMock: func(mockStore *mock_store.MockStore, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockClient *mock_remote.MockClient) {
+				mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+				mockStore.EXPECT().LoadUser("principalID").Return(principal(MockMMUserID), nil)
+				mockClient.EXPECT().GetSharedCalendars(MockRemoteUserID).Return(shared, nil)
+				mockClient.EXPECT().GetCalendarView(MockRemoteUserID, "managerCalendar", from, to).Return([]*remote.Event{
+					{ID: "public", Subject: "Team sync"},
+					{ID: "private", Subject: "Doctor", Sensitivity: remote.SensitivityPrivate, Location: &remote.Location{DisplayName: "Clinic"}},
+				}, nil)
+			},
+			assertion: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*remote.Event{
+					{ID: "public", Subject: "Team sync"},
+					{ID: "private", Subject: "Busy", Sensitivity: remote.SensitivityPrivate},
+				}, events)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mscalendar, mockStore, _, _, mockPluginAPI, mockClient, _ := GetMockSetup(t)
+			user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+			tt.setupMock(mockStore, mockPluginAPI, mockClient)
+
+			events, err := mscalendar.ViewCalendarAs(user, "@manager", from, to)
+
+			tt.assertion(events, err)
+		})
+	}
+}
+
+func TestAddDelegate(t *testing.T) {
+	principal := func(delegates ...string) *store.User {
+		return &store.User{
+			MattermostUserID: "principalID",
+			Remote:           &remote.User{ID: "principalRemoteID", Mail: "manager@example.com"},
+			Delegates:        delegates,
+		}
+	}
+	shared := []*remote.SharedCalendar{{ID: "managerCalendar", OwnerMail: "manager@example.com"}}
+
+	tests := []struct {
+		name      string
+		setupMock func(*mock_store.MockStore, *mock_plugin_api.MockPluginAPI, *mock_remote.MockClient)
+		assertion func(error)
+	}{
+		{
+			name: "calendar not shared",
+			setupMock: func(mockStore *mock_store.MockStore, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockClient *mock_remote.MockClient) {
+				mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+				mockStore.EXPECT().LoadUser("principalID").Return(principal(), nil)
+				mockClient.EXPECT().GetSharedCalendars(MockRemoteUserID).Return([]*remote.SharedCalendar{}, nil)
+			},
+			assertion: func(err error) {
+				require.EqualError(t, err, "the calendar of @manager is not shared with you")
+			},
+		},
+		{
+			name: "shared calendar",
+			setupMock: func(mockStore *mock_store.MockStore, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockClient *mock_remote.MockClient) {
+				mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+				mockStore.EXPECT().LoadUser("principalID").Return(principal(), nil)
+				mockClient.EXPECT().GetSharedCalendars(MockRemoteUserID).Return(shared, nil)
+				mockStore.EXPECT().StoreUser(principal(MockMMUserID)).Return(nil)
+			},
+			assertion: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "existing delegate is not stored again",
+			setupMock: func(mockStore *mock_store.MockStore, mockPluginAPI *mock_plugin_api.MockPluginAPI, mockClient *mock_remote.MockClient) {
+				mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+				mockStore.EXPECT().LoadUser("principalID").Return(principal(MockMMUserID), nil)
+				mockClient.EXPECT().GetSharedCalendars(MockRemoteUserID).Return(shared, nil)
+			},
+			assertion: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mscalendar, mockStore, _, _, mockPluginAPI, mockClient, _ := GetMockSetup(t)
+			user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+			tt.setupMock(mockStore, mockPluginAPI, mockClient)
+
+			tt.assertion(mscalendar.AddDelegate(user, "@manager"))
+		})
+	}
+}
+
+func TestRemoveDelegate(t *testing.T) {
+	principal := func(delegates ...string) *store.User {
+		return &store.User{
+			MattermostUserID: "principalID",
+			Remote:           &remote.User{ID: "principalRemoteID"},
+			Delegates:        delegates,
+		}
+	}
+
+	t.Run("delegate", func(t *testing.T) {
+		mscalendar, mockStore, _, _, mockPluginAPI, _, _ := GetMockSetup(t)
+		user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+		mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+		mockStore.EXPECT().LoadUser("principalID").Return(principal(MockMMUserID, "deputy"), nil)
+		mockStore.EXPECT().StoreUser(principal("deputy")).Return(nil)
+
+		require.NoError(t, mscalendar.RemoveDelegate(user, "@manager"))
+	})
+
+	t.Run("not a delegate", func(t *testing.T) {
+		mscalendar, mockStore, _, _, mockPluginAPI, _, _ := GetMockSetup(t)
+		user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+		mockPluginAPI.EXPECT().GetMattermostUserByUsername("manager").Return(&model.User{Id: "principalID"}, nil)
+		mockStore.EXPECT().LoadUser("principalID").Return(principal("deputy"), nil)
+
+		require.EqualError(t, mscalendar.RemoveDelegate(user, "@manager"), "you are not a delegate of @manager")
+	})
+}
+
+func TestRespondToEventAs(t *testing.T) {
+	principal := &store.User{
+		MattermostUserID: "principalID",
+		Remote:           &remote.User{ID: "principalRemoteID"},
+		Delegates:        []string{MockMMUserID},
+	}
+
+	t.Run("delegate responds on behalf of the principal", func(t *testing.T) {
+		mscalendar, mockStore, _, _, _, mockClient, _ := GetMockSetup(t)
+		user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+		mockStore.EXPECT().LoadUser("principalID").Return(principal, nil)
+		mockClient.EXPECT().AcceptEvent("principalRemoteID", MockEventID, gomock.Any()).Return(nil)
+
+		require.NoError(t, mscalendar.RespondToEventAs(user, "principalID", MockEventID, OptionYes, nil))
+	})
+
+	t.Run("other users cannot respond", func(t *testing.T) {
+		mscalendar, mockStore, _, _, _, _, _ := GetMockSetup(t)
+		user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), "otherUserID", GetMockStoreSettings())
+		mockStore.EXPECT().LoadUser("principalID").Return(principal, nil)
+
+		require.EqualError(t, mscalendar.RespondToEventAs(user, "principalID", MockEventID, OptionNo, nil), "not a delegate of the principal")
+	})
+}
+
+func TestSharedCalendarOf(t *testing.T) {
+	calendars := []*remote.SharedCalendar{
+		{ID: "noOwner"},
+		{ID: "upn", OwnerMail: "Manager@Contoso.onmicrosoft.com"},
+	}
+
+	require.Nil(t, sharedCalendarOf(calendars, nil))
+	require.Nil(t, sharedCalendarOf(calendars, &remote.User{}))
+	require.Equal(t, "upn", sharedCalendarOf(calendars, &remote.User{UserPrincipalName: "manager@contoso.onmicrosoft.com"}).ID)
+}
@@ -0,0 +1,293 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const (
+	// mirrorSyncInterval is how long a mirror is read without asking the
+	// remote for changes. The status sync job reads every mirror once per
+	// run, and webhooks mark the mirror stale when the calendar changes.
+	mirrorSyncInterval = StatusSyncJobInterval
+
+	// A mirror holds the events from the day before today to
+	// mirrorDaysAhead days after it, in UTC. This covers today in every time
+	// zone, for status sync, reminders and daily summaries.
+	mirrorDaysBefore = 1
+	mirrorDaysAhead  = 15
+)
+
+// mirroredClient serves calendar views from the event mirror of the user,
+// synced with delta queries. Views outside of the mirrored window, and views
+// of remotes that don't support delta queries, are read from the remote.
+type mirroredClient struct {
+	remote.Client
+
+	store  store.EventMirrorStore
+	logger bot.Logger
+
+	// remoteUserID is the user acting with the client, empty for a
+	// superuser client.
+	remoteUserID string
+
+	now func() time.Time
+}
+
+// withEventMirror wraps the client to read calendar views from the event
+// mirror, when enabled.
+func (m *mscalendar) withEventMirror(client remote.Client, remoteUserID string) remote.Client {
+	if m.Config == nil || !m.EnableEventMirror {
+		return client
+	}
+	return &mirroredClient{
+		Client:       client,
+		store:        m.Store,
+		logger:       m.Logger,
+		remoteUserID: remoteUserID,
+		now:          time.Now,
+	}
+}
+
+func mirrorWindow(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today.AddDate(0, 0, -mirrorDaysBefore), today.AddDate(0, 0, mirrorDaysAhead)
+}
+
+func (c *mirroredClient) GetDefaultCalendarView(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	if events, ok := c.view(remoteUserID, start, end); ok {
+		return events, nil
+	}
+	return c.Client.GetDefaultCalendarView(remoteUserID, start, end)
+}
+
+func (c *mirroredClient) GetEventsBetweenDates(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	if events, ok := c.view(remoteUserID, start, end); ok {
+		return events, nil
+	}
+	return c.Client.GetEventsBetweenDates(remoteUserID, start, end)
+}
+
+// DoBatchViewCalendarRequests answers from the mirrors first, synced with
+// one batch of delta queries, and batches the remaining requests to the
+// remote. Views of a calendar other than the default one are not mirrored.
+// The order of the parameters is kept.
+func (c *mirroredClient) DoBatchViewCalendarRequests(allParams []*remote.ViewCalendarParams) ([]*remote.ViewCalendarResponse, error) {
+	now := c.now()
+	windowStart, windowEnd := mirrorWindow(now)
+	remoteUserIDs := []string{}
+	for _, params := range allParams {
+		if c.isMirrored(params.RemoteUserID, params.CalendarID, params.StartTime, params.EndTime, windowStart, windowEnd) {
+			remoteUserIDs = append(remoteUserIDs, params.RemoteUserID)
+		}
+	}
+	mirrors := c.syncMirrors(remoteUserIDs, windowStart, windowEnd, now)
+
+	result := make([]*remote.ViewCalendarResponse, len(allParams))
+	missed := []*remote.ViewCalendarParams{}
+	missedIndexes := []int{}
+	for i, params := range allParams {
+		mirror := mirrors[params.RemoteUserID]
+		if mirror == nil || !c.isMirrored(params.RemoteUserID, params.CalendarID, params.StartTime, params.EndTime, windowStart, windowEnd) {
+			missed = append(missed, params)
+			missedIndexes = append(missedIndexes, i)
+			continue
+		}
+		result[i] = &remote.ViewCalendarResponse{
+			RemoteUserID: params.RemoteUserID,
+			Events:       mirror.Between(params.StartTime, params.EndTime),
+		}
+	}
+	if len(missed) == 0 {
+		return result, nil
+	}
+
+	responses, err := c.Client.DoBatchViewCalendarRequests(missed)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range missedIndexes {
+		var res *remote.ViewCalendarResponse
+		if j < len(responses) {
+			res = responses[j]
+		}
+		if res == nil {
+			res = &remote.ViewCalendarResponse{
+				RemoteUserID: missed[j].RemoteUserID,
+				Error:        &remote.APIError{Message: "no response for the user"},
+			}
+		}
+		result[i] = res
+	}
+	return result, nil
+}
+
+func (c *mirroredClient) CreateEvent(calendarID string, calendarEvent *remote.Event) (*remote.Event, error) {
+	defer c.markStale(c.remoteUserID)
+	return c.Client.CreateEvent(calendarID, calendarEvent)
+}
+
+func (c *mirroredClient) UpdateEvent(remoteUserID, eventID string, calendarEvent *remote.Event) (*remote.Event, error) {
+	defer c.markStale(remoteUserID)
+	return c.Client.UpdateEvent(remoteUserID, eventID, calendarEvent)
+}
+
+func (c *mirroredClient) CancelEvent(remoteUserID, eventID, comment string) error {
+	defer c.markStale(remoteUserID)
+	return c.Client.CancelEvent(remoteUserID, eventID, comment)
+}
+
+func (c *mirroredClient) DeleteEvent(remoteUserID, eventID string) error {
+	defer c.markStale(remoteUserID)
+	return c.Client.DeleteEvent(remoteUserID, eventID)
+}
+
+func (c *mirroredClient) AcceptEvent(remoteUserID, eventID string, response *remote.EventResponse) error {
+	defer c.markStale(remoteUserID)
+	return c.Client.AcceptEvent(remoteUserID, eventID, response)
+}
+
+func (c *mirroredClient) DeclineEvent(remoteUserID, eventID string, response *remote.EventResponse) error {
+	defer c.markStale(remoteUserID)
+	return c.Client.DeclineEvent(remoteUserID, eventID, response)
+}
+
+func (c *mirroredClient) TentativelyAcceptEvent(remoteUserID, eventID string, response *remote.EventResponse) error {
+	defer c.markStale(remoteUserID)
+	return c.Client.TentativelyAcceptEvent(remoteUserID, eventID, response)
+}
+
+// view returns the events of the user between start and end from their
+// mirror, syncing it first if needed. It returns false when the view must be
+// read from the remote.
+func (c *mirroredClient) view(remoteUserID string, start, end time.Time) ([]*remote.Event, bool) {
+	now := c.now()
+	windowStart, windowEnd := mirrorWindow(now)
+	if !c.isMirrored(remoteUserID, "", start, end, windowStart, windowEnd) {
+		return nil, false
+	}
+
+	mirror := c.syncMirrors([]string{remoteUserID}, windowStart, windowEnd, now)[remoteUserID]
+	if mirror == nil {
+		return nil, false
+	}
+	return mirror.Between(start, end), true
+}
+
+// isMirrored tells whether a view is in the mirror of the user. Only the
+// default calendar is mirrored.
+func (c *mirroredClient) isMirrored(remoteUserID, calendarID string, start, end, windowStart, windowEnd time.Time) bool {
+	return remoteUserID != "" && calendarID == "" && !start.Before(windowStart) && !end.After(windowEnd)
+}
+
+// syncMirrors returns the mirrors of the users, synced with a batch of delta
+// queries when older than mirrorSyncInterval. The mirrors that could not be
+// synced are left out, for their views to be read from the remote. A failed
+// delta query keeps the delta link, so that a throttled user doesn't read
+// the whole window again: only an expired link does.
+func (c *mirroredClient) syncMirrors(remoteUserIDs []string, start, end, now time.Time) map[string]*store.EventMirror {
+	mirrors := map[string]*store.EventMirror{}
+	toSync := []*store.EventMirror{}
+	seen := map[string]bool{}
+	for _, remoteUserID := range remoteUserIDs {
+		if seen[remoteUserID] {
+			continue
+		}
+		seen[remoteUserID] = true
+
+		mirror, err := c.store.LoadEventMirror(remoteUserID)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			c.logger.Warnf("Failed to load the event mirror of remote user %s. err=%v", remoteUserID, err)
+			continue
+		}
+		// The window moves every day: start over rather than keeping events
+		// that left it.
+		if mirror == nil || !mirror.Start.Equal(start) || !mirror.End.Equal(end) {
+			mirror = store.NewEventMirror(remoteUserID, start, end)
+		}
+		if mirror.DeltaLink != "" && !mirror.Stale && now.Sub(mirror.SyncedAt) < mirrorSyncInterval {
+			mirrors[remoteUserID] = mirror
+			continue
+		}
+		toSync = append(toSync, mirror)
+	}
+
+	for len(toSync) > 0 {
+		params := []*remote.CalendarViewDeltaParams{}
+		byRemoteUserID := map[string]*store.EventMirror{}
+		for _, mirror := range toSync {
+			byRemoteUserID[mirror.RemoteUserID] = mirror
+			params = append(params, &remote.CalendarViewDeltaParams{
+				RemoteUserID: mirror.RemoteUserID,
+				StartTime:    start,
+				EndTime:      end,
+				DeltaLink:    mirror.DeltaLink,
+			})
+		}
+
+		responses, err := c.Client.DoBatchCalendarViewDeltaRequests(params)
+		if err != nil {
+			if !errors.Is(err, remote.ErrNotImplemented) {
+				c.logger.Warnf("Failed to sync %d event mirror(s). err=%v", len(params), err)
+			}
+			break
+		}
+
+		expired := []*store.EventMirror{}
+		for _, res := range responses {
+			mirror := byRemoteUserID[res.RemoteUserID]
+			if mirror == nil {
+				continue
+			}
+			if res.Error != nil || res.Delta == nil {
+				if res.Expired && mirror.DeltaLink != "" {
+					expired = append(expired, store.NewEventMirror(res.RemoteUserID, start, end))
+					continue
+				}
+				c.logger.Warnf("Failed to sync the event mirror of remote user %s. err=%v", res.RemoteUserID, res.Error)
+				continue
+			}
+
+			mirror.Apply(res.Delta, now)
+			if err = c.store.StoreEventMirror(mirror); err != nil {
+				c.logger.Warnf("Failed to store the event mirror of remote user %s. err=%v", res.RemoteUserID, err)
+			}
+			mirrors[res.RemoteUserID] = mirror
+		}
+		// Delta links expire: read the whole window of these users again.
+		toSync = expired
+	}
+	return mirrors
+}
+
+// markStale makes the next read of the mirror of the user sync it, to pick
+// up a change made through the plugin.
+func (c *mirroredClient) markStale(remoteUserID string) {
+	markEventMirrorStale(c.store, c.logger, remoteUserID)
+}
+
+// markEventMirrorStale makes the next read of the mirror of the user sync it,
+// when there is one.
+func markEventMirrorStale(s store.EventMirrorStore, logger bot.Logger, remoteUserID string) {
+	if remoteUserID == "" {
+		return
+	}
+
+	mirror, err := s.LoadEventMirror(remoteUserID)
+	if err != nil || mirror.Stale {
+		return
+	}
+	mirror.Stale = true
+	if err = s.StoreEventMirror(mirror); err != nil {
+		logger.Warnf("Failed to store the event mirror of remote user %s. err=%v", remoteUserID, err)
+	}
+}
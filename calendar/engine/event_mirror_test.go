@@ -0,0 +1,301 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+func TestMirroredClientView(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	windowStart, windowEnd := mirrorWindow(now)
+	start, end := now, now.Add(10*time.Minute)
+	meeting := &remote.Event{
+		ID:    "meeting",
+		Start: remote.NewDateTime(now.Add(5*time.Minute), "UTC"),
+		End:   remote.NewDateTime(now.Add(35*time.Minute), "UTC"),
+	}
+	later := &remote.Event{
+		ID:    "later",
+		Start: remote.NewDateTime(now.Add(2*time.Hour), "UTC"),
+		End:   remote.NewDateTime(now.Add(3*time.Hour), "UTC"),
+	}
+	deltaParams := func(deltaLink string) []*remote.CalendarViewDeltaParams {
+		return []*remote.CalendarViewDeltaParams{{RemoteUserID: MockRemoteUserID, StartTime: windowStart, EndTime: windowEnd, DeltaLink: deltaLink}}
+	}
+	deltaResponse := func(delta *remote.EventsDelta) []*remote.CalendarViewDeltaResponse {
+		return []*remote.CalendarViewDeltaResponse{{RemoteUserID: MockRemoteUserID, Delta: delta}}
+	}
+	syncedMirror := func(syncedAt time.Time) *store.EventMirror {
+		mirror := store.NewEventMirror(MockRemoteUserID, windowStart, windowEnd)
+		mirror.Apply(&remote.EventsDelta{DeltaLink: "link", Events: []*remote.Event{meeting, later}}, syncedAt)
+		return mirror
+	}
+
+	tests := []struct {
+		name       string
+		start      time.Time
+		end        time.Time
+		setup      func(*mock_store.MockStore, *mock_remote.MockClient)
+		assertions func([]*remote.Event, error)
+	}{
+		{
+			name:  "first read loads the whole window",
+			start: start,
+			end:   end,
+			setup: func(mockStore *mock_store.MockStore, mockClient *mock_remote.MockClient) {
+				mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(nil, store.ErrNotFound)
+				mockClient.EXPECT().DoBatchCalendarViewDeltaRequests(deltaParams("")).Return(deltaResponse(&remote.EventsDelta{
+					DeltaLink: "link",
+					Events:    []*remote.Event{meeting, later},
+					Reset:     true,
+				}), nil)
+				mockStore.EXPECT().StoreEventMirror(gomock.Any()).DoAndReturn(func(mirror *store.EventMirror) error {
+					require.Equal(t, "link", mirror.DeltaLink)
+					require.Len(t, mirror.Events, 2)
+					return nil
+				})
+			},
+			assertions: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*remote.Event{meeting}, events)
+			},
+		},
+		{
+			name:  "mirror synced since the last job run is read without a remote call",
+			start: start,
+			end:   end,
+			setup: func(mockStore *mock_store.MockStore, _ *mock_remote.MockClient) {
+				mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(syncedMirror(now.Add(-4*time.Minute)), nil)
+			},
+			assertions: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*remote.Event{meeting}, events)
+			},
+		},
+		{
+			name:  "old mirror applies the changes since its delta link",
+			start: start,
+			end:   end,
+			setup: func(mockStore *mock_store.MockStore, mockClient *mock_remote.MockClient) {
+				mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(syncedMirror(now.Add(-time.Hour)), nil)
+				mockClient.EXPECT().DoBatchCalendarViewDeltaRequests(deltaParams("link")).Return(deltaResponse(&remote.EventsDelta{
+					DeltaLink:  "link2",
+					RemovedIDs: []string{"meeting"},
+				}), nil)
+				mockStore.EXPECT().StoreEventMirror(gomock.Any()).Return(nil)
+			},
+			assertions: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Empty(t, events)
+			},
+		},
+		{
+			name:  "expired delta link reloads the whole window",
+			start: start,
+			end:   end,
+			setup: func(mockStore *mock_store.MockStore, mockClient *mock_remote.MockClient) {
+				mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(syncedMirror(now.Add(-time.Hour)), nil)
+				mockClient.EXPECT().DoBatchCalendarViewDeltaRequests(deltaParams("link")).Return([]*remote.CalendarViewDeltaResponse{{
+					RemoteUserID: MockRemoteUserID,
+					Error:        &remote.APIError{Code: "SyncStateNotFound"},
+					Expired:      true,
+				}}, nil)
+				mockClient.EXPECT().DoBatchCalendarViewDeltaRequests(deltaParams("")).Return(deltaResponse(&remote.EventsDelta{
+					DeltaLink: "link2",
+					Events:    []*remote.Event{later},
+					Reset:     true,
+				}), nil)
+				mockStore.EXPECT().StoreEventMirror(gomock.Any()).Return(nil)
+			},
+			assertions: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Empty(t, events)
+			},
+		},
+		{
+			name:  "throttled delta keeps the delta link",
+			start: start,
+			end:   end,
+			setup: func(mockStore *mock_store.MockStore, mockClient *mock_remote.MockClient) {
+				mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(syncedMirror(now.Add(-time.Hour)), nil)
+				mockClient.EXPECT().DoBatchCalendarViewDeltaRequests(deltaParams("link")).Return([]*remote.CalendarViewDeltaResponse{{
+					RemoteUserID: MockRemoteUserID,
+					Error:        &remote.APIError{Code: "ApplicationThrottled"},
+				}}, nil)
+				mockStore.EXPECT().StoreEventMirror(gomock.Any()).Times(0)
+				mockClient.EXPECT().GetEventsBetweenDates(MockRemoteUserID, start, end).Return([]*remote.Event{meeting}, nil)
+			},
+			assertions: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*remote.Event{meeting}, events)
+			},
+		},
+		{
+			name:  "remote without delta queries is read directly",
+			start: start,
+			end:   end,
+			setup: func(mockStore *mock_store.MockStore, mockClient *mock_remote.MockClient) {
+				mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(nil, store.ErrNotFound)
+				mockClient.EXPECT().DoBatchCalendarViewDeltaRequests(deltaParams("")).Return(nil, remote.ErrNotImplemented)
+				mockClient.EXPECT().GetEventsBetweenDates(MockRemoteUserID, start, end).Return([]*remote.Event{meeting}, nil)
+			},
+			assertions: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []*remote.Event{meeting}, events)
+			},
+		},
+		{
+			name:  "view outside of the window is read directly",
+			start: now.AddDate(0, 0, -7),
+			end:   now,
+			setup: func(_ *mock_store.MockStore, mockClient *mock_remote.MockClient) {
+				mockClient.EXPECT().GetEventsBetweenDates(MockRemoteUserID, now.AddDate(0, 0, -7), now).Return([]*remote.Event{}, nil)
+			},
+			assertions: func(events []*remote.Event, err error) {
+				require.NoError(t, err)
+				require.Empty(t, events)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockClient := mock_remote.NewMockClient(ctrl)
+			c := &mirroredClient{
+				Client: mockClient,
+				store:  mockStore,
+				logger: &bot.NilLogger{},
+				now:    func() time.Time { return now },
+			}
+			tt.setup(mockStore, mockClient)
+
+			events, err := c.GetEventsBetweenDates(MockRemoteUserID, tt.start, tt.end)
+
+			tt.assertions(events, err)
+		})
+	}
+}
+
+func TestMirroredClientBatchView(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := mock_store.NewMockStore(ctrl)
+	mockClient := mock_remote.NewMockClient(ctrl)
+	now := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	windowStart, windowEnd := mirrorWindow(now)
+	c := &mirroredClient{
+		Client: mockClient,
+		store:  mockStore,
+		logger: &bot.NilLogger{},
+		now:    func() time.Time { return now },
+	}
+
+	meeting := &remote.Event{
+		ID:    "meeting",
+		Start: remote.NewDateTime(now.Add(5*time.Minute), "UTC"),
+		End:   remote.NewDateTime(now.Add(35*time.Minute), "UTC"),
+	}
+	mirror := store.NewEventMirror("mirrored", windowStart, windowEnd)
+	mirror.Apply(&remote.EventsDelta{DeltaLink: "link", Events: []*remote.Event{meeting}}, now)
+	stale := store.NewEventMirror("stale", windowStart, windowEnd)
+	stale.Apply(&remote.EventsDelta{DeltaLink: "stale_link"}, now.Add(-time.Hour))
+
+	params := []*remote.ViewCalendarParams{
+		{RemoteUserID: "failing", StartTime: now, EndTime: now.Add(10 * time.Minute)},
+		{RemoteUserID: "mirrored", StartTime: now, EndTime: now.Add(10 * time.Minute)},
+		{RemoteUserID: "mirrored", CalendarID: "team", StartTime: now, EndTime: now.Add(10 * time.Minute)},
+		{RemoteUserID: "stale", StartTime: now, EndTime: now.Add(10 * time.Minute)},
+	}
+	mockStore.EXPECT().LoadEventMirror("mirrored").Return(mirror, nil)
+	mockStore.EXPECT().LoadEventMirror("failing").Return(nil, store.ErrNotFound)
+	mockStore.EXPECT().LoadEventMirror("stale").Return(stale, nil)
+	// The mirrors to sync are synced in a single batch
+	mockClient.EXPECT().DoBatchCalendarViewDeltaRequests([]*remote.CalendarViewDeltaParams{
+		{RemoteUserID: "failing", StartTime: windowStart, EndTime: windowEnd},
+		{RemoteUserID: "stale", StartTime: windowStart, EndTime: windowEnd, DeltaLink: "stale_link"},
+	}).Return([]*remote.CalendarViewDeltaResponse{
+		{RemoteUserID: "failing", Error: &remote.APIError{Code: "ErrorAccessDenied"}},
+		{RemoteUserID: "stale", Delta: &remote.EventsDelta{DeltaLink: "stale_link2", Events: []*remote.Event{meeting}}},
+	}, nil)
+	mockStore.EXPECT().StoreEventMirror(stale).Return(nil)
+	mockClient.EXPECT().DoBatchViewCalendarRequests([]*remote.ViewCalendarParams{params[0], params[2]}).Return([]*remote.ViewCalendarResponse{
+		{RemoteUserID: "failing", Events: []*remote.Event{}},
+		{RemoteUserID: "mirrored", Error: &remote.APIError{Code: "ErrorItemNotFound"}},
+	}, nil)
+
+	responses, err := c.DoBatchViewCalendarRequests(params)
+	require.NoError(t, err)
+	require.Len(t, responses, 4)
+	require.Equal(t, "failing", responses[0].RemoteUserID)
+	require.Empty(t, responses[0].Events)
+	require.Equal(t, "mirrored", responses[1].RemoteUserID)
+	require.Equal(t, []*remote.Event{meeting}, responses[1].Events)
+	require.Equal(t, "mirrored", responses[2].RemoteUserID)
+	require.Equal(t, "ErrorItemNotFound", responses[2].Error.Code)
+	require.Equal(t, "stale", responses[3].RemoteUserID)
+	require.Equal(t, []*remote.Event{meeting}, responses[3].Events)
+	require.Equal(t, "stale_link2", stale.DeltaLink)
+}
+
+func TestMirroredClientMarksStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := mock_store.NewMockStore(ctrl)
+	mockClient := mock_remote.NewMockClient(ctrl)
+	c := &mirroredClient{
+		Client:       mockClient,
+		store:        mockStore,
+		logger:       &bot.NilLogger{},
+		remoteUserID: MockRemoteUserID,
+		now:          time.Now,
+	}
+
+	mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, nil).Return(nil)
+	mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(&store.EventMirror{RemoteUserID: MockRemoteUserID}, nil)
+	mockStore.EXPECT().StoreEventMirror(&store.EventMirror{RemoteUserID: MockRemoteUserID, Stale: true}).Return(nil)
+	require.NoError(t, c.AcceptEvent(MockRemoteUserID, MockEventID, nil))
+
+	mockClient.EXPECT().CreateEvent("", gomock.Any()).Return(&remote.Event{ID: MockEventID}, nil)
+	mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(nil, store.ErrNotFound)
+	_, err := c.CreateEvent("", &remote.Event{})
+	require.NoError(t, err)
+}
+
+func TestWithEventMirror(t *testing.T) {
+	mscalendar, _, _, _, _, mockClient, _ := GetMockSetup(t)
+
+	require.Equal(t, mockClient, mscalendar.withEventMirror(mockClient, MockRemoteUserID))
+
+	mscalendar.Config = &config.Config{StoredConfig: config.StoredConfig{EnableEventMirror: true}}
+	client, ok := mscalendar.withEventMirror(mockClient, MockRemoteUserID).(*mirroredClient)
+	require.True(t, ok)
+	require.Equal(t, MockRemoteUserID, client.remoteUserID)
+}
+
+func TestMarkEventMirrorStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockStore := mock_store.NewMockStore(ctrl)
+
+	mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(&store.EventMirror{RemoteUserID: MockRemoteUserID, DeltaLink: "link"}, nil)
+	mockStore.EXPECT().StoreEventMirror(&store.EventMirror{RemoteUserID: MockRemoteUserID, DeltaLink: "link", Stale: true}).Return(nil)
+	markEventMirrorStale(mockStore, &bot.NilLogger{}, MockRemoteUserID)
+
+	// Already stale mirrors are not stored again
+	mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(&store.EventMirror{RemoteUserID: MockRemoteUserID, Stale: true}, nil)
+	markEventMirrorStale(mockStore, &bot.NilLogger{}, MockRemoteUserID)
+
+	mockStore.EXPECT().LoadEventMirror(MockRemoteUserID).Return(nil, store.ErrNotFound)
+	markEventMirrorStale(mockStore, &bot.NilLogger{}, MockRemoteUserID)
+}
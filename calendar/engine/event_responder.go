@@ -5,41 +5,42 @@ package engine
 
 import (
 	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
 
+// EventResponder responds to invitations on behalf of the user. A nil
+// *remote.EventResponse sends a bare response to the organizer.
 type EventResponder interface {
-	AcceptEvent(user *User, eventID string) error
-	DeclineEvent(user *User, eventID string) error
-	TentativelyAcceptEvent(user *User, eventID string) error
-	RespondToEvent(user *User, eventID, response string) error
+	AcceptEvent(user *User, eventID string, response *remote.EventResponse) error
+	DeclineEvent(user *User, eventID string, response *remote.EventResponse) error
+	TentativelyAcceptEvent(user *User, eventID string, response *remote.EventResponse) error
+	RespondToEvent(user *User, eventID, option string, response *remote.EventResponse) error
+	RespondToEventAs(user *User, principalID, eventID, option string, response *remote.EventResponse) error
 }
 
-func (m *mscalendar) AcceptEvent(user *User, eventID string) error {
-	err := m.Filter(
-		withClient,
-		withUserExpanded(user),
-	)
-	if err != nil {
-		return err
-	}
+func (m *mscalendar) AcceptEvent(user *User, eventID string, response *remote.EventResponse) error {
+	return m.RespondToEvent(user, eventID, OptionYes, response)
+}
 
-	return m.client.AcceptEvent(user.Remote.ID, eventID)
+func (m *mscalendar) DeclineEvent(user *User, eventID string, response *remote.EventResponse) error {
+	return m.RespondToEvent(user, eventID, OptionNo, response)
 }
 
-func (m *mscalendar) DeclineEvent(user *User, eventID string) error {
-	err := m.Filter(
-		withClient,
-		withUserExpanded(user),
-	)
+func (m *mscalendar) TentativelyAcceptEvent(user *User, eventID string, response *remote.EventResponse) error {
+	return m.RespondToEvent(user, eventID, OptionMaybe, response)
+}
+
+func (m *mscalendar) RespondToEvent(user *User, eventID, option string, response *remote.EventResponse) error {
+	if option == OptionNotResponded {
+		return errors.New("not responded is not a valid response")
+	}
+	response, err := eventResponse(option, response)
 	if err != nil {
 		return err
 	}
 
-	return m.client.DeclineEvent(user.Remote.ID, eventID)
-}
-
-func (m *mscalendar) TentativelyAcceptEvent(user *User, eventID string) error {
-	err := m.Filter(
+	err = m.Filter(
 		withClient,
 		withUserExpanded(user),
 	)
@@ -47,15 +48,21 @@ func (m *mscalendar) TentativelyAcceptEvent(user *User, eventID string) error {
 		return err
 	}
 
-	return m.client.TentativelyAcceptEvent(eventID)
+	return m.respond(user.Remote.ID, eventID, option, response)
 }
 
-func (m *mscalendar) RespondToEvent(user *User, eventID, response string) error {
-	if response == OptionNotResponded {
+// RespondToEventAs responds to an invitation of the principal, as their
+// delegate.
+func (m *mscalendar) RespondToEventAs(user *User, principalID, eventID, option string, response *remote.EventResponse) error {
+	if option == OptionNotResponded {
 		return errors.New("not responded is not a valid response")
 	}
+	response, err := eventResponse(option, response)
+	if err != nil {
+		return err
+	}
 
-	err := m.Filter(
+	err = m.Filter(
 		withClient,
 		withUserExpanded(user),
 	)
@@ -63,14 +70,49 @@ func (m *mscalendar) RespondToEvent(user *User, eventID, response string) error
 		return err
 	}
 
-	switch response {
+	principal, err := m.Store.LoadUser(principalID)
+	if err != nil {
+		return errors.Wrap(err, "error loading the principal")
+	}
+	if !principal.IsDelegate(user.MattermostUserID) {
+		return errors.New("not a delegate of the principal")
+	}
+
+	return m.respond(principal.Remote.ID, eventID, option, response)
+}
+
+func (m *mscalendar) respond(remoteUserID, eventID, option string, response *remote.EventResponse) error {
+	switch option {
 	case OptionYes:
-		return m.client.AcceptEvent(user.Remote.ID, eventID)
+		return m.client.AcceptEvent(remoteUserID, eventID, response)
 	case OptionNo:
-		return m.client.DeclineEvent(user.Remote.ID, eventID)
+		return m.client.DeclineEvent(remoteUserID, eventID, response)
 	case OptionMaybe:
-		return m.client.TentativelyAcceptEvent(eventID)
+		return m.client.TentativelyAcceptEvent(remoteUserID, eventID, response)
 	default:
-		return errors.New(response + " is not a valid response")
+		return errors.New(option + " is not a valid response")
+	}
+}
+
+// eventResponse checks what is sent along with the response, a bare response
+// sent to the organizer if nothing is.
+func eventResponse(option string, response *remote.EventResponse) (*remote.EventResponse, error) {
+	if response == nil {
+		return &remote.EventResponse{SendResponse: true}, nil
+	}
+
+	proposed := response.ProposedNewTime
+	if proposed == nil {
+		return response, nil
+	}
+	if option == OptionYes {
+		return nil, errors.New("a new time can only be proposed when declining or tentatively accepting")
+	}
+	if !response.SendResponse {
+		return nil, errors.New("a new time can only be proposed with a response sent to the organizer")
+	}
+	if proposed.Start == nil || proposed.End == nil || !proposed.End.Time().After(proposed.Start.Time()) {
+		return nil, errors.New("the proposed new time must end after it starts")
 	}
+	return response, nil
 }
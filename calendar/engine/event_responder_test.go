@@ -5,11 +5,14 @@ package engine
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
 
 func TestAcceptEvent(t *testing.T) {
@@ -37,7 +40,7 @@ func TestAcceptEvent(t *testing.T) {
 			user: GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to accept the event")).Times(1)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(errors.New("unable to accept the event")).Times(1)
 			},
 			assertion: func(err error) {
 				require.Error(t, err)
@@ -49,7 +52,7 @@ func TestAcceptEvent(t *testing.T) {
 			user: GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil).Times(1)
 			},
 			assertion: func(err error) {
 				require.NoError(t, err)
@@ -60,7 +63,7 @@ func TestAcceptEvent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := mscalendar.AcceptEvent(tt.user, MockEventID)
+			err := mscalendar.AcceptEvent(tt.user, MockEventID, nil)
 
 			tt.assertion(err)
 		})
@@ -92,7 +95,7 @@ func TestDeclineEvent(t *testing.T) {
 			user: GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to decline event")).Times(1)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(errors.New("unable to decline event")).Times(1)
 			},
 			assertion: func(err error) {
 				require.Error(t, err)
@@ -104,7 +107,7 @@ func TestDeclineEvent(t *testing.T) {
 			user: GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil).Times(1)
 			},
 			assertion: func(err error) {
 				require.NoError(t, err)
@@ -115,7 +118,7 @@ func TestDeclineEvent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := mscalendar.DeclineEvent(tt.user, MockEventID)
+			err := mscalendar.DeclineEvent(tt.user, MockEventID, nil)
 
 			tt.assertion(err)
 		})
@@ -147,7 +150,7 @@ func TestTentativelyAcceptEvent(t *testing.T) {
 			user: GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().TentativelyAcceptEvent(MockEventID).Return(errors.New("unable to tentatively accept the event")).Times(1)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(errors.New("unable to tentatively accept the event")).Times(1)
 			},
 			assertion: func(err error) {
 				require.Error(t, err)
@@ -159,7 +162,7 @@ func TestTentativelyAcceptEvent(t *testing.T) {
 			user: GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID)
-				mockClient.EXPECT().TentativelyAcceptEvent(MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil).Times(1)
 			},
 			assertion: func(err error) {
 				require.NoError(t, err)
@@ -170,7 +173,7 @@ func TestTentativelyAcceptEvent(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := mscalendar.TentativelyAcceptEvent(tt.user, MockEventID)
+			err := mscalendar.TentativelyAcceptEvent(tt.user, MockEventID, nil)
 
 			tt.assertion(err)
 		})
@@ -179,10 +182,16 @@ func TestTentativelyAcceptEvent(t *testing.T) {
 
 func TestRespondToEvent(t *testing.T) {
 	mscalendar, mockStore, _, _, mockPluginAPI, mockClient, _ := GetMockSetup(t)
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Minute)
+	proposed := &remote.TimeSlot{
+		Start: remote.NewDateTime(start, "UTC"),
+		End:   remote.NewDateTime(start.Add(time.Hour), "UTC"),
+	}
 
 	tests := []struct {
 		name      string
 		response  string
+		details   *remote.EventResponse
 		user      *User
 		setupMock func()
 		assertion func(err error)
@@ -224,7 +233,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionYes,
 			user:     GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -236,7 +245,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionYes,
 			user:     GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to accept the event")).Times(1)
+				mockClient.EXPECT().AcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(errors.New("unable to accept the event")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -249,7 +258,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionNo,
 			user:     GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -261,7 +270,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionNo,
 			user:     GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID).Return(errors.New("unable to decline the event")).Times(1)
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(errors.New("unable to decline the event")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -274,7 +283,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionMaybe,
 			user:     GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().TentativelyAcceptEvent(MockEventID).Return(nil).Times(1)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(nil).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -286,7 +295,7 @@ func TestRespondToEvent(t *testing.T) {
 			response: OptionMaybe,
 			user:     GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
 			setupMock: func() {
-				mockClient.EXPECT().TentativelyAcceptEvent(MockEventID).Return(errors.New("unable to tentatively accept the event")).Times(1)
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{SendResponse: true}).Return(errors.New("unable to tentatively accept the event")).Times(1)
 				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
 			},
 			assertion: func(err error) {
@@ -294,12 +303,71 @@ func TestRespondToEvent(t *testing.T) {
 				require.EqualError(t, err, "unable to tentatively accept the event")
 			},
 		},
+		{
+			name:      "proposing a new time when accepting",
+			response:  OptionYes,
+			details:   &remote.EventResponse{SendResponse: true, ProposedNewTime: proposed},
+			user:      GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
+			setupMock: func() {},
+			assertion: func(err error) {
+				require.EqualError(t, err, "a new time can only be proposed when declining or tentatively accepting")
+			},
+		},
+		{
+			name:      "proposing a new time without sending the response",
+			response:  OptionNo,
+			details:   &remote.EventResponse{ProposedNewTime: proposed},
+			user:      GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
+			setupMock: func() {},
+			assertion: func(err error) {
+				require.EqualError(t, err, "a new time can only be proposed with a response sent to the organizer")
+			},
+		},
+		{
+			name:     "proposing a new time ending before it starts",
+			response: OptionMaybe,
+			details: &remote.EventResponse{SendResponse: true, ProposedNewTime: &remote.TimeSlot{
+				Start: proposed.End,
+				End:   proposed.Start,
+			}},
+			user:      GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
+			setupMock: func() {},
+			assertion: func(err error) {
+				require.EqualError(t, err, "the proposed new time must end after it starts")
+			},
+		},
+		{
+			name:     "declining with a comment and a proposed new time",
+			response: OptionNo,
+			details:  &remote.EventResponse{Comment: "Can we move it?", SendResponse: true, ProposedNewTime: proposed},
+			user:     GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
+			setupMock: func() {
+				mockClient.EXPECT().DeclineEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{Comment: "Can we move it?", SendResponse: true, ProposedNewTime: proposed}).Return(nil).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
+			},
+			assertion: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "tentatively accepting without sending the response",
+			response: OptionMaybe,
+			details:  &remote.EventResponse{Comment: "Might be late"},
+			user:     GetMockUser(model.NewPointer(MockRemoteUserID), nil, MockMMUserID, GetMockStoreSettings()),
+			setupMock: func() {
+				mockClient.EXPECT().TentativelyAcceptEvent(MockRemoteUserID, MockEventID, &remote.EventResponse{Comment: "Might be late"}).Return(nil).Times(1)
+				mockPluginAPI.EXPECT().GetMattermostUser(MockMMUserID).Return(&model.User{Id: MockMMUserID}, nil)
+			},
+			assertion: func(err error) {
+				require.NoError(t, err)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 
-			err := mscalendar.RespondToEvent(tt.user, MockEventID, tt.response)
+			err := mscalendar.RespondToEvent(tt.user, MockEventID, tt.response, tt.details)
 
 			tt.assertion(err)
 		})
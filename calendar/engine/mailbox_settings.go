@@ -0,0 +1,47 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// mailboxSettingsRefreshInterval is how long the mailbox settings of a user
+// are kept before being read again during the status sync.
+const mailboxSettingsRefreshInterval = 15 * time.Minute
+
+// mailboxSettingsOf returns the mailbox settings of the user, reading them
+// again when the stored ones are outdated. It returns the stored ones, if any,
+// when they cannot be read.
+func (m *mscalendar) mailboxSettingsOf(user *store.User, now time.Time) *remote.MailboxSettings {
+	if user.MailboxSettings != nil && now.Sub(time.Unix(user.MailboxSettingsUpdatedAt, 0)) < mailboxSettingsRefreshInterval {
+		return user.MailboxSettings
+	}
+
+	logger := m.Logger.With(bot.LogContext{"mm_user_id": user.MattermostUserID})
+	engine, err := m.FilterCopy(withActingUser(user.MattermostUserID), withClient)
+	if err != nil {
+		logger.Warnf("Not able to make a client to read the mailbox settings. err=%v", err)
+		return user.MailboxSettings
+	}
+	settings, err := engine.client.GetMailboxSettings(user.Remote.ID)
+	if err != nil {
+		logger.Warnf("Not able to read the mailbox settings. err=%v", err)
+		return user.MailboxSettings
+	}
+
+	if settings.WorkingHours.TimeZone.Name == "" {
+		settings.WorkingHours.TimeZone.Name = settings.TimeZone
+	}
+	user.MailboxSettings = settings
+	user.MailboxSettingsUpdatedAt = now.Unix()
+	if err := m.Store.StoreUser(user); err != nil {
+		logger.Warnf("Not able to store the mailbox settings. err=%v", err)
+	}
+	return user.MailboxSettings
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const (
+	maxMeetingTimeSuggestions = 5
+
+	attendeeTypeRequired = "required"
+)
+
+type MeetingTimes interface {
+	SuggestMeetingTimes(user *User, mattermostUsernames []string, duration time.Duration, start, end time.Time) (*MeetingSuggestions, error)
+	OfferMeetingTimes(user *User, suggestions *MeetingSuggestions, timezone string) error
+	ScheduleMeeting(user *User, subject string, attendeeMails []string, start, end time.Time) (*remote.Event, error)
+}
+
+// MeetingSuggestions are the times at which the user may meet the attendees,
+// best first.
+type MeetingSuggestions struct {
+	Subject               string
+	Duration              time.Duration
+	Attendees             []*remote.Attendee
+	Suggestions           []*remote.MeetingTimeSuggestion
+	EmptySuggestionReason string
+}
+
+// SuggestMeetingTimes asks the remote calendar when the user and the given
+// Mattermost users may meet for duration, between start and end.
+func (m *mscalendar) SuggestMeetingTimes(user *User, mattermostUsernames []string, duration time.Duration, start, end time.Time) (*MeetingSuggestions, error) {
+	attendees := []*remote.Attendee{}
+	names := []string{}
+	for _, username := range mattermostUsernames {
+		attendee, err := m.loadPrincipal(username)
+		if err != nil {
+			return nil, err
+		}
+		if attendee.MattermostUserID == user.MattermostUserID {
+			continue
+		}
+		if attendee.Remote == nil || attendee.Remote.Mail == "" {
+			return nil, errors.Errorf("@%s has no %s email address", strings.TrimPrefix(username, "@"), m.Provider.DisplayName)
+		}
+
+		name := attendee.Remote.DisplayName
+		if name == "" {
+			name = strings.TrimPrefix(username, "@")
+		}
+		names = append(names, name)
+		attendees = append(attendees, &remote.Attendee{
+			Type: attendeeTypeRequired,
+			EmailAddress: &remote.EmailAddress{
+				Address: attendee.Remote.Mail,
+				Name:    name,
+			},
+		})
+	}
+	if len(attendees) == 0 {
+		return nil, errors.New("please invite at least one other user")
+	}
+
+	params := &remote.FindMeetingTimesParameters{
+		TimeConstraint: &remote.TimeConstraint{
+			ActivityDomain: "work",
+			TimeSlots: []remote.TimeSlot{{
+				Start: remote.NewDateTime(start.UTC(), "UTC"),
+				End:   remote.NewDateTime(end.UTC(), "UTC"),
+			}},
+		},
+		MeetingDuration:         &duration,
+		MaxCandidates:           model.NewPointer(maxMeetingTimeSuggestions),
+		ReturnSuggestionReasons: model.NewPointer(true),
+	}
+	for _, a := range attendees {
+		params.Attendees = append(params.Attendees, *a)
+	}
+
+	results, err := m.FindMeetingTimes(user, params)
+	if errors.Is(err, remote.ErrNotImplemented) {
+		return nil, errors.Errorf("finding meeting times is not supported by %s", m.Provider.DisplayName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []*remote.MeetingTimeSuggestion{}
+	for _, s := range results.MeetingTimeSuggestions {
+		if s.MeetingTimeSlot != nil && s.MeetingTimeSlot.Start != nil && s.MeetingTimeSlot.End != nil {
+			suggestions = append(suggestions, s)
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Order != suggestions[j].Order {
+			return suggestions[i].Order < suggestions[j].Order
+		}
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+
+	return &MeetingSuggestions{
+		Subject:               "Meeting with " + strings.Join(names, ", "),
+		Duration:              duration,
+		Attendees:             attendees,
+		Suggestions:           suggestions,
+		EmptySuggestionReason: results.EmptySuggestionReason,
+	}, nil
+}
+
+// OfferMeetingTimes sends the suggested times to the user, with a button to
+// schedule the meeting at each of them.
+func (m *mscalendar) OfferMeetingTimes(user *User, suggestions *MeetingSuggestions, timezone string) error {
+	loc, err := time.LoadLocation(tz.Go(timezone))
+	if err != nil {
+		loc = time.UTC
+	}
+
+	mails := []string{}
+	names := []string{}
+	for _, a := range suggestions.Attendees {
+		mails = append(mails, a.EmailAddress.Address)
+		names = append(names, a.EmailAddress.Name)
+	}
+	message := fmt.Sprintf("Times for a %d-minute meeting with %s:", int(suggestions.Duration.Minutes()), strings.Join(names, ", "))
+	url := fmt.Sprintf("%s%s%s", m.Config.PluginURLPath, config.PathPostAction, config.PathScheduleMeeting)
+
+	attachments := []*model.SlackAttachment{}
+	for _, s := range suggestions.Suggestions {
+		attachments = append(attachments, meetingTimeAttachment(s, suggestions.Subject, mails, loc, url))
+	}
+	_, err = m.Poster.DMWithMessageAndAttachments(user.MattermostUserID, message, attachments...)
+	return err
+}
+
+func meetingTimeAttachment(s *remote.MeetingTimeSuggestion, subject string, mails []string, loc *time.Location, url string) *model.SlackAttachment {
+	start := s.MeetingTimeSlot.Start.Time().In(loc)
+	end := s.MeetingTimeSlot.End.Time().In(loc)
+	title := fmt.Sprintf("%s - %s", start.Format("Monday, January 02 · "+time.Kitchen), end.Format(time.Kitchen))
+
+	fields := []*model.SlackAttachmentField{{
+		Title: "Confidence",
+		Value: fmt.Sprintf("%.0f%%", s.Confidence),
+		Short: true,
+	}}
+	unavailable := []string{}
+	for _, a := range s.AttendeeAvailability {
+		if a.Attendee == nil || a.Attendee.EmailAddress == nil || a.Availability == "" || a.Availability == "free" {
+			continue
+		}
+		unavailable = append(unavailable, fmt.Sprintf("%s: %s", a.Attendee.EmailAddress.Address, a.Availability))
+	}
+	if len(unavailable) > 0 {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: "Availability",
+			Value: strings.Join(unavailable, "\n"),
+			Short: true,
+		})
+	}
+
+	return &model.SlackAttachment{
+		Title:    title,
+		Fallback: title,
+		Text:     views.MarkdownToHTMLEntities(s.SuggestionReason),
+		Fields:   fields,
+		Actions:  []*model.PostAction{NewPostActionForScheduleMeeting(subject, mails, start, end, url)},
+	}
+}
+
+// NewPostActionForScheduleMeeting returns the button scheduling the meeting
+// with the attendees from start to end.
+func NewPostActionForScheduleMeeting(subject string, attendeeMails []string, start, end time.Time, url string) *model.PostAction {
+	return &model.PostAction{
+		Name: "Schedule this",
+		Type: model.PostActionTypeButton,
+		Integration: &model.PostActionIntegration{
+			URL: url,
+			Context: map[string]interface{}{
+				config.SubjectKey:   subject,
+				config.AttendeesKey: attendeeMails,
+				config.StartKey:     start.Format(time.RFC3339),
+				config.EndKey:       end.Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+// ScheduleMeeting creates an event of the user from start to end, inviting
+// the attendees.
+func (m *mscalendar) ScheduleMeeting(user *User, subject string, attendeeMails []string, start, end time.Time) (*remote.Event, error) {
+	event := &remote.Event{
+		Subject: subject,
+		Start:   remote.NewDateTime(start.UTC(), "UTC"),
+		End:     remote.NewDateTime(end.UTC(), "UTC"),
+	}
+	for _, mail := range attendeeMails {
+		event.Attendees = append(event.Attendees, &remote.Attendee{
+			Type:         attendeeTypeRequired,
+			EmailAddress: &remote.EmailAddress{Address: mail},
+		})
+	}
+	return m.CreateEvent(user, event, nil)
+}
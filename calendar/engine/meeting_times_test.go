@@ -0,0 +1,185 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+)
+
+func TestSuggestMeetingTimes(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	end := start.Add(72 * time.Hour)
+	slot := func(hour int) *remote.TimeSlot {
+		s := time.Date(2024, 3, 4, hour, 0, 0, 0, time.UTC)
+		return &remote.TimeSlot{Start: remote.NewDateTime(s, "UTC"), End: remote.NewDateTime(s.Add(45*time.Minute), "UTC")}
+	}
+	alice := &store.User{MattermostUserID: "alice_mm_id", Remote: &remote.User{Mail: "alice@example.com", DisplayName: "Alice"}}
+
+	tests := []struct {
+		name      string
+		usernames []string
+		setupMock func(*mock_plugin_api.MockPluginAPI, *mock_store.MockStore, *mock_remote.MockClient)
+		expected  *MeetingSuggestions
+		err       string
+	}{
+		{
+			name:      "suggestions ranked by order",
+			usernames: []string{"@alice"},
+			setupMock: func(papi *mock_plugin_api.MockPluginAPI, s *mock_store.MockStore, c *mock_remote.MockClient) {
+				papi.EXPECT().GetMattermostUserByUsername("alice").Return(&model.User{Id: "alice_mm_id"}, nil)
+				s.EXPECT().LoadUser("alice_mm_id").Return(alice, nil)
+				c.EXPECT().FindMeetingTimes(gomock.Any()).DoAndReturn(func(params *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error) {
+					require.Equal(t, "alice@example.com", params.Attendees[0].EmailAddress.Address)
+					require.Equal(t, 45*time.Minute, *params.MeetingDuration)
+					require.Equal(t, end, params.TimeConstraint.TimeSlots[0].End.Time())
+					return &remote.MeetingTimeSuggestionResults{
+						MeetingTimeSuggestions: []*remote.MeetingTimeSuggestion{
+							{Order: 2, MeetingTimeSlot: slot(11)},
+							{Order: 1, MeetingTimeSlot: slot(10)},
+							{Order: 3},
+						},
+					}, nil
+				})
+			},
+			expected: &MeetingSuggestions{
+				Subject:  "Meeting with Alice",
+				Duration: 45 * time.Minute,
+				Attendees: []*remote.Attendee{{
+					Type:         "required",
+					EmailAddress: &remote.EmailAddress{Address: "alice@example.com", Name: "Alice"},
+				}},
+				Suggestions: []*remote.MeetingTimeSuggestion{
+					{Order: 1, MeetingTimeSlot: slot(10)},
+					{Order: 2, MeetingTimeSlot: slot(11)},
+				},
+			},
+		},
+		{
+			name:      "user not found",
+			usernames: []string{"@nobody"},
+			setupMock: func(papi *mock_plugin_api.MockPluginAPI, _ *mock_store.MockStore, _ *mock_remote.MockClient) {
+				papi.EXPECT().GetMattermostUserByUsername("nobody").Return(nil, store.ErrNotFound)
+			},
+			err: "user @nobody not found",
+		},
+		{
+			name:      "user not connected",
+			usernames: []string{"@bob"},
+			setupMock: func(papi *mock_plugin_api.MockPluginAPI, s *mock_store.MockStore, _ *mock_remote.MockClient) {
+				papi.EXPECT().GetMattermostUserByUsername("bob").Return(&model.User{Id: "bob_mm_id"}, nil)
+				s.EXPECT().LoadUser("bob_mm_id").Return(nil, store.ErrNotFound)
+			},
+			err: "@bob is not connected to testDisplayName",
+		},
+		{
+			name:      "only the user",
+			usernames: []string{"@self"},
+			setupMock: func(papi *mock_plugin_api.MockPluginAPI, s *mock_store.MockStore, _ *mock_remote.MockClient) {
+				papi.EXPECT().GetMattermostUserByUsername("self").Return(&model.User{Id: MockMMUserID}, nil)
+				s.EXPECT().LoadUser(MockMMUserID).Return(&store.User{MattermostUserID: MockMMUserID}, nil)
+			},
+			err: "please invite at least one other user",
+		},
+		{
+			name:      "not supported",
+			usernames: []string{"@alice"},
+			setupMock: func(papi *mock_plugin_api.MockPluginAPI, s *mock_store.MockStore, c *mock_remote.MockClient) {
+				papi.EXPECT().GetMattermostUserByUsername("alice").Return(&model.User{Id: "alice_mm_id"}, nil)
+				s.EXPECT().LoadUser("alice_mm_id").Return(alice, nil)
+				c.EXPECT().FindMeetingTimes(gomock.Any()).Return(nil, remote.ErrNotImplemented)
+			},
+			err: "finding meeting times is not supported by testDisplayName",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mscalendar, mockStore, _, _, mockPluginAPI, mockClient, _ := GetMockSetup(t)
+			user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+			tt.setupMock(mockPluginAPI, mockStore, mockClient)
+
+			suggestions, err := mscalendar.SuggestMeetingTimes(user, tt.usernames, 45*time.Minute, start, end)
+
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, suggestions)
+		})
+	}
+}
+
+func TestOfferMeetingTimes(t *testing.T) {
+	mscalendar, _, mockPoster, _, _, _, _ := GetMockSetup(t)
+	mscalendar.Config.PluginURLPath = "/plugins/mscalendar"
+	user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+
+	mockPoster.EXPECT().DMWithMessageAndAttachments(MockMMUserID, "Times for a 45-minute meeting with Alice:", gomock.Any()).DoAndReturn(
+		func(_, _ string, attachments ...*model.SlackAttachment) (string, error) {
+			require.Len(t, attachments, 1)
+			require.Equal(t, "Monday, March 04 · 2:00PM - 2:45PM", attachments[0].Title)
+			require.Equal(t, []*model.SlackAttachmentField{
+				{Title: "Confidence", Value: "50%", Short: true},
+				{Title: "Availability", Value: "alice@example.com: tentative", Short: true},
+			}, attachments[0].Fields)
+			action := attachments[0].Actions[0]
+			require.Equal(t, "Schedule this", action.Name)
+			require.Equal(t, "/plugins/mscalendar/action/schedule-meeting", action.Integration.URL)
+			require.Equal(t, "Meeting with Alice", action.Integration.Context[config.SubjectKey])
+			require.Equal(t, []string{"alice@example.com"}, action.Integration.Context[config.AttendeesKey])
+			require.Equal(t, "2024-03-04T14:00:00Z", action.Integration.Context[config.StartKey])
+			require.Equal(t, "2024-03-04T14:45:00Z", action.Integration.Context[config.EndKey])
+			return "postID", nil
+		})
+
+	attendee := &remote.Attendee{Type: "required", EmailAddress: &remote.EmailAddress{Address: "alice@example.com", Name: "Alice"}}
+	err := mscalendar.OfferMeetingTimes(user, &MeetingSuggestions{
+		Subject:   "Meeting with Alice",
+		Duration:  45 * time.Minute,
+		Attendees: []*remote.Attendee{attendee},
+		Suggestions: []*remote.MeetingTimeSuggestion{{
+			Confidence: 50,
+			MeetingTimeSlot: &remote.TimeSlot{
+				Start: remote.NewDateTime(start, "UTC"),
+				End:   remote.NewDateTime(start.Add(45*time.Minute), "UTC"),
+			},
+			AttendeeAvailability: []*remote.AttendeeAvailability{{Attendee: attendee, Availability: "tentative"}},
+		}},
+	}, "UTC")
+	require.NoError(t, err)
+}
+
+func TestScheduleMeeting(t *testing.T) {
+	mscalendar, _, _, _, _, mockClient, _ := GetMockSetup(t)
+	user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+	end := start.Add(45 * time.Minute)
+
+	mockClient.EXPECT().CreateEvent("", &remote.Event{
+		Subject: "Meeting with Alice",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(end, "UTC"),
+		Attendees: []*remote.Attendee{{
+			Type:         "required",
+			EmailAddress: &remote.EmailAddress{Address: "alice@example.com"},
+		}},
+	}).Return(&remote.Event{ID: MockEventID}, nil)
+
+	event, err := mscalendar.ScheduleMeeting(user, "Meeting with Alice", []string{"alice@example.com"}, start, end)
+	require.NoError(t, err)
+	require.Equal(t, MockEventID, event.ID)
+}
@@ -38,17 +38,31 @@ func (m *MockEngine) EXPECT() *MockEngineMockRecorder {
 }
 
 // AcceptEvent mocks base method.
-func (m *MockEngine) AcceptEvent(arg0 *engine.User, arg1 string) error {
+func (m *MockEngine) AcceptEvent(arg0 *engine.User, arg1 string, arg2 *remote.EventResponse) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AcceptEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "AcceptEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AcceptEvent indicates an expected call of AcceptEvent.
-func (mr *MockEngineMockRecorder) AcceptEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) AcceptEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEvent", reflect.TypeOf((*MockEngine)(nil).AcceptEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEvent", reflect.TypeOf((*MockEngine)(nil).AcceptEvent), arg0, arg1, arg2)
+}
+
+// AddDelegate mocks base method.
+func (m *MockEngine) AddDelegate(arg0 *engine.User, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDelegate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDelegate indicates an expected call of AddDelegate.
+func (mr *MockEngineMockRecorder) AddDelegate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDelegate", reflect.TypeOf((*MockEngine)(nil).AddDelegate), arg0, arg1)
 }
 
 // AfterDisconnect mocks base method.
@@ -79,6 +93,36 @@ func (mr *MockEngineMockRecorder) AfterSuccessfullyConnect(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterSuccessfullyConnect", reflect.TypeOf((*MockEngine)(nil).AfterSuccessfullyConnect), arg0, arg1)
 }
 
+// BookRoom mocks base method.
+func (m *MockEngine) BookRoom(arg0 *engine.User, arg1 *remote.Room, arg2, arg3 time.Time) (*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookRoom", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookRoom indicates an expected call of BookRoom.
+func (mr *MockEngineMockRecorder) BookRoom(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookRoom", reflect.TypeOf((*MockEngine)(nil).BookRoom), arg0, arg1, arg2, arg3)
+}
+
+// CancelEvent mocks base method.
+func (m *MockEngine) CancelEvent(arg0 *engine.User, arg1, arg2 string) (*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelEvent indicates an expected call of CancelEvent.
+func (mr *MockEngineMockRecorder) CancelEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelEvent", reflect.TypeOf((*MockEngine)(nil).CancelEvent), arg0, arg1, arg2)
+}
+
 // ClearSettingsPosts mocks base method.
 func (m *MockEngine) ClearSettingsPosts(arg0 string) {
 	m.ctrl.T.Helper()
@@ -91,6 +135,20 @@ func (mr *MockEngineMockRecorder) ClearSettingsPosts(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearSettingsPosts", reflect.TypeOf((*MockEngine)(nil).ClearSettingsPosts), arg0)
 }
 
+// ConnectWithCredentials mocks base method.
+func (m *MockEngine) ConnectWithCredentials(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectWithCredentials", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConnectWithCredentials indicates an expected call of ConnectWithCredentials.
+func (mr *MockEngineMockRecorder) ConnectWithCredentials(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectWithCredentials", reflect.TypeOf((*MockEngine)(nil).ConnectWithCredentials), arg0, arg1)
+}
+
 // CreateCalendar mocks base method.
 func (m *MockEngine) CreateCalendar(arg0 *engine.User, arg1 *remote.Calendar) (*remote.Calendar, error) {
 	m.ctrl.T.Helper()
@@ -137,17 +195,17 @@ func (mr *MockEngineMockRecorder) CreateMyEventSubscription() *gomock.Call {
 }
 
 // DeclineEvent mocks base method.
-func (m *MockEngine) DeclineEvent(arg0 *engine.User, arg1 string) error {
+func (m *MockEngine) DeclineEvent(arg0 *engine.User, arg1 string, arg2 *remote.EventResponse) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeclineEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeclineEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeclineEvent indicates an expected call of DeclineEvent.
-func (mr *MockEngineMockRecorder) DeclineEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) DeclineEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEvent", reflect.TypeOf((*MockEngine)(nil).DeclineEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEvent", reflect.TypeOf((*MockEngine)(nil).DeclineEvent), arg0, arg1, arg2)
 }
 
 // DeleteCalendar mocks base method.
@@ -206,6 +264,21 @@ func (mr *MockEngineMockRecorder) DisconnectUser(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectUser", reflect.TypeOf((*MockEngine)(nil).DisconnectUser), arg0)
 }
 
+// FindFreeRooms mocks base method.
+func (m *MockEngine) FindFreeRooms(arg0 *engine.User, arg1 string, arg2, arg3 time.Time) ([]*remote.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindFreeRooms", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*remote.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindFreeRooms indicates an expected call of FindFreeRooms.
+func (mr *MockEngineMockRecorder) FindFreeRooms(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFreeRooms", reflect.TypeOf((*MockEngine)(nil).FindFreeRooms), arg0, arg1, arg2, arg3)
+}
+
 // FindMeetingTimes mocks base method.
 func (m *MockEngine) FindMeetingTimes(arg0 *engine.User, arg1 *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error) {
 	m.ctrl.T.Helper()
@@ -235,6 +308,21 @@ func (mr *MockEngineMockRecorder) GetActingUser() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActingUser", reflect.TypeOf((*MockEngine)(nil).GetActingUser))
 }
 
+// GetAvailability mocks base method.
+func (m *MockEngine) GetAvailability(arg0 *engine.User, arg1 []string, arg2, arg3 time.Time, arg4 int) ([]*engine.UserAvailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailability", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*engine.UserAvailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailability indicates an expected call of GetAvailability.
+func (mr *MockEngineMockRecorder) GetAvailability(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailability", reflect.TypeOf((*MockEngine)(nil).GetAvailability), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetCalendarViews mocks base method.
 func (m *MockEngine) GetCalendarViews(arg0 []*store.User) ([]*remote.ViewCalendarResponse, error) {
 	m.ctrl.T.Helper()
@@ -355,6 +443,21 @@ func (mr *MockEngineMockRecorder) IsAuthorizedAdmin(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAuthorizedAdmin", reflect.TypeOf((*MockEngine)(nil).IsAuthorizedAdmin), arg0)
 }
 
+// ListFailedNotifications mocks base method.
+func (m *MockEngine) ListFailedNotifications() ([]*store.QueuedNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailedNotifications")
+	ret0, _ := ret[0].([]*store.QueuedNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailedNotifications indicates an expected call of ListFailedNotifications.
+func (mr *MockEngineMockRecorder) ListFailedNotifications() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailedNotifications", reflect.TypeOf((*MockEngine)(nil).ListFailedNotifications))
+}
+
 // ListRemoteSubscriptions mocks base method.
 func (m *MockEngine) ListRemoteSubscriptions() ([]*remote.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -385,6 +488,48 @@ func (mr *MockEngineMockRecorder) LoadMyEventSubscription() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadMyEventSubscription", reflect.TypeOf((*MockEngine)(nil).LoadMyEventSubscription))
 }
 
+// OfferMeetingTimes mocks base method.
+func (m *MockEngine) OfferMeetingTimes(arg0 *engine.User, arg1 *engine.MeetingSuggestions, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OfferMeetingTimes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OfferMeetingTimes indicates an expected call of OfferMeetingTimes.
+func (mr *MockEngineMockRecorder) OfferMeetingTimes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferMeetingTimes", reflect.TypeOf((*MockEngine)(nil).OfferMeetingTimes), arg0, arg1, arg2)
+}
+
+// OfferRooms mocks base method.
+func (m *MockEngine) OfferRooms(arg0 *engine.User, arg1 []*remote.Room, arg2, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OfferRooms", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OfferRooms indicates an expected call of OfferRooms.
+func (mr *MockEngineMockRecorder) OfferRooms(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferRooms", reflect.TypeOf((*MockEngine)(nil).OfferRooms), arg0, arg1, arg2, arg3)
+}
+
+// OpenConnectDialog mocks base method.
+func (m *MockEngine) OpenConnectDialog(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenConnectDialog", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OpenConnectDialog indicates an expected call of OpenConnectDialog.
+func (mr *MockEngineMockRecorder) OpenConnectDialog(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenConnectDialog", reflect.TypeOf((*MockEngine)(nil).OpenConnectDialog), arg0, arg1)
+}
+
 // PrintSettings mocks base method.
 func (m *MockEngine) PrintSettings(arg0 string) {
 	m.ctrl.T.Helper()
@@ -411,6 +556,34 @@ func (mr *MockEngineMockRecorder) ProcessAllDailySummary(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessAllDailySummary", reflect.TypeOf((*MockEngine)(nil).ProcessAllDailySummary), arg0)
 }
 
+// ProcessAllNotificationDigests mocks base method.
+func (m *MockEngine) ProcessAllNotificationDigests(arg0 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessAllNotificationDigests", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessAllNotificationDigests indicates an expected call of ProcessAllNotificationDigests.
+func (mr *MockEngineMockRecorder) ProcessAllNotificationDigests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessAllNotificationDigests", reflect.TypeOf((*MockEngine)(nil).ProcessAllNotificationDigests), arg0)
+}
+
+// RemoveDelegate mocks base method.
+func (m *MockEngine) RemoveDelegate(arg0 *engine.User, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDelegate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDelegate indicates an expected call of RemoveDelegate.
+func (mr *MockEngineMockRecorder) RemoveDelegate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDelegate", reflect.TypeOf((*MockEngine)(nil).RemoveDelegate), arg0, arg1)
+}
+
 // RenewMyEventSubscription mocks base method.
 func (m *MockEngine) RenewMyEventSubscription() (*store.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -426,18 +599,66 @@ func (mr *MockEngineMockRecorder) RenewMyEventSubscription() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewMyEventSubscription", reflect.TypeOf((*MockEngine)(nil).RenewMyEventSubscription))
 }
 
+// ReplayFailedNotifications mocks base method.
+func (m *MockEngine) ReplayFailedNotifications(arg0 ...string) (int, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReplayFailedNotifications", varargs...)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayFailedNotifications indicates an expected call of ReplayFailedNotifications.
+func (mr *MockEngineMockRecorder) ReplayFailedNotifications(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayFailedNotifications", reflect.TypeOf((*MockEngine)(nil).ReplayFailedNotifications), arg0...)
+}
+
 // RespondToEvent mocks base method.
-func (m *MockEngine) RespondToEvent(arg0 *engine.User, arg1, arg2 string) error {
+func (m *MockEngine) RespondToEvent(arg0 *engine.User, arg1, arg2 string, arg3 *remote.EventResponse) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RespondToEvent", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "RespondToEvent", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RespondToEvent indicates an expected call of RespondToEvent.
-func (mr *MockEngineMockRecorder) RespondToEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) RespondToEvent(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToEvent", reflect.TypeOf((*MockEngine)(nil).RespondToEvent), arg0, arg1, arg2, arg3)
+}
+
+// RespondToEventAs mocks base method.
+func (m *MockEngine) RespondToEventAs(arg0 *engine.User, arg1, arg2, arg3 string, arg4 *remote.EventResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondToEventAs", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RespondToEventAs indicates an expected call of RespondToEventAs.
+func (mr *MockEngineMockRecorder) RespondToEventAs(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToEventAs", reflect.TypeOf((*MockEngine)(nil).RespondToEventAs), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ScheduleMeeting mocks base method.
+func (m *MockEngine) ScheduleMeeting(arg0 *engine.User, arg1 string, arg2 []string, arg3, arg4 time.Time) (*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleMeeting", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleMeeting indicates an expected call of ScheduleMeeting.
+func (mr *MockEngineMockRecorder) ScheduleMeeting(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondToEvent", reflect.TypeOf((*MockEngine)(nil).RespondToEvent), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleMeeting", reflect.TypeOf((*MockEngine)(nil).ScheduleMeeting), arg0, arg1, arg2, arg3, arg4)
 }
 
 // SetDailySummaryEnabled mocks base method.
@@ -470,6 +691,21 @@ func (mr *MockEngineMockRecorder) SetDailySummaryPostTime(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDailySummaryPostTime", reflect.TypeOf((*MockEngine)(nil).SetDailySummaryPostTime), arg0, arg1)
 }
 
+// SuggestMeetingTimes mocks base method.
+func (m *MockEngine) SuggestMeetingTimes(arg0 *engine.User, arg1 []string, arg2 time.Duration, arg3, arg4 time.Time) (*engine.MeetingSuggestions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestMeetingTimes", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*engine.MeetingSuggestions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestMeetingTimes indicates an expected call of SuggestMeetingTimes.
+func (mr *MockEngineMockRecorder) SuggestMeetingTimes(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestMeetingTimes", reflect.TypeOf((*MockEngine)(nil).SuggestMeetingTimes), arg0, arg1, arg2, arg3, arg4)
+}
+
 // Sync mocks base method.
 func (m *MockEngine) Sync(arg0 string) (string, *engine.StatusSyncJobSummary, error) {
 	m.ctrl.T.Helper()
@@ -503,17 +739,46 @@ func (mr *MockEngineMockRecorder) SyncAll() *gomock.Call {
 }
 
 // TentativelyAcceptEvent mocks base method.
-func (m *MockEngine) TentativelyAcceptEvent(arg0 *engine.User, arg1 string) error {
+func (m *MockEngine) TentativelyAcceptEvent(arg0 *engine.User, arg1 string, arg2 *remote.EventResponse) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TentativelyAcceptEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "TentativelyAcceptEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TentativelyAcceptEvent indicates an expected call of TentativelyAcceptEvent.
-func (mr *MockEngineMockRecorder) TentativelyAcceptEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockEngineMockRecorder) TentativelyAcceptEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TentativelyAcceptEvent", reflect.TypeOf((*MockEngine)(nil).TentativelyAcceptEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TentativelyAcceptEvent", reflect.TypeOf((*MockEngine)(nil).TentativelyAcceptEvent), arg0, arg1, arg2)
+}
+
+// ThrottledRequests mocks base method.
+func (m *MockEngine) ThrottledRequests() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ThrottledRequests")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// ThrottledRequests indicates an expected call of ThrottledRequests.
+func (mr *MockEngineMockRecorder) ThrottledRequests() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ThrottledRequests", reflect.TypeOf((*MockEngine)(nil).ThrottledRequests))
+}
+
+// UpdateEvent mocks base method.
+func (m *MockEngine) UpdateEvent(arg0 *engine.User, arg1 string, arg2 *remote.Event) (*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockEngineMockRecorder) UpdateEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockEngine)(nil).UpdateEvent), arg0, arg1, arg2)
 }
 
 // ViewCalendar mocks base method.
@@ -531,6 +796,21 @@ func (mr *MockEngineMockRecorder) ViewCalendar(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCalendar", reflect.TypeOf((*MockEngine)(nil).ViewCalendar), arg0, arg1, arg2)
 }
 
+// ViewCalendarAs mocks base method.
+func (m *MockEngine) ViewCalendarAs(arg0 *engine.User, arg1 string, arg2, arg3 time.Time) ([]*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCalendarAs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCalendarAs indicates an expected call of ViewCalendarAs.
+func (mr *MockEngineMockRecorder) ViewCalendarAs(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCalendarAs", reflect.TypeOf((*MockEngine)(nil).ViewCalendarAs), arg0, arg1, arg2, arg3)
+}
+
 // Welcome mocks base method.
 func (m *MockEngine) Welcome(arg0 string) error {
 	m.ctrl.T.Helper()
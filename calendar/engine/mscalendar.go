@@ -4,6 +4,8 @@
 package engine
 
 import (
+	"sync/atomic"
+
 	"github.com/mattermost/mattermost/server/public/model"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
@@ -23,6 +25,11 @@ type Engine interface {
 	Welcomer
 	Settings
 	DailySummary
+	NotificationDigests
+	Rooms
+	MeetingTimes
+
+	ThrottledRequests() int64
 }
 
 // Dependencies contains all API dependencies
@@ -48,6 +55,7 @@ type PluginAPI interface {
 	UpdateMattermostUserCustomStatus(mattermostUserID string, customStatus *model.CustomStatus) *model.AppError
 	RemoveMattermostUserCustomStatus(mattermostUserID string) *model.AppError
 	GetPost(postID string) (*model.Post, error)
+	OpenInteractiveDialog(dialog model.OpenDialogRequest) error
 	CanLinkEventToChannel(channelID, userID string) bool
 	CanReadChannel(channelID, userID string) bool
 	SearchLinkableChannelForUser(teamID, mattermostUserID, search string) ([]*model.Channel, error)
@@ -66,6 +74,14 @@ type mscalendar struct {
 
 	actingUser *User
 	client     remote.Client
+
+	// background is set for the engines of background jobs, see
+	// remote.BackgroundContext.
+	background bool
+
+	// throttled counts the responses throttled by the remote for the
+	// clients made by the engine.
+	throttled *atomic.Int64
 }
 
 // copy returns a copy of the calendar engine
@@ -76,6 +92,8 @@ func (m mscalendar) copy() *mscalendar {
 		Env:        m.Env,
 		actingUser: &user,
 		client:     client,
+		background: m.background,
+		throttled:  m.throttled,
 	}
 }
 
@@ -83,5 +101,17 @@ func New(env Env, actingMattermostUserID string) Engine {
 	return &mscalendar{
 		Env:        env,
 		actingUser: NewUser(actingMattermostUserID),
+		throttled:  &atomic.Int64{},
+	}
+}
+
+// NewBackground returns the engine of a background job. Its requests wait
+// for a throttled remote instead of failing fast like those of users.
+func NewBackground(env Env, actingMattermostUserID string) Engine {
+	return &mscalendar{
+		Env:        env,
+		actingUser: NewUser(actingMattermostUserID),
+		background: true,
+		throttled:  &atomic.Int64{},
 	}
 }
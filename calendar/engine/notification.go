@@ -5,7 +5,8 @@ package engine
 
 import (
 	"context"
-	"fmt"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -16,7 +17,28 @@ import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
 )
 
-const maxQueueSize = 1024
+const (
+	notificationPollInterval    = 5 * time.Second
+	notificationLease           = 2 * time.Minute
+	notificationRetryBackoff    = 30 * time.Second
+	notificationMaxRetryBackoff = 15 * time.Minute
+
+	// A failing notification holds back the later ones of its subscription,
+	// so it is moved to the dead letters after about 15 minutes of retries.
+	maxNotificationAttempts = 6
+
+	defaultNotificationWorkers = 4
+	maxNotificationWorkers     = 32
+)
+
+// notificationLeaseRenewal is how often the lease of a notification being
+// processed is renewed.
+var notificationLeaseRenewal = notificationLease / 4
+
+var (
+	errOrphanedSubscription = errors.New("subscription is orphaned")
+	errUnauthorizedWebhook  = errors.New("unauthorized webhook")
+)
 
 const (
 	FieldSubject        = "Subject"
@@ -28,6 +50,7 @@ const (
 	FieldAttendees      = "Attendees"
 	FieldOrganizer      = "Organizer"
 	FieldResponseStatus = "ResponseStatus"
+	FieldRecurrence     = "Recurrence"
 )
 
 const (
@@ -44,10 +67,11 @@ const (
 	ResponseNone  = "notResponded"
 )
 
-var importantNotificationChanges = []string{FieldSubject, FieldWhen}
+var importantNotificationChanges = []string{FieldSubject, FieldWhen, FieldRecurrence}
 
 var notificationFieldOrder = []string{
 	FieldWhen,
+	FieldRecurrence,
 	FieldLocation,
 	FieldAttendees,
 	FieldImportance,
@@ -61,60 +85,262 @@ type NotificationProcessor interface {
 
 type notificationProcessor struct {
 	Env
-	envChan chan Env
+}
 
-	queue chan *remote.Notification
-	quit  chan bool
+// notificationWorkers processes the queued notifications with a pool of
+// workers. Notifications of different subscriptions are processed in
+// parallel, those of a subscription in order.
+type notificationWorkers struct {
+	// lock guards env, which Configure replaces while workers run.
+	lock sync.RWMutex
+	env  Env
+
+	// runLock guards size and quit, so that the workers are started and
+	// stopped once at a time.
+	runLock sync.Mutex
+	size    int
+	wake    chan struct{}
+	quit    chan struct{}
+	done    sync.WaitGroup
 }
 
 func NewNotificationProcessor(env Env) NotificationProcessor {
-	processor := &notificationProcessor{
-		Env:     env,
-		envChan: make(chan (Env)),
-		queue:   make(chan (*remote.Notification), maxQueueSize),
-		quit:    make(chan (bool)),
+	workers := &notificationWorkers{
+		env:  env,
+		wake: make(chan (struct{}), 1),
+	}
+	workers.start(notificationWorkerCount(env))
+	return workers
+}
+
+// notificationWorkerCount returns the configured number of workers, within
+// bounds.
+func notificationWorkerCount(env Env) int {
+	n := 0
+	if env.Config != nil {
+		n = env.NotificationWorkers
+	}
+	switch {
+	case n <= 0:
+		return defaultNotificationWorkers
+	case n > maxNotificationWorkers:
+		return maxNotificationWorkers
 	}
-	go processor.work()
-	return processor
+	return n
 }
 
-func (processor *notificationProcessor) Enqueue(notifications ...*remote.Notification) error {
+// Enqueue stores the notifications in the durable queue, to be processed by
+// any node of the cluster. It returns store.ErrNotificationQueueFull when the
+// queue is saturated.
+func (workers *notificationWorkers) Enqueue(notifications ...*remote.Notification) error {
+	env := workers.getEnv()
+
+	now := time.Now()
+	items := []*store.QueuedNotification{}
 	for _, n := range notifications {
-		select {
-		case processor.queue <- n:
-		default:
-			return fmt.Errorf("webhook notification: queue full, dropped notification")
-		}
+		items = append(items, &store.QueuedNotification{
+			ID:           model.NewId(),
+			Notification: n,
+			EnqueuedAt:   now,
+		})
+	}
+	err := env.Store.EnqueueNotifications(items...)
+	if err != nil {
+		return errors.Wrap(err, "webhook notification: failed to queue notifications")
 	}
+
+	workers.signal()
 	return nil
 }
 
-func (processor *notificationProcessor) Configure(env Env) {
-	processor.envChan <- env
+func (workers *notificationWorkers) Configure(env Env) {
+	workers.lock.Lock()
+	workers.env = env
+	workers.lock.Unlock()
+
+	workers.runLock.Lock()
+	defer workers.runLock.Unlock()
+	size := notificationWorkerCount(env)
+	if size != workers.size {
+		workers.stop()
+		workers.start(size)
+	}
+}
+
+// Quit stops processing. Queued notifications are kept in the store.
+func (workers *notificationWorkers) Quit() {
+	workers.runLock.Lock()
+	defer workers.runLock.Unlock()
+	workers.stop()
+}
+
+func (workers *notificationWorkers) start(size int) {
+	workers.size = size
+	workers.quit = make(chan (struct{}))
+	for i := 0; i < size; i++ {
+		workers.done.Add(1)
+		go workers.work(workers.quit)
+	}
+}
+
+// stop stops the workers, if they are running, and waits for them to finish.
+func (workers *notificationWorkers) stop() {
+	if workers.quit == nil {
+		return
+	}
+	close(workers.quit)
+	workers.quit = nil
+	workers.done.Wait()
+}
+
+func (workers *notificationWorkers) getEnv() Env {
+	workers.lock.RLock()
+	defer workers.lock.RUnlock()
+	return workers.env
 }
 
-func (processor *notificationProcessor) Quit() {
-	processor.quit <- true
+// signal wakes up an idle worker, if any.
+func (workers *notificationWorkers) signal() {
+	select {
+	case workers.wake <- struct{}{}:
+	default:
+	}
 }
 
-func (processor *notificationProcessor) work() {
+func (workers *notificationWorkers) work(quit chan struct{}) {
+	defer workers.done.Done()
+
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+
 	for {
+		workers.processQueue(quit)
+
 		select {
-		case n := <-processor.queue:
-			err := processor.processNotification(n)
-			if err != nil {
-				processor.Logger.With(bot.LogContext{
-					"subscriptionID": n.SubscriptionID,
-				}).Infof("webhook notification: failed: `%v`.", err)
-			}
+		case <-workers.wake:
+		case <-ticker.C:
+		case <-quit:
+			return
+		}
+	}
+}
 
-		case env := <-processor.envChan:
-			processor.Env = env
+// processQueue processes the queued notifications that are due, until there
+// are none left or the workers are stopped.
+func (workers *notificationWorkers) processQueue(quit chan struct{}) {
+	for {
+		select {
+		case <-quit:
+			return
+		default:
+		}
 
-		case <-processor.quit:
+		processor := &notificationProcessor{Env: workers.getEnv()}
+		item, err := processor.Store.LeaseNotification(time.Now(), notificationLease)
+		if err == store.ErrNotFound {
+			return
+		}
+		if err != nil {
+			processor.Logger.Warnf("webhook notification: failed to read the queue: `%v`.", err)
 			return
 		}
+
+		// There may be more to process, for other subscriptions.
+		workers.signal()
+		processor.processQueuedNotification(item)
+	}
+}
+
+// processQueuedNotification processes a leased notification, and retries it
+// later with a growing delay if it fails. Notifications that keep failing are
+// moved to the dead letters, for an admin to inspect and replay.
+func (processor *notificationProcessor) processQueuedNotification(item *store.QueuedNotification) {
+	item.Attempts++
+	logger := processor.Logger.With(bot.LogContext{
+		"subscriptionID": item.Notification.SubscriptionID,
+		"notificationID": item.ID,
+		"attempts":       item.Attempts,
+	})
+
+	stopRenewal := processor.renewLease(item.ID)
+	processErr := processor.processNotification(item.Notification)
+	stopRenewal()
+
+	var err error
+	switch {
+	case processErr == nil:
+		err = processor.Store.CompleteNotification(item.ID)
+
+	case isPermanentNotificationError(processErr):
+		logger.Infof("webhook notification: dropped: `%v`.", processErr)
+		err = processor.Store.CompleteNotification(item.ID)
+
+	case item.Attempts >= maxNotificationAttempts:
+		logger.Warnf("webhook notification: failed, moved to dead letters: `%v`.", processErr)
+		item.LastError = processErr.Error()
+		item.FailedAt = time.Now()
+		err = processor.Store.DeadLetterNotification(item)
+
+	default:
+		logger.Infof("webhook notification: failed, will retry: `%v`.", processErr)
+		item.LastError = processErr.Error()
+		item.NextAttemptAt = time.Now().Add(notificationRetryDelay(item.Attempts))
+		err = processor.Store.RetryNotification(item)
+	}
+	if err != nil {
+		logger.Warnf("webhook notification: failed to update the queue: `%v`.", err)
+	}
+}
+
+// notificationRetryDelay doubles the delay before each new attempt.
+func notificationRetryDelay(attempts int) time.Duration {
+	delay := notificationRetryBackoff
+	for i := 1; i < attempts && delay < notificationMaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > notificationMaxRetryBackoff {
+		delay = notificationMaxRetryBackoff
 	}
+	return delay
+}
+
+// renewLease keeps the notification leased while it is processed, however
+// long the remote takes, so that no other node processes it meanwhile. The
+// returned function stops renewing the lease.
+func (processor *notificationProcessor) renewLease(id string) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(notificationLeaseRenewal)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				err := processor.Store.RenewNotificationLease(id, notificationLease)
+				if err != nil {
+					processor.Logger.With(bot.LogContext{
+						"notificationID": id,
+					}).Warnf("webhook notification: failed to renew the lease: `%v`.", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// isPermanentNotificationError returns true for failures retrying would not
+// fix, such as notifications of deleted subscriptions.
+func isPermanentNotificationError(err error) bool {
+	return errors.Is(err, errOrphanedSubscription) ||
+		errors.Is(err, errUnauthorizedWebhook) ||
+		errors.Is(err, store.ErrNotFound)
 }
 
 func (processor *notificationProcessor) processNotification(n *remote.Notification) error {
@@ -127,16 +353,22 @@ func (processor *notificationProcessor) processNotification(n *remote.Notificati
 		return err
 	}
 	if sub.Remote.ID != creator.Settings.EventSubscriptionID {
-		return errors.New("subscription is orphaned")
+		return errOrphanedSubscription
 	}
 	if sub.Remote.ClientState != "" && sub.Remote.ClientState != n.ClientState {
-		return errors.New("unauthorized webhook")
+		return errUnauthorizedWebhook
 	}
 
 	n.Subscription = sub.Remote
 	n.SubscriptionCreator = creator.Remote
+	if processor.Config != nil && processor.EnableEventMirror {
+		// The calendar changed, the next read of the mirror picks it up
+		markEventMirrorStale(processor.Store, processor.Logger, creator.Remote.ID)
+	}
 
-	client, err := processor.Remote.MakeUserClient(context.Background(), creator.OAuth2Token, sub.MattermostCreatorID, processor.Poster, processor.Store)
+	// Notifications are processed in the background, and can wait for a
+	// throttled remote.
+	client, err := processor.Remote.MakeUserClient(remote.BackgroundContext(context.Background()), creator.OAuth2Token, sub.MattermostCreatorID, processor.Poster, processor.Store)
 	if err != nil {
 		// MakeUserClient already disconnects the user (when applicable) and
 		// logs a warning. Returning here lets the work loop log the failure
@@ -145,26 +377,19 @@ func (processor *notificationProcessor) processNotification(n *remote.Notificati
 		return errors.Wrap(err, "unable to build user client for notification")
 	}
 
+	if n.LifecycleEvent != "" {
+		return processor.processLifecycleEvent(n, creator, client)
+	}
+
 	if n.RecommendRenew {
-		var renewed *remote.Subscription
-		renewed, err = client.RenewSubscription(processor.Config.GetNotificationURL(), sub.Remote.CreatorID, n.Subscription)
+		err = processor.renewSubscription(n, creator, client)
 		if err != nil {
 			return err
 		}
+	}
 
-		storedSub := &store.Subscription{
-			Remote:              renewed,
-			MattermostCreatorID: creator.MattermostUserID,
-			PluginVersion:       processor.Config.PluginVersion,
-		}
-		err = processor.Store.StoreUserSubscription(creator, storedSub)
-		if err != nil {
-			return err
-		}
-		processor.Logger.With(bot.LogContext{
-			"MattermostUserID": creator.MattermostUserID,
-			"SubscriptionID":   n.SubscriptionID,
-		}).Debugf("webhook notification: renewed user subscription.")
+	if n.ChangeType == remote.ChangeTypeDeleted {
+		return processor.processDeletedEvent(n, creator, client)
 	}
 
 	if n.IsBare {
@@ -175,6 +400,8 @@ func (processor *notificationProcessor) processNotification(n *remote.Notificati
 	}
 
 	var sa *model.SlackAttachment
+	isNewInvitation := false
+	kind := store.DigestKindUpdated
 	prior, err := processor.Store.LoadUserEvent(creator.MattermostUserID, n.Event.ICalUID)
 	if err != nil && err != store.ErrNotFound {
 		return err
@@ -186,6 +413,10 @@ func (processor *notificationProcessor) processNotification(n *remote.Notificati
 	}
 	timezone := mailSettings.TimeZone
 
+	if n.Event.IsCancelled {
+		return processor.processCancelledEvent(n, creator, prior, timezone)
+	}
+
 	if prior != nil {
 		var changed bool
 		changed, sa = processor.updatedEventSlackAttachment(n, prior.Remote, timezone)
@@ -202,12 +433,21 @@ func (processor *notificationProcessor) processNotification(n *remote.Notificati
 	} else {
 		sa = processor.newEventSlackAttachment(n, timezone)
 		prior = &store.Event{}
+		kind = store.DigestKindNew
+		isNewInvitation = n.Event.ResponseRequested && !n.Event.IsOrganizer
 	}
 
-	_, err = processor.Poster.DMWithAttachments(creator.MattermostUserID, sa)
+	postID, err := processor.postEventNotification(creator, n.Event, kind, timezone, sa)
 	if err != nil {
 		return err
 	}
+	if postID != "" {
+		prior.PostIDs = append(prior.PostIDs, postID)
+	}
+
+	if isNewInvitation && len(creator.Delegates) > 0 {
+		processor.notifyDelegates(creator, n)
+	}
 
 	prior.Remote = n.Event
 	err = processor.Store.StoreUserEvent(creator.MattermostUserID, prior)
@@ -222,3 +462,25 @@ func (processor *notificationProcessor) processNotification(n *remote.Notificati
 
 	return nil
 }
+
+func (processor *notificationProcessor) renewSubscription(n *remote.Notification, creator *store.User, client remote.Client) error {
+	renewed, err := client.RenewSubscription(processor.Config.GetNotificationURL(), n.Subscription.CreatorID, n.Subscription)
+	if err != nil {
+		return err
+	}
+
+	storedSub := &store.Subscription{
+		Remote:              renewed,
+		MattermostCreatorID: creator.MattermostUserID,
+		PluginVersion:       processor.Config.PluginVersion,
+	}
+	err = processor.Store.StoreUserSubscription(creator, storedSub)
+	if err != nil {
+		return err
+	}
+	processor.Logger.With(bot.LogContext{
+		"MattermostUserID": creator.MattermostUserID,
+		"SubscriptionID":   n.SubscriptionID,
+	}).Debugf("webhook notification: renewed user subscription.")
+	return nil
+}
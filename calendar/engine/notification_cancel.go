@@ -0,0 +1,159 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const (
+	titleCancelled = "(cancelled)"
+	titleDeleted   = "(deleted)"
+
+	// Microsoft Graph prefixes the subject of cancelled meetings.
+	cancelledSubjectPrefix = "Canceled: "
+
+	textMeetingCancelled = "This meeting was cancelled."
+	textEventDeleted     = "This event was deleted from your calendar."
+)
+
+// processCancelledEvent notifies the user that an event they were notified of
+// was cancelled by its organizer. The event is kept as cancelled, so that the
+// following updates and its deletion are not notified again.
+func (processor *notificationProcessor) processCancelledEvent(n *remote.Notification, creator *store.User, prior *store.Event, timezone string) error {
+	if prior == nil || prior.Remote.IsCancelled {
+		processor.Logger.With(bot.LogContext{
+			"MattermostUserID": creator.MattermostUserID,
+			"SubscriptionID":   n.SubscriptionID,
+			"EventID":          n.Event.ID,
+		}).Debugf("webhook notification: ignored cancelled event.")
+		return nil
+	}
+
+	sa := processor.cancelledEventSlackAttachment(n, titleCancelled, textMeetingCancelled, timezone)
+	_, err := processor.postEventNotification(creator, n.Event, store.DigestKindCancelled, timezone, sa)
+	if err != nil {
+		return err
+	}
+
+	// A cancelled occurrence leaves the rest of its series, and the posts
+	// about it, as they are.
+	if n.Event.ID != prior.Remote.ID && (n.Event.Type == remote.EventTypeOccurrence || n.Event.Type == remote.EventTypeException) {
+		return nil
+	}
+
+	processor.strikeThroughPosts(prior.PostIDs, prior.Remote)
+	prior.Remote = n.Event
+	return processor.Store.StoreUserEvent(creator.MattermostUserID, prior)
+}
+
+// processDeletedEvent notifies the user that an event was removed from their
+// calendar. The notification only carries the ID of the event, the rest is
+// read from the stored event.
+func (processor *notificationProcessor) processDeletedEvent(n *remote.Notification, creator *store.User, client remote.Client) error {
+	prior, err := processor.Store.LoadUserEventByRemoteID(creator.MattermostUserID, n.Event.ID)
+	if err == store.ErrNotFound {
+		processor.Logger.With(bot.LogContext{
+			"MattermostUserID": creator.MattermostUserID,
+			"SubscriptionID":   n.SubscriptionID,
+			"EventID":          n.Event.ID,
+		}).Debugf("webhook notification: ignored deleted event that was not notified.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !prior.Remote.IsCancelled {
+		var mailSettings *remote.MailboxSettings
+		mailSettings, err = client.GetMailboxSettings(creator.Remote.ID)
+		if err != nil {
+			return err
+		}
+
+		// The meeting is cancelled for its attendees when its organizer
+		// deletes it.
+		event := prior.Remote
+		kind, title, text := store.DigestKindDeleted, titleDeleted, textEventDeleted
+		if event.IsOrganizer && len(event.Attendees) > 0 {
+			kind, title, text = store.DigestKindCancelled, titleCancelled, textMeetingCancelled
+		}
+		sa := processor.cancelledEventSlackAttachment(&remote.Notification{Event: event}, title, text, mailSettings.TimeZone)
+		_, err = processor.postEventNotification(creator, event, kind, mailSettings.TimeZone, sa)
+		if err != nil {
+			return err
+		}
+		processor.strikeThroughPosts(prior.PostIDs, event)
+
+		if event.IsOrganizer {
+			processor.notifyLinkedChannelsOfCancellation(creator, event, mailSettings.TimeZone)
+		}
+	}
+
+	return processor.Store.DeleteUserEvent(creator.MattermostUserID, prior.Remote.ICalUID)
+}
+
+// strikeThroughPosts marks the earlier notifications of an event as
+// cancelled. Digest posts list other events too, only the attachment linking
+// to the event is marked in those.
+func (processor *notificationProcessor) strikeThroughPosts(postIDs []string, event *remote.Event) {
+	for _, postID := range postIDs {
+		post, err := processor.PluginAPI.GetPost(postID)
+		if err != nil {
+			processor.Logger.With(bot.LogContext{
+				"PostID": postID,
+				"err":    err.Error(),
+			}).Warnf("webhook notification: failed to load the post of a cancelled event.")
+			continue
+		}
+
+		attachments := post.Attachments()
+		for _, sa := range attachments {
+			if len(attachments) > 1 && sa.TitleLink != event.Weblink {
+				continue
+			}
+			strikeThroughCancelledAttachment(sa)
+		}
+		model.ParseSlackAttachment(post, attachments)
+		err = processor.Poster.UpdatePost(post)
+		if err != nil {
+			processor.Logger.With(bot.LogContext{
+				"PostID": postID,
+				"err":    err.Error(),
+			}).Warnf("webhook notification: failed to update the post of a cancelled event.")
+		}
+	}
+}
+
+// notifyLinkedChannelsOfCancellation tells the channels linked to an event
+// that its organizer cancelled it. Events cancelled from Mattermost have no
+// linked channels left.
+func (processor *notificationProcessor) notifyLinkedChannelsOfCancellation(creator *store.User, event *remote.Event, timezone string) {
+	eventMetadata, err := processor.Store.LoadEventMetadata(event.ICalUID)
+	if err != nil {
+		if err != store.ErrNotFound {
+			processor.Logger.With(bot.LogContext{
+				"eventID": event.ID,
+				"err":     err.Error(),
+			}).Warnf("webhook notification: failed to load event metadata.")
+		}
+		return
+	}
+
+	user := newUserFromStoredUser(creator)
+	user.MattermostUser, _ = processor.PluginAPI.GetMattermostUser(creator.MattermostUserID)
+	postToLinkedChannels(processor.Env, eventMetadata, user, event, timezone, "The event **%s** was canceled by %s")
+
+	err = processor.Store.DeleteEventMetadata(event.ICalUID)
+	if err != nil {
+		processor.Logger.With(bot.LogContext{
+			"eventID": event.ID,
+			"err":     err.Error(),
+		}).Warnf("webhook notification: failed to delete event metadata.")
+	}
+}
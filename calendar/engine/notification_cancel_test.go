@@ -0,0 +1,232 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+)
+
+func TestProcessCancelledNotification(t *testing.T) {
+	newCancelledEvent := func() *remote.Event {
+		event := newTestEvent("1", "event_location_display_name", "Canceled: event_subject")
+		event.IsCancelled = true
+		return event
+	}
+	newPriorEvent := func() *store.Event {
+		return &store.Event{
+			Remote:  newTestEvent("1", "event_location_display_name", "event_subject"),
+			PostIDs: []string{"post_id_1"},
+		}
+	}
+	newOrganizedEvent := func() *store.Event {
+		prior := newPriorEvent()
+		prior.Remote.IsOrganizer = true
+		prior.Remote.Start = remote.NewDateTime(time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), "UTC")
+		prior.Remote.End = remote.NewDateTime(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), "UTC")
+		prior.Remote.Attendees = []*remote.Attendee{
+			{EmailAddress: &remote.EmailAddress{Address: "attendee_email"}},
+		}
+		return prior
+	}
+	notifiedPost := func() *model.Post {
+		post := &model.Post{Id: "post_id_1"}
+		model.ParseSlackAttachment(post, []*model.SlackAttachment{{
+			Title:   "(new) event_subject",
+			Fields:  []*model.SlackAttachmentField{{Title: FieldWhen, Value: "Monday"}},
+			Actions: []*model.PostAction{{Name: "Accept"}},
+		}})
+		return post
+	}
+	expectStruckThroughPost := func(papi *mock_plugin_api.MockPluginAPI, p *mock_bot.MockPoster) {
+		papi.EXPECT().GetPost("post_id_1").Return(notifiedPost(), nil)
+		p.EXPECT().UpdatePost(gomock.Any()).DoAndReturn(func(post *model.Post) error {
+			attachments := post.Attachments()
+			require.Len(t, attachments, 1)
+			require.Equal(t, "(cancelled) event_subject", attachments[0].Title)
+			require.Equal(t, "~~Monday~~", attachments[0].Fields[0].Value)
+			require.Empty(t, attachments[0].Actions)
+			return nil
+		})
+	}
+
+	for name, tc := range map[string]struct {
+		notification *remote.Notification
+		setup        func(*mock_store.MockStore, *mock_remote.MockClient, *mock_bot.MockPoster, *mock_plugin_api.MockPluginAPI)
+	}{
+		"cancelled event notifies the user and strikes through earlier posts": {
+			notification: &remote.Notification{Event: newCancelledEvent()},
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, p *mock_bot.MockPoster, papi *mock_plugin_api.MockPluginAPI) {
+				s.EXPECT().LoadUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(newPriorEvent(), nil)
+				c.EXPECT().GetMailboxSettings("remote_user_id_1").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil)
+				p.EXPECT().DMWithAttachments("creator_mm_id_1", gomock.Any()).DoAndReturn(func(_ string, sa ...*model.SlackAttachment) (string, error) {
+					require.Equal(t, "(cancelled) event_subject", sa[0].Title)
+					require.Equal(t, textMeetingCancelled, sa[0].Text)
+					return "post_id_2", nil
+				})
+				expectStruckThroughPost(papi, p)
+				s.EXPECT().StoreUserEvent("creator_mm_id_1", &store.Event{
+					Remote:  newCancelledEvent(),
+					PostIDs: []string{"post_id_1"},
+				}).Return(nil)
+			},
+		},
+		"cancelled event already notified is ignored": {
+			notification: &remote.Notification{Event: newCancelledEvent()},
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, p *mock_bot.MockPoster, _ *mock_plugin_api.MockPluginAPI) {
+				s.EXPECT().LoadUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(&store.Event{Remote: newCancelledEvent()}, nil)
+				c.EXPECT().GetMailboxSettings("remote_user_id_1").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil)
+				p.EXPECT().DMWithAttachments(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		"cancelled event never notified is ignored": {
+			notification: &remote.Notification{Event: newCancelledEvent()},
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, p *mock_bot.MockPoster, _ *mock_plugin_api.MockPluginAPI) {
+				s.EXPECT().LoadUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(nil, store.ErrNotFound)
+				c.EXPECT().GetMailboxSettings("remote_user_id_1").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil)
+				p.EXPECT().DMWithAttachments(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		"deleted event notifies the user and forgets the event": {
+			notification: &remote.Notification{ChangeType: remote.ChangeTypeDeleted, Event: &remote.Event{ID: "remote_event_id_1"}},
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, p *mock_bot.MockPoster, papi *mock_plugin_api.MockPluginAPI) {
+				s.EXPECT().LoadUserEventByRemoteID("creator_mm_id_1", "remote_event_id_1").Return(newPriorEvent(), nil)
+				c.EXPECT().GetMailboxSettings("remote_user_id_1").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil)
+				p.EXPECT().DMWithAttachments("creator_mm_id_1", gomock.Any()).DoAndReturn(func(_ string, sa ...*model.SlackAttachment) (string, error) {
+					require.Equal(t, "(deleted) event_subject", sa[0].Title)
+					require.Equal(t, textEventDeleted, sa[0].Text)
+					return "", nil
+				})
+				expectStruckThroughPost(papi, p)
+				s.EXPECT().DeleteUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(nil)
+			},
+		},
+		"deleted event cancelled by its organizer notifies linked channels": {
+			notification: &remote.Notification{ChangeType: remote.ChangeTypeDeleted, Event: &remote.Event{ID: "remote_event_id_1"}},
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, p *mock_bot.MockPoster, papi *mock_plugin_api.MockPluginAPI) {
+				s.EXPECT().LoadUserEventByRemoteID("creator_mm_id_1", "remote_event_id_1").Return(newOrganizedEvent(), nil)
+				c.EXPECT().GetMailboxSettings("remote_user_id_1").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil)
+				p.EXPECT().DMWithAttachments("creator_mm_id_1", gomock.Any()).DoAndReturn(func(_ string, sa ...*model.SlackAttachment) (string, error) {
+					require.Equal(t, "(cancelled) event_subject", sa[0].Title)
+					return "", nil
+				})
+				expectStruckThroughPost(papi, p)
+				s.EXPECT().LoadEventMetadata("remote_event_uid_1").Return(&store.EventMetadata{
+					LinkedChannelIDs: map[string]struct{}{"channel_id_1": {}},
+				}, nil)
+				papi.EXPECT().GetMattermostUser("creator_mm_id_1").Return(&model.User{Username: "creator"}, nil)
+				p.EXPECT().CreatePost(gomock.Any()).DoAndReturn(func(post *model.Post) error {
+					require.Equal(t, "channel_id_1", post.ChannelId)
+					require.Contains(t, post.Message, "was canceled by")
+					return nil
+				})
+				s.EXPECT().DeleteEventMetadata("remote_event_uid_1").Return(nil)
+				s.EXPECT().DeleteUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(nil)
+			},
+		},
+		"deleted event already cancelled is only forgotten": {
+			notification: &remote.Notification{ChangeType: remote.ChangeTypeDeleted, Event: &remote.Event{ID: "remote_event_id_1"}},
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, p *mock_bot.MockPoster, _ *mock_plugin_api.MockPluginAPI) {
+				s.EXPECT().LoadUserEventByRemoteID("creator_mm_id_1", "remote_event_id_1").Return(&store.Event{Remote: newCancelledEvent()}, nil)
+				c.EXPECT().GetMailboxSettings(gomock.Any()).Times(0)
+				p.EXPECT().DMWithAttachments(gomock.Any(), gomock.Any()).Times(0)
+				s.EXPECT().DeleteUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(nil)
+			},
+		},
+		"deleted event never notified is ignored": {
+			notification: &remote.Notification{ChangeType: remote.ChangeTypeDeleted, Event: &remote.Event{ID: "remote_event_id_1"}},
+			setup: func(s *mock_store.MockStore, _ *mock_remote.MockClient, p *mock_bot.MockPoster, _ *mock_plugin_api.MockPluginAPI) {
+				s.EXPECT().LoadUserEventByRemoteID("creator_mm_id_1", "remote_event_id_1").Return(nil, store.ErrNotFound)
+				p.EXPECT().DMWithAttachments(gomock.Any(), gomock.Any()).Times(0)
+				s.EXPECT().DeleteUserEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockPoster := mock_bot.NewMockPoster(ctrl)
+			mockRemote := mock_remote.NewMockRemote(ctrl)
+			mockClient := mock_remote.NewMockClient(ctrl)
+			mockPluginAPI := mock_plugin_api.NewMockPluginAPI(ctrl)
+			env := Env{
+				Config: &config.Config{PluginVersion: "x.x.x", PluginURL: "https://plugin"},
+				Dependencies: &Dependencies{
+					Store:     mockStore,
+					Logger:    &bot.NilLogger{},
+					Poster:    mockPoster,
+					Remote:    mockRemote,
+					PluginAPI: mockPluginAPI,
+				},
+			}
+
+			user := newTestUser()
+			sub := &store.Subscription{
+				PluginVersion:       "x.x.x",
+				Remote:              &remote.Subscription{ID: "remote_subscription_id_1", CreatorID: "remote_user_id_1"},
+				MattermostCreatorID: "creator_mm_id_1",
+			}
+			mockStore.EXPECT().LoadSubscription("remote_subscription_id_1").Return(sub, nil)
+			mockStore.EXPECT().LoadUser("creator_mm_id_1").Return(user, nil)
+			mockRemote.EXPECT().MakeUserClient(remote.BackgroundContext(context.Background()), user.OAuth2Token, "creator_mm_id_1", mockPoster, mockStore).Return(mockClient, nil)
+			mockClient.EXPECT().GetNotificationData(gomock.Any()).Times(0)
+			tc.setup(mockStore, mockClient, mockPoster, mockPluginAPI)
+
+			n := tc.notification
+			n.SubscriptionID = "remote_subscription_id_1"
+			processor := newTestNotificationProcessor(env)
+			err := processor.processNotification(n)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestStrikeThroughDigestPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPluginAPI := mock_plugin_api.NewMockPluginAPI(ctrl)
+	mockPoster := mock_bot.NewMockPoster(ctrl)
+	processor := newTestNotificationProcessor(Env{
+		Dependencies: &Dependencies{
+			Logger:    &bot.NilLogger{},
+			Poster:    mockPoster,
+			PluginAPI: mockPluginAPI,
+		},
+	})
+
+	post := &model.Post{Id: "digest_post_id"}
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{
+		{Title: "(new) event_subject", TitleLink: "event_weblink", Actions: []*model.PostAction{{Name: "Accept"}}},
+		{Title: "(updated) other_subject", TitleLink: "other_weblink", Actions: []*model.PostAction{{Name: "Accept"}}},
+	})
+	mockPluginAPI.EXPECT().GetPost("digest_post_id").Return(post, nil)
+	mockPoster.EXPECT().UpdatePost(gomock.Any()).DoAndReturn(func(post *model.Post) error {
+		attachments := post.Attachments()
+		require.Len(t, attachments, 2)
+		require.Equal(t, "(cancelled) event_subject", attachments[0].Title)
+		require.Empty(t, attachments[0].Actions)
+		require.Equal(t, "(updated) other_subject", attachments[1].Title)
+		require.NotEmpty(t, attachments[1].Actions)
+		return nil
+	})
+
+	processor.strikeThroughPosts([]string{"digest_post_id"}, newTestEvent("1", "event_location_display_name", "event_subject"))
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
+)
+
+const (
+	notificationCertificateValidity = 365 * 24 * time.Hour
+
+	// Renewed subscriptions keep the certificate they were created with, so
+	// it is replaced well before it expires.
+	notificationCertificateRenewBefore = 30 * 24 * time.Hour
+)
+
+// LoadNotificationCertificate returns the certificate the remote encrypts
+// the resource data of notifications with. A new certificate is created when
+// there is none, or when the stored one is about to expire or cannot be read,
+// e.g. after the encryption key changed. Notifications encrypted with a
+// replaced certificate are fetched from the remote as before.
+func LoadNotificationCertificate(s store.CertificateStore, now time.Time) (*certificate.Certificate, error) {
+	stored, err := s.LoadNotificationCertificate()
+	if err != nil {
+		stored = nil
+	}
+	if stored != nil && now.Add(notificationCertificateRenewBefore).Before(stored.NotAfter) {
+		return stored, nil
+	}
+
+	cert, err := certificate.New(config.Provider.Name, now, notificationCertificateValidity)
+	if err != nil {
+		return nil, err
+	}
+	cert, err = s.StoreNotificationCertificate(stored, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to store the notification certificate")
+	}
+	return cert, nil
+}
+
+// RenewNotificationCertificate replaces the notification certificate of the
+// configuration before it expires, when rich notifications are enabled. The
+// certificate is otherwise only loaded when the configuration changes.
+func RenewNotificationCertificate(env Env, now time.Time) {
+	if !env.Provider.Features.EventNotifications || !env.EnableRichNotifications {
+		return
+	}
+
+	cert, err := LoadNotificationCertificate(env.Store, now)
+	if err != nil {
+		env.Logger.Warnf("Failed to renew the notification certificate. err=%v", err)
+		return
+	}
+	env.Config.NotificationCertificate = cert
+}
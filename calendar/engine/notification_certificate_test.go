@@ -0,0 +1,72 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
+)
+
+func TestLoadNotificationCertificate(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	valid := &certificate.Certificate{ID: "valid", NotAfter: now.AddDate(0, 6, 0)}
+	expiring := &certificate.Certificate{ID: "expiring", NotAfter: now.AddDate(0, 0, 7)}
+
+	for name, tc := range map[string]struct {
+		stored  *certificate.Certificate
+		loadErr error
+		created bool
+	}{
+		"valid certificate is kept":         {stored: valid},
+		"missing certificate is created":    {loadErr: store.ErrNotFound, created: true},
+		"expiring certificate is replaced":  {stored: expiring, created: true},
+		"unreadable certificate is created": {loadErr: errors.New("cipher: message authentication failed"), created: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock_store.NewMockStore(ctrl)
+			s.EXPECT().LoadNotificationCertificate().Return(tc.stored, tc.loadErr)
+			if tc.created {
+				s.EXPECT().StoreNotificationCertificate(tc.stored, gomock.Any()).DoAndReturn(func(_, cert *certificate.Certificate) (*certificate.Certificate, error) {
+					return cert, nil
+				})
+			}
+
+			cert, err := LoadNotificationCertificate(s, now)
+			require.NoError(t, err)
+			if !tc.created {
+				require.Equal(t, tc.stored, cert)
+				return
+			}
+			require.NotEqual(t, tc.stored, cert)
+			require.Equal(t, now.Add(notificationCertificateValidity), cert.NotAfter)
+		})
+	}
+}
+
+func TestLoadNotificationCertificateStoredByAnotherServer(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	other := &certificate.Certificate{ID: "other", NotAfter: now.AddDate(1, 0, 0)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := mock_store.NewMockStore(ctrl)
+	s.EXPECT().LoadNotificationCertificate().Return(nil, store.ErrNotFound)
+	s.EXPECT().StoreNotificationCertificate(nil, gomock.Any()).Return(other, nil)
+
+	cert, err := LoadNotificationCertificate(s, now)
+	require.NoError(t, err)
+	require.Equal(t, other, cert)
+}
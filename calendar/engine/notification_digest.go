@@ -0,0 +1,217 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// Run notification digest job every 15 minutes
+const NotificationDigestJobInterval = 15 * time.Minute
+
+const (
+	digestGroupNew           = "New events"
+	digestGroupUpdated       = "Updated events"
+	digestGroupCancelled     = "Cancelled events"
+	digestGroupAwaitingReply = "Awaiting your reply"
+)
+
+var digestGroupOrder = []string{
+	digestGroupNew,
+	digestGroupUpdated,
+	digestGroupCancelled,
+	digestGroupAwaitingReply,
+}
+
+type NotificationDigests interface {
+	ProcessAllNotificationDigests(now time.Time) error
+}
+
+// ProcessAllNotificationDigests posts the notification digests that are due.
+func (m *mscalendar) ProcessAllNotificationDigests(now time.Time) error {
+	userIndex, err := m.Store.LoadUserIndex()
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	for _, user := range userIndex {
+		err = m.postNotificationDigest(user.MattermostUserID, now)
+		if err != nil {
+			m.Logger.With(bot.LogContext{
+				"mm_user_id": user.MattermostUserID,
+				"err":        err,
+			}).Warnf("Error posting notification digest")
+		}
+	}
+	return nil
+}
+
+// postNotificationDigest posts the digest of the user once its interval
+// elapsed. The digest left by a user going back to immediate notifications
+// is posted right away.
+func (m *mscalendar) postNotificationDigest(mattermostUserID string, now time.Time) error {
+	// Most users have nothing queued, the user is only loaded for a digest.
+	digest, err := m.Store.LoadNotificationDigest(mattermostUserID)
+	if err == store.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(digest.Items) == 0 {
+		return nil
+	}
+
+	user, err := m.Store.LoadUser(mattermostUserID)
+	if err != nil {
+		return errors.Wrap(err, "error loading user")
+	}
+	interval := user.Settings.NotificationDigestInterval()
+	if interval > 0 && now.Before(digest.DueAt(interval)) {
+		return nil
+	}
+
+	digest, err = m.Store.TakeNotificationDigest(user.MattermostUserID)
+	if err == store.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(digest.Items) == 0 {
+		return nil
+	}
+
+	processor := &notificationProcessor{Env: m.Env}
+	message := fmt.Sprintf("Here is what changed in your calendar, %d event(s):", len(digest.Items))
+	postID, err := m.Poster.DMWithMessageAndAttachments(user.MattermostUserID, message, processor.digestSlackAttachments(digest)...)
+	if err != nil {
+		// Keep the notifications for the next run
+		restoreErr := m.Store.AddDigestItems(user.MattermostUserID, digest.Items...)
+		if restoreErr != nil {
+			m.Logger.With(bot.LogContext{
+				"mm_user_id": user.MattermostUserID,
+				"err":        restoreErr,
+			}).Errorf("Error restoring notification digest, %d notification(s) lost", len(digest.Items))
+		}
+		return err
+	}
+
+	m.addDigestPostToEvents(user.MattermostUserID, digest, postID)
+	return nil
+}
+
+// addDigestPostToEvents records the digest post on the stored events it
+// notified of, so that it is updated once they are cancelled.
+func (m *mscalendar) addDigestPostToEvents(mattermostUserID string, digest *store.NotificationDigest, postID string) {
+	for _, item := range digest.Items {
+		if item.Kind == store.DigestKindCancelled || item.Kind == store.DigestKindDeleted {
+			continue
+		}
+		logger := m.Logger.With(bot.LogContext{
+			"mm_user_id": mattermostUserID,
+			"EventID":    item.Event.ID,
+		})
+
+		stored, err := m.Store.LoadUserEvent(mattermostUserID, item.Event.ICalUID)
+		if err != nil {
+			if err != store.ErrNotFound {
+				logger.Warnf("Error loading event for notification digest. err=%v", err)
+			}
+			continue
+		}
+		// Another occurrence of the series may be the one stored
+		if stored.Remote == nil || stored.Remote.ID != item.Event.ID {
+			continue
+		}
+
+		stored.PostIDs = append(stored.PostIDs, postID)
+		err = m.Store.StoreUserEvent(mattermostUserID, stored)
+		if err != nil {
+			logger.Warnf("Error storing notification digest post of event. err=%v", err)
+		}
+	}
+}
+
+// postEventNotification DMs the notification of an event to the user, or adds
+// it to their digest. Events of high importance are always posted right away.
+// It returns the ID of the post, empty when the notification was buffered.
+func (processor *notificationProcessor) postEventNotification(user *store.User, event *remote.Event, kind, timezone string, sa *model.SlackAttachment) (string, error) {
+	if user.Settings.NotificationDigestInterval() == 0 || event.Importance == remote.ImportanceHigh {
+		return processor.Poster.DMWithAttachments(user.MattermostUserID, sa)
+	}
+
+	err := processor.Store.AddDigestItems(user.MattermostUserID, &store.DigestItem{
+		Event:      event,
+		Kind:       kind,
+		Timezone:   timezone,
+		ReceivedAt: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	processor.Logger.With(bot.LogContext{
+		"MattermostUserID": user.MattermostUserID,
+		"EventID":          event.ID,
+		"Kind":             kind,
+	}).Debugf("webhook notification: added to digest.")
+	return "", nil
+}
+
+// digestSlackAttachments renders the digest as one attachment per event,
+// grouped by what happened to them. The invitations not answered yet keep
+// their response buttons.
+func (processor *notificationProcessor) digestSlackAttachments(digest *store.NotificationDigest) []*model.SlackAttachment {
+	groups := map[string][]*model.SlackAttachment{}
+	for _, item := range digest.Items {
+		n := &remote.Notification{Event: item.Event}
+
+		var group string
+		var sa *model.SlackAttachment
+		switch {
+		case item.Kind == store.DigestKindCancelled:
+			group = digestGroupCancelled
+			sa = processor.cancelledEventSlackAttachment(n, titleCancelled, textMeetingCancelled, item.Timezone)
+		case item.Kind == store.DigestKindDeleted:
+			group = digestGroupCancelled
+			sa = processor.cancelledEventSlackAttachment(n, titleDeleted, textEventDeleted, item.Timezone)
+		default:
+			group = digestGroupUpdated
+			if item.Kind == store.DigestKindNew {
+				group = digestGroupNew
+			}
+			if isPendingInvitation(item.Event) {
+				group = digestGroupAwaitingReply
+			}
+			sa = processor.newEventSlackAttachment(n, item.Timezone)
+			if item.Kind == store.DigestKindUpdated {
+				sa.Title = "(updated) " + views.EnsureSubject(item.Event.Subject)
+			}
+		}
+		groups[group] = append(groups[group], sa)
+	}
+
+	attachments := []*model.SlackAttachment{}
+	for _, group := range digestGroupOrder {
+		if len(groups[group]) == 0 {
+			continue
+		}
+		groups[group][0].Pretext = fmt.Sprintf("#### %s", group)
+		attachments = append(attachments, groups[group]...)
+	}
+	return attachments
+}
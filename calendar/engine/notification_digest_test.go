@@ -0,0 +1,244 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+)
+
+func TestPostEventNotification(t *testing.T) {
+	for name, tc := range map[string]struct {
+		digest     string
+		importance string
+		setup      func(*mock_store.MockStore, *mock_bot.MockPoster)
+		postID     string
+	}{
+		"immediate notification is posted": {
+			digest: "",
+			setup: func(_ *mock_store.MockStore, p *mock_bot.MockPoster) {
+				p.EXPECT().DMWithAttachments("creator_mm_id_1", gomock.Any()).Return("post_id_1", nil)
+			},
+			postID: "post_id_1",
+		},
+		"digest notification is buffered": {
+			digest: store.NotificationDigestHourlyOption,
+			setup: func(s *mock_store.MockStore, p *mock_bot.MockPoster) {
+				s.EXPECT().AddDigestItems("creator_mm_id_1", gomock.Any()).DoAndReturn(func(_ string, items ...*store.DigestItem) error {
+					require.Len(t, items, 1)
+					require.Equal(t, store.DigestKindNew, items[0].Kind)
+					require.Equal(t, "UTC", items[0].Timezone)
+					require.Equal(t, "remote_event_uid_1", items[0].Event.ICalUID)
+					return nil
+				})
+				p.EXPECT().DMWithAttachments(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		"high importance notification is posted in digest mode": {
+			digest:     store.NotificationDigestDailyOption,
+			importance: remote.ImportanceHigh,
+			setup: func(s *mock_store.MockStore, p *mock_bot.MockPoster) {
+				s.EXPECT().AddDigestItems(gomock.Any(), gomock.Any()).Times(0)
+				p.EXPECT().DMWithAttachments("creator_mm_id_1", gomock.Any()).Return("post_id_1", nil)
+			},
+			postID: "post_id_1",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockPoster := mock_bot.NewMockPoster(ctrl)
+			env := Env{
+				Config: &config.Config{PluginVersion: "x.x.x", PluginURL: "https://plugin"},
+				Dependencies: &Dependencies{
+					Store:  mockStore,
+					Logger: &bot.NilLogger{},
+					Poster: mockPoster,
+				},
+			}
+			tc.setup(mockStore, mockPoster)
+
+			user := newTestUser()
+			user.Settings.NotificationDigest = tc.digest
+			event := newTestEvent("1", "event_location_display_name", "event_subject")
+			event.Importance = tc.importance
+
+			processor := newTestNotificationProcessor(env)
+			postID, err := processor.postEventNotification(user, event, store.DigestKindNew, "UTC", &model.SlackAttachment{})
+			require.NoError(t, err)
+			require.Equal(t, tc.postID, postID)
+		})
+	}
+}
+
+func TestDigestSlackAttachments(t *testing.T) {
+	newEvent := newTestEvent("1", "event_location_display_name", "new_subject")
+	newEvent.ResponseRequested = false
+	invitation := newTestEvent("2", "event_location_display_name", "invitation_subject")
+	invitation.ResponseStatus = &remote.EventResponseStatus{Response: remote.EventResponseStatusNotAnswered}
+	updated := newTestEvent("3", "event_location_display_name", "updated_subject")
+	cancelled := newTestEvent("4", "event_location_display_name", "Canceled: cancelled_subject")
+	cancelled.IsCancelled = true
+
+	processor := newTestNotificationProcessor(Env{
+		Config:       &config.Config{PluginVersion: "x.x.x", PluginURL: "https://plugin"},
+		Dependencies: &Dependencies{Logger: &bot.NilLogger{}},
+	})
+	attachments := processor.digestSlackAttachments(&store.NotificationDigest{Items: []*store.DigestItem{
+		{Event: invitation, Kind: store.DigestKindNew, Timezone: "UTC"},
+		{Event: cancelled, Kind: store.DigestKindCancelled, Timezone: "UTC"},
+		{Event: updated, Kind: store.DigestKindUpdated, Timezone: "UTC"},
+		{Event: newEvent, Kind: store.DigestKindNew, Timezone: "UTC"},
+	}})
+
+	require.Len(t, attachments, 4)
+	require.Equal(t, "#### "+digestGroupNew, attachments[0].Pretext)
+	require.Equal(t, "(new) new_subject", attachments[0].Title)
+	require.Empty(t, attachments[0].Actions)
+	require.Equal(t, "#### "+digestGroupUpdated, attachments[1].Pretext)
+	require.Equal(t, "(updated) updated_subject", attachments[1].Title)
+	require.Equal(t, "#### "+digestGroupCancelled, attachments[2].Pretext)
+	require.Equal(t, "(cancelled) cancelled_subject", attachments[2].Title)
+	require.Equal(t, "#### "+digestGroupAwaitingReply, attachments[3].Pretext)
+	require.Equal(t, "(new) invitation_subject", attachments[3].Title)
+	require.NotEmpty(t, attachments[3].Actions)
+}
+
+func TestProcessAllNotificationDigests(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	newDigest := func(receivedAt time.Time) *store.NotificationDigest {
+		return &store.NotificationDigest{Items: []*store.DigestItem{{
+			Event:      newTestEvent("1", "event_location_display_name", "event_subject"),
+			Kind:       store.DigestKindNew,
+			Timezone:   "UTC",
+			ReceivedAt: receivedAt,
+		}}}
+	}
+	newUser := func(digest string) *store.User {
+		user := newTestUser()
+		user.Settings.NotificationDigest = digest
+		return user
+	}
+
+	for name, tc := range map[string]struct {
+		user  *store.User
+		setup func(*mock_store.MockStore, *mock_bot.MockPoster, *store.User)
+	}{
+		"no digest": {
+			user: newUser(store.NotificationDigestHourlyOption),
+			setup: func(s *mock_store.MockStore, _ *mock_bot.MockPoster, _ *store.User) {
+				s.EXPECT().LoadNotificationDigest("creator_mm_id_1").Return(nil, store.ErrNotFound)
+			},
+		},
+		"empty digest": {
+			user: newUser(store.NotificationDigestHourlyOption),
+			setup: func(s *mock_store.MockStore, _ *mock_bot.MockPoster, _ *store.User) {
+				s.EXPECT().LoadNotificationDigest("creator_mm_id_1").Return(&store.NotificationDigest{}, nil)
+			},
+		},
+		"digest not due yet": {
+			user: newUser(store.NotificationDigestHourlyOption),
+			setup: func(s *mock_store.MockStore, _ *mock_bot.MockPoster, user *store.User) {
+				s.EXPECT().LoadNotificationDigest("creator_mm_id_1").Return(newDigest(now.Add(-30*time.Minute)), nil)
+				s.EXPECT().LoadUser("creator_mm_id_1").Return(user, nil)
+				s.EXPECT().TakeNotificationDigest(gomock.Any()).Times(0)
+			},
+		},
+		"digest due is posted": {
+			user: newUser(store.NotificationDigestHourlyOption),
+			setup: func(s *mock_store.MockStore, p *mock_bot.MockPoster, user *store.User) {
+				s.EXPECT().LoadNotificationDigest("creator_mm_id_1").Return(newDigest(now.Add(-time.Hour)), nil)
+				s.EXPECT().LoadUser("creator_mm_id_1").Return(user, nil)
+				s.EXPECT().TakeNotificationDigest("creator_mm_id_1").Return(newDigest(now.Add(-time.Hour)), nil)
+				p.EXPECT().DMWithMessageAndAttachments("creator_mm_id_1", gomock.Any(), gomock.Any()).Return("post_id_1", nil)
+				s.EXPECT().LoadUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(nil, store.ErrNotFound)
+			},
+		},
+		"digest left by immediate notifications is posted": {
+			user: newUser(store.NotificationDigestImmediatelyOption),
+			setup: func(s *mock_store.MockStore, p *mock_bot.MockPoster, user *store.User) {
+				s.EXPECT().LoadNotificationDigest("creator_mm_id_1").Return(newDigest(now), nil)
+				s.EXPECT().LoadUser("creator_mm_id_1").Return(user, nil)
+				s.EXPECT().TakeNotificationDigest("creator_mm_id_1").Return(newDigest(now), nil)
+				p.EXPECT().DMWithMessageAndAttachments("creator_mm_id_1", gomock.Any(), gomock.Any()).Return("post_id_1", nil)
+				s.EXPECT().LoadUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(nil, store.ErrNotFound)
+			},
+		},
+		"digest failing to post is kept": {
+			user: newUser(store.NotificationDigestHourlyOption),
+			setup: func(s *mock_store.MockStore, p *mock_bot.MockPoster, user *store.User) {
+				digest := newDigest(now.Add(-time.Hour))
+				s.EXPECT().LoadNotificationDigest("creator_mm_id_1").Return(digest, nil)
+				s.EXPECT().LoadUser("creator_mm_id_1").Return(user, nil)
+				s.EXPECT().TakeNotificationDigest("creator_mm_id_1").Return(digest, nil)
+				p.EXPECT().DMWithMessageAndAttachments("creator_mm_id_1", gomock.Any(), gomock.Any()).Return("", &model.AppError{Message: "failed"})
+				s.EXPECT().AddDigestItems("creator_mm_id_1", digest.Items[0]).Return(nil)
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockPoster := mock_bot.NewMockPoster(ctrl)
+			env := Env{
+				Config: &config.Config{PluginVersion: "x.x.x", PluginURL: "https://plugin"},
+				Dependencies: &Dependencies{
+					Store:  mockStore,
+					Logger: &bot.NilLogger{},
+					Poster: mockPoster,
+				},
+			}
+			mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{{MattermostUserID: "creator_mm_id_1"}}, nil)
+			tc.setup(mockStore, mockPoster, tc.user)
+
+			err := New(env, "").ProcessAllNotificationDigests(now)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAddDigestPostToEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_store.NewMockStore(ctrl)
+	m := New(Env{
+		Dependencies: &Dependencies{
+			Store:  mockStore,
+			Logger: &bot.NilLogger{},
+		},
+	}, "").(*mscalendar)
+
+	notified := newTestEvent("1", "event_location_display_name", "event_subject")
+	occurrence := newTestEvent("2", "event_location_display_name", "event_subject")
+	occurrence.ICalUID = notified.ICalUID
+	cancelled := newTestEvent("3", "event_location_display_name", "event_subject")
+	unknown := newTestEvent("4", "event_location_display_name", "event_subject")
+
+	mockStore.EXPECT().LoadUserEvent("creator_mm_id_1", "remote_event_uid_1").Return(&store.Event{Remote: notified, PostIDs: []string{"post_id_1"}}, nil).Times(2)
+	mockStore.EXPECT().StoreUserEvent("creator_mm_id_1", &store.Event{Remote: notified, PostIDs: []string{"post_id_1", "digest_post_id"}}).Return(nil)
+	mockStore.EXPECT().LoadUserEvent("creator_mm_id_1", "remote_event_uid_4").Return(nil, store.ErrNotFound)
+
+	m.addDigestPostToEvents("creator_mm_id_1", &store.NotificationDigest{Items: []*store.DigestItem{
+		{Event: notified, Kind: store.DigestKindUpdated},
+		{Event: occurrence, Kind: store.DigestKindUpdated},
+		{Event: cancelled, Kind: store.DigestKindCancelled},
+		{Event: unknown, Kind: store.DigestKindNew},
+	}}, "digest_post_id")
+}
@@ -36,7 +36,10 @@ func (processor *notificationProcessor) newEventSlackAttachment(n *remote.Notifi
 
 	fields := eventToFields(n.Event, timezone)
 	for _, k := range notificationFieldOrder {
-		v := fields[k]
+		v, ok := fields[k]
+		if !ok {
+			continue
+		}
 
 		sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
 			Title: k,
@@ -46,7 +49,7 @@ func (processor *notificationProcessor) newEventSlackAttachment(n *remote.Notifi
 	}
 
 	if n.Event.ResponseRequested && !n.Event.IsOrganizer {
-		sa.Actions = NewPostActionForEventResponse(n.Event.ID, n.Event.ResponseStatus.Response, processor.actionURL(config.PathRespond))
+		sa.Actions = processor.eventResponseActions(n.Event)
 	}
 	return sa
 }
@@ -111,11 +114,48 @@ func (processor *notificationProcessor) updatedEventSlackAttachment(n *remote.No
 	}
 
 	if n.Event.ResponseRequested && !n.Event.IsOrganizer && !n.Event.IsCancelled {
-		sa.Actions = NewPostActionForEventResponse(n.Event.ID, n.Event.ResponseStatus.Response, processor.actionURL(config.PathRespond))
+		sa.Actions = processor.eventResponseActions(n.Event)
 	}
 	return true, sa
 }
 
+// cancelledEventSlackAttachment tells that the event was cancelled, with the
+// time it was planned at struck through.
+func (processor *notificationProcessor) cancelledEventSlackAttachment(n *remote.Notification, title, text, timezone string) *model.SlackAttachment {
+	sa := processor.newSlackAttachment(n)
+	sa.Title = title + " " + strings.TrimPrefix(sa.Title, cancelledSubjectPrefix)
+	sa.Text = text
+	sa.Fallback = fmt.Sprintf("[%s](%s): %s", sa.Title, sa.TitleLink, text)
+
+	fields := eventToFields(n.Event, timezone)
+	for _, k := range []string{FieldWhen, FieldRecurrence} {
+		v, ok := fields[k]
+		if !ok {
+			continue
+		}
+		sa.Fields = append(sa.Fields, &model.SlackAttachmentField{
+			Title: k,
+			Value: fmt.Sprintf("~~%s~~", strings.Join(v.Strings(), ", ")),
+			Short: true,
+		})
+	}
+	return sa
+}
+
+// strikeThroughCancelledAttachment marks an earlier notification of the event
+// as cancelled, and removes its response actions.
+func strikeThroughCancelledAttachment(sa *model.SlackAttachment) {
+	title := strings.TrimPrefix(strings.TrimPrefix(sa.Title, "(new) "), "(updated) ")
+	sa.Title = titleCancelled + " " + title
+	sa.Actions = nil
+	for _, f := range sa.Fields {
+		value := fmt.Sprintf("%v", f.Value)
+		if value != "" && !strings.Contains(value, "~~") {
+			f.Value = fmt.Sprintf("~~%s~~", value)
+		}
+	}
+}
+
 func isImportantChange(fieldName string) bool {
 	for _, ic := range importantNotificationChanges {
 		if ic == fieldName {
@@ -129,6 +169,26 @@ func (processor *notificationProcessor) actionURL(action string) string {
 	return fmt.Sprintf("%s%s%s", processor.Config.PluginURLPath, config.PathPostAction, action)
 }
 
+func (processor *notificationProcessor) eventResponseActions(event *remote.Event) []*model.PostAction {
+	actions := NewPostActionForEventResponse(event.ID, event.ResponseStatus.Response, processor.actionURL(config.PathRespond))
+	return append(actions, NewPostActionForRespondWithComment(event.ID, processor.actionURL(config.PathRespondWithComment)))
+}
+
+// NewPostActionForRespondWithComment returns the button opening the dialog to
+// respond with a comment, or propose a new time.
+func NewPostActionForRespondWithComment(eventID, url string) *model.PostAction {
+	return &model.PostAction{
+		Name: "Respond with comment",
+		Type: model.PostActionTypeButton,
+		Integration: &model.PostActionIntegration{
+			URL: url,
+			Context: map[string]interface{}{
+				config.EventIDKey: eventID,
+			},
+		},
+	}
+}
+
 func NewPostActionForEventResponse(eventID, response, url string) []*model.PostAction {
 	context := map[string]interface{}{
 		config.EventIDKey: eventID,
@@ -252,6 +312,9 @@ func eventToFields(e *remote.Event, timezone string) fields.Fields {
 		FieldResponseStatus: fields.NewStringValue(e.ResponseStatus.Response),
 		FieldAttendees:      fields.NewMultiValue(attendees...),
 	}
+	if recurrence := views.RenderRecurrence(e); recurrence != "" {
+		ff[FieldRecurrence] = fields.NewStringValue(recurrence)
+	}
 
 	return ff
 }
@@ -0,0 +1,140 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// missedInvitationsWindow is how far ahead invitations are looked up once
+// notifications have been missed.
+const missedInvitationsWindow = 14 * 24 * time.Hour
+
+// processLifecycleEvent keeps the subscription of the user working, so that
+// invitations keep being notified until the daily renewal job runs.
+func (processor *notificationProcessor) processLifecycleEvent(n *remote.Notification, creator *store.User, client remote.Client) error {
+	switch n.LifecycleEvent {
+	case remote.LifecycleEventReauthorizationRequired:
+		// Renewing a subscription reauthorizes it.
+		return processor.renewSubscription(n, creator, client)
+	case remote.LifecycleEventSubscriptionRemoved:
+		return processor.recreateSubscription(n, creator, client)
+	case remote.LifecycleEventMissed:
+		return processor.notifyMissedInvitations(creator, client)
+	}
+
+	processor.Logger.With(bot.LogContext{
+		"MattermostUserID": creator.MattermostUserID,
+		"SubscriptionID":   n.SubscriptionID,
+		"LifecycleEvent":   n.LifecycleEvent,
+	}).Debugf("webhook notification: ignored unknown lifecycle event.")
+	return nil
+}
+
+func (processor *notificationProcessor) recreateSubscription(n *remote.Notification, creator *store.User, client remote.Client) error {
+	sub, err := client.CreateMySubscription(processor.Config.GetNotificationURL(), processor.Config.GetLifecycleNotificationURL(), creator.Remote.ID)
+	if err != nil {
+		return errors.Wrap(err, "error recreating the removed subscription")
+	}
+
+	storedSub := &store.Subscription{
+		Remote:              sub,
+		MattermostCreatorID: creator.MattermostUserID,
+		PluginVersion:       processor.Config.PluginVersion,
+	}
+	err = processor.Store.StoreUserSubscription(creator, storedSub)
+	if err != nil {
+		return err
+	}
+
+	// The user now refers to the new subscription, only the removed one is
+	// left to delete.
+	err = processor.Store.DeleteUserSubscription(nil, n.SubscriptionID)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to delete subscription %s", n.SubscriptionID)
+	}
+
+	processor.Logger.With(bot.LogContext{
+		"MattermostUserID":  creator.MattermostUserID,
+		"SubscriptionID":    n.SubscriptionID,
+		"NewSubscriptionID": sub.ID,
+	}).Debugf("webhook notification: recreated removed user subscription.")
+	return nil
+}
+
+// notifyMissedInvitations notifies the user of the upcoming invitations they
+// have not answered nor been notified of.
+func (processor *notificationProcessor) notifyMissedInvitations(creator *store.User, client remote.Client) error {
+	now := time.Now()
+	events, err := client.GetDefaultCalendarView(creator.Remote.ID, now, now.Add(missedInvitationsWindow))
+	if err != nil {
+		return errors.Wrap(err, "error fetching events to resync")
+	}
+
+	var mailSettings *remote.MailboxSettings
+	notified := 0
+	for _, event := range events {
+		if !isPendingInvitation(event) {
+			continue
+		}
+		_, err = processor.Store.LoadUserEvent(creator.MattermostUserID, event.ICalUID)
+		if err == nil {
+			continue
+		}
+		if err != store.ErrNotFound {
+			return err
+		}
+
+		if mailSettings == nil {
+			mailSettings, err = client.GetMailboxSettings(creator.Remote.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		n := &remote.Notification{
+			Event:               event,
+			SubscriptionCreator: creator.Remote,
+		}
+		var postID string
+		postID, err = processor.postEventNotification(creator, event, store.DigestKindNew, mailSettings.TimeZone, processor.newEventSlackAttachment(n, mailSettings.TimeZone))
+		if err != nil {
+			return err
+		}
+		if len(creator.Delegates) > 0 {
+			processor.notifyDelegates(creator, n)
+		}
+
+		stored := &store.Event{Remote: event}
+		if postID != "" {
+			stored.PostIDs = []string{postID}
+		}
+		err = processor.Store.StoreUserEvent(creator.MattermostUserID, stored)
+		if err != nil {
+			return err
+		}
+		notified++
+	}
+
+	processor.Logger.With(bot.LogContext{
+		"MattermostUserID": creator.MattermostUserID,
+		"Notified":         notified,
+	}).Debugf("webhook notification: resynced after missed notifications.")
+	return nil
+}
+
+func isPendingInvitation(event *remote.Event) bool {
+	if !event.ResponseRequested || event.IsOrganizer || event.IsCancelled {
+		return false
+	}
+	return event.ResponseStatus == nil ||
+		event.ResponseStatus.Response == remote.EventResponseStatusNotAnswered ||
+		event.ResponseStatus.Response == ResponseNone
+}
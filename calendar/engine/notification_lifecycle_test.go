@@ -0,0 +1,111 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+)
+
+func TestProcessLifecycleNotification(t *testing.T) {
+	pending := newTestEvent("1", "event_location_display_name", "event_subject")
+	pending.ResponseStatus = &remote.EventResponseStatus{Response: remote.EventResponseStatusNotAnswered}
+	known := newTestEvent("2", "event_location_display_name", "known_subject")
+	known.ResponseStatus = &remote.EventResponseStatus{Response: remote.EventResponseStatusNotAnswered}
+	answered := newTestEvent("3", "event_location_display_name", "answered_subject")
+
+	for name, tc := range map[string]struct {
+		lifecycleEvent string
+		setup          func(*mock_store.MockStore, *mock_remote.MockClient, *mock_bot.MockPoster, *store.User)
+	}{
+		"reauthorization required renews the subscription": {
+			lifecycleEvent: remote.LifecycleEventReauthorizationRequired,
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockPoster, user *store.User) {
+				renewed := &remote.Subscription{ID: "remote_subscription_id_1", CreatorID: "remote_user_id_1"}
+				c.EXPECT().RenewSubscription("https://plugin/notification/v1/event", "remote_user_id_1", gomock.Any()).Return(renewed, nil)
+				s.EXPECT().StoreUserSubscription(user, &store.Subscription{
+					Remote:              renewed,
+					MattermostCreatorID: "creator_mm_id_1",
+					PluginVersion:       "x.x.x",
+				}).Return(nil)
+			},
+		},
+		"removed subscription is recreated": {
+			lifecycleEvent: remote.LifecycleEventSubscriptionRemoved,
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, _ *mock_bot.MockPoster, user *store.User) {
+				created := &remote.Subscription{ID: "new_subscription_id", CreatorID: "remote_user_id_1"}
+				c.EXPECT().CreateMySubscription("https://plugin/notification/v1/event", "https://plugin/notification/v1/lifecycle", "remote_user_id_1").Return(created, nil)
+				s.EXPECT().StoreUserSubscription(user, &store.Subscription{
+					Remote:              created,
+					MattermostCreatorID: "creator_mm_id_1",
+					PluginVersion:       "x.x.x",
+				}).Return(nil)
+				s.EXPECT().DeleteUserSubscription(nil, "remote_subscription_id_1").Return(nil)
+			},
+		},
+		"missed notifications notify pending invitations": {
+			lifecycleEvent: remote.LifecycleEventMissed,
+			setup: func(s *mock_store.MockStore, c *mock_remote.MockClient, p *mock_bot.MockPoster, _ *store.User) {
+				c.EXPECT().GetDefaultCalendarView("remote_user_id_1", gomock.Any(), gomock.Any()).Return([]*remote.Event{pending, known, answered}, nil)
+				s.EXPECT().LoadUserEvent("creator_mm_id_1", known.ICalUID).Return(&store.Event{Remote: known}, nil)
+				s.EXPECT().LoadUserEvent("creator_mm_id_1", pending.ICalUID).Return(nil, store.ErrNotFound)
+				c.EXPECT().GetMailboxSettings("remote_user_id_1").Return(&remote.MailboxSettings{TimeZone: "UTC"}, nil)
+				p.EXPECT().DMWithAttachments("creator_mm_id_1", gomock.Any()).Return("", nil).Times(1)
+				s.EXPECT().StoreUserEvent("creator_mm_id_1", &store.Event{Remote: pending}).Return(nil)
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockPoster := mock_bot.NewMockPoster(ctrl)
+			mockRemote := mock_remote.NewMockRemote(ctrl)
+			mockClient := mock_remote.NewMockClient(ctrl)
+			env := Env{
+				Config: &config.Config{PluginVersion: "x.x.x", PluginURL: "https://plugin"},
+				Dependencies: &Dependencies{
+					Store:     mockStore,
+					Logger:    &bot.NilLogger{},
+					Poster:    mockPoster,
+					Remote:    mockRemote,
+					PluginAPI: mock_plugin_api.NewMockPluginAPI(ctrl),
+				},
+			}
+
+			user := newTestUser()
+			sub := &store.Subscription{
+				PluginVersion:       "x.x.x",
+				Remote:              &remote.Subscription{ID: "remote_subscription_id_1", ClientState: "stored_client_state", CreatorID: "remote_user_id_1"},
+				MattermostCreatorID: "creator_mm_id_1",
+			}
+			mockStore.EXPECT().LoadSubscription("remote_subscription_id_1").Return(sub, nil)
+			mockStore.EXPECT().LoadUser("creator_mm_id_1").Return(user, nil)
+			mockRemote.EXPECT().MakeUserClient(remote.BackgroundContext(context.Background()), user.OAuth2Token, "creator_mm_id_1", mockPoster, mockStore).Return(mockClient, nil)
+			mockClient.EXPECT().GetNotificationData(gomock.Any()).Times(0)
+			tc.setup(mockStore, mockClient, mockPoster, user)
+
+			processor := newTestNotificationProcessor(env)
+			err := processor.processNotification(&remote.Notification{
+				SubscriptionID: "remote_subscription_id_1",
+				LifecycleEvent: tc.lifecycleEvent,
+				ClientState:    "stored_client_state",
+			})
+			require.NoError(t, err)
+		})
+	}
+}
@@ -7,10 +7,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
@@ -23,7 +25,7 @@ import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
 )
 
-func newTestNotificationProcessor(env Env) NotificationProcessor {
+func newTestNotificationProcessor(env Env) *notificationProcessor {
 	processor := &notificationProcessor{
 		Env: env,
 	}
@@ -158,7 +160,7 @@ func TestProcessNotification(t *testing.T) {
 			mockStore.EXPECT().LoadUser("creator_mm_id").Return(user, nil).Times(1)
 
 			if tc.notification.ClientState == subscription.Remote.ClientState {
-				mockRemote.EXPECT().MakeUserClient(context.Background(), &oauth2.Token{
+				mockRemote.EXPECT().MakeUserClient(remote.BackgroundContext(context.Background()), &oauth2.Token{
 					AccessToken: "creator_oauth_token",
 				}, "creator_mm_id", mockPoster, gomock.Any()).Return(mockClient, nil).Times(1)
 				mockClient.EXPECT().GetMailboxSettings(user.Remote.ID).Return(&remote.MailboxSettings{TimeZone: "Eastern Standard Time"}, nil)
@@ -186,8 +188,7 @@ func TestProcessNotification(t *testing.T) {
 				mockStore.EXPECT().StoreUserEvent("creator_mm_id", gomock.Any()).Return(nil).Times(1)
 			}
 
-			p := newTestNotificationProcessor(env)
-			processor := p.(*notificationProcessor)
+			processor := newTestNotificationProcessor(env)
 			err := processor.processNotification(tc.notification)
 
 			if tc.expectedError != "" {
@@ -199,20 +200,165 @@ func TestProcessNotification(t *testing.T) {
 	}
 }
 
-func TestProcessNotificationOverflow(t *testing.T) {
-	t.Run("overflow", func(t *testing.T) {
-		ctrl := gomock.NewController(t)
-		defer ctrl.Finish()
+func TestProcessQueuedNotification(t *testing.T) {
+	for name, tc := range map[string]struct {
+		attempts int
+		loadErr  error
+		expect   func(*mock_store.MockStore, *store.QueuedNotification)
+	}{
+		"failure is retried later": {
+			attempts: 1,
+			loadErr:  errors.New("some error"),
+			expect: func(s *mock_store.MockStore, item *store.QueuedNotification) {
+				s.EXPECT().RetryNotification(item).DoAndReturn(func(item *store.QueuedNotification) error {
+					require.Equal(t, 2, item.Attempts)
+					require.Equal(t, "some error", item.LastError)
+					require.WithinDuration(t, time.Now().Add(time.Minute), item.NextAttemptAt, 5*time.Second)
+					return nil
+				})
+			},
+		},
+		"last failure is moved to the dead letters": {
+			attempts: maxNotificationAttempts - 1,
+			loadErr:  errors.New("some error"),
+			expect: func(s *mock_store.MockStore, item *store.QueuedNotification) {
+				s.EXPECT().DeadLetterNotification(item).DoAndReturn(func(item *store.QueuedNotification) error {
+					require.Equal(t, maxNotificationAttempts, item.Attempts)
+					require.False(t, item.FailedAt.IsZero())
+					return nil
+				})
+			},
+		},
+		"notification of a deleted subscription is dropped": {
+			loadErr: store.ErrNotFound,
+			expect: func(s *mock_store.MockStore, item *store.QueuedNotification) {
+				s.EXPECT().CompleteNotification("notification_id").Return(nil)
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
 
-		processor := &notificationProcessor{
-			queue: make(chan (*remote.Notification), maxQueueSize),
-		}
+			mockStore := mock_store.NewMockStore(ctrl)
+			env := Env{
+				Config: &config.Config{},
+				Dependencies: &Dependencies{
+					Store:  mockStore,
+					Logger: &bot.NilLogger{},
+				},
+			}
+			item := &store.QueuedNotification{
+				ID:           "notification_id",
+				Notification: &remote.Notification{SubscriptionID: "remote_subscription_id"},
+				Attempts:     tc.attempts,
+			}
+			mockStore.EXPECT().LoadSubscription("remote_subscription_id").Return(nil, tc.loadErr)
+			tc.expect(mockStore, item)
+
+			processor := newTestNotificationProcessor(env)
+			processor.processQueuedNotification(item)
+		})
+	}
+}
+
+func TestNotificationRetryDelay(t *testing.T) {
+	require.Equal(t, 30*time.Second, notificationRetryDelay(1))
+	require.Equal(t, time.Minute, notificationRetryDelay(2))
+	require.Equal(t, 4*time.Minute, notificationRetryDelay(4))
+	require.Equal(t, 15*time.Minute, notificationRetryDelay(20))
+}
+
+func TestNotificationWorkersEnqueue(t *testing.T) {
+	for name, tc := range map[string]struct {
+		enqueueErr  error
+		expectedErr error
+	}{
+		"queued":          {},
+		"queue saturated": {enqueueErr: store.ErrNotificationQueueFull, expectedErr: store.ErrNotificationQueueFull},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := mock_store.NewMockStore(ctrl)
+			mockStore.EXPECT().LeaseNotification(gomock.Any(), notificationLease).Return(nil, store.ErrNotFound).AnyTimes()
+			mockStore.EXPECT().EnqueueNotifications(gomock.Any(), gomock.Any()).DoAndReturn(func(items ...*store.QueuedNotification) error {
+				require.Len(t, items, 2)
+				require.NotEqual(t, items[0].ID, items[1].ID)
+				return tc.enqueueErr
+			})
+
+			workers := NewNotificationProcessor(Env{
+				Config: &config.Config{StoredConfig: config.StoredConfig{NotificationWorkers: 2}},
+				Dependencies: &Dependencies{
+					Store:  mockStore,
+					Logger: &bot.NilLogger{},
+				},
+			})
+			defer workers.Quit()
+
+			err := workers.Enqueue(&remote.Notification{}, &remote.Notification{})
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNotificationWorkersQuit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-		for i := 0; i < maxQueueSize; i++ {
-			err := processor.Enqueue(&remote.Notification{})
-			require.NoError(t, err)
+	mockStore := mock_store.NewMockStore(ctrl)
+	mockStore.EXPECT().LeaseNotification(gomock.Any(), notificationLease).Return(nil, store.ErrNotFound).AnyTimes()
+	workers := NewNotificationProcessor(Env{
+		Config: &config.Config{},
+		Dependencies: &Dependencies{
+			Store:  mockStore,
+			Logger: &bot.NilLogger{},
+		},
+	})
+
+	workers.Quit()
+	require.NotPanics(t, workers.Quit)
+}
+
+func TestRenewNotificationLease(t *testing.T) {
+	notificationLeaseRenewal = 10 * time.Millisecond
+	defer func() { notificationLeaseRenewal = notificationLease / 4 }()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := mock_store.NewMockStore(ctrl)
+	renewed := make(chan struct{}, 1)
+	mockStore.EXPECT().RenewNotificationLease("notification_id", notificationLease).DoAndReturn(func(string, time.Duration) error {
+		select {
+		case renewed <- struct{}{}:
+		default:
 		}
-		err := processor.Enqueue(&remote.Notification{})
-		require.Error(t, err)
+		return nil
+	}).MinTimes(1)
+	processor := newTestNotificationProcessor(Env{
+		Dependencies: &Dependencies{
+			Store:  mockStore,
+			Logger: &bot.NilLogger{},
+		},
 	})
+
+	stop := processor.renewLease("notification_id")
+	<-renewed
+	stop()
+}
+
+func TestNotificationWorkerCount(t *testing.T) {
+	count := func(n int) int {
+		return notificationWorkerCount(Env{Config: &config.Config{StoredConfig: config.StoredConfig{NotificationWorkers: n}}})
+	}
+	require.Equal(t, defaultNotificationWorkers, count(0))
+	require.Equal(t, 8, count(8))
+	require.Equal(t, maxNotificationWorkers, count(1000))
 }
@@ -13,6 +13,7 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/oauth2connect"
 )
@@ -38,7 +39,7 @@ func NewOAuth2App(env Env) oauth2connect.App {
 func (app *oauth2App) InitOAuth2(mattermostUserID string) (url string, err error) {
 	user, err := app.Store.LoadUser(mattermostUserID)
 	if err == nil {
-		return "", fmt.Errorf("user is already connected to %s", user.Remote.Mail)
+		return "", fmt.Errorf("user is already connected to %s", user.Remote.Name())
 	}
 
 	conf := app.Remote.NewOAuth2Config()
@@ -73,49 +74,55 @@ func (app *oauth2App) CompleteOAuth2(authedUserID, code, state string) error {
 		return errors.New("not authorized, user ID mismatch")
 	}
 
-	ctx := context.Background()
+	ctx := remote.OAuth2Context(context.Background(), app.Remote)
 	tok, err := oconf.Exchange(ctx, code)
 	if err != nil {
 		return err
 	}
 
-	client, err := app.Remote.MakeUserClient(ctx, tok, mattermostUserID, app.Poster, app.Store)
+	return connectRemoteUser(ctx, app.Env, mattermostUserID, tok, "OAuth2 completion")
+}
+
+// connectRemoteUser maps the remote account the token belongs to onto the
+// Mattermost user, and stores the user.
+func connectRemoteUser(ctx context.Context, env Env, mattermostUserID string, tok *oauth2.Token, flow string) error {
+	client, err := env.Remote.MakeUserClient(ctx, tok, mattermostUserID, env.Poster, env.Store)
 	if err != nil {
-		return errors.Wrap(err, "unable to build user client during OAuth2 completion")
+		return errors.Wrap(err, "unable to build user client during "+flow)
 	}
 	me, err := client.GetMe()
 	if err != nil {
 		return err
 	}
 
-	uid, err := app.Store.LoadMattermostUserID(me.ID)
+	uid, err := env.Store.LoadMattermostUserID(me.ID)
 	if err == nil {
-		user, userErr := app.PluginAPI.GetMattermostUser(uid)
+		user, userErr := env.PluginAPI.GetMattermostUser(uid)
 		if userErr == nil {
-			msg := fmt.Sprintf(RemoteUserAlreadyConnected, config.Provider.DisplayName, me.Mail, user.Username, config.Provider.CommandTrigger)
-			app.Poster.DM(authedUserID, "%s", msg)
+			msg := fmt.Sprintf(RemoteUserAlreadyConnected, config.Provider.DisplayName, me.Name(), user.Username, config.Provider.CommandTrigger)
+			env.Poster.DM(mattermostUserID, "%s", msg)
 			return errors.New(msg)
 		}
 
 		if userErr == store.ErrNotFound {
-			msg := fmt.Sprintf(RemoteUserAlreadyConnectedDisabled, config.Provider.DisplayName, me.Mail, config.Provider.CommandTrigger)
-			app.Poster.DM(authedUserID, "%s", msg)
+			msg := fmt.Sprintf(RemoteUserAlreadyConnectedDisabled, config.Provider.DisplayName, me.Name(), config.Provider.CommandTrigger)
+			env.Poster.DM(mattermostUserID, "%s", msg)
 			return errors.New(msg)
 		}
 
 		// Couldn't fetch connected MM account. Reject connect attempt.
-		msg := fmt.Sprintf(RemoteUserAlreadyConnectedNotFound, config.Provider.DisplayName, me.Mail)
-		app.Poster.DM(authedUserID, "%s", msg)
+		msg := fmt.Sprintf(RemoteUserAlreadyConnectedNotFound, config.Provider.DisplayName, me.Name())
+		env.Poster.DM(mattermostUserID, "%s", msg)
 		return errors.New(msg)
 	}
 
-	user, userErr := app.PluginAPI.GetMattermostUser(mattermostUserID)
+	user, userErr := env.PluginAPI.GetMattermostUser(mattermostUserID)
 	if userErr != nil {
 		return fmt.Errorf("error retrieving mattermost user (%s): %w", mattermostUserID, userErr)
 	}
 
 	u := &store.User{
-		PluginVersion:         app.Config.PluginVersion,
+		PluginVersion:         env.Config.PluginVersion,
 		MattermostUserID:      mattermostUserID,
 		MattermostUsername:    user.Username,
 		MattermostDisplayName: user.GetDisplayName(model.ShowFullName),
@@ -135,17 +142,17 @@ func (app *oauth2App) CompleteOAuth2(authedUserID, code, state string) error {
 		Enable:   false,
 	}
 
-	err = app.Store.StoreUser(u)
+	err = env.Store.StoreUser(u)
 	if err != nil {
 		return err
 	}
 
-	err = app.Store.StoreUserInIndex(u)
+	err = env.Store.StoreUserInIndex(u)
 	if err != nil {
 		return err
 	}
 
-	app.Welcomer.AfterSuccessfullyConnect(mattermostUserID, me.Mail)
+	env.Welcomer.AfterSuccessfullyConnect(mattermostUserID, me.Name())
 
 	return nil
 }
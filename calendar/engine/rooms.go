@@ -0,0 +1,206 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/views"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const (
+	// maxRoomsChecked bounds the number of rooms whose schedule is read to
+	// find the free ones.
+	maxRoomsChecked = 100
+
+	roomAvailabilityViewInterval = 15
+
+	roomBookingSubject = "Room booking"
+)
+
+type Rooms interface {
+	FindFreeRooms(user *User, building string, start, end time.Time) ([]*remote.Room, error)
+	OfferRooms(user *User, rooms []*remote.Room, start, end time.Time) error
+	BookRoom(user *User, room *remote.Room, start, end time.Time) (*remote.Event, error)
+}
+
+// FindFreeRooms returns the rooms free from start to end, of the building
+// when one is given. A building is either the name or the address of a room
+// list, or the building of the rooms.
+func (m *mscalendar) FindFreeRooms(user *User, building string, start, end time.Time) ([]*remote.Room, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms, err := m.roomsOf(building)
+	if errors.Is(err, remote.ErrNotImplemented) {
+		return nil, errors.Errorf("rooms are not supported by %s", m.Provider.DisplayName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rooms) > maxRoomsChecked {
+		rooms = rooms[:maxRoomsChecked]
+	}
+	if len(rooms) == 0 {
+		return rooms, nil
+	}
+
+	requests := []*remote.ScheduleUserInfo{}
+	for _, room := range rooms {
+		requests = append(requests, &remote.ScheduleUserInfo{
+			RemoteUserID: user.Remote.ID,
+			Mail:         room.EmailAddress,
+		})
+	}
+	schedules, err := m.client.GetSchedule(requests, remote.NewDateTime(start.UTC(), "UTC"), remote.NewDateTime(end.UTC(), "UTC"), roomAvailabilityViewInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	free := map[string]bool{}
+	for _, s := range schedules {
+		free[strings.ToLower(s.ScheduleID)] = isFree(s)
+	}
+	result := []*remote.Room{}
+	for _, room := range rooms {
+		if free[strings.ToLower(room.EmailAddress)] {
+			result = append(result, room)
+		}
+	}
+	return result, nil
+}
+
+func (m *mscalendar) roomsOf(building string) ([]*remote.Room, error) {
+	if building == "" {
+		return m.client.GetRooms("")
+	}
+
+	lists, err := m.client.GetRoomLists()
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range lists {
+		if strings.EqualFold(list.DisplayName, building) || strings.EqualFold(list.EmailAddress, building) {
+			return m.client.GetRooms(list.EmailAddress)
+		}
+	}
+
+	rooms, err := m.client.GetRooms("")
+	if err != nil {
+		return nil, err
+	}
+	result := []*remote.Room{}
+	for _, room := range rooms {
+		if strings.EqualFold(room.Building, building) {
+			result = append(result, room)
+		}
+	}
+	return result, nil
+}
+
+// isFree tells whether the schedule is free during its whole availability
+// view.
+func isFree(s *remote.ScheduleInformation) bool {
+	if s.Error != nil || len(s.AvailabilityView) == 0 {
+		return false
+	}
+	for _, code := range []byte(s.AvailabilityView) {
+		if code != remote.AvailabilityViewFree {
+			return false
+		}
+	}
+	return true
+}
+
+// OfferRooms sends the rooms to the user, with a button to book each of them
+// from start to end.
+func (m *mscalendar) OfferRooms(user *User, rooms []*remote.Room, start, end time.Time) error {
+	message := fmt.Sprintf("Rooms free from %s to %s:", start.Format("Monday, January 02 · "+time.Kitchen), end.Format(time.Kitchen))
+	url := fmt.Sprintf("%s%s%s", m.Config.PluginURLPath, config.PathPostAction, config.PathBookRoom)
+
+	attachments := []*model.SlackAttachment{}
+	for _, room := range rooms {
+		attachments = append(attachments, roomAttachment(room, start, end, url))
+	}
+	_, err := m.Poster.DMWithMessageAndAttachments(user.MattermostUserID, message, attachments...)
+	return err
+}
+
+func roomAttachment(room *remote.Room, start, end time.Time, url string) *model.SlackAttachment {
+	fields := []*model.SlackAttachmentField{}
+	if room.Building != "" {
+		fields = append(fields, &model.SlackAttachmentField{Title: "Building", Value: views.MarkdownToHTMLEntities(room.Building), Short: true})
+	}
+	if room.FloorLabel != "" {
+		fields = append(fields, &model.SlackAttachmentField{Title: "Floor", Value: views.MarkdownToHTMLEntities(room.FloorLabel), Short: true})
+	}
+	if room.Capacity > 0 {
+		fields = append(fields, &model.SlackAttachmentField{Title: "Capacity", Value: strconv.Itoa(room.Capacity), Short: true})
+	}
+
+	return &model.SlackAttachment{
+		Title:    views.MarkdownToHTMLEntities(room.DisplayName),
+		Fallback: room.DisplayName,
+		Fields:   fields,
+		Actions:  []*model.PostAction{NewPostActionForBookRoom(room, start, end, url)},
+	}
+}
+
+// NewPostActionForBookRoom returns the button booking the room from start to
+// end.
+func NewPostActionForBookRoom(room *remote.Room, start, end time.Time, url string) *model.PostAction {
+	return &model.PostAction{
+		Name: "Book",
+		Type: model.PostActionTypeButton,
+		Integration: &model.PostActionIntegration{
+			URL: url,
+			Context: map[string]interface{}{
+				config.RoomKey:     room.EmailAddress,
+				config.RoomNameKey: room.DisplayName,
+				config.StartKey:    start.Format(time.RFC3339),
+				config.EndKey:      end.Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+// BookRoom creates an event of the user from start to end, inviting the room.
+// The room accepts or declines the invitation on its own.
+func (m *mscalendar) BookRoom(user *User, room *remote.Room, start, end time.Time) (*remote.Event, error) {
+	err := m.Filter(
+		withClient,
+		withUserExpanded(user),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &remote.Event{
+		Subject:  roomBookingSubject,
+		Start:    remote.NewDateTime(start.UTC(), "UTC"),
+		End:      remote.NewDateTime(end.UTC(), "UTC"),
+		Location: &remote.Location{DisplayName: room.DisplayName},
+		Attendees: []*remote.Attendee{{
+			Type: remote.AttendeeTypeResource,
+			EmailAddress: &remote.EmailAddress{
+				Address: room.EmailAddress,
+				Name:    room.DisplayName,
+			},
+		}},
+	}
+	return m.client.CreateEvent("", event)
+}
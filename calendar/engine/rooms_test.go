@@ -0,0 +1,145 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+)
+
+func TestFindFreeRooms(t *testing.T) {
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	room1 := &remote.Room{DisplayName: "Room 1", EmailAddress: "room1@example.com", Building: "North"}
+	room2 := &remote.Room{DisplayName: "Room 2", EmailAddress: "room2@example.com", Building: "South"}
+	room3 := &remote.Room{DisplayName: "Room 3", EmailAddress: "room3@example.com", Building: "South"}
+	schedules := []*remote.ScheduleInformation{
+		{ScheduleID: "Room1@example.com", AvailabilityView: "0000"},
+		{ScheduleID: "room2@example.com", AvailabilityView: "0020"},
+		{ScheduleID: "room3@example.com", Error: &remote.ScheduleInformationError{Message: "not found"}},
+	}
+
+	tests := []struct {
+		name      string
+		building  string
+		setupMock func(*mock_remote.MockClient)
+		expected  []*remote.Room
+		err       string
+	}{
+		{
+			name:     "free rooms of the organization",
+			building: "",
+			setupMock: func(mockClient *mock_remote.MockClient) {
+				mockClient.EXPECT().GetRooms("").Return([]*remote.Room{room1, room2, room3}, nil)
+				mockClient.EXPECT().GetSchedule(gomock.Len(3), remote.NewDateTime(start, "UTC"), remote.NewDateTime(end, "UTC"), roomAvailabilityViewInterval).Return(schedules, nil)
+			},
+			expected: []*remote.Room{room1},
+		},
+		{
+			name:     "building is a room list",
+			building: "headquarters",
+			setupMock: func(mockClient *mock_remote.MockClient) {
+				mockClient.EXPECT().GetRoomLists().Return([]*remote.RoomList{{DisplayName: "Headquarters", EmailAddress: "hq@example.com"}}, nil)
+				mockClient.EXPECT().GetRooms("hq@example.com").Return([]*remote.Room{room1}, nil)
+				mockClient.EXPECT().GetSchedule([]*remote.ScheduleUserInfo{{RemoteUserID: MockRemoteUserID, Mail: "room1@example.com"}}, gomock.Any(), gomock.Any(), roomAvailabilityViewInterval).Return(schedules[:1], nil)
+			},
+			expected: []*remote.Room{room1},
+		},
+		{
+			name:     "building of the rooms",
+			building: "south",
+			setupMock: func(mockClient *mock_remote.MockClient) {
+				mockClient.EXPECT().GetRoomLists().Return([]*remote.RoomList{}, nil)
+				mockClient.EXPECT().GetRooms("").Return([]*remote.Room{room1, room2, room3}, nil)
+				mockClient.EXPECT().GetSchedule(gomock.Len(2), gomock.Any(), gomock.Any(), roomAvailabilityViewInterval).Return(schedules[1:], nil)
+			},
+			expected: []*remote.Room{},
+		},
+		{
+			name:     "no rooms",
+			building: "",
+			setupMock: func(mockClient *mock_remote.MockClient) {
+				mockClient.EXPECT().GetRooms("").Return([]*remote.Room{}, nil)
+			},
+			expected: []*remote.Room{},
+		},
+		{
+			name:     "rooms not supported",
+			building: "",
+			setupMock: func(mockClient *mock_remote.MockClient) {
+				mockClient.EXPECT().GetRooms("").Return(nil, remote.ErrNotImplemented)
+			},
+			err: "rooms are not supported by testDisplayName",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mscalendar, _, _, _, _, mockClient, _ := GetMockSetup(t)
+			user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+			tt.setupMock(mockClient)
+
+			rooms, err := mscalendar.FindFreeRooms(user, tt.building, start, end)
+
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, rooms)
+		})
+	}
+}
+
+func TestOfferRooms(t *testing.T) {
+	mscalendar, _, mockPoster, _, _, _, _ := GetMockSetup(t)
+	mscalendar.Config.PluginURLPath = "/plugins/mscalendar"
+	user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+
+	mockPoster.EXPECT().DMWithMessageAndAttachments(MockMMUserID, "Rooms free from Monday, March 04 · 2:00PM to 2:30PM:", gomock.Any()).DoAndReturn(
+		func(_, _ string, attachments ...*model.SlackAttachment) (string, error) {
+			require.Len(t, attachments, 1)
+			require.Equal(t, "Room 1", attachments[0].Title)
+			require.Equal(t, []*model.SlackAttachmentField{{Title: "Capacity", Value: "8", Short: true}}, attachments[0].Fields)
+			action := attachments[0].Actions[0]
+			require.Equal(t, "/plugins/mscalendar/action/book-room", action.Integration.URL)
+			require.Equal(t, "room1@example.com", action.Integration.Context[config.RoomKey])
+			require.Equal(t, "2024-03-04T14:00:00Z", action.Integration.Context[config.StartKey])
+			require.Equal(t, "2024-03-04T14:30:00Z", action.Integration.Context[config.EndKey])
+			return "postID", nil
+		})
+
+	err := mscalendar.OfferRooms(user, []*remote.Room{{DisplayName: "Room 1", EmailAddress: "room1@example.com", Capacity: 8}}, start, start.Add(30*time.Minute))
+	require.NoError(t, err)
+}
+
+func TestBookRoom(t *testing.T) {
+	mscalendar, _, _, _, _, mockClient, _ := GetMockSetup(t)
+	user := GetMockUser(model.NewPointer(MockRemoteUserID), model.NewPointer(MockMMModelUserID), MockMMUserID, GetMockStoreSettings())
+	start := time.Date(2024, 3, 4, 14, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	mockClient.EXPECT().CreateEvent("", &remote.Event{
+		Subject:  roomBookingSubject,
+		Start:    remote.NewDateTime(start, "UTC"),
+		End:      remote.NewDateTime(end, "UTC"),
+		Location: &remote.Location{DisplayName: "Room 1"},
+		Attendees: []*remote.Attendee{{
+			Type:         remote.AttendeeTypeResource,
+			EmailAddress: &remote.EmailAddress{Address: "room1@example.com", Name: "Room 1"},
+		}},
+	}).Return(&remote.Event{ID: MockEventID}, nil)
+
+	event, err := mscalendar.BookRoom(user, &remote.Room{DisplayName: "Room 1", EmailAddress: "room1@example.com"}, start, end)
+	require.NoError(t, err)
+	require.Equal(t, MockEventID, event.ID)
+}
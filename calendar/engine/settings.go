@@ -5,6 +5,7 @@ package engine
 
 import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/settingspanel"
@@ -44,6 +45,15 @@ func NewSettingsPanel(bot bot.Bot, panelStore settingspanel.PanelStore, settingS
 		store.UpdateStatusFromOptionsSettingID,
 		settingStore,
 	))
+	settings = append(settings, settingspanel.NewOptionSetting(
+		store.WorkingHoursStatusSettingID,
+		"Working Hours",
+		"Do you want to update your status on Mattermost outside your working hours? Outside of them, your status is not set for meetings and you don't receive reminders.",
+		"",
+		store.NotSetStatusOption,
+		[]string{store.AwayStatusOption, store.OfflineStatusOption, store.NotSetStatusOption},
+		settingStore,
+	))
 	settings = append(settings, settingspanel.NewBoolSetting(
 		store.SetCustomStatusSettingID,
 		"Set Custom Status",
@@ -51,6 +61,13 @@ func NewSettingsPanel(bot bot.Bot, panelStore settingspanel.PanelStore, settingS
 		"",
 		settingStore,
 	))
+	settings = append(settings, settingspanel.NewBoolSetting(
+		store.ShowAutomaticReplySettingID,
+		"Show Automatic Reply",
+		"Do you want your custom status to show your automatic reply when you are out of office? Everyone can see your custom status.",
+		store.SetCustomStatusSettingID,
+		settingStore,
+	))
 	settings = append(settings, settingspanel.NewBoolSetting(
 		store.ReceiveRemindersSettingID,
 		"Receive Reminders",
@@ -58,8 +75,21 @@ func NewSettingsPanel(bot bot.Bot, panelStore settingspanel.PanelStore, settingS
 		"",
 		settingStore,
 	))
+	settings = append(settings, NewCalendarsSetting(
+		settingStore,
+		func(userID string) ([]*remote.Calendar, error) { return getCal(userID).GetCalendars(NewUser(userID)) },
+	))
 	if providerFeatures.EventNotifications {
 		settings = append(settings, NewNotificationsSetting(getCal))
+		settings = append(settings, settingspanel.NewOptionSetting(
+			store.NotificationDigestSettingID,
+			"Notification Digest",
+			"Do you want to receive your event notifications together in a digest? Events of high importance are still notified right away.",
+			NotificationsSettingID,
+			store.NotificationDigestImmediatelyOption,
+			[]string{store.NotificationDigestImmediatelyOption, store.NotificationDigestHourlyOption, store.NotificationDigestFourHoursOption, store.NotificationDigestDailyOption},
+			settingStore,
+		))
 	}
 	settings = append(settings, NewDailySummarySetting(
 		settingStore,
@@ -0,0 +1,145 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost/server/public/model"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/settingspanel"
+)
+
+type calendarsSetting struct {
+	store        settingspanel.SettingStore
+	getCalendars func(userID string) ([]*remote.Calendar, error)
+	title        string
+	description  string
+	id           string
+	dependsOn    string
+}
+
+// NewCalendarsSetting lets the user pick the calendars used for status sync,
+// reminders and the daily summary. Each calendar button toggles the
+// calendar, and the default calendar button clears the selection.
+func NewCalendarsSetting(inStore settingspanel.SettingStore, getCalendars func(userID string) ([]*remote.Calendar, error)) settingspanel.Setting {
+	return &calendarsSetting{
+		title:        "Calendars",
+		description:  "Which calendars should be used to update your status, send reminders and build your daily summary?",
+		id:           store.CalendarsSettingID,
+		dependsOn:    "",
+		store:        inStore,
+		getCalendars: getCalendars,
+	}
+}
+
+func (s *calendarsSetting) Set(userID string, value interface{}) error {
+	_, ok := value.(string)
+	if !ok {
+		return errors.New("trying to set Calendars Setting without a string value")
+	}
+	return s.store.SetSetting(userID, s.id, value)
+}
+
+func (s *calendarsSetting) Get(userID string) (interface{}, error) {
+	value, err := s.store.GetSetting(userID, s.id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok := value.([]string)
+	if !ok {
+		return nil, errors.New("current value is not a Calendars Setting")
+	}
+
+	return value, nil
+}
+
+func (s *calendarsSetting) GetID() string {
+	return s.id
+}
+
+func (s *calendarsSetting) GetTitle() string {
+	return s.title
+}
+
+func (s *calendarsSetting) GetDescription() string {
+	return s.description
+}
+
+func (s *calendarsSetting) GetDependency() string {
+	return s.dependsOn
+}
+
+func (s *calendarsSetting) GetSlackAttachments(userID, settingHandler string, disabled bool) (*model.SlackAttachment, error) {
+	title := fmt.Sprintf("Setting: %s", s.title)
+	currentValueMessage := "Disabled"
+
+	actions := []*model.PostAction{}
+	if !disabled {
+		value, err := s.Get(userID)
+		if err != nil {
+			return nil, err
+		}
+		selected := map[string]bool{}
+		for _, id := range value.([]string) {
+			selected[id] = true
+		}
+
+		calendars, err := s.getCalendars(userID)
+		if err != nil {
+			return nil, fmt.Errorf("could not load the calendars. err=%v", err)
+		}
+
+		names := []string{}
+		for _, cal := range calendars {
+			style := "default"
+			if selected[cal.ID] {
+				style = "primary"
+				names = append(names, cal.Name)
+			}
+			actions = append(actions, s.makeAction(cal.Name, cal.ID, style, settingHandler))
+		}
+
+		defaultStyle := "default"
+		if len(names) == 0 {
+			defaultStyle = "primary"
+			names = append(names, "Default calendar")
+		}
+		actions = append(actions, s.makeAction("Default calendar", "", defaultStyle, settingHandler))
+		currentValueMessage = fmt.Sprintf("**Current value:** %s", strings.Join(names, ", "))
+	}
+
+	text := fmt.Sprintf("%s\n%s", s.description, currentValueMessage)
+	sa := model.SlackAttachment{
+		Title:    title,
+		Text:     text,
+		Actions:  actions,
+		Fallback: fmt.Sprintf("%s: %s", title, text),
+	}
+
+	return &sa, nil
+}
+
+func (s *calendarsSetting) makeAction(name, value, style, settingHandler string) *model.PostAction {
+	return &model.PostAction{
+		Name:  name,
+		Style: style,
+		Integration: &model.PostActionIntegration{
+			URL: settingHandler,
+			Context: map[string]interface{}{
+				settingspanel.ContextIDKey:          s.id,
+				settingspanel.ContextButtonValueKey: value,
+			},
+		},
+	}
+}
+
+func (s *calendarsSetting) IsDisabled(_ interface{}) bool {
+	return false
+}
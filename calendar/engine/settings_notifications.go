@@ -11,6 +11,8 @@ import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/settingspanel"
 )
 
+const NotificationsSettingID = "new_or_updated_event_setting"
+
 type notificationSetting struct {
 	getCal      func(string) Engine
 	title       string
@@ -23,7 +25,7 @@ func NewNotificationsSetting(getCal func(string) Engine) settingspanel.Setting {
 	return &notificationSetting{
 		title:       "Receive notifications of new events",
 		description: "Do you want to subscribe to new events and receive a message when they are created?",
-		id:          "new_or_updated_event_setting",
+		id:          NotificationsSettingID,
 		dependsOn:   "",
 		getCal:      getCal,
 	}
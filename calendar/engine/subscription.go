@@ -20,6 +20,8 @@ type Subscriptions interface {
 	DeleteMyEventSubscription() error
 	ListRemoteSubscriptions() ([]*remote.Subscription, error)
 	LoadMyEventSubscription() (*store.Subscription, error)
+	ListFailedNotifications() ([]*store.QueuedNotification, error)
+	ReplayFailedNotifications(ids ...string) (int, error)
 }
 
 func (m *mscalendar) CreateMyEventSubscription() (*store.Subscription, error) {
@@ -28,7 +30,7 @@ func (m *mscalendar) CreateMyEventSubscription() (*store.Subscription, error) {
 		return nil, fmt.Errorf("error withClient in CreateMyEventSubscription: %w", err)
 	}
 
-	sub, err := m.client.CreateMySubscription(m.Config.GetNotificationURL(), m.actingUser.Remote.ID)
+	sub, err := m.client.CreateMySubscription(m.Config.GetNotificationURL(), m.Config.GetLifecycleNotificationURL(), m.actingUser.Remote.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -153,3 +155,15 @@ func (m *mscalendar) DeleteOrphanedSubscription(sub *store.Subscription) error {
 	}
 	return nil
 }
+
+// ListFailedNotifications returns the webhook notifications that were moved to
+// the dead letters after failing too many times.
+func (m *mscalendar) ListFailedNotifications() ([]*store.QueuedNotification, error) {
+	return m.Store.LoadDeadLetterNotifications()
+}
+
+// ReplayFailedNotifications queues failed notifications again, all of them if
+// no ID is given.
+func (m *mscalendar) ReplayFailedNotifications(ids ...string) (int, error) {
+	return m.Store.ReplayDeadLetterNotifications(ids...)
+}
@@ -41,7 +41,7 @@ func TestCreateMyEventSubscription(t *testing.T) {
 			setupMock: func() {
 				mscalendar.client = mockClient
 				mscalendar.actingUser = GetMockUser(model.NewPointer(MockActingUserRemoteID), nil, MockActingUserID, nil)
-				mockClient.EXPECT().CreateMySubscription(gomock.Any(), MockActingUserRemoteID).Return(nil, errors.New("error creating the subscription"))
+				mockClient.EXPECT().CreateMySubscription(gomock.Any(), gomock.Any(), MockActingUserRemoteID).Return(nil, errors.New("error creating the subscription"))
 			},
 			assertion: func(sub *store.Subscription, err error) {
 				require.EqualError(t, err, "error creating the subscription")
@@ -52,7 +52,7 @@ func TestCreateMyEventSubscription(t *testing.T) {
 			setupMock: func() {
 				mscalendar.client = mockClient
 				mscalendar.actingUser = GetMockUser(model.NewPointer(MockActingUserRemoteID), nil, MockActingUserID, nil)
-				mockClient.EXPECT().CreateMySubscription(gomock.Any(), MockActingUserRemoteID).Return(&remote.Subscription{}, nil)
+				mockClient.EXPECT().CreateMySubscription(gomock.Any(), gomock.Any(), MockActingUserRemoteID).Return(&remote.Subscription{}, nil)
 				mockStore.EXPECT().StoreUserSubscription(mscalendar.actingUser.User, expectedSub)
 			},
 			assertion: func(sub *store.Subscription, err error) {
@@ -32,6 +32,7 @@ const (
 
 	MockEventName           = "Test Event"
 	MockEventID             = "testEventID"
+	MockICalUID             = "testICalUID"
 	MockEventSubscriptionID = "testEventSubscriptionID"
 
 	MockActingUserID       = "testActingUserID"
@@ -4,12 +4,14 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/pkg/errors"
 
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
@@ -18,12 +20,19 @@ import (
 type Users interface {
 	GetActingUser() *User
 	GetTimezone(user *User) (string, error)
+	ConnectWithCredentials(mattermostUserID string, args []string) error
+	OpenConnectDialog(mattermostUserID, triggerID string) error
 	DisconnectUser(mattermostUserID string) error
 	GetRemoteUser(mattermostUserID string) (*remote.User, error)
 	IsAuthorizedAdmin(mattermostUserID string) (bool, error)
 	GetUserSettings(user *User) (*store.Settings, error)
 }
 
+const (
+	ConnectDialogUsername = "username"
+	ConnectDialogPassword = "password"
+)
+
 type User struct {
 	*store.User
 	MattermostUser   *model.User
@@ -123,6 +132,58 @@ func (user *User) Markdown() string {
 	return fmt.Sprintf("UserID: `%s`", user.MattermostUserID)
 }
 
+// ConnectWithCredentials connects the user with the credentials passed to the
+// connect command, for remotes that don't use OAuth2.
+func (m *mscalendar) ConnectWithCredentials(mattermostUserID string, args []string) error {
+	connector, ok := m.Remote.(remote.CredentialsConnector)
+	if !ok {
+		return errors.Errorf("%s does not support connecting with credentials", m.Provider.DisplayName)
+	}
+
+	if user, err := m.Store.LoadUser(mattermostUserID); err == nil {
+		return errors.Errorf("user is already connected to %s", user.Remote.Name())
+	}
+
+	ctx := context.Background()
+	tok, err := connector.NewTokenFromCredentials(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	return connectRemoteUser(ctx, m.Env, mattermostUserID, tok, "credentials connect")
+}
+
+// OpenConnectDialog asks the user for the credentials to connect with, in a
+// dialog so that the password is not typed in a command.
+func (m *mscalendar) OpenConnectDialog(mattermostUserID, triggerID string) error {
+	if _, ok := m.Remote.(remote.CredentialsConnector); !ok {
+		return errors.Errorf("%s does not support connecting with credentials", m.Provider.DisplayName)
+	}
+
+	return m.PluginAPI.OpenInteractiveDialog(model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       m.Config.PluginURLPath + config.PathDialogs + config.PathConnect,
+		Dialog: model.Dialog{
+			Title:       fmt.Sprintf("Connect to %s", m.Provider.DisplayName),
+			SubmitLabel: "Connect",
+			Elements: []model.DialogElement{
+				{
+					DisplayName: "Username",
+					Name:        ConnectDialogUsername,
+					Type:        "text",
+				},
+				{
+					DisplayName: "Password",
+					Name:        ConnectDialogPassword,
+					Type:        "text",
+					SubType:     "password",
+					HelpText:    "Use an app password when your server supports them.",
+				},
+			},
+		},
+	})
+}
+
 func (m *mscalendar) DisconnectUser(mattermostUserID string) error {
 	m.AfterDisconnect(mattermostUserID)
 
@@ -196,6 +257,12 @@ func (m *mscalendar) DisconnectUser(mattermostUserID string) error {
 		}
 	}
 
+	if storedUser.Remote != nil {
+		if errDelete := m.Store.DeleteEventMirror(storedUser.Remote.ID); errDelete != nil && errDelete != store.ErrNotFound {
+			m.Logger.Warnf("failed to delete the event mirror of user %s. err=%v", mattermostUserID, errDelete)
+		}
+	}
+
 	err = m.Store.DeleteUser(mattermostUserID)
 	if err != nil {
 		return err
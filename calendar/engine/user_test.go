@@ -476,10 +476,14 @@ func TestDisconnectUser(t *testing.T) {
 				mscalendar.client = mockClient
 				mscalendar.actingUser = &User{MattermostUserID: MockRemoteUserID}
 				mockWelcomer.EXPECT().AfterDisconnect(MockMMUserID).Return(nil).Times(1)
-				mockStore.EXPECT().LoadUser(MockMMUserID).Return(&store.User{Settings: store.Settings{EventSubscriptionID: MockEventSubscriptionID}}, nil).Times(1)
+				mockStore.EXPECT().LoadUser(MockMMUserID).Return(&store.User{
+					Remote:   &remote.User{ID: MockRemoteUserID},
+					Settings: store.Settings{EventSubscriptionID: MockEventSubscriptionID},
+				}, nil).Times(1)
 				mockStore.EXPECT().LoadSubscription(MockEventSubscriptionID).Return(&store.Subscription{Remote: &remote.Subscription{}}, nil).Times(1)
 				mockStore.EXPECT().DeleteUserSubscription(gomock.Any(), MockEventSubscriptionID).Return(nil).Times(1)
 				mockClient.EXPECT().DeleteSubscription(gomock.Any()).Return(nil).Times(1)
+				mockStore.EXPECT().DeleteEventMirror(MockRemoteUserID).Return(nil).Times(1)
 				mockStore.EXPECT().DeleteUser(MockMMUserID).Return(nil).Times(1)
 				mockStore.EXPECT().DeleteUserFromIndex(MockMMUserID).Return(nil).Times(1)
 			},
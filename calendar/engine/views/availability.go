@@ -0,0 +1,85 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const availabilityUnknown = "⬜"
+
+var availabilityLegend = []struct {
+	code  byte
+	emoji string
+	label string
+}{
+	{remote.AvailabilityViewFree, "🟩", "Free"},
+	{remote.AvailabilityViewTentative, "🟨", "Tentative"},
+	{remote.AvailabilityViewBusy, "🟥", "Busy"},
+	{remote.AvailabilityViewOutOfOffice, "🟪", "Out of office"},
+	{remote.AvailabilityViewWorkingElsewhere, "🟦", "Working elsewhere"},
+}
+
+// AvailabilityRow is the free/busy schedule of a user, or the reason it is
+// not available.
+type AvailabilityRow struct {
+	Username         string
+	AvailabilityView remote.AvailabilityView
+	Error            string
+}
+
+// RenderAvailabilityGrid renders one row per user, with one column per hour
+// starting at start and one square per interval of availabilityViewInterval
+// minutes.
+func RenderAvailabilityGrid(rows []*AvailabilityRow, start time.Time, hours, availabilityViewInterval int) string {
+	perHour := 60 / availabilityViewInterval
+
+	header := "| User |"
+	separator := "| :--- |"
+	for i := 0; i < hours; i++ {
+		header += " " + start.Add(time.Duration(i)*time.Hour).Format("15:04") + " |"
+		separator += " :--- |"
+	}
+
+	resp := fmt.Sprintf("Availability on %s (%s)\n\n", start.Format("Monday January 02, 2006"), start.Location())
+	resp += header + "\n" + separator + "\n"
+	for _, row := range rows {
+		resp += "| @" + row.Username + " |"
+		if row.Error != "" {
+			resp += " " + row.Error + " |" + strings.Repeat(" |", hours-1) + "\n"
+			continue
+		}
+		for i := 0; i < hours; i++ {
+			resp += " "
+			for j := i * perHour; j < (i+1)*perHour; j++ {
+				resp += availabilityEmoji(row.AvailabilityView, j)
+			}
+			resp += " |"
+		}
+		resp += "\n"
+	}
+
+	legend := []string{}
+	for _, l := range availabilityLegend {
+		legend = append(legend, l.emoji+" "+l.label)
+	}
+	legend = append(legend, availabilityUnknown+" Unknown")
+	return resp + "\n" + strings.Join(legend, "  ")
+}
+
+func availabilityEmoji(view remote.AvailabilityView, i int) string {
+	if i >= len(view) {
+		return availabilityUnknown
+	}
+	for _, l := range availabilityLegend {
+		if view[i] == l.code {
+			return l.emoji
+		}
+	}
+	return availabilityUnknown
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderAvailabilityGrid(t *testing.T) {
+	start := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	rows := []*AvailabilityRow{
+		{Username: "alice", AvailabilityView: "0123"},
+		{Username: "bob", AvailabilityView: "4"},
+		{Username: "carol", Error: "User not found."},
+	}
+
+	expected := "Availability on Monday January 08, 2024 (UTC)\n\n" +
+		"| User | 08:00 | 09:00 |\n" +
+		"| :--- | :--- | :--- |\n" +
+		"| @alice | 🟩🟨 | 🟥🟪 |\n" +
+		"| @bob | 🟦⬜ | ⬜⬜ |\n" +
+		"| @carol | User not found. | |\n" +
+		"\n🟩 Free  🟨 Tentative  🟥 Busy  🟪 Out of office  🟦 Working elsewhere  ⬜ Unknown"
+	require.Equal(t, expected, RenderAvailabilityGrid(rows, start, 2, 30))
+}
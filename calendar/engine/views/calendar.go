@@ -100,6 +100,13 @@ func RenderDaySummary(events []*remote.Event, timezone string) (string, []*model
 				Short: true,
 			})
 		}
+		if recurrence := RenderRecurrence(event); recurrence != "" {
+			fields = append(fields, &model.SlackAttachmentField{
+				Title: "Repeats",
+				Value: recurrence,
+				Short: true,
+			})
+		}
 
 		attachments = append(attachments, &model.SlackAttachment{
 			Title: event.Subject,
@@ -160,25 +167,29 @@ func renderEvent(event *remote.Event, asRow bool, timeZone string) (string, erro
 	}
 
 	subject := EnsureSubject(event.Subject)
+	marker := ""
+	if event.IsRecurring() {
+		marker = " " + RecurringMarker
+	}
 
 	if event.IsAllDay {
-		format := "(All day event) [%s](%s)"
+		format := "(All day event) [%s](%s)%s"
 		if asRow {
-			format = "| All day event | [%s](%s) |"
+			format = "| All day event | [%s](%s)%s |"
 		}
 
-		return fmt.Sprintf(format, MarkdownToHTMLEntities(subject), link), nil
+		return fmt.Sprintf(format, MarkdownToHTMLEntities(subject), link, marker), nil
 	}
 
 	start := event.Start.In(timeZone).Time().Format(time.Kitchen)
 	end := event.End.In(timeZone).Time().Format(time.Kitchen)
 
-	format := "(%s - %s) [%s](%s)"
+	format := "(%s - %s) [%s](%s)%s"
 	if asRow {
-		format = "| %s - %s | [%s](%s) |"
+		format = "| %s - %s | [%s](%s)%s |"
 	}
 
-	return fmt.Sprintf(format, start, end, MarkdownToHTMLEntities(subject), link), nil
+	return fmt.Sprintf(format, start, end, MarkdownToHTMLEntities(subject), link, marker), nil
 }
 
 func RenderEventAsAttachment(event *remote.Event, timezone string, options ...Option) (*model.SlackAttachment, error) {
@@ -210,6 +221,14 @@ func RenderEventAsAttachment(event *remote.Event, timezone string, options ...Op
 		})
 	}
 
+	if recurrence := RenderRecurrence(event); recurrence != "" {
+		fields = append(fields, &model.SlackAttachmentField{
+			Title: "Repeats",
+			Value: recurrence,
+			Short: true,
+		})
+	}
+
 	attachment := &model.SlackAttachment{
 		Title:     MarkdownToHTMLEntities(event.Subject),
 		TitleLink: titleLink,
@@ -271,6 +290,30 @@ func EnsureSubject(s string) string {
 	return s
 }
 
+// PrivateEventSubject replaces the subject of private events shown to
+// people other than the owner of the calendar.
+const PrivateEventSubject = "Busy"
+
+// ForOthers returns the event as it can be shown to people other than the
+// owner of its calendar, such as channel members or delegates. Private events
+// keep their time and response status, but not their subject, location, body
+// or attendees.
+func ForOthers(event *remote.Event) *remote.Event {
+	if event == nil || !event.IsPrivate() {
+		return event
+	}
+
+	masked := *event
+	masked.Subject = PrivateEventSubject
+	masked.Location = nil
+	masked.Body = nil
+	masked.BodyPreview = ""
+	masked.Conference = nil
+	masked.OnlineMeeting = nil
+	masked.Attendees = nil
+	return &masked
+}
+
 func RenderUpcomingEventAsAttachment(event *remote.Event, timeZone string, options ...Option) (message string, attachment *model.SlackAttachment, err error) {
 	message = "Upcoming event:\n"
 	attachment, err = RenderEventAsAttachment(event, timeZone, options...)
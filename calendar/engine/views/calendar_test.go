@@ -5,8 +5,11 @@ package views
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
 
 func TestMarkdownToHTMLEntities(t *testing.T) {
@@ -72,3 +75,51 @@ func TestMarkdownToHTMLEntities(t *testing.T) {
 		})
 	}
 }
+
+func TestForOthers(t *testing.T) {
+	for _, tc := range []struct {
+		sensitivity     string
+		expectedSubject string
+	}{
+		{sensitivity: "", expectedSubject: "Interview"},
+		{sensitivity: remote.SensitivityNormal, expectedSubject: "Interview"},
+		{sensitivity: remote.SensitivityPersonal, expectedSubject: "Interview"},
+		{sensitivity: remote.SensitivityPrivate, expectedSubject: PrivateEventSubject},
+		{sensitivity: remote.SensitivityConfidential, expectedSubject: PrivateEventSubject},
+	} {
+		t.Run(tc.sensitivity, func(t *testing.T) {
+			event := &remote.Event{
+				ID:          "event_id",
+				Subject:     "Interview",
+				Start:       remote.NewDateTime(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC), "UTC"),
+				End:         remote.NewDateTime(time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC), "UTC"),
+				Sensitivity: tc.sensitivity,
+				Location:    &remote.Location{DisplayName: "Room 1"},
+				Body:        &remote.ItemBody{Content: "Candidate resume"},
+				BodyPreview: "Candidate resume",
+				Conference:  &remote.Conference{URL: "https://example.com/join"},
+				Attendees:   []*remote.Attendee{{EmailAddress: &remote.EmailAddress{Address: "candidate@example.com"}}},
+			}
+
+			shown := ForOthers(event)
+
+			require.Equal(t, tc.expectedSubject, shown.Subject)
+			require.Equal(t, "event_id", shown.ID)
+			require.Equal(t, "Interview", event.Subject)
+			if !event.IsPrivate() {
+				require.Same(t, event, shown)
+				return
+			}
+			require.Nil(t, shown.Location)
+			require.Nil(t, shown.Body)
+			require.Empty(t, shown.BodyPreview)
+			require.Nil(t, shown.Conference)
+			require.Empty(t, shown.Attendees)
+
+			attachment, err := RenderEventAsAttachment(shown, "UTC")
+			require.NoError(t, err)
+			require.Equal(t, PrivateEventSubject, attachment.Title)
+			require.Empty(t, attachment.Fields)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// RecurringMarker is appended to recurring events in calendar views.
+const RecurringMarker = ":repeat:"
+
+// RenderRecurrence describes how a recurring event repeats, for example
+// "Every 2 weeks on Monday, Wednesday, until March 01, 2024". Occurrences
+// don't carry the pattern of their series, so they are only described as
+// recurring. It returns an empty string for events that don't repeat.
+func RenderRecurrence(event *remote.Event) string {
+	if !event.IsRecurring() {
+		return ""
+	}
+	if event.Recurrence == nil || event.Recurrence.Pattern == nil {
+		return "Recurring event"
+	}
+
+	p := event.Recurrence.Pattern
+	days := []string{}
+	for _, d := range p.DaysOfWeek {
+		days = append(days, capitalize(d))
+	}
+	month := ""
+	if p.Month >= 1 && p.Month <= 12 {
+		month = time.Month(p.Month).String()
+	}
+
+	var resp string
+	switch p.Type {
+	case remote.RecurrencePatternDaily:
+		resp = every(p.Interval, "day")
+	case remote.RecurrencePatternWeekly:
+		resp = every(p.Interval, "week")
+		if len(days) > 0 {
+			resp += " on " + strings.Join(days, ", ")
+		}
+	case remote.RecurrencePatternAbsoluteMonthly:
+		resp = every(p.Interval, "month") + fmt.Sprintf(" on day %d", p.DayOfMonth)
+	case remote.RecurrencePatternRelativeMonthly:
+		resp = every(p.Interval, "month") + fmt.Sprintf(" on the %s %s", p.Index, strings.Join(days, ", "))
+	case remote.RecurrencePatternAbsoluteYearly:
+		resp = every(p.Interval, "year") + fmt.Sprintf(" on %s %d", month, p.DayOfMonth)
+	case remote.RecurrencePatternRelativeYearly:
+		resp = every(p.Interval, "year") + fmt.Sprintf(" on the %s %s of %s", p.Index, strings.Join(days, ", "), month)
+	default:
+		return "Recurring event"
+	}
+
+	if r := event.Recurrence.Range; r != nil {
+		switch r.Type {
+		case remote.RecurrenceRangeEndDate:
+			if end, err := time.Parse("2006-01-02", r.EndDate); err == nil {
+				resp += ", until " + end.Format("January 02, 2006")
+			}
+		case remote.RecurrenceRangeNumbered:
+			resp += fmt.Sprintf(", %d times", r.NumberOfOccurrences)
+		}
+	}
+	return resp
+}
+
+func every(interval int, unit string) string {
+	if interval <= 1 {
+		return "Every " + unit
+	}
+	return fmt.Sprintf("Every %d %ss", interval, unit)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestRenderRecurrence(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		event    *remote.Event
+		expected string
+	}{
+		{
+			name:     "single instance",
+			event:    &remote.Event{Type: remote.EventTypeSingleInstance},
+			expected: "",
+		},
+		{
+			name:     "occurrence",
+			event:    &remote.Event{Type: remote.EventTypeOccurrence, SeriesMasterID: "master"},
+			expected: "Recurring event",
+		},
+		{
+			name: "daily",
+			event: &remote.Event{Recurrence: &remote.PatternedRecurrence{
+				Pattern: &remote.RecurrencePattern{Type: remote.RecurrencePatternDaily, Interval: 1},
+			}},
+			expected: "Every day",
+		},
+		{
+			name: "weekly with count",
+			event: &remote.Event{Recurrence: &remote.PatternedRecurrence{
+				Pattern: &remote.RecurrencePattern{Type: remote.RecurrencePatternWeekly, Interval: 2, DaysOfWeek: []string{"monday", "wednesday"}},
+				Range:   &remote.RecurrenceRange{Type: remote.RecurrenceRangeNumbered, NumberOfOccurrences: 10},
+			}},
+			expected: "Every 2 weeks on Monday, Wednesday, 10 times",
+		},
+		{
+			name: "monthly until end date",
+			event: &remote.Event{Recurrence: &remote.PatternedRecurrence{
+				Pattern: &remote.RecurrencePattern{Type: remote.RecurrencePatternAbsoluteMonthly, Interval: 1, DayOfMonth: 15},
+				Range:   &remote.RecurrenceRange{Type: remote.RecurrenceRangeEndDate, EndDate: "2024-03-01"},
+			}},
+			expected: "Every month on day 15, until March 01, 2024",
+		},
+		{
+			name: "relative yearly",
+			event: &remote.Event{Recurrence: &remote.PatternedRecurrence{
+				Pattern: &remote.RecurrencePattern{Type: remote.RecurrencePatternRelativeYearly, Interval: 1, Month: 3, DaysOfWeek: []string{"sunday"}, Index: "last"},
+				Range:   &remote.RecurrenceRange{Type: remote.RecurrenceRangeNoEnd},
+			}},
+			expected: "Every year on the last Sunday of March",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, RenderRecurrence(tc.event))
+		})
+	}
+}
+
+func TestRenderCalendarViewRecurringMarker(t *testing.T) {
+	start := time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)
+	events := []*remote.Event{
+		{
+			Subject:        "Standup",
+			Weblink:        "https://example.com/standup",
+			Start:          remote.NewDateTime(start, "UTC"),
+			End:            remote.NewDateTime(start.Add(15*time.Minute), "UTC"),
+			Type:           remote.EventTypeOccurrence,
+			SeriesMasterID: "master",
+		},
+		{
+			Subject: "Review",
+			Weblink: "https://example.com/review",
+			Start:   remote.NewDateTime(start.Add(time.Hour), "UTC"),
+			End:     remote.NewDateTime(start.Add(2*time.Hour), "UTC"),
+		},
+	}
+
+	out, err := RenderCalendarView(events, "UTC")
+	require.NoError(t, err)
+	require.Contains(t, out, "| 9:00AM - 9:15AM | [Standup](https://example.com/standup) :repeat: |")
+	require.Contains(t, out, "| 10:00AM - 11:00AM | [Review](https://example.com/review) |")
+
+	attachment, err := RenderEventAsAttachment(events[0], "UTC")
+	require.NoError(t, err)
+	require.Len(t, attachment.Fields, 1)
+	require.Equal(t, "Repeats", attachment.Fields[0].Title)
+	require.Equal(t, "Recurring event", attachment.Fields[0].Value)
+}
@@ -34,7 +34,8 @@ type mscBot struct {
 }
 
 const (
-	WelcomeMessage = `Welcome to the %s plugin. [Click here to link your account.](%s/oauth2/connect)`
+	WelcomeMessage            = `Welcome to the %s plugin. [Click here to link your account.](%s/oauth2/connect)`
+	WelcomeCredentialsMessage = "Welcome to the %s plugin. Run `/%s connect` to link your account."
 )
 
 func (m *mscalendar) Welcome(userID string) error {
@@ -117,6 +118,9 @@ func (bot *mscBot) WelcomeFlowEnd(userID string) {
 func (bot *mscBot) newConnectAttachment() *model.SlackAttachment {
 	title := "Connect"
 	text := fmt.Sprintf(WelcomeMessage, bot.Provider.DisplayName, bot.pluginURL)
+	if bot.Provider.Features.CredentialsConnect {
+		text = fmt.Sprintf(WelcomeCredentialsMessage, bot.Provider.DisplayName, bot.Provider.CommandTrigger)
+	}
 	sa := model.SlackAttachment{
 		Title:    title,
 		Text:     text,
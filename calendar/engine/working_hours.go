@@ -0,0 +1,114 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+// usersOutsideWorkingHours returns the Mattermost IDs of the users who opted
+// into working hours and are outside of them at the given time.
+func (m *mscalendar) usersOutsideWorkingHours(users []*store.User, now time.Time) map[string]bool {
+	outside := map[string]bool{}
+	for _, user := range users {
+		if !user.IsConfiguredForWorkingHours() {
+			continue
+		}
+		settings := m.mailboxSettingsOf(user, now)
+		if settings != nil && !isWithinWorkingHours(&settings.WorkingHours, now) {
+			outside[user.MattermostUserID] = true
+		}
+	}
+	return outside
+}
+
+// isWithinWorkingHours tells whether t is within the working hours. Working
+// hours that cannot be read are ignored, and working hours ending before they
+// start end on the next day.
+func isWithinWorkingHours(wh *remote.WorkingHours, t time.Time) bool {
+	start, errStart := parseWorkingTime(wh.StartTime)
+	end, errEnd := parseWorkingTime(wh.EndTime)
+	if errStart != nil || errEnd != nil || len(wh.DaysOfWeek) == 0 {
+		return true
+	}
+
+	loc, err := time.LoadLocation(tz.Go(wh.TimeZone.Name))
+	if err != nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	sinceMidnight := t.Sub(day)
+
+	isWorkday := func(d time.Time) bool {
+		for _, name := range wh.DaysOfWeek {
+			if strings.EqualFold(name, d.Weekday().String()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if start < end {
+		return isWorkday(day) && sinceMidnight >= start && sinceMidnight < end
+	}
+	// Overnight working hours: the evening of a workday, or the morning after.
+	return (isWorkday(day) && sinceMidnight >= start) || (isWorkday(day.AddDate(0, 0, -1)) && sinceMidnight < end)
+}
+
+// parseWorkingTime reads a time of the day such as 08:30:00.0000000.
+func parseWorkingTime(s string) (time.Duration, error) {
+	if i := strings.Index(s, "."); i >= 0 {
+		s = s[:i]
+	}
+	t, err := time.Parse("15:04:05", s)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid working time %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+}
+
+// setWorkingHoursStatus sets the status chosen by the user once their working
+// hours end, and sets it back to online once they start again, unless the user
+// changed it in between. Users already away or offline are left alone.
+func (m *mscalendar) setWorkingHoursStatus(user *store.User, status *model.Status, outside bool) (bool, error) {
+	offStatus := model.StatusAway
+	if user.Settings.WorkingHoursStatus == store.OfflineStatusOption {
+		offStatus = model.StatusOffline
+	}
+
+	switch {
+	case outside && !user.IsWorkingHoursStatusSet:
+		if status.Status == offStatus || status.Status == model.StatusOffline {
+			return false, nil
+		}
+		if _, err := m.PluginAPI.UpdateMattermostUserStatus(user.MattermostUserID, offStatus); err != nil {
+			return false, err
+		}
+		status.Status = offStatus
+		user.IsWorkingHoursStatusSet = true
+		return true, m.Store.StoreUser(user)
+
+	case !outside && user.IsWorkingHoursStatusSet:
+		changed := false
+		if status.Status == offStatus {
+			if _, err := m.PluginAPI.UpdateMattermostUserStatus(user.MattermostUserID, model.StatusOnline); err != nil {
+				return false, err
+			}
+			status.Status = model.StatusOnline
+			changed = true
+		}
+		user.IsWorkingHoursStatusSet = false
+		return changed, m.Store.StoreUser(user)
+	}
+	return false, nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine/mock_plugin_api"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote/mock_remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot/mock_bot"
+)
+
+func TestIsWithinWorkingHours(t *testing.T) {
+	weekdays := []string{"monday", "tuesday", "wednesday", "thursday", "friday"}
+	nineToFive := &remote.WorkingHours{StartTime: "09:00:00.0000000", EndTime: "17:00:00.0000000", DaysOfWeek: weekdays}
+	nineToFive.TimeZone.Name = "Pacific Standard Time"
+	nightShift := &remote.WorkingHours{StartTime: "22:00:00.0000000", EndTime: "06:00:00.0000000", DaysOfWeek: []string{"Monday"}}
+
+	for name, tc := range map[string]struct {
+		wh       *remote.WorkingHours
+		at       time.Time
+		expected bool
+	}{
+		"during the workday":                 {wh: nineToFive, at: time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC), expected: true},
+		"before the workday in its timezone": {wh: nineToFive, at: time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC), expected: false},
+		"after the workday in its timezone":  {wh: nineToFive, at: time.Date(2024, 3, 5, 1, 30, 0, 0, time.UTC), expected: false},
+		"on the weekend":                     {wh: nineToFive, at: time.Date(2024, 3, 9, 18, 0, 0, 0, time.UTC), expected: false},
+		"evening of a night shift":           {wh: nightShift, at: time.Date(2024, 3, 4, 23, 0, 0, 0, time.UTC), expected: true},
+		"morning after a night shift":        {wh: nightShift, at: time.Date(2024, 3, 5, 5, 0, 0, 0, time.UTC), expected: true},
+		"evening after no night shift":       {wh: nightShift, at: time.Date(2024, 3, 5, 23, 0, 0, 0, time.UTC), expected: false},
+		"unreadable working hours":           {wh: &remote.WorkingHours{StartTime: "9am", EndTime: "5pm", DaysOfWeek: weekdays}, at: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), expected: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, isWithinWorkingHours(tc.wh, tc.at))
+		})
+	}
+}
+
+func TestSyncWorkingHoursStatus(t *testing.T) {
+	now := time.Now().UTC()
+	allDay := &remote.WorkingHours{
+		StartTime:  "00:00:00.0000000",
+		EndTime:    "00:00:00.0000000",
+		DaysOfWeek: []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"},
+	}
+	dayOff := &remote.WorkingHours{
+		StartTime:  "00:00:00.0000000",
+		EndTime:    "00:00:00.0000000",
+		DaysOfWeek: []string{strings.ToLower(now.AddDate(0, 0, 3).Weekday().String())},
+	}
+	busyEvent := &remote.Event{
+		ICalUID: "event_id",
+		Start:   remote.NewDateTime(now.Add(5*time.Minute), "UTC"),
+		End:     remote.NewDateTime(now.Add(time.Hour), "UTC"),
+		ShowAs:  "busy",
+		Attendees: []*remote.Attendee{{
+			EmailAddress: &remote.EmailAddress{Address: "mock-attendee@example.com"},
+		}},
+	}
+
+	for name, tc := range map[string]struct {
+		workingHours      *remote.WorkingHours
+		updateStatus      string
+		statusSet         bool
+		currentStatus     string
+		newStatus         string
+		expectedStatusSet bool
+	}{
+		"Working hours end. Set status to away instead of DND.": {
+			workingHours:      dayOff,
+			updateStatus:      store.DNDStatusOption,
+			currentStatus:     model.StatusOnline,
+			newStatus:         model.StatusAway,
+			expectedStatusSet: true,
+		},
+		"Outside working hours, already offline. No status change.": {
+			workingHours:  dayOff,
+			updateStatus:  store.DNDStatusOption,
+			currentStatus: model.StatusOffline,
+		},
+		"Outside working hours, status set to online by the user. No status change.": {
+			workingHours:      dayOff,
+			updateStatus:      store.DNDStatusOption,
+			statusSet:         true,
+			currentStatus:     model.StatusOnline,
+			expectedStatusSet: true,
+		},
+		"Working hours start. Set status back to online.": {
+			workingHours:  allDay,
+			updateStatus:  store.NotSetStatusOption,
+			statusSet:     true,
+			currentStatus: model.StatusAway,
+			newStatus:     model.StatusOnline,
+		},
+		"Working hours start, status changed by the user. No status change.": {
+			workingHours:  allDay,
+			updateStatus:  store.NotSetStatusOption,
+			statusSet:     true,
+			currentStatus: model.StatusDnd,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			env, client := makeStatusSyncTestEnv(ctrl)
+			deps := env.Dependencies
+
+			c, r, papi, s := client.(*mock_remote.MockClient), env.Remote.(*mock_remote.MockRemote), deps.PluginAPI.(*mock_plugin_api.MockPluginAPI), deps.Store.(*mock_store.MockStore)
+			s.EXPECT().LoadUserIndex().Return(store.UserIndex{{MattermostUserID: "user_mm_id", RemoteID: "user_remote_id"}}, nil)
+			r.EXPECT().MakeSuperuserClient(gomock.Any()).Return(client, nil)
+
+			mockUser := &store.User{
+				MattermostUserID: "user_mm_id",
+				Remote:           &remote.User{ID: "user_remote_id", Mail: "user_email@example.com"},
+				Settings: store.Settings{
+					UpdateStatusFromOptions: tc.updateStatus,
+					WorkingHoursStatus:      store.AwayStatusOption,
+				},
+				MailboxSettings:          &remote.MailboxSettings{WorkingHours: *tc.workingHours},
+				MailboxSettingsUpdatedAt: now.Unix(),
+				IsWorkingHoursStatusSet:  tc.statusSet,
+			}
+			s.EXPECT().LoadUser("user_mm_id").Return(mockUser, nil)
+			c.EXPECT().DoBatchViewCalendarRequests(gomock.Any()).Return([]*remote.ViewCalendarResponse{
+				{Events: []*remote.Event{busyEvent}, RemoteUserID: "user_remote_id"},
+			}, nil)
+			papi.EXPECT().GetMattermostUserStatusesByIds([]string{"user_mm_id"}).Return([]*model.Status{{Status: tc.currentStatus, UserId: "user_mm_id"}}, nil)
+
+			if tc.newStatus == "" {
+				papi.EXPECT().UpdateMattermostUserStatus("user_mm_id", gomock.Any()).Times(0)
+			} else {
+				papi.EXPECT().UpdateMattermostUserStatus("user_mm_id", tc.newStatus).Return(nil, nil)
+			}
+			if tc.expectedStatusSet != tc.statusSet {
+				s.EXPECT().StoreUser(mockUser).Return(nil)
+			}
+			_, _, err := New(env, "").SyncAll()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedStatusSet, mockUser.IsWorkingHoursStatusSet)
+		})
+	}
+}
+
+func TestNoRemindersOutsideWorkingHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	env, _ := makeStatusSyncTestEnv(ctrl)
+	poster := env.Dependencies.Poster.(*mock_bot.MockPoster)
+	poster.EXPECT().DMWithAttachments(gomock.Any(), gomock.Any()).Times(0)
+
+	user := &store.User{
+		MattermostUserID: "user_mm_id",
+		Remote:           &remote.User{ID: "user_remote_id"},
+		Settings:         store.Settings{ReceiveReminders: true, WorkingHoursStatus: store.AwayStatusOption},
+	}
+	upcoming := &remote.Event{
+		ID:    "event_id",
+		Start: remote.NewDateTime(time.Now().Add(5*time.Minute).UTC(), "UTC"),
+		End:   remote.NewDateTime(time.Now().Add(time.Hour).UTC(), "UTC"),
+	}
+
+	New(env, "").(*mscalendar).deliverReminders(
+		[]*store.User{user},
+		[]*remote.ViewCalendarResponse{{RemoteUserID: "user_remote_id", Events: []*remote.Event{upcoming}}},
+		false,
+		map[string]bool{"user_mm_id": true},
+	)
+}
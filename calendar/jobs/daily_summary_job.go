@@ -26,11 +26,12 @@ func NewDailySummaryJob() RegisteredJob {
 // runDailySummaryJob delivers the daily calendar summary to all users who have their settings configured to receive it now
 func runDailySummaryJob(env engine.Env) {
 	env.Logger.Debugf("Daily summary job beginning")
+	m := engine.NewBackground(env, "")
 
-	err := engine.New(env, "").ProcessAllDailySummary(time.Now())
+	err := m.ProcessAllDailySummary(time.Now())
 	if err != nil {
 		env.Logger.Errorf("Error during daily summary job. err=%v", err)
 	}
 
-	env.Logger.Debugf("Daily summary job finished")
+	env.Logger.Debugf("Daily summary job finished.\nNumber of throttled requests:- %d", m.ThrottledRequests())
 }
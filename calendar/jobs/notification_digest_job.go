@@ -0,0 +1,34 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package jobs
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
+)
+
+// Unique id for the notification digest job
+const notificationDigestJobID = "notification_digest"
+
+// NewNotificationDigestJob creates a RegisteredJob with the parameters specific to the NotificationDigestJob
+func NewNotificationDigestJob() RegisteredJob {
+	return RegisteredJob{
+		id:       notificationDigestJobID,
+		interval: engine.NotificationDigestJobInterval,
+		work:     runNotificationDigestJob,
+	}
+}
+
+// runNotificationDigestJob posts the event notifications buffered for the users in digest mode, once their interval elapsed
+func runNotificationDigestJob(env engine.Env) {
+	env.Logger.Debugf("Notification digest job beginning")
+
+	err := engine.NewBackground(env, "").ProcessAllNotificationDigests(time.Now())
+	if err != nil {
+		env.Logger.Errorf("Error during notification digest job. err=%v", err)
+	}
+
+	env.Logger.Debugf("Notification digest job finished.")
+}
@@ -19,8 +19,11 @@ func NewRenewJob() RegisteredJob {
 	}
 }
 
-// runRenewJob calls renews the event subscription for each connected user
+// runRenewJob renews the notification certificate, then the event
+// subscription of each connected user.
 func runRenewJob(env engine.Env) {
+	engine.RenewNotificationCertificate(env, time.Now())
+
 	uindex, err := env.Store.LoadUserIndex()
 	if err != nil {
 		env.Logger.Errorf("Renew job failed to load user index. err=%v", err)
@@ -33,7 +36,7 @@ func runRenewJob(env engine.Env) {
 			continue
 		}
 
-		asUser := engine.New(env, u.MattermostUserID)
+		asUser := engine.NewBackground(env, u.MattermostUserID)
 
 		env.Logger.Debugf("Renewing for user: %s", u.MattermostUserID)
 		_, err = asUser.RenewMyEventSubscription()
@@ -20,6 +20,7 @@ import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/store/mock_store"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
 )
 
 // noopLogger is a bot.Logger that discards everything, so tests can focus on
@@ -39,9 +40,13 @@ func TestRunRenewJob(t *testing.T) {
 		connectedRemote = "connectedRemoteID"
 	)
 
+	renewed := &certificate.Certificate{ID: "renewed"}
+
 	tests := []struct {
-		name  string
-		setup func(*mock_store.MockStore, *mock_remote.MockRemote, *mock_plugin_api.MockPluginAPI)
+		name              string
+		richNotifications bool
+		setup             func(*mock_store.MockStore, *mock_remote.MockRemote, *mock_plugin_api.MockPluginAPI)
+		expectedCert      *certificate.Certificate
 	}{
 		{
 			name: "skips nil index entries and users without a connected remote account",
@@ -79,6 +84,16 @@ func TestRunRenewJob(t *testing.T) {
 				mockStore.EXPECT().LoadUserIndex().Return(nil, errors.New("load failed"))
 			},
 		},
+		{
+			name:              "renews the notification certificate",
+			richNotifications: true,
+			setup: func(mockStore *mock_store.MockStore, _ *mock_remote.MockRemote, _ *mock_plugin_api.MockPluginAPI) {
+				mockStore.EXPECT().LoadNotificationCertificate().Return(nil, store.ErrNotFound)
+				mockStore.EXPECT().StoreNotificationCertificate(nil, gomock.Any()).Return(renewed, nil)
+				mockStore.EXPECT().LoadUserIndex().Return(store.UserIndex{}, nil)
+			},
+			expectedCert: renewed,
+		},
 	}
 
 	for _, tc := range tests {
@@ -92,8 +107,11 @@ func TestRunRenewJob(t *testing.T) {
 
 			tc.setup(mockStore, mockRemote, mockPluginAPI)
 
+			conf := &config.Config{}
+			conf.Provider.Features.EventNotifications = true
+			conf.EnableRichNotifications = tc.richNotifications
 			env := engine.Env{
-				Config: &config.Config{},
+				Config: conf,
 				Dependencies: &engine.Dependencies{
 					Store:     mockStore,
 					Remote:    mockRemote,
@@ -105,6 +123,7 @@ func TestRunRenewJob(t *testing.T) {
 			assert.NotPanics(t, func() {
 				runRenewJob(env)
 			})
+			assert.Equal(t, tc.expectedCert, env.Config.NotificationCertificate)
 		})
 	}
 }
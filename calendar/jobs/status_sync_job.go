@@ -21,10 +21,10 @@ func NewStatusSyncJob() RegisteredJob {
 func runSyncJob(env engine.Env) {
 	env.Logger.Debugf("User status sync job beginning")
 
-	_, syncJobSummary, err := engine.New(env, "").SyncAll()
+	_, syncJobSummary, err := engine.NewBackground(env, "").SyncAll()
 	if err != nil {
 		env.Logger.Errorf("Error during user status sync job. err=%v", err)
 	}
 
-	env.Logger.Debugf("User status sync job finished.\nSummary\nNumber of users processed:- %d\nNumber of users had their status changed:- %d\nNumber of users had errors:- %d", syncJobSummary.NumberOfUsersProcessed, syncJobSummary.NumberOfUsersStatusChanged, syncJobSummary.NumberOfUsersFailedStatusChanged)
+	env.Logger.Debugf("User status sync job finished.\nSummary\nNumber of users processed:- %d\nNumber of users had their status changed:- %d\nNumber of users had errors:- %d\nNumber of throttled requests:- %d", syncJobSummary.NumberOfUsersProcessed, syncJobSummary.NumberOfUsersStatusChanged, syncJobSummary.NumberOfUsersFailedStatusChanged, syncJobSummary.NumberOfThrottledRequests)
 }
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
 	pluginapiclient "github.com/mattermost/mattermost/server/public/pluginapi"
 
@@ -132,6 +133,11 @@ func (p *Plugin) OnDeactivate() error {
 	}
 
 	e := p.getEnv()
+	if e.notificationProcessor != nil {
+		// Queued notifications are processed by the other nodes, or after
+		// the next activation.
+		e.notificationProcessor.Quit()
+	}
 	if e.jobManager != nil {
 		if err := e.jobManager.Close(); err != nil {
 			p.env.Logger.Warnf("OnDeactivate: Failed to close job manager. err=%v", err)
@@ -210,6 +216,15 @@ func (p *Plugin) OnConfigurationChange() (err error) {
 			p.reEncryptUserData(e, previousEncryptionKey)
 		}
 
+		e.Config.NotificationCertificate = nil
+		if e.Provider.Features.EventNotifications && stored.EnableRichNotifications {
+			cert, certErr := engine.LoadNotificationCertificate(e.Dependencies.Store, time.Now())
+			if certErr != nil {
+				p.API.LogWarn("Failed to load the notification certificate, notifications will not include resource data", "error", certErr.Error())
+			}
+			e.Config.NotificationCertificate = cert
+		}
+
 		e.Dependencies.SettingsPanel = engine.NewSettingsPanel(
 			e.bot,
 			e.Dependencies.Store,
@@ -243,6 +258,7 @@ func (p *Plugin) OnConfigurationChange() (err error) {
 			e.jobManager = jobs.NewJobManager(p.API, e.Env)
 			e.jobManager.AddJob(jobs.NewStatusSyncJob())
 			e.jobManager.AddJob(jobs.NewDailySummaryJob())
+			e.jobManager.AddJob(jobs.NewNotificationDigestJob())
 			e.jobManager.AddJob(jobs.NewRenewJob())
 		}
 	})
@@ -15,10 +15,23 @@ type Calendar struct {
 	CalendarView []Event `json:"calendarView,omitempty"`
 }
 
+// SharedCalendar is a calendar of another user, shared with or delegated to
+// the user.
+type SharedCalendar struct {
+	ID        string
+	Name      string
+	OwnerName string
+	OwnerMail string
+	CanEdit   bool
+}
+
+// ViewCalendarParams selects the events of a user between two times, in
+// their default calendar unless CalendarID is set.
 type ViewCalendarParams struct {
 	StartTime    time.Time
 	EndTime      time.Time
 	RemoteUserID string
+	CalendarID   string
 }
 
 type ViewCalendarResponse struct {
@@ -26,3 +39,33 @@ type ViewCalendarResponse struct {
 	RemoteUserID string
 	Events       []*Event
 }
+
+// CalendarViewDeltaParams selects the changes to the default calendar view
+// of a user since the delta query that returned DeltaLink, or the whole view
+// between StartTime and EndTime when DeltaLink is empty.
+type CalendarViewDeltaParams struct {
+	StartTime    time.Time
+	EndTime      time.Time
+	RemoteUserID string
+	DeltaLink    string
+}
+
+// CalendarViewDeltaResponse holds the delta of a user, or the error reading
+// it. Expired is set when the delta link is no longer valid and the whole
+// view must be read again.
+type CalendarViewDeltaResponse struct {
+	Error        *APIError
+	Delta        *EventsDelta
+	RemoteUserID string
+	Expired      bool
+}
+
+// EventsDelta holds the changes to a calendar view since the previous delta
+// query. When Reset is set, Events holds the whole view and replaces any
+// previously known events.
+type EventsDelta struct {
+	DeltaLink  string
+	Events     []*Event
+	RemovedIDs []string
+	Reset      bool
+}
@@ -13,6 +13,7 @@ type Client interface {
 	Calendars
 	Events
 	Subscriptions
+	Rooms
 	Utils
 	Unsupported
 }
@@ -24,27 +25,42 @@ type Core interface {
 type Calendars interface {
 	GetEvent(remoteUserID, eventID string) (*Event, error)
 	GetCalendars(remoteUserID string) ([]*Calendar, error)
+	GetSharedCalendars(remoteUserID string) ([]*SharedCalendar, error)
 	GetDefaultCalendarView(remoteUserID string, startTime, endTime time.Time) ([]*Event, error)
+	GetCalendarView(remoteUserID, calendarID string, startTime, endTime time.Time) ([]*Event, error)
+	DoBatchCalendarViewDeltaRequests([]*CalendarViewDeltaParams) ([]*CalendarViewDeltaResponse, error)
 	DoBatchViewCalendarRequests([]*ViewCalendarParams) ([]*ViewCalendarResponse, error)
 	GetMailboxSettings(remoteUserID string) (*MailboxSettings, error)
+	GetSchedule(requests []*ScheduleUserInfo, startTime, endTime *DateTime, availabilityViewInterval int) ([]*ScheduleInformation, error)
+	FindMeetingTimes(meetingParams *FindMeetingTimesParameters) (*MeetingTimeSuggestionResults, error)
 }
 
 type Events interface {
-	CreateEvent(calendarEvent *Event) (*Event, error)
-	AcceptEvent(remoteUserID, eventID string) error
-	DeclineEvent(remoteUserID, eventID string) error
-	TentativelyAcceptEvent(eventID string) error
+	CreateEvent(calendarID string, calendarEvent *Event) (*Event, error)
+	UpdateEvent(remoteUserID, eventID string, calendarEvent *Event) (*Event, error)
+	CancelEvent(remoteUserID, eventID, comment string) error
+	DeleteEvent(remoteUserID, eventID string) error
+	AcceptEvent(remoteUserID, eventID string, response *EventResponse) error
+	DeclineEvent(remoteUserID, eventID string, response *EventResponse) error
+	TentativelyAcceptEvent(remoteUserID, eventID string, response *EventResponse) error
 	GetEventsBetweenDates(remoteUserID string, start, end time.Time) ([]*Event, error)
 }
 
 type Subscriptions interface {
-	CreateMySubscription(notificationURL, remoteUserID string) (*Subscription, error)
+	CreateMySubscription(notificationURL, lifecycleNotificationURL, remoteUserID string) (*Subscription, error)
 	DeleteSubscription(sub *Subscription) error
 	GetNotificationData(*Notification) (*Notification, error)
 	ListSubscriptions() ([]*Subscription, error)
 	RenewSubscription(notificationURL, remoteUserID string, sub *Subscription) (*Subscription, error)
 }
 
+type Rooms interface {
+	GetRoomLists() ([]*RoomList, error)
+	// GetRooms lists the rooms of the room list with the given address, or
+	// all the rooms of the organization when it is empty.
+	GetRooms(roomListAddress string) ([]*Room, error)
+}
+
 type Utils interface {
 	GetSuperuserToken() (string, error)
 	CallFormPost(method, path string, in url.Values, out interface{}) (responseData []byte, err error)
@@ -54,5 +70,4 @@ type Utils interface {
 type Unsupported interface {
 	CreateCalendar(calendar *Calendar) (*Calendar, error)
 	DeleteCalendar(calendarID string) error
-	FindMeetingTimes(meetingParams *FindMeetingTimesParameters) (*MeetingTimeSuggestionResults, error)
 }
@@ -10,6 +10,28 @@ const (
 	EventResponseStatusDeclined    = "declined"
 )
 
+// Online meeting providers, as named by Microsoft.
+const (
+	OnlineMeetingProviderTeams            = "teamsForBusiness"
+	OnlineMeetingProviderSkypeForBusiness = "skypeForBusiness"
+	OnlineMeetingProviderSkypeForConsumer = "skypeForConsumer"
+)
+
+// Event sensitivities, as named by Microsoft.
+const (
+	SensitivityNormal       = "normal"
+	SensitivityPersonal     = "personal"
+	SensitivityPrivate      = "private"
+	SensitivityConfidential = "confidential"
+)
+
+// Event importances, as named by Microsoft.
+const (
+	ImportanceLow    = "low"
+	ImportanceNormal = "normal"
+	ImportanceHigh   = "high"
+)
+
 type Event struct {
 	Start                      *DateTime            `json:"start,omitempty"`
 	Location                   *Location            `json:"location,omitempty"`
@@ -19,21 +41,33 @@ type Event struct {
 	Organizer                  *Attendee            `json:"organizer,omitempty"`
 	Body                       *ItemBody            `json:"Body,omitempty"`
 	ResponseStatus             *EventResponseStatus `json:"responseStatus,omitempty"`
+	Recurrence                 *PatternedRecurrence `json:"recurrence,omitempty"`
+	Type                       string               `json:"type,omitempty"`
+	SeriesMasterID             string               `json:"seriesMasterId,omitempty"`
 	Importance                 string               `json:"importance,omitempty"`
 	ICalUID                    string               `json:"iCalUId,omitempty"`
 	Subject                    string               `json:"subject,omitempty"`
 	BodyPreview                string               `json:"bodyPreview,omitempty"`
 	ShowAs                     string               `json:"showAs,omitempty"`
 	Weblink                    string               `json:"weblink,omitempty"`
+	OnlineMeetingProvider      string               `json:"onlineMeetingProvider,omitempty"`
+	Sensitivity                string               `json:"sensitivity,omitempty"`
 	ID                         string               `json:"id,omitempty"`
 	Attendees                  []*Attendee          `json:"attendees,omitempty"`
 	ReminderMinutesBeforeStart int                  `json:"reminderMinutesBeforeStart,omitempty"`
 	IsOrganizer                bool                 `json:"isOrganizer,omitempty"`
 	IsCancelled                bool                 `json:"isCancelled,omitempty"`
 	IsAllDay                   bool                 `json:"isAllDay,omitempty"`
+	IsOnlineMeeting            bool                 `json:"isOnlineMeeting,omitempty"`
 	ResponseRequested          bool                 `json:"responseRequested,omitempty"`
 }
 
+// IsPrivate tells whether the details of the event must only be shown to
+// the owner of the calendar.
+func (e *Event) IsPrivate() bool {
+	return e.Sensitivity == SensitivityPrivate || e.Sensitivity == SensitivityConfidential
+}
+
 type ItemBody struct {
 	Content     string `json:"content,omitempty"`
 	ContentType string `json:"contentType,omitempty"`
@@ -76,8 +110,18 @@ type OnlineMeetingInfo struct {
 }
 
 type Attendee struct {
-	RemoteID     string               `json:"remoteId,omitempty"`
-	Status       *EventResponseStatus `json:"status,omitempty"`
-	EmailAddress *EmailAddress        `json:"emailAddress,omitempty"`
-	Type         string               `json:"type,omitempty"`
+	RemoteID        string               `json:"remoteId,omitempty"`
+	Status          *EventResponseStatus `json:"status,omitempty"`
+	EmailAddress    *EmailAddress        `json:"emailAddress,omitempty"`
+	ProposedNewTime *TimeSlot            `json:"proposedNewTime,omitempty"`
+	Type            string               `json:"type,omitempty"`
+}
+
+// EventResponse holds what is sent along with a response to an invitation.
+// A new time can only be proposed when declining or tentatively accepting,
+// with the response sent to the organizer.
+type EventResponse struct {
+	ProposedNewTime *TimeSlot `json:"proposedNewTime,omitempty"`
+	Comment         string    `json:"comment,omitempty"`
+	SendResponse    bool      `json:"sendResponse"`
 }
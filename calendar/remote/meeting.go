@@ -36,7 +36,7 @@ type AttendeeAvailability struct {
 }
 
 type MeetingTimeSuggestionResults struct {
-	EmptySuggestionReason  string                   `json:"emptySuggestionReason"`
+	EmptySuggestionReason  string                   `json:"emptySuggestionsReason"`
 	MeetingTimeSuggestions []*MeetingTimeSuggestion `json:"meetingTimeSuggestions"`
 }
 
@@ -37,17 +37,17 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 }
 
 // AcceptEvent mocks base method.
-func (m *MockClient) AcceptEvent(arg0, arg1 string) error {
+func (m *MockClient) AcceptEvent(arg0, arg1 string, arg2 *remote.EventResponse) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AcceptEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "AcceptEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AcceptEvent indicates an expected call of AcceptEvent.
-func (mr *MockClientMockRecorder) AcceptEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) AcceptEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEvent", reflect.TypeOf((*MockClient)(nil).AcceptEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptEvent", reflect.TypeOf((*MockClient)(nil).AcceptEvent), arg0, arg1, arg2)
 }
 
 // CallFormPost mocks base method.
@@ -80,6 +80,20 @@ func (mr *MockClientMockRecorder) CallJSON(arg0, arg1, arg2, arg3 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallJSON", reflect.TypeOf((*MockClient)(nil).CallJSON), arg0, arg1, arg2, arg3)
 }
 
+// CancelEvent mocks base method.
+func (m *MockClient) CancelEvent(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelEvent indicates an expected call of CancelEvent.
+func (mr *MockClientMockRecorder) CancelEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelEvent", reflect.TypeOf((*MockClient)(nil).CancelEvent), arg0, arg1, arg2)
+}
+
 // CreateCalendar mocks base method.
 func (m *MockClient) CreateCalendar(arg0 *remote.Calendar) (*remote.Calendar, error) {
 	m.ctrl.T.Helper()
@@ -96,47 +110,47 @@ func (mr *MockClientMockRecorder) CreateCalendar(arg0 interface{}) *gomock.Call
 }
 
 // CreateEvent mocks base method.
-func (m *MockClient) CreateEvent(arg0 *remote.Event) (*remote.Event, error) {
+func (m *MockClient) CreateEvent(arg0 string, arg1 *remote.Event) (*remote.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEvent", arg0)
+	ret := m.ctrl.Call(m, "CreateEvent", arg0, arg1)
 	ret0, _ := ret[0].(*remote.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateEvent indicates an expected call of CreateEvent.
-func (mr *MockClientMockRecorder) CreateEvent(arg0 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateEvent(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockClient)(nil).CreateEvent), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockClient)(nil).CreateEvent), arg0, arg1)
 }
 
 // CreateMySubscription mocks base method.
-func (m *MockClient) CreateMySubscription(arg0, arg1 string) (*remote.Subscription, error) {
+func (m *MockClient) CreateMySubscription(arg0, arg1, arg2 string) (*remote.Subscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMySubscription", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateMySubscription", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*remote.Subscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMySubscription indicates an expected call of CreateMySubscription.
-func (mr *MockClientMockRecorder) CreateMySubscription(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) CreateMySubscription(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMySubscription", reflect.TypeOf((*MockClient)(nil).CreateMySubscription), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMySubscription", reflect.TypeOf((*MockClient)(nil).CreateMySubscription), arg0, arg1, arg2)
 }
 
 // DeclineEvent mocks base method.
-func (m *MockClient) DeclineEvent(arg0, arg1 string) error {
+func (m *MockClient) DeclineEvent(arg0, arg1 string, arg2 *remote.EventResponse) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeclineEvent", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeclineEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeclineEvent indicates an expected call of DeclineEvent.
-func (mr *MockClientMockRecorder) DeclineEvent(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) DeclineEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEvent", reflect.TypeOf((*MockClient)(nil).DeclineEvent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineEvent", reflect.TypeOf((*MockClient)(nil).DeclineEvent), arg0, arg1, arg2)
 }
 
 // DeleteCalendar mocks base method.
@@ -153,6 +167,20 @@ func (mr *MockClientMockRecorder) DeleteCalendar(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCalendar", reflect.TypeOf((*MockClient)(nil).DeleteCalendar), arg0)
 }
 
+// DeleteEvent mocks base method.
+func (m *MockClient) DeleteEvent(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvent indicates an expected call of DeleteEvent.
+func (mr *MockClientMockRecorder) DeleteEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockClient)(nil).DeleteEvent), arg0, arg1)
+}
+
 // DeleteSubscription mocks base method.
 func (m *MockClient) DeleteSubscription(arg0 *remote.Subscription) error {
 	m.ctrl.T.Helper()
@@ -167,6 +195,21 @@ func (mr *MockClientMockRecorder) DeleteSubscription(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscription", reflect.TypeOf((*MockClient)(nil).DeleteSubscription), arg0)
 }
 
+// DoBatchCalendarViewDeltaRequests mocks base method.
+func (m *MockClient) DoBatchCalendarViewDeltaRequests(arg0 []*remote.CalendarViewDeltaParams) ([]*remote.CalendarViewDeltaResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DoBatchCalendarViewDeltaRequests", arg0)
+	ret0, _ := ret[0].([]*remote.CalendarViewDeltaResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DoBatchCalendarViewDeltaRequests indicates an expected call of DoBatchCalendarViewDeltaRequests.
+func (mr *MockClientMockRecorder) DoBatchCalendarViewDeltaRequests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DoBatchCalendarViewDeltaRequests", reflect.TypeOf((*MockClient)(nil).DoBatchCalendarViewDeltaRequests), arg0)
+}
+
 // DoBatchViewCalendarRequests mocks base method.
 func (m *MockClient) DoBatchViewCalendarRequests(arg0 []*remote.ViewCalendarParams) ([]*remote.ViewCalendarResponse, error) {
 	m.ctrl.T.Helper()
@@ -197,6 +240,21 @@ func (mr *MockClientMockRecorder) FindMeetingTimes(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMeetingTimes", reflect.TypeOf((*MockClient)(nil).FindMeetingTimes), arg0)
 }
 
+// GetCalendarView mocks base method.
+func (m *MockClient) GetCalendarView(arg0, arg1 string, arg2, arg3 time.Time) ([]*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarView", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarView indicates an expected call of GetCalendarView.
+func (mr *MockClientMockRecorder) GetCalendarView(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarView", reflect.TypeOf((*MockClient)(nil).GetCalendarView), arg0, arg1, arg2, arg3)
+}
+
 // GetCalendars mocks base method.
 func (m *MockClient) GetCalendars(arg0 string) ([]*remote.Calendar, error) {
 	m.ctrl.T.Helper()
@@ -302,6 +360,66 @@ func (mr *MockClientMockRecorder) GetNotificationData(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationData", reflect.TypeOf((*MockClient)(nil).GetNotificationData), arg0)
 }
 
+// GetRoomLists mocks base method.
+func (m *MockClient) GetRoomLists() ([]*remote.RoomList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoomLists")
+	ret0, _ := ret[0].([]*remote.RoomList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoomLists indicates an expected call of GetRoomLists.
+func (mr *MockClientMockRecorder) GetRoomLists() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoomLists", reflect.TypeOf((*MockClient)(nil).GetRoomLists))
+}
+
+// GetRooms mocks base method.
+func (m *MockClient) GetRooms(arg0 string) ([]*remote.Room, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRooms", arg0)
+	ret0, _ := ret[0].([]*remote.Room)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRooms indicates an expected call of GetRooms.
+func (mr *MockClientMockRecorder) GetRooms(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRooms", reflect.TypeOf((*MockClient)(nil).GetRooms), arg0)
+}
+
+// GetSchedule mocks base method.
+func (m *MockClient) GetSchedule(arg0 []*remote.ScheduleUserInfo, arg1, arg2 *remote.DateTime, arg3 int) ([]*remote.ScheduleInformation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchedule", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*remote.ScheduleInformation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchedule indicates an expected call of GetSchedule.
+func (mr *MockClientMockRecorder) GetSchedule(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchedule", reflect.TypeOf((*MockClient)(nil).GetSchedule), arg0, arg1, arg2, arg3)
+}
+
+// GetSharedCalendars mocks base method.
+func (m *MockClient) GetSharedCalendars(arg0 string) ([]*remote.SharedCalendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedCalendars", arg0)
+	ret0, _ := ret[0].([]*remote.SharedCalendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedCalendars indicates an expected call of GetSharedCalendars.
+func (mr *MockClientMockRecorder) GetSharedCalendars(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedCalendars", reflect.TypeOf((*MockClient)(nil).GetSharedCalendars), arg0)
+}
+
 // GetSuperuserToken mocks base method.
 func (m *MockClient) GetSuperuserToken() (string, error) {
 	m.ctrl.T.Helper()
@@ -348,15 +466,30 @@ func (mr *MockClientMockRecorder) RenewSubscription(arg0, arg1, arg2 interface{}
 }
 
 // TentativelyAcceptEvent mocks base method.
-func (m *MockClient) TentativelyAcceptEvent(arg0 string) error {
+func (m *MockClient) TentativelyAcceptEvent(arg0, arg1 string, arg2 *remote.EventResponse) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TentativelyAcceptEvent", arg0)
+	ret := m.ctrl.Call(m, "TentativelyAcceptEvent", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TentativelyAcceptEvent indicates an expected call of TentativelyAcceptEvent.
-func (mr *MockClientMockRecorder) TentativelyAcceptEvent(arg0 interface{}) *gomock.Call {
+func (mr *MockClientMockRecorder) TentativelyAcceptEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TentativelyAcceptEvent", reflect.TypeOf((*MockClient)(nil).TentativelyAcceptEvent), arg0, arg1, arg2)
+}
+
+// UpdateEvent mocks base method.
+func (m *MockClient) UpdateEvent(arg0, arg1 string, arg2 *remote.Event) (*remote.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*remote.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockClientMockRecorder) UpdateEvent(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TentativelyAcceptEvent", reflect.TypeOf((*MockClient)(nil).TentativelyAcceptEvent), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockClient)(nil).UpdateEvent), arg0, arg1, arg2)
 }
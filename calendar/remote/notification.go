@@ -3,6 +3,23 @@
 
 package remote
 
+import (
+	"encoding/json"
+)
+
+// Lifecycle events sent about a subscription rather than about a change to
+// its resource.
+const (
+	LifecycleEventReauthorizationRequired = "reauthorizationRequired"
+	LifecycleEventSubscriptionRemoved     = "subscriptionRemoved"
+	LifecycleEventMissed                  = "missed"
+)
+
+// ChangeTypeDeleted is the change type of the notification of a deleted
+// event. The remote cannot return its data anymore, only the ID of Event is
+// set.
+const ChangeTypeDeleted = "deleted"
+
 type Notification struct {
 	Webhook interface{}
 
@@ -18,11 +35,15 @@ type Notification struct {
 	// Notification type
 	ChangeType string
 
+	// Set instead of ChangeType for a lifecycle notification. The handler is
+	// to reauthorize, recreate or resync the subscription.
+	LifecycleEvent string
+
 	// The (remote) subscription ID the notification is for
 	SubscriptionID string
 
-	// Remote-specific data: full raw JSON of the webhook, and the decoded
-	// backend-specific struct.
+	// Remote-specific data: raw JSON of the webhook entry the notification
+	// was built from, and the decoded backend-specific struct.
 	WebhookRawData []byte
 
 	// Set if subscription renewal is recommended. The date/time logic is
@@ -35,3 +56,14 @@ type Notification struct {
 	// credentials.
 	IsBare bool
 }
+
+// DecodeWebhook decodes the webhook entry the notification was built from
+// into wh, a pointer to the backend-specific struct. Webhook is only a
+// generic value once the notification was read back from the queue.
+func (n *Notification) DecodeWebhook(wh interface{}) error {
+	data, err := json.Marshal(n.Webhook)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, wh)
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package remote
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationDecodeWebhook(t *testing.T) {
+	type webhook struct {
+		ChangeType     string `json:"changeType"`
+		SubscriptionID string `json:"subscriptionId"`
+	}
+	orig := &Notification{Webhook: &webhook{ChangeType: "updated", SubscriptionID: "sub_id"}}
+
+	wh := &webhook{}
+	require.NoError(t, orig.DecodeWebhook(wh))
+	require.Equal(t, orig.Webhook, wh)
+
+	// Read back from the queue, the webhook is a map
+	data, err := json.Marshal(orig)
+	require.NoError(t, err)
+	queued := &Notification{}
+	require.NoError(t, json.Unmarshal(data, queued))
+	require.IsType(t, map[string]interface{}{}, queued.Webhook)
+
+	wh = &webhook{}
+	require.NoError(t, queued.DecodeWebhook(wh))
+	require.Equal(t, orig.Webhook, wh)
+}
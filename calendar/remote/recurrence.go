@@ -0,0 +1,68 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package remote
+
+const (
+	EventTypeSingleInstance = "singleInstance"
+	EventTypeOccurrence     = "occurrence"
+	EventTypeException      = "exception"
+	EventTypeSeriesMaster   = "seriesMaster"
+)
+
+const (
+	RecurrencePatternDaily           = "daily"
+	RecurrencePatternWeekly          = "weekly"
+	RecurrencePatternAbsoluteMonthly = "absoluteMonthly"
+	RecurrencePatternRelativeMonthly = "relativeMonthly"
+	RecurrencePatternAbsoluteYearly  = "absoluteYearly"
+	RecurrencePatternRelativeYearly  = "relativeYearly"
+)
+
+const (
+	RecurrenceRangeEndDate  = "endDate"
+	RecurrenceRangeNoEnd    = "noEnd"
+	RecurrenceRangeNumbered = "numbered"
+)
+
+// WeekIndexes are the values of RecurrencePattern.Index, in order.
+var WeekIndexes = []string{"first", "second", "third", "fourth", "last"}
+
+// PatternedRecurrence is the recurrence of a series master event, as modeled
+// by the MS Graph API.
+type PatternedRecurrence struct {
+	Pattern *RecurrencePattern `json:"pattern,omitempty"`
+	Range   *RecurrenceRange   `json:"range,omitempty"`
+}
+
+// RecurrencePattern describes how often the event repeats. DaysOfWeek holds
+// lowercase English day names, such as "monday".
+type RecurrencePattern struct {
+	Type           string   `json:"type,omitempty"`
+	Interval       int      `json:"interval,omitempty"`
+	Month          int      `json:"month,omitempty"`
+	DayOfMonth     int      `json:"dayOfMonth,omitempty"`
+	DaysOfWeek     []string `json:"daysOfWeek,omitempty"`
+	FirstDayOfWeek string   `json:"firstDayOfWeek,omitempty"`
+	Index          string   `json:"index,omitempty"`
+}
+
+// RecurrenceRange describes how long the event repeats. Dates are formatted
+// as "2006-01-02".
+type RecurrenceRange struct {
+	Type                string `json:"type,omitempty"`
+	StartDate           string `json:"startDate,omitempty"`
+	EndDate             string `json:"endDate,omitempty"`
+	RecurrenceTimeZone  string `json:"recurrenceTimeZone,omitempty"`
+	NumberOfOccurrences int    `json:"numberOfOccurrences,omitempty"`
+}
+
+// IsRecurring tells whether the event is a series master, or an occurrence or
+// exception of one.
+func (e *Event) IsRecurring() bool {
+	switch e.Type {
+	case EventTypeOccurrence, EventTypeException, EventTypeSeriesMaster:
+		return true
+	}
+	return e.Recurrence != nil || e.SeriesMasterID != ""
+}
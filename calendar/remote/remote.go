@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sync/atomic"
 
 	"golang.org/x/oauth2"
 
@@ -17,16 +18,88 @@ import (
 var (
 	ErrSuperUserClientNotSupported = errors.New("superuser client is not supported")
 	ErrNotImplemented              = errors.New("not implemented")
+	ErrThrottled                   = errors.New("the calendar service is busy, please try again later")
 )
 
 type Remote interface {
 	MakeUserClient(context.Context, *oauth2.Token, string, bot.Poster, UserTokenHelpers) (Client, error)
 	MakeSuperuserClient(ctx context.Context) (Client, error)
 	NewOAuth2Config() *oauth2.Config
+	// HandleWebhook returns the notifications of a webhook request, for the
+	// caller to authenticate and answer. It answers the request itself and
+	// returns nil when there are none to process, e.g. for endpoint validation
+	// or invalid requests.
 	HandleWebhook(http.ResponseWriter, *http.Request) []*Notification
 	CheckConfiguration(configuration config.StoredConfig) error
 }
 
+// CredentialsConnector is implemented by remotes whose users connect by
+// passing credentials to the connect command instead of going through OAuth2.
+type CredentialsConnector interface {
+	// NewTokenFromCredentials verifies the connect command arguments and
+	// returns the token to store for the user.
+	NewTokenFromCredentials(ctx context.Context, args []string) (*oauth2.Token, error)
+}
+
+// UserClientRequirer is implemented by remotes that can only read the
+// calendars of some users with the users' own client, e.g. users connected
+// to a calendar feed instead of the provider. Jobs fetch the calendars of
+// these users individually even when a superuser client is available.
+type UserClientRequirer interface {
+	RequiresUserClient(token *oauth2.Token) bool
+}
+
+// OAuth2HTTPClientProvider is implemented by remotes whose OAuth2 token
+// endpoint is reached with a dedicated HTTP client, e.g. one answering
+// in-process. The default client is used otherwise.
+type OAuth2HTTPClientProvider interface {
+	OAuth2HTTPClient() *http.Client
+}
+
+// OAuth2Context returns the context to exchange OAuth2 codes and tokens of
+// the remote in.
+func OAuth2Context(ctx context.Context, r Remote) context.Context {
+	if p, ok := r.(OAuth2HTTPClientProvider); ok {
+		if httpClient := p.OAuth2HTTPClient(); httpClient != nil {
+			return context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+		}
+	}
+	return ctx
+}
+
+type backgroundKey struct{}
+
+// BackgroundContext marks the requests made with the context as made by a
+// background job. They may wait longer for a throttled provider than the
+// requests of a user, who is answered with ErrThrottled instead.
+func BackgroundContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundKey{}, true)
+}
+
+// IsBackground tells whether the requests made with the context are made by
+// a background job.
+func IsBackground(ctx context.Context) bool {
+	background, _ := ctx.Value(backgroundKey{}).(bool)
+	return background
+}
+
+type throttleCounterKey struct{}
+
+// WithThrottleCounter makes the remotes count the responses throttled by the
+// provider for the requests made with the context in counter, so that a job
+// can report its own throttled requests in its summary.
+func WithThrottleCounter(ctx context.Context, counter *atomic.Int64) context.Context {
+	return context.WithValue(ctx, throttleCounterKey{}, counter)
+}
+
+// CountThrottled counts a throttled response in the counter of the context,
+// if any.
+func CountThrottled(ctx context.Context) {
+	if counter, ok := ctx.Value(throttleCounterKey{}).(*atomic.Int64); ok && counter != nil {
+		counter.Add(1)
+	}
+}
+
 var Makers = map[string]func(*config.Config, bot.Logger) Remote{}
 
 type APIError struct {
@@ -0,0 +1,26 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package remote
+
+// AttendeeTypeResource is the type of the attendees booking a room or
+// equipment rather than inviting a person.
+const AttendeeTypeResource = "resource"
+
+// Room is a meeting room, booked by inviting its mailbox to an event as a
+// resource attendee.
+type Room struct {
+	ID           string `json:"id,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Building     string `json:"building,omitempty"`
+	FloorLabel   string `json:"floorLabel,omitempty"`
+	Capacity     int    `json:"capacity,omitempty"`
+}
+
+// RoomList groups the rooms of a building or a site.
+type RoomList struct {
+	ID           string `json:"id,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
@@ -3,6 +3,8 @@
 
 package remote
 
+import "time"
+
 const (
 	AvailabilityViewFree             = '0'
 	AvailabilityViewTentative        = '1'
@@ -54,3 +56,85 @@ type ScheduleItem struct {
 	Location  string
 	IsPrivate bool
 }
+
+// showAsAvailability maps the ShowAs value of an event to its availability
+// view code, and the busiest status wins when events overlap.
+var showAsAvailability = []struct {
+	showAs string
+	code   byte
+}{
+	{ScheduleStatusOof, AvailabilityViewOutOfOffice},
+	{ScheduleStatusBusy, AvailabilityViewBusy},
+	{ScheduleStatusTentative, AvailabilityViewTentative},
+	{ScheduleStatusWorkingElsewhere, AvailabilityViewWorkingElsewhere},
+}
+
+func availabilityRank(showAs string) int {
+	for i, a := range showAsAvailability {
+		if a.showAs == showAs {
+			return len(showAsAvailability) - i
+		}
+	}
+	return 0
+}
+
+// NewScheduleInformation builds the schedule of a user from their events, for
+// remotes without a free/busy API. The availability view has one code per
+// interval of availabilityViewInterval minutes from start to end.
+func NewScheduleInformation(scheduleID string, events []*Event, start, end time.Time, availabilityViewInterval int) *ScheduleInformation {
+	interval := time.Duration(availabilityViewInterval) * time.Minute
+	if interval <= 0 {
+		interval = end.Sub(start)
+	}
+
+	ranks := []int{}
+	for t := start; t.Before(end); t = t.Add(interval) {
+		ranks = append(ranks, 0)
+	}
+
+	info := &ScheduleInformation{
+		ScheduleID:    scheduleID,
+		ScheduleItems: []*ScheduleItem{},
+	}
+	for _, e := range events {
+		rank := availabilityRank(e.ShowAs)
+		if e.IsCancelled || rank == 0 {
+			continue
+		}
+		eventStart, eventEnd := e.Start.Time(), e.End.Time()
+		if !eventStart.Before(end) || !eventEnd.After(start) {
+			continue
+		}
+
+		info.ScheduleItems = append(info.ScheduleItems, &ScheduleItem{
+			Start:    e.Start,
+			End:      e.End,
+			Status:   e.ShowAs,
+			Subject:  e.Subject,
+			Location: locationName(e.Location),
+		})
+		for i := range ranks {
+			slotStart := start.Add(time.Duration(i) * interval)
+			if eventStart.Before(slotStart.Add(interval)) && eventEnd.After(slotStart) && rank > ranks[i] {
+				ranks[i] = rank
+			}
+		}
+	}
+
+	view := make([]byte, len(ranks))
+	for i, rank := range ranks {
+		view[i] = AvailabilityViewFree
+		if rank > 0 {
+			view[i] = showAsAvailability[len(showAsAvailability)-rank].code
+		}
+	}
+	info.AvailabilityView = AvailabilityView(view)
+	return info
+}
+
+func locationName(l *Location) string {
+	if l == nil {
+		return ""
+	}
+	return l.DisplayName
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package remote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScheduleInformation(t *testing.T) {
+	start := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+	event := func(showAs string, fromMinutes, toMinutes int) *Event {
+		return &Event{
+			Subject: showAs,
+			ShowAs:  showAs,
+			Start:   NewDateTime(start.Add(time.Duration(fromMinutes)*time.Minute), "UTC"),
+			End:     NewDateTime(start.Add(time.Duration(toMinutes)*time.Minute), "UTC"),
+		}
+	}
+
+	for _, tc := range []struct {
+		name          string
+		events        []*Event
+		expectedView  AvailabilityView
+		expectedItems int
+	}{
+		{
+			name:         "no events",
+			expectedView: "000000",
+		},
+		{
+			name:          "partial slots are busy",
+			events:        []*Event{event(ScheduleStatusBusy, 40, 70)},
+			expectedView:  "022000",
+			expectedItems: 1,
+		},
+		{
+			name: "busiest status wins",
+			events: []*Event{
+				event(ScheduleStatusTentative, 0, 90),
+				event(ScheduleStatusBusy, 30, 60),
+				event(ScheduleStatusWorkingElsewhere, 120, 180),
+				event(ScheduleStatusOof, 150, 240),
+			},
+			expectedView:  "121043",
+			expectedItems: 4,
+		},
+		{
+			name: "free and cancelled events are skipped",
+			events: []*Event{
+				event(ScheduleStatusFree, 0, 60),
+				{ShowAs: ScheduleStatusBusy, IsCancelled: true, Start: NewDateTime(start, "UTC"), End: NewDateTime(end, "UTC")},
+			},
+			expectedView: "000000",
+		},
+		{
+			name:         "events outside the window are skipped",
+			events:       []*Event{event(ScheduleStatusBusy, -60, 0), event(ScheduleStatusBusy, 180, 240)},
+			expectedView: "000000",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			info := NewScheduleInformation("user@example.com", tc.events, start, end, 30)
+			require.Equal(t, "user@example.com", info.ScheduleID)
+			require.Equal(t, tc.expectedView, info.AvailabilityView)
+			require.Len(t, info.ScheduleItems, tc.expectedItems)
+		})
+	}
+}
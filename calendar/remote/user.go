@@ -3,7 +3,11 @@
 
 package remote
 
-import "golang.org/x/oauth2"
+import (
+	"time"
+
+	"golang.org/x/oauth2"
+)
 
 type User struct {
 	ID                string `json:"id"`
@@ -12,6 +16,15 @@ type User struct {
 	Mail              string `json:"mail,omitempty"`
 }
 
+// Name returns the mail of the user, or their display name for accounts
+// without a mailbox, such as ICS feeds.
+func (u *User) Name() string {
+	if u.Mail != "" {
+		return u.Mail
+	}
+	return u.DisplayName
+}
+
 type WorkingHours struct {
 	StartTime string `json:"startTime"`
 	EndTime   string `json:"endTime"`
@@ -21,9 +34,44 @@ type WorkingHours struct {
 	DaysOfWeek []string `json:"daysOfWeek"`
 }
 
+const (
+	AutomaticRepliesDisabled      = "disabled"
+	AutomaticRepliesAlwaysEnabled = "alwaysEnabled"
+	AutomaticRepliesScheduled     = "scheduled"
+)
+
+// AutomaticRepliesSetting are the automatic replies, or out-of-office
+// replies, of a mailbox.
+type AutomaticRepliesSetting struct {
+	Status                 string    `json:"status,omitempty"`
+	ExternalAudience       string    `json:"externalAudience,omitempty"`
+	ScheduledStartDateTime *DateTime `json:"scheduledStartDateTime,omitempty"`
+	ScheduledEndDateTime   *DateTime `json:"scheduledEndDateTime,omitempty"`
+	InternalReplyMessage   string    `json:"internalReplyMessage,omitempty"`
+	ExternalReplyMessage   string    `json:"externalReplyMessage,omitempty"`
+}
+
+// IsActive tells whether the automatic replies are sent at the given time.
+func (s *AutomaticRepliesSetting) IsActive(t time.Time) bool {
+	switch s.Status {
+	case AutomaticRepliesAlwaysEnabled:
+		return true
+	case AutomaticRepliesScheduled:
+		if s.ScheduledStartDateTime != nil && t.Before(s.ScheduledStartDateTime.Time()) {
+			return false
+		}
+		if s.ScheduledEndDateTime != nil && !t.Before(s.ScheduledEndDateTime.Time()) {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
 type MailboxSettings struct {
-	TimeZone     string       `json:"timeZone"`
-	WorkingHours WorkingHours `json:"workingHours"`
+	TimeZone                string                  `json:"timeZone"`
+	WorkingHours            WorkingHours            `json:"workingHours"`
+	AutomaticRepliesSetting AutomaticRepliesSetting `json:"automaticRepliesSetting"`
 }
 
 type UserTokenHelpers interface {
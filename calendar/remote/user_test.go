@@ -0,0 +1,38 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package remote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomaticRepliesIsActive(t *testing.T) {
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	scheduled := &AutomaticRepliesSetting{
+		Status:                 AutomaticRepliesScheduled,
+		ScheduledStartDateTime: NewDateTime(start, "UTC"),
+		ScheduledEndDateTime:   NewDateTime(end, "UTC"),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		setting  *AutomaticRepliesSetting
+		at       time.Time
+		expected bool
+	}{
+		{name: "disabled", setting: &AutomaticRepliesSetting{Status: AutomaticRepliesDisabled}, at: start},
+		{name: "always enabled", setting: &AutomaticRepliesSetting{Status: AutomaticRepliesAlwaysEnabled}, at: start, expected: true},
+		{name: "before the schedule", setting: scheduled, at: start.Add(-time.Minute)},
+		{name: "during the schedule", setting: scheduled, at: start.Add(time.Hour), expected: true},
+		{name: "end of the schedule", setting: scheduled, at: end},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.setting.IsActive(tc.at))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+const notificationCertificateKey = "notification"
+
+type CertificateStore interface {
+	LoadNotificationCertificate() (*certificate.Certificate, error)
+	StoreNotificationCertificate(previous, cert *certificate.Certificate) (*certificate.Certificate, error)
+}
+
+func (s *pluginStore) LoadNotificationCertificate() (*certificate.Certificate, error) {
+	cert := certificate.Certificate{}
+	err := kvstore.LoadJSON(s.certificateKV, notificationCertificateKey, &cert)
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// StoreNotificationCertificate stores cert in place of previous, the
+// certificate loaded before, or nil when none could be loaded. Servers
+// replacing the certificate at the same time end up with the same one: when
+// another certificate was stored since previous was loaded, it is kept and
+// returned instead of cert.
+func (s *pluginStore) StoreNotificationCertificate(previous, cert *certificate.Certificate) (*certificate.Certificate, error) {
+	data, err := json.Marshal(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	if previous == nil {
+		// A certificate that cannot be read, e.g. after the encryption key
+		// changed, cannot be compared: it is removed, then created again.
+		if _, err = s.LoadNotificationCertificate(); err != nil && err != ErrNotFound {
+			if err = s.certificateKV.Delete(notificationCertificateKey); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	stored := cert
+	err = kvstore.AtomicModify(s.certificateKV, notificationCertificateKey, func(initial []byte, storeErr error) ([]byte, error) {
+		if storeErr == ErrNotFound {
+			stored = cert
+			return data, nil
+		}
+		if storeErr != nil {
+			return initial, storeErr
+		}
+
+		current := &certificate.Certificate{}
+		if unmarshalErr := json.Unmarshal(initial, current); unmarshalErr != nil {
+			return initial, unmarshalErr
+		}
+		if previous != nil && current.ID == previous.ID {
+			stored = cert
+			return data, nil
+		}
+		stored = current
+		return initial, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/testutil"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
+)
+
+func TestLoadNotificationCertificate(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(*testutil.MockPluginAPI)
+		assertions func(*testing.T, *certificate.Certificate, error)
+	}{
+		{
+			name: "Certificate not found",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return(nil, nil).Times(1)
+			},
+			assertions: func(t *testing.T, cert *certificate.Certificate, err error) {
+				require.Nil(t, cert)
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		{
+			name: "Successful load",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return([]byte(`{"ID":"cert_id","Raw":"AQI="}`), nil).Times(1)
+			},
+			assertions: func(t *testing.T, cert *certificate.Certificate, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "cert_id", cert.ID)
+				require.Equal(t, []byte{1, 2}, cert.Raw)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			tt.setup(mockAPI)
+
+			cert, err := store.LoadNotificationCertificate()
+
+			tt.assertions(t, cert, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStoreNotificationCertificate(t *testing.T) {
+	old := &certificate.Certificate{ID: "old"}
+	other := &certificate.Certificate{ID: "other"}
+	cert := &certificate.Certificate{ID: "new"}
+	oldJSON, _ := json.Marshal(old)
+	otherJSON, _ := json.Marshal(other)
+	certJSON, _ := json.Marshal(cert)
+
+	tests := []struct {
+		name     string
+		previous *certificate.Certificate
+		setup    func(*testutil.MockPluginAPI)
+		expected *certificate.Certificate
+	}{
+		{
+			name: "created when absent",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return(nil, nil).Times(2)
+				mockAPI.On("KVSetWithOptions", MockString, certJSON, model.PluginKVSetOptions{Atomic: true}).Return(true, nil).Once()
+			},
+			expected: cert,
+		},
+		{
+			name: "created by another server first",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return(nil, nil).Times(2)
+				mockAPI.On("KVSetWithOptions", MockString, certJSON, model.PluginKVSetOptions{Atomic: true}).Return(false, nil).Once()
+				mockAPI.On("KVGet", MockString).Return(otherJSON, nil).Once()
+			},
+			expected: other,
+		},
+		{
+			name:     "replaces the previous certificate",
+			previous: old,
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return(oldJSON, nil).Once()
+				mockAPI.On("KVSetWithOptions", MockString, certJSON, model.PluginKVSetOptions{Atomic: true, OldValue: oldJSON}).Return(true, nil).Once()
+			},
+			expected: cert,
+		},
+		{
+			name:     "keeps the certificate of another server",
+			previous: old,
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return(otherJSON, nil).Once()
+			},
+			expected: other,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			tt.setup(mockAPI)
+
+			stored, err := store.StoreNotificationCertificate(tt.previous, cert)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, stored)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
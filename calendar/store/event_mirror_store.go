@@ -0,0 +1,108 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+// EventMirror is a local copy of the calendar view of a user between Start
+// and End, kept current with delta queries.
+type EventMirror struct {
+	SyncedAt     time.Time
+	Start        time.Time
+	End          time.Time
+	Events       map[string]*remote.Event
+	RemoteUserID string
+	DeltaLink    string
+	// Stale is set when the calendar was changed through the plugin, so
+	// the next read syncs the mirror regardless of SyncedAt.
+	Stale bool
+}
+
+type EventMirrorStore interface {
+	LoadEventMirror(remoteUserID string) (*EventMirror, error)
+	StoreEventMirror(mirror *EventMirror) error
+	DeleteEventMirror(remoteUserID string) error
+}
+
+func NewEventMirror(remoteUserID string, start, end time.Time) *EventMirror {
+	return &EventMirror{
+		RemoteUserID: remoteUserID,
+		Start:        start,
+		End:          end,
+		Events:       map[string]*remote.Event{},
+	}
+}
+
+// Apply merges the changes returned by a delta query into the mirror.
+func (m *EventMirror) Apply(delta *remote.EventsDelta, syncedAt time.Time) {
+	if delta.Reset || m.Events == nil {
+		m.Events = map[string]*remote.Event{}
+	}
+	for _, id := range delta.RemovedIDs {
+		delete(m.Events, id)
+	}
+	for _, event := range delta.Events {
+		m.Events[event.ID] = event
+	}
+	m.DeltaLink = delta.DeltaLink
+	m.SyncedAt = syncedAt
+	m.Stale = false
+}
+
+// Between returns the mirrored events overlapping the range, sorted by start
+// time. Events without an end are treated as ending when they start.
+func (m *EventMirror) Between(start, end time.Time) []*remote.Event {
+	events := []*remote.Event{}
+	for _, event := range m.Events {
+		if event.Start == nil {
+			continue
+		}
+		eventStart := event.Start.Time()
+		eventEnd := eventStart
+		if event.End != nil {
+			eventEnd = event.End.Time()
+		}
+		if eventStart.Before(end) && (eventEnd.After(start) || eventEnd.Equal(start) && eventEnd.Equal(eventStart)) {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		si, sj := events[i].Start.Time(), events[j].Start.Time()
+		if !si.Equal(sj) {
+			return si.Before(sj)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events
+}
+
+func (s *pluginStore) LoadEventMirror(remoteUserID string) (*EventMirror, error) {
+	mirror := EventMirror{}
+	err := kvstore.LoadJSON(s.eventMirrorKV, remoteUserID, &mirror)
+	if err != nil {
+		return nil, err
+	}
+	return &mirror, nil
+}
+
+func (s *pluginStore) StoreEventMirror(mirror *EventMirror) error {
+	err := kvstore.StoreJSON(s.eventMirrorKV, mirror.RemoteUserID, mirror)
+	if err != nil {
+		return errors.Wrap(err, "error storing event mirror")
+	}
+	return nil
+}
+
+func (s *pluginStore) DeleteEventMirror(remoteUserID string) error {
+	return s.eventMirrorKV.Delete(remoteUserID)
+}
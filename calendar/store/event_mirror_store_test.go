@@ -0,0 +1,124 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/testutil"
+
+	"github.com/mattermost/mattermost/server/public/model"
+)
+
+func TestEventMirrorApply(t *testing.T) {
+	syncedAt := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	mirror := NewEventMirror(MockRemoteUserID, syncedAt.AddDate(0, 0, -1), syncedAt.AddDate(0, 0, 14))
+	mirror.Stale = true
+
+	mirror.Apply(&remote.EventsDelta{
+		DeltaLink: "link1",
+		Events:    []*remote.Event{{ID: "a", Subject: "A"}, {ID: "b"}},
+		Reset:     true,
+	}, syncedAt)
+	require.Len(t, mirror.Events, 2)
+	require.Equal(t, "link1", mirror.DeltaLink)
+	require.Equal(t, syncedAt, mirror.SyncedAt)
+	require.False(t, mirror.Stale)
+
+	mirror.Apply(&remote.EventsDelta{
+		DeltaLink:  "link2",
+		Events:     []*remote.Event{{ID: "a", Subject: "A2"}, {ID: "c"}},
+		RemovedIDs: []string{"b"},
+	}, syncedAt.Add(time.Minute))
+	require.Len(t, mirror.Events, 2)
+	require.Equal(t, "A2", mirror.Events["a"].Subject)
+	require.Contains(t, mirror.Events, "c")
+	require.Equal(t, "link2", mirror.DeltaLink)
+
+	mirror.Apply(&remote.EventsDelta{
+		DeltaLink: "link3",
+		Events:    []*remote.Event{{ID: "d"}},
+		Reset:     true,
+	}, syncedAt.Add(2*time.Minute))
+	require.Len(t, mirror.Events, 1)
+	require.Contains(t, mirror.Events, "d")
+}
+
+func TestEventMirrorBetween(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) *remote.DateTime {
+		return remote.NewDateTime(day.Add(time.Duration(hour)*time.Hour), "UTC")
+	}
+	mirror := NewEventMirror(MockRemoteUserID, day, day.AddDate(0, 0, 1))
+	mirror.Apply(&remote.EventsDelta{Events: []*remote.Event{
+		{ID: "before", Start: at(7), End: at(8)},
+		{ID: "overlapping", Start: at(8), End: at(10)},
+		{ID: "inside", Start: at(9), End: at(9)},
+		{ID: "no-end", Start: at(9)},
+		{ID: "after", Start: at(11), End: at(12)},
+		{ID: "no-start"},
+	}}, day)
+
+	events := mirror.Between(day.Add(9*time.Hour), day.Add(11*time.Hour))
+	ids := []string{}
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	require.Equal(t, []string{"overlapping", "inside", "no-end"}, ids)
+}
+
+func TestLoadEventMirror(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(*testutil.MockPluginAPI)
+		assertions func(*testing.T, *EventMirror, error)
+	}{
+		{
+			name: "Mirror not found",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return(nil, nil).Times(1)
+			},
+			assertions: func(t *testing.T, mirror *EventMirror, err error) {
+				require.Nil(t, mirror)
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		{
+			name: "Error loading mirror",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return(nil, &model.AppError{Message: "KVGet failed"}).Times(1)
+			},
+			assertions: func(t *testing.T, mirror *EventMirror, err error) {
+				require.Nil(t, mirror)
+				require.EqualError(t, err, "failed plugin KVGet: KVGet failed")
+			},
+		},
+		{
+			name: "Successful load",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", MockString).Return([]byte(`{"RemoteUserID":"mockRemoteUserID","DeltaLink":"link","Events":{"a":{"id":"a"}}}`), nil).Times(1)
+			},
+			assertions: func(t *testing.T, mirror *EventMirror, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "link", mirror.DeltaLink)
+				require.Equal(t, "a", mirror.Events["a"].ID)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			tt.setup(mockAPI)
+
+			mirror, err := store.LoadEventMirror(MockRemoteUserID)
+
+			tt.assertions(t, mirror, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
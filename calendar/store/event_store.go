@@ -27,6 +27,10 @@ type EventMetadata struct {
 type Event struct {
 	Remote        *remote.Event
 	PluginVersion string
+
+	// PostIDs are the DMs that notified the user of the event, updated once
+	// it is cancelled.
+	PostIDs []string `json:",omitempty"`
 }
 
 type EventStore interface {
@@ -38,6 +42,7 @@ type EventStore interface {
 	DeleteLinkedChannelFromEvent(eventID, channelID string) error
 
 	LoadUserEvent(mattermostUserID, eventID string) (*Event, error)
+	LoadUserEventByRemoteID(mattermostUserID, remoteEventID string) (*Event, error)
 	StoreUserEvent(mattermostUserID string, event *Event) error
 	DeleteUserEvent(mattermostUserID, eventID string) error
 }
@@ -45,6 +50,12 @@ type EventStore interface {
 func eventKey(mattermostUserID, eventID string) string { return mattermostUserID + "_" + eventID }
 func eventMetaKey(eventID string) string               { return "metadata_" + eventID }
 
+// eventRemoteIDKey maps the remote ID of an event to its iCalUID, for the
+// notifications of deleted events which only carry the former.
+func eventRemoteIDKey(mattermostUserID, remoteEventID string) string {
+	return mattermostUserID + "_id_" + remoteEventID
+}
+
 func (s *pluginStore) LoadUserEvent(mattermostUserID, eventID string) (*Event, error) {
 	event := Event{}
 	err := kvstore.LoadJSON(s.eventKV, eventKey(mattermostUserID, eventID), &event)
@@ -54,6 +65,14 @@ func (s *pluginStore) LoadUserEvent(mattermostUserID, eventID string) (*Event, e
 	return &event, nil
 }
 
+func (s *pluginStore) LoadUserEventByRemoteID(mattermostUserID, remoteEventID string) (*Event, error) {
+	iCalUID, err := s.eventKV.Load(eventRemoteIDKey(mattermostUserID, remoteEventID))
+	if err != nil {
+		return nil, err
+	}
+	return s.LoadUserEvent(mattermostUserID, string(iCalUID))
+}
+
 func (s *pluginStore) AddLinkedChannelToEvent(eventID, channelID string) error {
 	eventMeta, err := s.LoadEventMetadata(eventID)
 	if err != nil && !errors.Is(err, ErrNotFound) {
@@ -123,6 +142,12 @@ func (s *pluginStore) StoreUserEvent(mattermostUserID string, event *Event) erro
 	if err != nil {
 		return err
 	}
+	if event.Remote.ID != "" {
+		err = s.eventKV.StoreTTL(eventRemoteIDKey(mattermostUserID, event.Remote.ID), []byte(event.Remote.ICalUID), ttl)
+		if err != nil {
+			return err
+		}
+	}
 
 	s.Logger.With(bot.LogContext{
 		"mattermostUserID": mattermostUserID,
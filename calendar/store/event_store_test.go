@@ -59,6 +59,47 @@ func TestLoadUserEvent(t *testing.T) {
 	}
 }
 
+func TestLoadUserEventByRemoteID(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(*testutil.MockPluginAPI)
+		assertions func(*testing.T, *Event, error)
+	}{
+		{
+			name: "Unknown remote ID",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", "ev_bddf5d37abb6983ba028bfbf8b16c2ba").Return(nil, nil).Times(1)
+			},
+			assertions: func(t *testing.T, event *Event, err error) {
+				require.Nil(t, event)
+				require.ErrorIs(t, err, ErrNotFound)
+			},
+		},
+		{
+			name: "Successful Load",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", "ev_bddf5d37abb6983ba028bfbf8b16c2ba").Return([]byte(MockEventID), nil).Times(1)
+				mockAPI.On("KVGet", "ev_ff63e69da944334bfa44f98fe45e3c0c").Return([]byte(`{"PluginVersion":"1.0","Remote":{"ID":"mockRemoteID"}}`), nil).Times(1)
+			},
+			assertions: func(t *testing.T, event *Event, err error) {
+				require.NoError(t, err)
+				require.Equal(t, MockRemoteID, event.Remote.ID)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			tt.setup(mockAPI)
+
+			event, err := store.LoadUserEventByRemoteID(MockUserID, MockRemoteID)
+
+			tt.assertions(t, event, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
 func TestAddLinkedChannelToEvent(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -322,6 +363,7 @@ func TestStoreUserEvent(t *testing.T) {
 			setup: func(mockAPI *testutil.MockPluginAPI, mockLogger *mock_bot.MockLogger, mockLoggerWith *mock_bot.MockLogger) {
 				mockEvent.Remote.End = remote.NewDateTime(time.Now(), "UTC")
 				mockAPI.On("KVSetWithExpiry", "ev_ad2104c3b0ad765e6e9e03857a3348a5", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Times(1)
+				mockAPI.On("KVSetWithExpiry", MockString, []byte(MockICalUID), mock.AnythingOfType("int64")).Return(nil).Times(1)
 				mockLogger.EXPECT().With(gomock.Any()).Return(mockLoggerWith).Times(1)
 				mockLoggerWith.EXPECT().Debugf("store: stored user event.").Times(1)
 			},
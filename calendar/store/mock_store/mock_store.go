@@ -6,9 +6,11 @@ package mock_store
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	store "github.com/mattermost/mattermost-plugin-mscalendar/calendar/store"
+	certificate "github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
 	oauth2 "golang.org/x/oauth2"
 )
 
@@ -35,6 +37,25 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddDigestItems mocks base method.
+func (m *MockStore) AddDigestItems(arg0 string, arg1 ...*store.DigestItem) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddDigestItems", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDigestItems indicates an expected call of AddDigestItems.
+func (mr *MockStoreMockRecorder) AddDigestItems(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDigestItems", reflect.TypeOf((*MockStore)(nil).AddDigestItems), varargs...)
+}
+
 // AddLinkedChannelToEvent mocks base method.
 func (m *MockStore) AddLinkedChannelToEvent(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -63,6 +84,34 @@ func (mr *MockStoreMockRecorder) CheckUserConnected(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckUserConnected", reflect.TypeOf((*MockStore)(nil).CheckUserConnected), arg0)
 }
 
+// CompleteNotification mocks base method.
+func (m *MockStore) CompleteNotification(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteNotification", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteNotification indicates an expected call of CompleteNotification.
+func (mr *MockStoreMockRecorder) CompleteNotification(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteNotification", reflect.TypeOf((*MockStore)(nil).CompleteNotification), arg0)
+}
+
+// DeadLetterNotification mocks base method.
+func (m *MockStore) DeadLetterNotification(arg0 *store.QueuedNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetterNotification", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetterNotification indicates an expected call of DeadLetterNotification.
+func (mr *MockStoreMockRecorder) DeadLetterNotification(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetterNotification", reflect.TypeOf((*MockStore)(nil).DeadLetterNotification), arg0)
+}
+
 // DeleteCurrentStep mocks base method.
 func (m *MockStore) DeleteCurrentStep(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -91,6 +140,20 @@ func (mr *MockStoreMockRecorder) DeleteEventMetadata(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventMetadata", reflect.TypeOf((*MockStore)(nil).DeleteEventMetadata), arg0)
 }
 
+// DeleteEventMirror mocks base method.
+func (m *MockStore) DeleteEventMirror(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventMirror", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEventMirror indicates an expected call of DeleteEventMirror.
+func (mr *MockStoreMockRecorder) DeleteEventMirror(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventMirror", reflect.TypeOf((*MockStore)(nil).DeleteEventMirror), arg0)
+}
+
 // DeleteLinkedChannelFromEvent mocks base method.
 func (m *MockStore) DeleteLinkedChannelFromEvent(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -133,20 +196,6 @@ func (mr *MockStoreMockRecorder) DeleteUser(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0)
 }
 
-// ForceDeleteUser mocks base method.
-func (m *MockStore) ForceDeleteUser(arg0, arg1 string) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ForceDeleteUser", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// ForceDeleteUser indicates an expected call of ForceDeleteUser.
-func (mr *MockStoreMockRecorder) ForceDeleteUser(arg0, arg1 interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceDeleteUser", reflect.TypeOf((*MockStore)(nil).ForceDeleteUser), arg0, arg1)
-}
-
 // DeleteUserEvent mocks base method.
 func (m *MockStore) DeleteUserEvent(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -216,6 +265,38 @@ func (mr *MockStoreMockRecorder) DisconnectUserFromStoreIfNecessary(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisconnectUserFromStoreIfNecessary", reflect.TypeOf((*MockStore)(nil).DisconnectUserFromStoreIfNecessary), arg0, arg1)
 }
 
+// EnqueueNotifications mocks base method.
+func (m *MockStore) EnqueueNotifications(arg0 ...*store.QueuedNotification) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EnqueueNotifications", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueNotifications indicates an expected call of EnqueueNotifications.
+func (mr *MockStoreMockRecorder) EnqueueNotifications(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueNotifications", reflect.TypeOf((*MockStore)(nil).EnqueueNotifications), arg0...)
+}
+
+// ForceDeleteUser mocks base method.
+func (m *MockStore) ForceDeleteUser(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceDeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceDeleteUser indicates an expected call of ForceDeleteUser.
+func (mr *MockStoreMockRecorder) ForceDeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceDeleteUser", reflect.TypeOf((*MockStore)(nil).ForceDeleteUser), arg0, arg1)
+}
+
 // GetConnectedUserCount mocks base method.
 func (m *MockStore) GetConnectedUserCount() (uint64, error) {
 	m.ctrl.T.Helper()
@@ -306,6 +387,36 @@ func (mr *MockStoreMockRecorder) GetSubscriptionCount() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionCount", reflect.TypeOf((*MockStore)(nil).GetSubscriptionCount))
 }
 
+// LeaseNotification mocks base method.
+func (m *MockStore) LeaseNotification(arg0 time.Time, arg1 time.Duration) (*store.QueuedNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaseNotification", arg0, arg1)
+	ret0, _ := ret[0].(*store.QueuedNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LeaseNotification indicates an expected call of LeaseNotification.
+func (mr *MockStoreMockRecorder) LeaseNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaseNotification", reflect.TypeOf((*MockStore)(nil).LeaseNotification), arg0, arg1)
+}
+
+// LoadDeadLetterNotifications mocks base method.
+func (m *MockStore) LoadDeadLetterNotifications() ([]*store.QueuedNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDeadLetterNotifications")
+	ret0, _ := ret[0].([]*store.QueuedNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDeadLetterNotifications indicates an expected call of LoadDeadLetterNotifications.
+func (mr *MockStoreMockRecorder) LoadDeadLetterNotifications() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDeadLetterNotifications", reflect.TypeOf((*MockStore)(nil).LoadDeadLetterNotifications))
+}
+
 // LoadEventMetadata mocks base method.
 func (m *MockStore) LoadEventMetadata(arg0 string) (*store.EventMetadata, error) {
 	m.ctrl.T.Helper()
@@ -321,6 +432,21 @@ func (mr *MockStoreMockRecorder) LoadEventMetadata(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadEventMetadata", reflect.TypeOf((*MockStore)(nil).LoadEventMetadata), arg0)
 }
 
+// LoadEventMirror mocks base method.
+func (m *MockStore) LoadEventMirror(arg0 string) (*store.EventMirror, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadEventMirror", arg0)
+	ret0, _ := ret[0].(*store.EventMirror)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadEventMirror indicates an expected call of LoadEventMirror.
+func (mr *MockStoreMockRecorder) LoadEventMirror(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadEventMirror", reflect.TypeOf((*MockStore)(nil).LoadEventMirror), arg0)
+}
+
 // LoadMattermostUserID mocks base method.
 func (m *MockStore) LoadMattermostUserID(arg0 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -336,6 +462,36 @@ func (mr *MockStoreMockRecorder) LoadMattermostUserID(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadMattermostUserID", reflect.TypeOf((*MockStore)(nil).LoadMattermostUserID), arg0)
 }
 
+// LoadNotificationCertificate mocks base method.
+func (m *MockStore) LoadNotificationCertificate() (*certificate.Certificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadNotificationCertificate")
+	ret0, _ := ret[0].(*certificate.Certificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadNotificationCertificate indicates an expected call of LoadNotificationCertificate.
+func (mr *MockStoreMockRecorder) LoadNotificationCertificate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadNotificationCertificate", reflect.TypeOf((*MockStore)(nil).LoadNotificationCertificate))
+}
+
+// LoadNotificationDigest mocks base method.
+func (m *MockStore) LoadNotificationDigest(arg0 string) (*store.NotificationDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadNotificationDigest", arg0)
+	ret0, _ := ret[0].(*store.NotificationDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadNotificationDigest indicates an expected call of LoadNotificationDigest.
+func (mr *MockStoreMockRecorder) LoadNotificationDigest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadNotificationDigest", reflect.TypeOf((*MockStore)(nil).LoadNotificationDigest), arg0)
+}
+
 // LoadSubscription mocks base method.
 func (m *MockStore) LoadSubscription(arg0 string) (*store.Subscription, error) {
 	m.ctrl.T.Helper()
@@ -381,6 +537,21 @@ func (mr *MockStoreMockRecorder) LoadUserEvent(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserEvent", reflect.TypeOf((*MockStore)(nil).LoadUserEvent), arg0, arg1)
 }
 
+// LoadUserEventByRemoteID mocks base method.
+func (m *MockStore) LoadUserEventByRemoteID(arg0, arg1 string) (*store.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserEventByRemoteID", arg0, arg1)
+	ret0, _ := ret[0].(*store.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserEventByRemoteID indicates an expected call of LoadUserEventByRemoteID.
+func (mr *MockStoreMockRecorder) LoadUserEventByRemoteID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserEventByRemoteID", reflect.TypeOf((*MockStore)(nil).LoadUserEventByRemoteID), arg0, arg1)
+}
+
 // LoadUserFromIndex mocks base method.
 func (m *MockStore) LoadUserFromIndex(arg0 string) (*store.UserShort, error) {
 	m.ctrl.T.Helper()
@@ -469,6 +640,53 @@ func (mr *MockStoreMockRecorder) RemovePostID(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePostID", reflect.TypeOf((*MockStore)(nil).RemovePostID), arg0, arg1)
 }
 
+// RenewNotificationLease mocks base method.
+func (m *MockStore) RenewNotificationLease(arg0 string, arg1 time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewNotificationLease", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenewNotificationLease indicates an expected call of RenewNotificationLease.
+func (mr *MockStoreMockRecorder) RenewNotificationLease(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewNotificationLease", reflect.TypeOf((*MockStore)(nil).RenewNotificationLease), arg0, arg1)
+}
+
+// ReplayDeadLetterNotifications mocks base method.
+func (m *MockStore) ReplayDeadLetterNotifications(arg0 ...string) (int, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReplayDeadLetterNotifications", varargs...)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayDeadLetterNotifications indicates an expected call of ReplayDeadLetterNotifications.
+func (mr *MockStoreMockRecorder) ReplayDeadLetterNotifications(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetterNotifications", reflect.TypeOf((*MockStore)(nil).ReplayDeadLetterNotifications), arg0...)
+}
+
+// RetryNotification mocks base method.
+func (m *MockStore) RetryNotification(arg0 *store.QueuedNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryNotification", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryNotification indicates an expected call of RetryNotification.
+func (mr *MockStoreMockRecorder) RetryNotification(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryNotification", reflect.TypeOf((*MockStore)(nil).RetryNotification), arg0)
+}
+
 // SearchInUserIndex mocks base method.
 func (m *MockStore) SearchInUserIndex(arg0 string, arg1 int) (store.UserIndex, error) {
 	m.ctrl.T.Helper()
@@ -568,6 +786,35 @@ func (mr *MockStoreMockRecorder) StoreEventMetadata(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreEventMetadata", reflect.TypeOf((*MockStore)(nil).StoreEventMetadata), arg0, arg1)
 }
 
+// StoreEventMirror mocks base method.
+func (m *MockStore) StoreEventMirror(arg0 *store.EventMirror) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreEventMirror", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreEventMirror indicates an expected call of StoreEventMirror.
+func (mr *MockStoreMockRecorder) StoreEventMirror(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreEventMirror", reflect.TypeOf((*MockStore)(nil).StoreEventMirror), arg0)
+}
+
+// StoreNotificationCertificate mocks base method.
+func (m *MockStore) StoreNotificationCertificate(arg0, arg1 *certificate.Certificate) (*certificate.Certificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreNotificationCertificate", arg0, arg1)
+	ret0, _ := ret[0].(*certificate.Certificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreNotificationCertificate indicates an expected call of StoreNotificationCertificate.
+func (mr *MockStoreMockRecorder) StoreNotificationCertificate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreNotificationCertificate", reflect.TypeOf((*MockStore)(nil).StoreNotificationCertificate), arg0, arg1)
+}
+
 // StoreOAuth2State mocks base method.
 func (m *MockStore) StoreOAuth2State(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -694,6 +941,21 @@ func (mr *MockStoreMockRecorder) StoreUserWelcomePost(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreUserWelcomePost", reflect.TypeOf((*MockStore)(nil).StoreUserWelcomePost), arg0, arg1)
 }
 
+// TakeNotificationDigest mocks base method.
+func (m *MockStore) TakeNotificationDigest(arg0 string) (*store.NotificationDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeNotificationDigest", arg0)
+	ret0, _ := ret[0].(*store.NotificationDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeNotificationDigest indicates an expected call of TakeNotificationDigest.
+func (mr *MockStoreMockRecorder) TakeNotificationDigest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeNotificationDigest", reflect.TypeOf((*MockStore)(nil).TakeNotificationDigest), arg0)
+}
+
 // VerifyOAuth2State mocks base method.
 func (m *MockStore) VerifyOAuth2State(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,128 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+const (
+	DigestKindNew       = "new"
+	DigestKindUpdated   = "updated"
+	DigestKindCancelled = "cancelled"
+	DigestKindDeleted   = "deleted"
+)
+
+// NotificationDigest holds the event notifications of a user waiting to be
+// posted together, oldest first.
+type NotificationDigest struct {
+	Items []*DigestItem
+}
+
+// DigestItem is the last known state of an event notified to a user in
+// digest mode.
+type DigestItem struct {
+	Event      *remote.Event
+	Kind       string
+	Timezone   string `json:",omitempty"`
+	ReceivedAt time.Time
+}
+
+type NotificationDigestStore interface {
+	AddDigestItems(mattermostUserID string, items ...*DigestItem) error
+	LoadNotificationDigest(mattermostUserID string) (*NotificationDigest, error)
+	TakeNotificationDigest(mattermostUserID string) (*NotificationDigest, error)
+}
+
+// Add buffers an event notification. An event already in the digest is
+// replaced in place, so that it is listed once with its latest state. A new
+// event stays new when updated. Events are told apart by their remote ID, as
+// the occurrences of a series share their iCalUID.
+func (d *NotificationDigest) Add(item *DigestItem) {
+	for i, existing := range d.Items {
+		if existing.Event.ID != item.Event.ID {
+			continue
+		}
+		kind := item.Kind
+		if existing.Kind == DigestKindNew && kind == DigestKindUpdated {
+			kind = DigestKindNew
+		}
+		d.Items[i] = &DigestItem{
+			Event:      item.Event,
+			Kind:       kind,
+			Timezone:   item.Timezone,
+			ReceivedAt: existing.ReceivedAt,
+		}
+		return
+	}
+	d.Items = append(d.Items, item)
+}
+
+// DueAt returns when the digest is to be posted, an interval after its oldest
+// notification.
+func (d *NotificationDigest) DueAt(interval time.Duration) time.Time {
+	if len(d.Items) == 0 {
+		return time.Time{}
+	}
+	return d.Items[0].ReceivedAt.Add(interval)
+}
+
+func (s *pluginStore) AddDigestItems(mattermostUserID string, items ...*DigestItem) error {
+	return kvstore.AtomicModify(s.notificationDigestKV, mattermostUserID, func(initial []byte, storeErr error) ([]byte, error) {
+		if storeErr != nil && storeErr != ErrNotFound {
+			return nil, storeErr
+		}
+		digest := NotificationDigest{}
+		if len(initial) > 0 {
+			err := json.Unmarshal(initial, &digest)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, item := range items {
+			digest.Add(item)
+		}
+		return json.Marshal(digest)
+	})
+}
+
+func (s *pluginStore) LoadNotificationDigest(mattermostUserID string) (*NotificationDigest, error) {
+	digest := NotificationDigest{}
+	err := kvstore.LoadJSON(s.notificationDigestKV, mattermostUserID, &digest)
+	if err != nil {
+		return nil, err
+	}
+	return &digest, nil
+}
+
+// TakeNotificationDigest removes the digest of the user and returns it. It
+// returns ErrNotFound when there is none.
+func (s *pluginStore) TakeNotificationDigest(mattermostUserID string) (*NotificationDigest, error) {
+	var digest *NotificationDigest
+	err := kvstore.AtomicModify(s.notificationDigestKV, mattermostUserID, func(initial []byte, storeErr error) ([]byte, error) {
+		digest = nil
+		if storeErr != nil {
+			return nil, storeErr
+		}
+		digest = &NotificationDigest{}
+		err := json.Unmarshal(initial, digest)
+		if err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	if errors.Cause(err) == ErrNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/testutil"
+)
+
+var mockDigestKey = mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, DigestKeyPrefix) })
+
+func TestNotificationDigestAdd(t *testing.T) {
+	first := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	later := first.Add(time.Hour)
+	event := func(id, subject string) *remote.Event {
+		return &remote.Event{ID: id, ICalUID: "uid", Subject: subject}
+	}
+
+	tests := []struct {
+		name     string
+		items    []*DigestItem
+		expected []*DigestItem
+	}{
+		{
+			name: "Different events are kept in order",
+			items: []*DigestItem{
+				{Event: event("id_1", "first"), Kind: DigestKindNew, ReceivedAt: first},
+				{Event: event("id_2", "second"), Kind: DigestKindUpdated, ReceivedAt: later},
+			},
+			expected: []*DigestItem{
+				{Event: event("id_1", "first"), Kind: DigestKindNew, ReceivedAt: first},
+				{Event: event("id_2", "second"), Kind: DigestKindUpdated, ReceivedAt: later},
+			},
+		},
+		{
+			name: "Occurrences of a series are kept apart",
+			items: []*DigestItem{
+				{Event: event("occurrence_1", "first"), Kind: DigestKindUpdated, ReceivedAt: first},
+				{Event: event("occurrence_2", "first"), Kind: DigestKindCancelled, ReceivedAt: later},
+			},
+			expected: []*DigestItem{
+				{Event: event("occurrence_1", "first"), Kind: DigestKindUpdated, ReceivedAt: first},
+				{Event: event("occurrence_2", "first"), Kind: DigestKindCancelled, ReceivedAt: later},
+			},
+		},
+		{
+			name: "Updated new event stays new with its latest state",
+			items: []*DigestItem{
+				{Event: event("id_1", "first"), Kind: DigestKindNew, ReceivedAt: first},
+				{Event: event("id_1", "renamed"), Kind: DigestKindUpdated, ReceivedAt: later},
+			},
+			expected: []*DigestItem{
+				{Event: event("id_1", "renamed"), Kind: DigestKindNew, ReceivedAt: first},
+			},
+		},
+		{
+			name: "Cancelled event replaces its updates",
+			items: []*DigestItem{
+				{Event: event("id_1", "first"), Kind: DigestKindUpdated, ReceivedAt: first},
+				{Event: event("id_1", "first"), Kind: DigestKindCancelled, ReceivedAt: later},
+			},
+			expected: []*DigestItem{
+				{Event: event("id_1", "first"), Kind: DigestKindCancelled, ReceivedAt: first},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest := NotificationDigest{}
+			for _, item := range tt.items {
+				digest.Add(item)
+			}
+			require.Equal(t, tt.expected, digest.Items)
+			require.Equal(t, first.Add(time.Hour), digest.DueAt(time.Hour))
+		})
+	}
+}
+
+func TestAddDigestItems(t *testing.T) {
+	receivedAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	existing, _ := json.Marshal(&NotificationDigest{Items: []*DigestItem{
+		{Event: &remote.Event{ID: "id_1", ICalUID: "uid_1"}, Kind: DigestKindNew, ReceivedAt: receivedAt},
+	}})
+	expected, _ := json.Marshal(&NotificationDigest{Items: []*DigestItem{
+		{Event: &remote.Event{ID: "id_1", ICalUID: "uid_1"}, Kind: DigestKindNew, ReceivedAt: receivedAt},
+		{Event: &remote.Event{ID: "id_2", ICalUID: "uid_2"}, Kind: DigestKindCancelled, ReceivedAt: receivedAt},
+	}})
+
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+	mockAPI.On("KVGet", mockDigestKey).Return(existing, nil).Once()
+	mockAPI.On("KVSetWithOptions", mockDigestKey, expected, mock.Anything).Return(true, nil).Once()
+
+	err := store.AddDigestItems(MockMMUserID, &DigestItem{Event: &remote.Event{ID: "id_2", ICalUID: "uid_2"}, Kind: DigestKindCancelled, ReceivedAt: receivedAt})
+	require.NoError(t, err)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestTakeNotificationDigest(t *testing.T) {
+	digestJSON, _ := json.Marshal(&NotificationDigest{Items: []*DigestItem{
+		{Event: &remote.Event{ICalUID: "uid_1"}, Kind: DigestKindNew},
+	}})
+
+	tests := []struct {
+		name       string
+		setup      func(*testutil.MockPluginAPI)
+		assertions func(*testing.T, *NotificationDigest, error)
+	}{
+		{
+			name: "No digest",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", mockDigestKey).Return(nil, nil).Once()
+			},
+			assertions: func(t *testing.T, digest *NotificationDigest, err error) {
+				require.Equal(t, ErrNotFound, err)
+				require.Nil(t, digest)
+			},
+		},
+		{
+			name: "Digest is removed",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", mockDigestKey).Return(digestJSON, nil).Once()
+				mockAPI.On("KVSetWithOptions", mockDigestKey, []byte(nil), mock.Anything).Return(true, nil).Once()
+			},
+			assertions: func(t *testing.T, digest *NotificationDigest, err error) {
+				require.NoError(t, err)
+				require.Len(t, digest.Items, 1)
+				require.Equal(t, "uid_1", digest.Items[0].Event.ICalUID)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			tt.setup(mockAPI)
+
+			digest, err := store.TakeNotificationDigest(MockMMUserID)
+
+			tt.assertions(t, digest, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
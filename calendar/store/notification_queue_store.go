@@ -0,0 +1,383 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/kvstore"
+)
+
+const (
+	// MaxQueuedNotifications is the number of notifications waiting to be
+	// processed above which new ones are refused.
+	MaxQueuedNotifications = 1024
+
+	// MaxDeadLetterNotifications is the number of failed notifications kept
+	// for inspection. The oldest ones are dropped first.
+	MaxDeadLetterNotifications = 1000
+
+	pendingNotificationsKey    = "pending"
+	deadLetterNotificationsKey = "deadletters"
+	notificationLeaseKeyPrefix = "lease_"
+)
+
+var ErrNotificationQueueFull = errors.New("notification queue is full")
+
+// QueuedNotification is a webhook notification waiting to be processed, or
+// one that failed too many times.
+type QueuedNotification struct {
+	ID            string
+	Notification  *remote.Notification
+	Attempts      int
+	EnqueuedAt    time.Time
+	NextAttemptAt time.Time
+	LastError     string `json:",omitempty"`
+	FailedAt      time.Time
+}
+
+// NotificationQueueStore keeps webhook notifications until they are processed,
+// so that they survive restarts. A notification is processed by one node of
+// the cluster at a time, the one holding its lease, and only once the earlier
+// notifications of its subscription are processed.
+type NotificationQueueStore interface {
+	EnqueueNotifications(...*QueuedNotification) error
+	LeaseNotification(now time.Time, lease time.Duration) (*QueuedNotification, error)
+	RenewNotificationLease(id string, lease time.Duration) error
+	CompleteNotification(id string) error
+	RetryNotification(*QueuedNotification) error
+	DeadLetterNotification(*QueuedNotification) error
+	LoadDeadLetterNotifications() ([]*QueuedNotification, error)
+	ReplayDeadLetterNotifications(ids ...string) (int, error)
+}
+
+// EnqueueNotifications queues all the notifications, or none of them if the
+// queue is full.
+func (s *pluginStore) EnqueueNotifications(items ...*QueuedNotification) error {
+	for _, item := range items {
+		err := kvstore.StoreJSON(s.notificationKV, item.ID, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := s.modifyPendingNotifications(func(refs []notificationRef) ([]notificationRef, error) {
+		if len(refs)+len(items) > MaxQueuedNotifications {
+			return nil, ErrNotificationQueueFull
+		}
+		for _, item := range items {
+			refs = append(refs, newNotificationRef(item))
+		}
+		return refs, nil
+	})
+	if err != nil {
+		for _, item := range items {
+			_ = s.notificationKV.Delete(item.ID)
+		}
+		if errors.Cause(err) == ErrNotificationQueueFull {
+			return ErrNotificationQueueFull
+		}
+		return err
+	}
+	return nil
+}
+
+// LeaseNotification returns the oldest notification due for processing that
+// no other node is processing, and leases it for the given duration. The
+// notifications of a subscription are leased in order, one at a time. It
+// returns ErrNotFound when there is none.
+func (s *pluginStore) LeaseNotification(now time.Time, lease time.Duration) (*QueuedNotification, error) {
+	refs, err := s.loadPendingNotifications()
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	defer func() {
+		if len(missing) > 0 {
+			_ = s.removePendingNotifications(missing...)
+		}
+	}()
+
+	// Subscriptions with an earlier notification still to process
+	blocked := map[string]bool{}
+	for _, ref := range refs {
+		if blocked[ref.SubscriptionID] {
+			continue
+		}
+		blocked[ref.SubscriptionID] = true
+		if ref.NextAttemptAt > now.Unix() {
+			continue
+		}
+
+		leased, err := s.notificationQueueKV.StoreWithOptions(notificationLeaseKeyPrefix+ref.ID, []byte{1}, model.PluginKVSetOptions{
+			Atomic:          true,
+			OldValue:        nil,
+			ExpireInSeconds: int64(lease / time.Second),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !leased {
+			continue
+		}
+
+		item := QueuedNotification{}
+		err = kvstore.LoadJSON(s.notificationKV, ref.ID, &item)
+		if err == ErrNotFound {
+			missing = append(missing, ref.ID)
+			_ = s.notificationQueueKV.Delete(notificationLeaseKeyPrefix + ref.ID)
+			blocked[ref.SubscriptionID] = false
+			continue
+		}
+		if err != nil {
+			_ = s.notificationQueueKV.Delete(notificationLeaseKeyPrefix + ref.ID)
+			return nil, err
+		}
+		return &item, nil
+	}
+	return nil, ErrNotFound
+}
+
+// RenewNotificationLease extends the lease of a notification being processed
+// by the given duration.
+func (s *pluginStore) RenewNotificationLease(id string, lease time.Duration) error {
+	_, err := s.notificationQueueKV.StoreWithOptions(notificationLeaseKeyPrefix+id, []byte{1}, model.PluginKVSetOptions{
+		ExpireInSeconds: int64(lease / time.Second),
+	})
+	return err
+}
+
+func (s *pluginStore) CompleteNotification(id string) error {
+	err := s.removePendingNotifications(id)
+	if err != nil {
+		return err
+	}
+	err = s.notificationKV.Delete(id)
+	if err != nil {
+		return err
+	}
+	return s.notificationQueueKV.Delete(notificationLeaseKeyPrefix + id)
+}
+
+// RetryNotification stores the attempts made on a leased notification and
+// releases it.
+func (s *pluginStore) RetryNotification(item *QueuedNotification) error {
+	err := kvstore.StoreJSON(s.notificationKV, item.ID, item)
+	if err != nil {
+		return err
+	}
+	err = s.modifyPendingNotifications(func(refs []notificationRef) ([]notificationRef, error) {
+		for i := range refs {
+			if refs[i].ID == item.ID {
+				refs[i] = newNotificationRef(item)
+			}
+		}
+		return refs, nil
+	})
+	if err != nil {
+		return err
+	}
+	return s.notificationQueueKV.Delete(notificationLeaseKeyPrefix + item.ID)
+}
+
+// DeadLetterNotification moves a leased notification to the dead letters.
+func (s *pluginStore) DeadLetterNotification(item *QueuedNotification) error {
+	err := kvstore.StoreJSON(s.notificationKV, item.ID, item)
+	if err != nil {
+		return err
+	}
+
+	dropped := []string{}
+	err = s.modifyDeadLetterNotifications(func(ids []string) ([]string, error) {
+		ids = append(ids, item.ID)
+		dropped = nil
+		if len(ids) > MaxDeadLetterNotifications {
+			dropped = ids[:len(ids)-MaxDeadLetterNotifications]
+			ids = ids[len(ids)-MaxDeadLetterNotifications:]
+		}
+		return ids, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range dropped {
+		_ = s.notificationKV.Delete(id)
+	}
+
+	err = s.removePendingNotifications(item.ID)
+	if err != nil {
+		return err
+	}
+	return s.notificationQueueKV.Delete(notificationLeaseKeyPrefix + item.ID)
+}
+
+func (s *pluginStore) LoadDeadLetterNotifications() ([]*QueuedNotification, error) {
+	ids := []string{}
+	err := kvstore.LoadJSON(s.notificationQueueKV, deadLetterNotificationsKey, &ids)
+	if err != nil && err != ErrNotFound {
+		return nil, err
+	}
+
+	items := []*QueuedNotification{}
+	for _, id := range ids {
+		item := QueuedNotification{}
+		err = kvstore.LoadJSON(s.notificationKV, id, &item)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, nil
+}
+
+// ReplayDeadLetterNotifications queues the given dead letters again, or all of
+// them if no ID is given. It returns how many were queued. A dead letter the
+// queue cannot take stays in the dead letters.
+func (s *pluginStore) ReplayDeadLetterNotifications(ids ...string) (int, error) {
+	items, err := s.LoadDeadLetterNotifications()
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, item := range items {
+		if len(ids) > 0 && !containsString(ids, item.ID) {
+			continue
+		}
+
+		deadLetter := *item
+		item.Attempts = 0
+		item.NextAttemptAt = time.Time{}
+		item.FailedAt = time.Time{}
+		err = s.EnqueueNotifications(item)
+		if err != nil {
+			// The failed enqueue deleted the notification
+			storeErr := kvstore.StoreJSON(s.notificationKV, deadLetter.ID, &deadLetter)
+			if storeErr != nil {
+				return replayed, errors.Wrapf(err, "failed to restore dead letter: %v", storeErr)
+			}
+			return replayed, err
+		}
+
+		err = s.modifyDeadLetterNotifications(func(ids []string) ([]string, error) {
+			return removeStrings(ids, item.ID), nil
+		})
+		if err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+// notificationRef is the entry of a notification in the queue, with what is
+// needed to pick the next one to lease.
+type notificationRef struct {
+	ID             string
+	SubscriptionID string `json:",omitempty"`
+	NextAttemptAt  int64  `json:",omitempty"`
+}
+
+func newNotificationRef(item *QueuedNotification) notificationRef {
+	ref := notificationRef{ID: item.ID}
+	if item.Notification != nil {
+		ref.SubscriptionID = item.Notification.SubscriptionID
+	}
+	if !item.NextAttemptAt.IsZero() {
+		ref.NextAttemptAt = item.NextAttemptAt.Unix()
+	}
+	return ref
+}
+
+func (s *pluginStore) loadPendingNotifications() ([]notificationRef, error) {
+	refs := []notificationRef{}
+	err := kvstore.LoadJSON(s.notificationQueueKV, pendingNotificationsKey, &refs)
+	if err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	return refs, nil
+}
+
+func (s *pluginStore) modifyPendingNotifications(modify func(refs []notificationRef) ([]notificationRef, error)) error {
+	return modifyNotificationIndex(s.notificationQueueKV, pendingNotificationsKey, func(data []byte) ([]byte, error) {
+		refs := []notificationRef{}
+		if len(data) > 0 {
+			err := json.Unmarshal(data, &refs)
+			if err != nil {
+				return nil, err
+			}
+		}
+		updated, err := modify(refs)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(updated)
+	})
+}
+
+func (s *pluginStore) removePendingNotifications(ids ...string) error {
+	return s.modifyPendingNotifications(func(refs []notificationRef) ([]notificationRef, error) {
+		result := []notificationRef{}
+		for _, ref := range refs {
+			if !containsString(ids, ref.ID) {
+				result = append(result, ref)
+			}
+		}
+		return result, nil
+	})
+}
+
+func (s *pluginStore) modifyDeadLetterNotifications(modify func(ids []string) ([]string, error)) error {
+	return modifyNotificationIndex(s.notificationQueueKV, deadLetterNotificationsKey, func(data []byte) ([]byte, error) {
+		ids := []string{}
+		if len(data) > 0 {
+			err := json.Unmarshal(data, &ids)
+			if err != nil {
+				return nil, err
+			}
+		}
+		updated, err := modify(ids)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(updated)
+	})
+}
+
+func modifyNotificationIndex(kv kvstore.KVStore, key string, modify func(data []byte) ([]byte, error)) error {
+	return kvstore.AtomicModify(kv, key, func(initial []byte, storeErr error) ([]byte, error) {
+		if storeErr != nil && storeErr != ErrNotFound {
+			return initial, storeErr
+		}
+		return modify(initial)
+	})
+}
+
+func removeStrings(list []string, remove ...string) []string {
+	result := []string{}
+	for _, v := range list {
+		if !containsString(remove, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/testutil"
+)
+
+var (
+	mockNotificationKey      = mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, NotificationKeyPrefix) })
+	mockNotificationQueueKey = mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, NotificationQueueKeyPrefix) })
+)
+
+func mockQueuedNotificationJSON(t *testing.T, id string, nextAttemptAt time.Time) []byte {
+	data, err := json.Marshal(&QueuedNotification{
+		ID:            id,
+		Notification:  &remote.Notification{SubscriptionID: MockSubscriptionID},
+		NextAttemptAt: nextAttemptAt,
+	})
+	require.NoError(t, err)
+	return data
+}
+
+func TestEnqueueNotification(t *testing.T) {
+	fullQueue := make([]notificationRef, MaxQueuedNotifications)
+	fullQueueJSON, _ := json.Marshal(fullQueue)
+
+	tests := []struct {
+		name       string
+		setup      func(*testutil.MockPluginAPI)
+		assertions func(*testing.T, error)
+	}{
+		{
+			name: "Queue full",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVSet", mockNotificationKey, MockByteValue).Return(nil).Once()
+				mockAPI.On("KVGet", mockNotificationQueueKey).Return(fullQueueJSON, nil).Once()
+				mockAPI.On("KVDelete", mockNotificationKey).Return(nil).Once()
+			},
+			assertions: func(t *testing.T, err error) {
+				require.Equal(t, ErrNotificationQueueFull, err)
+			},
+		},
+		{
+			name: "Successful enqueue",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVSet", mockNotificationKey, MockByteValue).Return(nil).Once()
+				mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(`[{"ID":"other_id"}]`), nil).Once()
+				mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte(`[{"ID":"other_id"},{"ID":"notification_id","SubscriptionID":"mockSubscriptionID"}]`), mock.Anything).Return(true, nil).Once()
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			tt.setup(mockAPI)
+
+			err := store.EnqueueNotifications(&QueuedNotification{
+				ID:           "notification_id",
+				Notification: &remote.Notification{SubscriptionID: MockSubscriptionID},
+			})
+
+			tt.assertions(t, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLeaseNotification(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute).Unix()
+
+	tests := []struct {
+		name       string
+		setup      func(*testutil.MockPluginAPI)
+		assertions func(*testing.T, *QueuedNotification, error)
+	}{
+		{
+			name: "Empty queue",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", mockNotificationQueueKey).Return(nil, nil).Once()
+			},
+			assertions: func(t *testing.T, item *QueuedNotification, err error) {
+				require.Nil(t, item)
+				require.Equal(t, ErrNotFound, err)
+			},
+		},
+		{
+			name: "Leases the first notification due",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(fmt.Sprintf(`[{"ID":"later_id","SubscriptionID":"sub1","NextAttemptAt":%d},{"ID":"due_id","SubscriptionID":"sub2"}]`, later)), nil).Once()
+				mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte{1}, model.PluginKVSetOptions{
+					Atomic:          true,
+					ExpireInSeconds: 120,
+				}).Return(true, nil).Once()
+				mockAPI.On("KVGet", mockNotificationKey).Return(mockQueuedNotificationJSON(t, "due_id", time.Time{}), nil).Once()
+			},
+			assertions: func(t *testing.T, item *QueuedNotification, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "due_id", item.ID)
+				require.Equal(t, MockSubscriptionID, item.Notification.SubscriptionID)
+			},
+		},
+		{
+			name: "Keeps the notifications of a subscription in order",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(fmt.Sprintf(`[{"ID":"retried_id","SubscriptionID":"sub1","NextAttemptAt":%d},{"ID":"next_id","SubscriptionID":"sub1"}]`, later)), nil).Once()
+			},
+			assertions: func(t *testing.T, item *QueuedNotification, err error) {
+				require.Nil(t, item)
+				require.Equal(t, ErrNotFound, err)
+			},
+		},
+		{
+			name: "Waits for notifications leased by another node",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(`[{"ID":"leased_id","SubscriptionID":"sub1"},{"ID":"next_id","SubscriptionID":"sub1"}]`), nil).Once()
+				mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte{1}, mock.Anything).Return(false, nil).Once()
+			},
+			assertions: func(t *testing.T, item *QueuedNotification, err error) {
+				require.Nil(t, item)
+				require.Equal(t, ErrNotFound, err)
+			},
+		},
+		{
+			name: "Removes missing notifications from the queue",
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(`[{"ID":"missing_id"}]`), nil).Once()
+				mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte{1}, mock.Anything).Return(true, nil).Once()
+				mockAPI.On("KVGet", mockNotificationKey).Return(nil, nil).Once()
+				mockAPI.On("KVDelete", mockNotificationQueueKey).Return(nil).Once()
+				mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(`[{"ID":"missing_id"}]`), nil).Once()
+				mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte(`[]`), mock.Anything).Return(true, nil).Once()
+			},
+			assertions: func(t *testing.T, item *QueuedNotification, err error) {
+				require.Nil(t, item)
+				require.Equal(t, ErrNotFound, err)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI, store, _, _, _ := GetMockSetup(t)
+			tt.setup(mockAPI)
+
+			item, err := store.LeaseNotification(now, 2*time.Minute)
+
+			tt.assertions(t, item, err)
+			mockAPI.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRenewNotificationLease(t *testing.T) {
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+	mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte{1}, model.PluginKVSetOptions{
+		ExpireInSeconds: 120,
+	}).Return(true, nil).Once()
+
+	err := store.RenewNotificationLease("notification_id", 2*time.Minute)
+
+	require.NoError(t, err)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestDeadLetterNotification(t *testing.T) {
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+	mockAPI.On("KVSet", mockNotificationKey, MockByteValue).Return(nil).Once()
+	mockAPI.On("KVGet", mockNotificationQueueKey).Return(nil, nil).Once()
+	mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte(`["notification_id"]`), mock.Anything).Return(true, nil).Once()
+	mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(`[{"ID":"notification_id"},{"ID":"other_id"}]`), nil).Once()
+	mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte(`[{"ID":"other_id"}]`), mock.Anything).Return(true, nil).Once()
+	mockAPI.On("KVDelete", mockNotificationQueueKey).Return(nil).Once()
+
+	err := store.DeadLetterNotification(&QueuedNotification{
+		ID:           "notification_id",
+		Notification: &remote.Notification{SubscriptionID: MockSubscriptionID},
+		LastError:    "some error",
+	})
+
+	require.NoError(t, err)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterNotifications(t *testing.T) {
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+	mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(`["failed_id"]`), nil).Once()
+	mockAPI.On("KVGet", mockNotificationKey).Return(mockQueuedNotificationJSON(t, "failed_id", time.Now().Add(time.Hour)), nil).Once()
+	mockAPI.On("KVSet", mockNotificationKey, mock.MatchedBy(func(data []byte) bool {
+		item := QueuedNotification{}
+		return json.Unmarshal(data, &item) == nil && item.NextAttemptAt.IsZero()
+	})).Return(nil).Once()
+	mockAPI.On("KVGet", mockNotificationQueueKey).Return(nil, nil).Once()
+	mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte(`[{"ID":"failed_id","SubscriptionID":"mockSubscriptionID"}]`), mock.Anything).Return(true, nil).Once()
+	mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(`["failed_id"]`), nil).Once()
+	mockAPI.On("KVSetWithOptions", mockNotificationQueueKey, []byte(`[]`), mock.Anything).Return(true, nil).Once()
+
+	replayed, err := store.ReplayDeadLetterNotifications()
+
+	require.NoError(t, err)
+	require.Equal(t, 1, replayed)
+	mockAPI.AssertExpectations(t)
+}
+
+func TestReplayDeadLetterNotificationsQueueFull(t *testing.T) {
+	fullQueue := make([]notificationRef, MaxQueuedNotifications)
+	fullQueueJSON, _ := json.Marshal(fullQueue)
+	nextAttemptAt := time.Now().Add(time.Hour)
+
+	mockAPI, store, _, _, _ := GetMockSetup(t)
+	mockAPI.On("KVGet", mockNotificationQueueKey).Return([]byte(`["failed_id"]`), nil).Once()
+	mockAPI.On("KVGet", mockNotificationKey).Return(mockQueuedNotificationJSON(t, "failed_id", nextAttemptAt), nil).Once()
+	mockAPI.On("KVSet", mockNotificationKey, mock.MatchedBy(func(data []byte) bool {
+		item := QueuedNotification{}
+		return json.Unmarshal(data, &item) == nil && item.NextAttemptAt.IsZero()
+	})).Return(nil).Once()
+	mockAPI.On("KVGet", mockNotificationQueueKey).Return(fullQueueJSON, nil).Once()
+	mockAPI.On("KVDelete", mockNotificationKey).Return(nil).Once()
+	// The dead letter is written back, and stays in the dead letters
+	mockAPI.On("KVSet", mockNotificationKey, mock.MatchedBy(func(data []byte) bool {
+		item := QueuedNotification{}
+		return json.Unmarshal(data, &item) == nil && item.ID == "failed_id" && item.NextAttemptAt.Equal(nextAttemptAt)
+	})).Return(nil).Once()
+
+	replayed, err := store.ReplayDeadLetterNotifications()
+
+	require.Equal(t, ErrNotificationQueueFull, err)
+	require.Equal(t, 0, replayed)
+	mockAPI.AssertExpectations(t)
+	mockAPI.AssertNotCalled(t, "KVSetWithOptions", mockNotificationQueueKey, []byte(`[]`), mock.Anything)
+}
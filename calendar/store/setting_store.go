@@ -14,6 +14,10 @@ const (
 	SetCustomStatusSettingID         = "set_custom_status"
 	ReceiveRemindersSettingID        = "get_reminders"
 	DailySummarySettingID            = "summary_setting"
+	CalendarsSettingID               = "calendars"
+	WorkingHoursStatusSettingID      = "working_hours_status"
+	ShowAutomaticReplySettingID      = "show_automatic_reply"
+	NotificationDigestSettingID      = "notification_digest"
 )
 
 func (s *pluginStore) SetSetting(userID, settingID string, value interface{}) error {
@@ -53,6 +57,30 @@ func (s *pluginStore) SetSetting(userID, settingID string, value interface{}) er
 		user.Settings.ReceiveReminders = storableValue
 	case DailySummarySettingID:
 		s.updateDailySummarySettingForUser(user, value)
+	case CalendarsSettingID:
+		storableValue, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("cannot read value %v for setting %s (expecting string)", value, settingID)
+		}
+		user.Settings.CalendarIDs = toggleCalendarID(user.Settings.CalendarIDs, storableValue)
+	case WorkingHoursStatusSettingID:
+		storableValue, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("cannot read value %v for setting %s (expecting string)", value, settingID)
+		}
+		user.Settings.WorkingHoursStatus = storableValue
+	case ShowAutomaticReplySettingID:
+		storableValue, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("cannot read value %v for setting %s (expecting bool)", value, settingID)
+		}
+		user.Settings.ShowAutomaticReply = storableValue
+	case NotificationDigestSettingID:
+		storableValue, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("cannot read value %v for setting %s (expecting string)", value, settingID)
+		}
+		user.Settings.NotificationDigest = storableValue
 	default:
 		return fmt.Errorf("setting %s not found", settingID)
 	}
@@ -83,6 +111,17 @@ func (s *pluginStore) GetSetting(userID, settingID string) (interface{}, error)
 	case DailySummarySettingID:
 		dsum := user.Settings.DailySummary
 		return dsum, nil
+	case CalendarsSettingID:
+		return user.Settings.CalendarIDs, nil
+	case WorkingHoursStatusSettingID:
+		return user.Settings.WorkingHoursStatus, nil
+	case ShowAutomaticReplySettingID:
+		return user.Settings.ShowAutomaticReply, nil
+	case NotificationDigestSettingID:
+		if user.Settings.NotificationDigest == "" {
+			return NotificationDigestImmediatelyOption, nil
+		}
+		return user.Settings.NotificationDigest, nil
 	default:
 		return nil, fmt.Errorf("setting %s not found", settingID)
 	}
@@ -97,6 +136,28 @@ func DefaultDailySummaryUserSettings() *DailySummaryUserSettings {
 	}
 }
 
+// toggleCalendarID adds the calendar to the selection, or removes it when
+// already selected. An empty ID clears the selection, going back to the
+// default calendar.
+func toggleCalendarID(calendarIDs []string, calendarID string) []string {
+	if calendarID == "" {
+		return nil
+	}
+	result := []string{}
+	for _, id := range calendarIDs {
+		if id != calendarID {
+			result = append(result, id)
+		}
+	}
+	if len(result) == len(calendarIDs) {
+		result = append(result, calendarID)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
 func (s *pluginStore) updateDailySummarySettingForUser(user *User, value interface{}) error {
 	if user.Settings.DailySummary == nil {
 		user.Settings.DailySummary = DefaultDailySummaryUserSettings()
@@ -138,6 +138,59 @@ func TestSetSetting(t *testing.T) {
 				require.NoError(t, err)
 			},
 		},
+		{
+			name:      "error setting WorkingHoursStatusSettingID",
+			settingID: WorkingHoursStatusSettingID,
+			value:     true,
+			setup: func(mockAPI *testutil.MockPluginAPI, _ *mock_tracker.MockTracker) {
+				mockAPI.On("KVGet", "user_ed8ba8dcdc37081824b09b84f8e061e6").Return(mockUserJSON, nil).Times(1)
+			},
+			assertions: func(t *testing.T, err error) {
+				require.EqualError(t, err, "cannot read value true for setting working_hours_status (expecting string)")
+			},
+		},
+		{
+			name:      "Set WorkingHoursStatusSettingID",
+			settingID: WorkingHoursStatusSettingID,
+			value:     OfflineStatusOption,
+			setup: func(mockAPI *testutil.MockPluginAPI, mockTracker *mock_tracker.MockTracker) {
+				mockAPI.On("KVGet", "user_ed8ba8dcdc37081824b09b84f8e061e6").Return(mockUserJSON, nil).Times(1)
+				mockAPI.On("KVSet", "user_c3b5020d58a049787bc969768465b890", mock.Anything).Return(nil).Times(1)
+				mockAPI.On("KVSet", "mmuid_e138a0f218087f9324d8c77f87d5f3a0", mock.Anything).Return(nil).Times(1)
+				mockTracker.EXPECT().TrackAutomaticStatusUpdate(MockUserID, "available", "settings").Times(1)
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:      "Set ShowAutomaticReplySettingID",
+			settingID: ShowAutomaticReplySettingID,
+			value:     true,
+			setup: func(mockAPI *testutil.MockPluginAPI, mockTracker *mock_tracker.MockTracker) {
+				mockAPI.On("KVGet", "user_ed8ba8dcdc37081824b09b84f8e061e6").Return(mockUserJSON, nil).Times(1)
+				mockAPI.On("KVSet", "user_c3b5020d58a049787bc969768465b890", mock.Anything).Return(nil).Times(1)
+				mockAPI.On("KVSet", "mmuid_e138a0f218087f9324d8c77f87d5f3a0", mock.Anything).Return(nil).Times(1)
+				mockTracker.EXPECT().TrackAutomaticStatusUpdate(MockUserID, "available", "settings").Times(1)
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:      "Set NotificationDigestSettingID",
+			settingID: NotificationDigestSettingID,
+			value:     NotificationDigestHourlyOption,
+			setup: func(mockAPI *testutil.MockPluginAPI, mockTracker *mock_tracker.MockTracker) {
+				mockAPI.On("KVGet", "user_ed8ba8dcdc37081824b09b84f8e061e6").Return(mockUserJSON, nil).Times(1)
+				mockAPI.On("KVSet", "user_c3b5020d58a049787bc969768465b890", mock.Anything).Return(nil).Times(1)
+				mockAPI.On("KVSet", "mmuid_e138a0f218087f9324d8c77f87d5f3a0", mock.Anything).Return(nil).Times(1)
+				mockTracker.EXPECT().TrackAutomaticStatusUpdate(MockUserID, "available", "settings").Times(1)
+			},
+			assertions: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
 		{
 			name:      "Set DailySummarySettingID",
 			settingID: DailySummarySettingID,
@@ -269,6 +322,39 @@ func TestGetSetting(t *testing.T) {
 				require.Equal(t, &DailySummaryUserSettings{PostTime: "10:00AM"}, setting)
 			},
 		},
+		{
+			name:      "Get Calendars",
+			settingID: CalendarsSettingID,
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", "user_ed8ba8dcdc37081824b09b84f8e061e6").Return(mockUserJSON, nil).Times(1)
+			},
+			assertions: func(t *testing.T, setting interface{}, err error) {
+				require.NoError(t, err)
+				require.Equal(t, []string{"mockCalendarID"}, setting)
+			},
+		},
+		{
+			name:      "Get WorkingHoursStatus",
+			settingID: WorkingHoursStatusSettingID,
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", "user_ed8ba8dcdc37081824b09b84f8e061e6").Return(mockUserJSON, nil).Times(1)
+			},
+			assertions: func(t *testing.T, setting interface{}, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "", setting)
+			},
+		},
+		{
+			name:      "Get NotificationDigest defaults to immediately",
+			settingID: NotificationDigestSettingID,
+			setup: func(mockAPI *testutil.MockPluginAPI) {
+				mockAPI.On("KVGet", "user_ed8ba8dcdc37081824b09b84f8e061e6").Return(mockUserJSON, nil).Times(1)
+			},
+			assertions: func(t *testing.T, setting interface{}, err error) {
+				require.NoError(t, err)
+				require.Equal(t, NotificationDigestImmediatelyOption, setting)
+			},
+		},
 		{
 			name:      "invalid settingID",
 			settingID: "invalidSettingID",
@@ -294,6 +380,50 @@ func TestGetSetting(t *testing.T) {
 	}
 }
 
+func TestToggleCalendarID(t *testing.T) {
+	tests := []struct {
+		name        string
+		calendarIDs []string
+		calendarID  string
+		expected    []string
+	}{
+		{
+			name:       "select a first calendar",
+			calendarID: "a",
+			expected:   []string{"a"},
+		},
+		{
+			name:        "select another calendar",
+			calendarIDs: []string{"a"},
+			calendarID:  "b",
+			expected:    []string{"a", "b"},
+		},
+		{
+			name:        "unselect a calendar",
+			calendarIDs: []string{"a", "b"},
+			calendarID:  "a",
+			expected:    []string{"b"},
+		},
+		{
+			name:        "unselect the last calendar",
+			calendarIDs: []string{"a"},
+			calendarID:  "a",
+			expected:    nil,
+		},
+		{
+			name:        "go back to the default calendar",
+			calendarIDs: []string{"a", "b"},
+			calendarID:  "",
+			expected:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, toggleCalendarID(tt.calendarIDs, tt.calendarID))
+		})
+	}
+}
+
 func TestDefaultDailySummaryUserSettings(t *testing.T) {
 	dailySummaryUserSettings := DefaultDailySummaryUserSettings()
 
@@ -16,15 +16,20 @@ import (
 )
 
 const (
-	UserKeyPrefix             = "user_"
-	UserIndexKeyPrefix        = "userindex_"
-	MattermostUserIDKeyPrefix = "mmuid_"
-	OAuth2KeyPrefix           = "oauth2_"
-	SubscriptionKeyPrefix     = "sub_"
-	EventKeyPrefix            = "ev_"
-	WelcomeKeyPrefix          = "welcome_"
-	SettingsPanelPrefix       = "settings_panel_"
-	CacheKeyPrefix            = "cache_"
+	UserKeyPrefix              = "user_"
+	UserIndexKeyPrefix         = "userindex_"
+	MattermostUserIDKeyPrefix  = "mmuid_"
+	OAuth2KeyPrefix            = "oauth2_"
+	SubscriptionKeyPrefix      = "sub_"
+	EventKeyPrefix             = "ev_"
+	WelcomeKeyPrefix           = "welcome_"
+	SettingsPanelPrefix        = "settings_panel_"
+	CacheKeyPrefix             = "cache_"
+	EventMirrorKeyPrefix       = "mirror_"
+	CertificateKeyPrefix       = "cert_"
+	NotificationKeyPrefix      = "notif_"
+	NotificationQueueKeyPrefix = "notifq_"
+	DigestKeyPrefix            = "digest_"
 )
 
 const OAuth2KeyExpiration = 15 * time.Minute
@@ -36,6 +41,10 @@ type Store interface {
 	OAuth2StateStore
 	SubscriptionStore
 	EventStore
+	EventMirrorStore
+	CertificateStore
+	NotificationQueueStore
+	NotificationDigestStore
 	WelcomeStore
 	flow.Store
 	settingspanel.SettingStore
@@ -43,42 +52,62 @@ type Store interface {
 }
 
 type pluginStore struct {
-	basicKV            kvstore.KVStore
-	oauth2KV           kvstore.KVStore
-	userKV             kvstore.KVStore
-	mattermostUserIDKV kvstore.KVStore
-	userIndexKV        kvstore.KVStore
-	subscriptionKV     kvstore.KVStore
-	eventKV            kvstore.KVStore
-	welcomeIndexKV     kvstore.KVStore
-	settingsPanelKV    kvstore.KVStore
-	Logger             bot.Logger
-	Poster             bot.Poster
-	Tracker            tracker.Tracker
+	basicKV              kvstore.KVStore
+	oauth2KV             kvstore.KVStore
+	userKV               kvstore.KVStore
+	mattermostUserIDKV   kvstore.KVStore
+	userIndexKV          kvstore.KVStore
+	subscriptionKV       kvstore.KVStore
+	eventKV              kvstore.KVStore
+	eventMirrorKV        kvstore.KVStore
+	certificateKV        kvstore.KVStore
+	notificationKV       kvstore.KVStore
+	notificationQueueKV  kvstore.KVStore
+	notificationDigestKV kvstore.KVStore
+	welcomeIndexKV       kvstore.KVStore
+	settingsPanelKV      kvstore.KVStore
+	Logger               bot.Logger
+	Poster               bot.Poster
+	Tracker              tracker.Tracker
 }
 
 func NewPluginStore(api plugin.API, logger bot.Logger, poster bot.Poster, tracker tracker.Tracker, enableEncryption bool, encryptionKey []byte) Store {
 	basicKV := kvstore.NewPluginStore(api)
 	oauth2KV := kvstore.NewHashedKeyStore(kvstore.NewOneTimePluginStore(api, OAuth2KeyExpiration), OAuth2KeyPrefix)
 	user2KV := kvstore.NewHashedKeyStore(basicKV, UserKeyPrefix)
+	eventMirrorKV := kvstore.NewHashedKeyStore(basicKV, EventMirrorKeyPrefix)
+	certificateKV := kvstore.NewHashedKeyStore(basicKV, CertificateKeyPrefix)
+	notificationDigestKV := kvstore.NewHashedKeyStore(basicKV, DigestKeyPrefix)
+	notificationKV := kvstore.NewHashedKeyStore(basicKV, NotificationKeyPrefix)
+	notificationQueueKV := kvstore.NewHashedKeyStore(basicKV, NotificationQueueKeyPrefix)
 
 	if enableEncryption {
 		oauth2KV = kvstore.NewEncryptedKeyStore(oauth2KV, encryptionKey)
 		user2KV = kvstore.NewEncryptedKeyStore(user2KV, encryptionKey)
+		eventMirrorKV = kvstore.NewEncryptedKeyStore(eventMirrorKV, encryptionKey)
+		certificateKV = kvstore.NewEncryptedKeyStore(certificateKV, encryptionKey)
+		notificationDigestKV = kvstore.NewEncryptedKeyStore(notificationDigestKV, encryptionKey)
+		notificationKV = kvstore.NewEncryptedKeyStore(notificationKV, encryptionKey)
+		notificationQueueKV = kvstore.NewEncryptedKeyStore(notificationQueueKV, encryptionKey)
 	}
 
 	return &pluginStore{
-		basicKV:            basicKV,
-		userKV:             user2KV,
-		userIndexKV:        kvstore.NewHashedKeyStore(basicKV, UserIndexKeyPrefix),
-		mattermostUserIDKV: kvstore.NewHashedKeyStore(basicKV, MattermostUserIDKeyPrefix),
-		subscriptionKV:     kvstore.NewHashedKeyStore(basicKV, SubscriptionKeyPrefix),
-		eventKV:            kvstore.NewHashedKeyStore(basicKV, EventKeyPrefix),
-		oauth2KV:           oauth2KV,
-		welcomeIndexKV:     kvstore.NewCacheStore(kvstore.NewHashedKeyStore(basicKV, WelcomeKeyPrefix)),
-		settingsPanelKV:    kvstore.NewCacheStore(kvstore.NewHashedKeyStore(basicKV, SettingsPanelPrefix)),
-		Logger:             logger,
-		Poster:             poster,
-		Tracker:            tracker,
+		basicKV:              basicKV,
+		userKV:               user2KV,
+		userIndexKV:          kvstore.NewHashedKeyStore(basicKV, UserIndexKeyPrefix),
+		mattermostUserIDKV:   kvstore.NewHashedKeyStore(basicKV, MattermostUserIDKeyPrefix),
+		subscriptionKV:       kvstore.NewHashedKeyStore(basicKV, SubscriptionKeyPrefix),
+		eventKV:              kvstore.NewHashedKeyStore(basicKV, EventKeyPrefix),
+		eventMirrorKV:        eventMirrorKV,
+		certificateKV:        certificateKV,
+		notificationKV:       notificationKV,
+		notificationQueueKV:  notificationQueueKV,
+		notificationDigestKV: notificationDigestKV,
+		oauth2KV:             oauth2KV,
+		welcomeIndexKV:       kvstore.NewCacheStore(kvstore.NewHashedKeyStore(basicKV, WelcomeKeyPrefix)),
+		settingsPanelKV:      kvstore.NewCacheStore(kvstore.NewHashedKeyStore(basicKV, SettingsPanelPrefix)),
+		Logger:               logger,
+		Poster:               poster,
+		Tracker:              tracker,
 	}
 }
@@ -110,6 +110,7 @@ func GetMockUserWithSettings() *User {
 			GetConfirmation:                   true,
 			ReceiveReminders:                  true,
 			SetCustomStatus:                   false,
+			CalendarIDs:                       []string{"mockCalendarID"},
 			UpdateStatus:                      false,
 			ReceiveNotificationsDuringMeeting: true,
 		},
@@ -87,7 +87,18 @@ type User struct {
 	WelcomeFlowStatus     WelcomeFlowStatus `json:"mattermostFlags,omitempty"`
 	ActiveEvents          []string          `json:"events"`
 	ChannelEvents         ChannelEventLink  `json:"linkedEvents,omitempty"`
-	IsCustomStatusSet     bool
+	// Delegates are the Mattermost users the calendar of the user is shared
+	// with or delegated to, who receive its new invitations.
+	Delegates         []string `json:"delegates,omitempty"`
+	IsCustomStatusSet bool
+	// MailboxSettings are the working hours and automatic replies of the
+	// remote mailbox, read again once MailboxSettingsUpdatedAt is outdated.
+	MailboxSettings          *remote.MailboxSettings `json:"mailboxSettings,omitempty"`
+	MailboxSettingsUpdatedAt int64                   `json:"mailboxSettingsUpdatedAt,omitempty"`
+	// IsWorkingHoursStatusSet tells whether the status of the user was set
+	// at the end of their working hours, to be set back to online at the
+	// start of the next ones.
+	IsWorkingHoursStatusSet bool `json:"isWorkingHoursStatusSet,omitempty"`
 }
 
 var DefaultSettings = Settings{
@@ -101,6 +112,19 @@ type Settings struct {
 	GetConfirmation         bool
 	ReceiveReminders        bool
 	SetCustomStatus         bool
+	// ShowAutomaticReply adds the internal automatic reply to the custom
+	// status set while the user is out of office.
+	ShowAutomaticReply bool
+	// CalendarIDs are the calendars used for status sync, reminders and the
+	// daily summary. The default calendar is used when empty.
+	CalendarIDs []string
+	// WorkingHoursStatus is the status set outside working hours, during
+	// which no meeting status is set and no reminder is sent.
+	WorkingHoursStatus string
+	// NotificationDigest is how often the event notifications are posted
+	// together, one of the NotificationDigest options. They are posted as
+	// they come when empty.
+	NotificationDigest string `json:",omitempty"`
 
 	// Legacy settings
 	UpdateStatus                      bool
@@ -120,11 +144,34 @@ type WelcomeFlowStatus struct {
 }
 
 const (
-	AwayStatusOption   = "Away"
-	DNDStatusOption    = "Do Not Disturb"
-	NotSetStatusOption = "Don't set status for me"
+	AwayStatusOption    = "Away"
+	DNDStatusOption     = "Do Not Disturb"
+	NotSetStatusOption  = "Don't set status for me"
+	OfflineStatusOption = "Offline"
 )
 
+const (
+	NotificationDigestImmediatelyOption = "Immediately"
+	NotificationDigestHourlyOption      = "Every hour"
+	NotificationDigestFourHoursOption   = "Every 4 hours"
+	NotificationDigestDailyOption       = "Once a day"
+)
+
+// NotificationDigestInterval returns how long the event notifications of the
+// user are buffered, or 0 when they are posted as they come.
+func (settings Settings) NotificationDigestInterval() time.Duration {
+	switch settings.NotificationDigest {
+	case NotificationDigestHourlyOption:
+		return time.Hour
+	case NotificationDigestFourHoursOption:
+		return 4 * time.Hour
+	case NotificationDigestDailyOption:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
 func (settings Settings) String() string {
 	sub := "no subscription"
 	if settings.EventSubscriptionID != "" {
@@ -532,3 +579,40 @@ func (user *User) IsConfiguredForStatusUpdates() bool {
 func (user *User) IsConfiguredForCustomStatusUpdates() bool {
 	return user.Settings.SetCustomStatus
 }
+
+func (user *User) IsConfiguredForWorkingHours() bool {
+	return user.Settings.WorkingHoursStatus == AwayStatusOption || user.Settings.WorkingHoursStatus == OfflineStatusOption
+}
+
+func (user *User) IsDelegate(mattermostUserID string) bool {
+	for _, id := range user.Delegates {
+		if id == mattermostUserID {
+			return true
+		}
+	}
+	return false
+}
+
+// AddDelegate returns false if the user was already a delegate.
+func (user *User) AddDelegate(mattermostUserID string) bool {
+	if user.IsDelegate(mattermostUserID) {
+		return false
+	}
+	user.Delegates = append(user.Delegates, mattermostUserID)
+	return true
+}
+
+// RemoveDelegate returns false if the user was not a delegate.
+func (user *User) RemoveDelegate(mattermostUserID string) bool {
+	delegates := []string{}
+	for _, id := range user.Delegates {
+		if id != mattermostUserID {
+			delegates = append(delegates, id)
+		}
+	}
+	if len(delegates) == len(user.Delegates) {
+		return false
+	}
+	user.Delegates = delegates
+	return true
+}
@@ -1010,6 +1010,22 @@ func TestIsConfiguredForCustomStatusUpdates(t *testing.T) {
 	}
 }
 
+func TestUserDelegates(t *testing.T) {
+	user := &User{}
+	require.False(t, user.IsDelegate("assistant"))
+
+	require.True(t, user.AddDelegate("assistant"))
+	require.False(t, user.AddDelegate("assistant"))
+	require.True(t, user.AddDelegate("deputy"))
+	require.True(t, user.IsDelegate("assistant"))
+	require.Equal(t, []string{"assistant", "deputy"}, user.Delegates)
+
+	require.True(t, user.RemoveDelegate("assistant"))
+	require.False(t, user.RemoveDelegate("assistant"))
+	require.False(t, user.IsDelegate("assistant"))
+	require.Equal(t, []string{"deputy"}, user.Delegates)
+}
+
 func TestDisconnectUserFromStoreIfNecessary(t *testing.T) {
 	const (
 		userKey   = "user_c3b5020d58a049787bc969768465b890"
@@ -0,0 +1,74 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+)
+
+const keyBits = 2048
+
+// Certificate is a self-signed certificate with its RSA private key. Both are
+// kept in DER so that the certificate can be stored as JSON.
+type Certificate struct {
+	ID         string
+	Raw        []byte
+	PrivateKey []byte
+	NotAfter   time.Time
+}
+
+// New creates a self-signed certificate valid from now for the given
+// duration.
+func New(commonName string, now time.Time, validity time.Duration) (*Certificate, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the certificate key")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the certificate serial number")
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment,
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the certificate")
+	}
+
+	return &Certificate{
+		ID:         model.NewId(),
+		Raw:        raw,
+		PrivateKey: x509.MarshalPKCS1PrivateKey(key),
+		NotAfter:   template.NotAfter,
+	}, nil
+}
+
+// Key returns the private key of the certificate.
+func (c *Certificate) Key() (*rsa.PrivateKey, error) {
+	key, err := x509.ParsePKCS1PrivateKey(c.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid certificate key")
+	}
+	return key, nil
+}
+
+// Encoded returns the certificate in base64-encoded DER.
+func (c *Certificate) Encoded() string {
+	return base64.StdEncoding.EncodeToString(c.Raw)
+}
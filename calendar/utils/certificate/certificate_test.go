@@ -0,0 +1,31 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package certificate
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	c, err := New("mscalendar", now, 24*time.Hour)
+	require.NoError(t, err)
+	require.NotEmpty(t, c.ID)
+	require.Equal(t, now.Add(24*time.Hour), c.NotAfter)
+
+	raw, err := base64.StdEncoding.DecodeString(c.Encoded())
+	require.NoError(t, err)
+	parsed, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+	require.Equal(t, "mscalendar", parsed.Subject.CommonName)
+
+	key, err := c.Key()
+	require.NoError(t, err)
+	require.True(t, key.PublicKey.Equal(parsed.PublicKey))
+}
@@ -0,0 +1,440 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const (
+	ProdID = "-//Mattermost//Mattermost Calendar Plugin//EN"
+
+	PartStatNeedsAction = "NEEDS-ACTION"
+	PartStatAccepted    = "ACCEPTED"
+	PartStatDeclined    = "DECLINED"
+	PartStatTentative   = "TENTATIVE"
+
+	mailtoPrefix = "mailto:"
+)
+
+var partStatToResponse = map[string]string{
+	PartStatNeedsAction: remote.EventResponseStatusNotAnswered,
+	PartStatAccepted:    remote.EventResponseStatusAccepted,
+	PartStatDeclined:    remote.EventResponseStatusDeclined,
+	PartStatTentative:   remote.EventResponseStatusTentative,
+}
+
+// PartStat returns the PARTSTAT value matching a remote response status.
+func PartStat(response string) string {
+	for partStat, r := range partStatToResponse {
+		if r == response {
+			return partStat
+		}
+	}
+	return PartStatNeedsAction
+}
+
+// Address returns the e-mail address of a cal-address value such as
+// "mailto:alice@example.com".
+func Address(calAddress string) string {
+	if len(calAddress) >= len(mailtoPrefix) && strings.EqualFold(calAddress[:len(mailtoPrefix)], mailtoPrefix) {
+		return calAddress[len(mailtoPrefix):]
+	}
+	return calAddress
+}
+
+// ToEvent converts a VEVENT into a remote event as seen by the owner of the
+// calendar it was read from, identified by their calendar addresses.
+func ToEvent(vevent *Component, loc *time.Location, ownerAddresses ...string) (*remote.Event, error) {
+	start, allDay, err := ParseTime(vevent.Prop("DTSTART"), loc)
+	if err != nil {
+		return nil, err
+	}
+
+	end := start
+	switch {
+	case vevent.Prop("DTEND") != nil:
+		end, _, err = ParseTime(vevent.Prop("DTEND"), loc)
+		if err != nil {
+			return nil, err
+		}
+	case vevent.Prop("DURATION") != nil:
+		d, err := ParseDuration(vevent.Prop("DURATION").Value)
+		if err != nil {
+			return nil, err
+		}
+		end = start.Add(d)
+	case allDay:
+		end = start.AddDate(0, 0, 1)
+	}
+
+	uid := vevent.Text("UID")
+	e := &remote.Event{
+		ID:          uid,
+		ICalUID:     uid,
+		Subject:     vevent.Text("SUMMARY"),
+		Start:       remote.NewDateTime(start.UTC(), "UTC"),
+		End:         remote.NewDateTime(end.UTC(), "UTC"),
+		IsAllDay:    allDay,
+		IsCancelled: strings.EqualFold(vevent.Text("STATUS"), "CANCELLED"),
+		Location:    &remote.Location{DisplayName: vevent.Text("LOCATION")},
+		Weblink:     vevent.Text("URL"),
+		Importance:  importance(vevent.Text("PRIORITY")),
+		Sensitivity: sensitivity(vevent.Text("CLASS")),
+		ShowAs:      "busy",
+	}
+
+	switch rrule := vevent.Prop("RRULE"); {
+	case vevent.Prop("RECURRENCE-ID") != nil:
+		// Servers expanding recurring events return every occurrence with a
+		// RECURRENCE-ID, so modified occurrences can't be told apart.
+		e.Type = remote.EventTypeOccurrence
+		e.SeriesMasterID = uid
+	case rrule != nil:
+		e.Type = remote.EventTypeSeriesMaster
+		if r, err := ParseRecurrence(rrule.Value, start.Location()); err == nil {
+			e.Recurrence = r.ToRemote(start)
+		}
+	case vevent.Prop("RDATE") != nil:
+		e.Type = remote.EventTypeSeriesMaster
+	default:
+		e.Type = remote.EventTypeSingleInstance
+	}
+
+	if description := vevent.Text("DESCRIPTION"); description != "" {
+		e.Body = &remote.ItemBody{Content: description, ContentType: "text"}
+		e.BodyPreview = description
+	}
+
+	switch {
+	case strings.EqualFold(vevent.Text("TRANSP"), "TRANSPARENT"):
+		e.ShowAs = "free"
+	case strings.EqualFold(vevent.Text("STATUS"), "TENTATIVE"):
+		e.ShowAs = "tentative"
+	}
+
+	for _, alarm := range vevent.ChildrenNamed(CompAlarm) {
+		trigger := alarm.Prop("TRIGGER")
+		if trigger == nil || strings.EqualFold(trigger.Param("VALUE"), "DATE-TIME") {
+			continue
+		}
+		d, err := ParseDuration(trigger.Value)
+		if err != nil || d > 0 {
+			continue
+		}
+		e.ReminderMinutesBeforeStart = int(-d / time.Minute)
+		break
+	}
+
+	if p := vevent.Prop("ORGANIZER"); p != nil {
+		e.Organizer = attendee(p)
+		e.IsOrganizer = isOwner(e.Organizer.EmailAddress.Address, ownerAddresses)
+	}
+
+	for _, p := range vevent.PropsNamed("ATTENDEE") {
+		a := attendee(p)
+		e.Attendees = append(e.Attendees, a)
+		if !e.IsOrganizer && isOwner(a.EmailAddress.Address, ownerAddresses) {
+			e.ResponseStatus = a.Status
+			e.ResponseRequested = true
+			if a.Status.Response == remote.EventResponseStatusNotAnswered || a.Status.Response == remote.EventResponseStatusTentative {
+				e.ShowAs = "tentative"
+			}
+			if a.Status.Response == remote.EventResponseStatusDeclined {
+				e.ShowAs = "free"
+			}
+		}
+	}
+
+	if e.ResponseStatus == nil {
+		e.ResponseStatus = &remote.EventResponseStatus{Response: remote.EventResponseStatusAccepted}
+	}
+	if e.Organizer == nil {
+		e.IsOrganizer = true
+	}
+
+	return e, nil
+}
+
+func attendee(p *Property) *remote.Attendee {
+	a := &remote.Attendee{
+		EmailAddress: &remote.EmailAddress{
+			Address: Address(p.Value),
+			Name:    p.Param("CN"),
+		},
+		Status: &remote.EventResponseStatus{
+			Response: remote.EventResponseStatusNotAnswered,
+		},
+		Type: "required",
+	}
+	if response, ok := partStatToResponse[strings.ToUpper(p.Param("PARTSTAT"))]; ok {
+		a.Status.Response = response
+	}
+	switch strings.ToUpper(p.Param("CUTYPE")) {
+	case "RESOURCE", "ROOM":
+		a.Type = "resource"
+	default:
+		if strings.EqualFold(p.Param("ROLE"), "OPT-PARTICIPANT") {
+			a.Type = "optional"
+		}
+	}
+	return a
+}
+
+func isOwner(address string, ownerAddresses []string) bool {
+	for _, owner := range ownerAddresses {
+		if strings.EqualFold(Address(owner), address) {
+			return true
+		}
+	}
+	return false
+}
+
+// importance maps the PRIORITY property (1 highest, 9 lowest, 0 undefined).
+func importance(priority string) string {
+	n, err := strconv.Atoi(priority)
+	switch {
+	case err != nil || n == 0 || n == 5:
+		return "normal"
+	case n < 5:
+		return "high"
+	default:
+		return "low"
+	}
+}
+
+// sensitivity maps the CLASS property, PUBLIC by default.
+func sensitivity(class string) string {
+	switch strings.ToUpper(class) {
+	case "PRIVATE":
+		return remote.SensitivityPrivate
+	case "CONFIDENTIAL":
+		return remote.SensitivityConfidential
+	default:
+		return remote.SensitivityNormal
+	}
+}
+
+// class returns the CLASS value matching a remote sensitivity, or "" for
+// public events.
+func class(sensitivity string) string {
+	switch sensitivity {
+	case remote.SensitivityPrivate:
+		return "PRIVATE"
+	case remote.SensitivityConfidential:
+		return "CONFIDENTIAL"
+	default:
+		return ""
+	}
+}
+
+// FromEvent builds a VCALENDAR holding a single VEVENT for the given event,
+// organized by the given address.
+func FromEvent(e *remote.Event, uid, organizerAddress string) (*Component, error) {
+	if e.Start == nil || e.End == nil {
+		return nil, errors.New("event start and end are required")
+	}
+	start := e.Start.Time()
+	end := e.End.Time()
+	if start.IsZero() || end.IsZero() {
+		return nil, errors.New("invalid event start or end")
+	}
+
+	vevent := NewComponent(CompEvent)
+	vevent.Add(NewProperty("UID", EscapeText(uid)))
+	vevent.Add(NewProperty("DTSTAMP", time.Now().UTC().Format(UTCDateTimeFormat)))
+	if e.Recurrence != nil {
+		// Recurring events keep their wall clock time across daylight saving
+		// time changes only when their start is in their own time zone.
+		r, err := RecurrenceFromRemote(e.Recurrence, start)
+		if err != nil {
+			return nil, err
+		}
+		vevent.Add(NewZonedTimeProperty("DTSTART", start, e.IsAllDay))
+		vevent.Add(NewZonedTimeProperty("DTEND", end, e.IsAllDay))
+		vevent.Add(NewProperty("RRULE", r.String()))
+	} else {
+		vevent.Add(NewTimeProperty("DTSTART", start, e.IsAllDay))
+		vevent.Add(NewTimeProperty("DTEND", end, e.IsAllDay))
+	}
+	vevent.Add(NewProperty("SUMMARY", EscapeText(e.Subject)))
+	if e.Body != nil && e.Body.Content != "" {
+		vevent.Add(NewProperty("DESCRIPTION", EscapeText(e.Body.Content)))
+	}
+	if e.Location != nil && e.Location.DisplayName != "" {
+		vevent.Add(NewProperty("LOCATION", EscapeText(e.Location.DisplayName)))
+	}
+	if e.ShowAs == "free" {
+		vevent.Add(NewProperty("TRANSP", "TRANSPARENT"))
+	}
+	switch e.Importance {
+	case "high":
+		vevent.Add(NewProperty("PRIORITY", "1"))
+	case "low":
+		vevent.Add(NewProperty("PRIORITY", "9"))
+	}
+	if c := class(e.Sensitivity); c != "" {
+		vevent.Add(NewProperty("CLASS", c))
+	}
+
+	if len(e.Attendees) > 0 && organizerAddress != "" {
+		vevent.Add(NewProperty("ORGANIZER", mailtoPrefix+organizerAddress))
+	}
+	for _, a := range e.Attendees {
+		if a.EmailAddress == nil || a.EmailAddress.Address == "" {
+			continue
+		}
+		p := NewProperty("ATTENDEE", mailtoPrefix+a.EmailAddress.Address)
+		if a.EmailAddress.Name != "" {
+			p.SetParam("CN", a.EmailAddress.Name)
+		}
+		switch a.Type {
+		case "resource":
+			p.SetParam("CUTYPE", "RESOURCE")
+		case "optional":
+			p.SetParam("ROLE", "OPT-PARTICIPANT")
+		default:
+			p.SetParam("ROLE", "REQ-PARTICIPANT")
+		}
+		p.SetParam("PARTSTAT", PartStatNeedsAction)
+		p.SetParam("RSVP", "TRUE")
+		vevent.Add(p)
+	}
+
+	if e.ReminderMinutesBeforeStart > 0 {
+		alarm := NewComponent(CompAlarm)
+		alarm.Add(NewProperty("ACTION", "DISPLAY"))
+		alarm.Add(NewProperty("DESCRIPTION", EscapeText(e.Subject)))
+		alarm.Add(NewProperty("TRIGGER", FormatDuration(-time.Duration(e.ReminderMinutesBeforeStart)*time.Minute)))
+		vevent.Children = append(vevent.Children, alarm)
+	}
+
+	cal := NewComponent(CompCalendar)
+	cal.Add(NewProperty("VERSION", "2.0"))
+	cal.Add(NewProperty("PRODID", ProdID))
+	cal.Children = append(cal.Children, vevent)
+	return cal, nil
+}
+
+// SetPartStat records the response of the attendee matching one of the given
+// addresses. It returns false if none of them is an attendee.
+func SetPartStat(vevent *Component, response string, addresses ...string) bool {
+	found := false
+	for _, p := range vevent.PropsNamed("ATTENDEE") {
+		if !isOwner(Address(p.Value), addresses) {
+			continue
+		}
+		p.SetParam("PARTSTAT", PartStat(response))
+		delete(p.Params, "RSVP")
+		found = true
+	}
+	return found
+}
+
+// SetReplyOptions sets the comment the server sends along with the reply of
+// an attendee, and whether it sends the reply to the organizer at all
+// (RFC 6638, section 7.1).
+func SetReplyOptions(vevent *Component, comment string, send bool) {
+	vevent.Remove("COMMENT")
+	if comment != "" {
+		vevent.Set(NewProperty("COMMENT", EscapeText(comment)))
+	}
+	organizer := vevent.Prop("ORGANIZER")
+	if organizer == nil {
+		return
+	}
+	if send {
+		delete(organizer.Params, "SCHEDULE-AGENT")
+	} else {
+		organizer.SetParam("SCHEDULE-AGENT", "CLIENT")
+	}
+}
+
+// UpdateEvent applies the fields set on the given event to the VEVENT, the
+// master event of a series for recurring events. The SEQUENCE is incremented
+// so that attendees take the update over their copy.
+func UpdateEvent(vevent *Component, e *remote.Event, loc *time.Location) error {
+	recurring := vevent.Prop("RRULE") != nil || e.Recurrence != nil
+	start, allDay, err := ParseTime(vevent.Prop("DTSTART"), loc)
+	if err != nil {
+		return err
+	}
+
+	if e.Start != nil && e.End != nil {
+		start, allDay = e.Start.Time(), e.IsAllDay
+		end := e.End.Time()
+		if start.IsZero() || end.IsZero() {
+			return errors.New("invalid event start or end")
+		}
+		newTimeProperty := NewTimeProperty
+		if recurring {
+			newTimeProperty = NewZonedTimeProperty
+		}
+		vevent.Remove("DURATION")
+		vevent.Set(newTimeProperty("DTSTART", start, allDay))
+		vevent.Set(newTimeProperty("DTEND", end, allDay))
+	}
+	if e.Recurrence != nil {
+		r, err := RecurrenceFromRemote(e.Recurrence, start)
+		if err != nil {
+			return err
+		}
+		vevent.Set(NewProperty("RRULE", r.String()))
+	}
+
+	if e.Subject != "" {
+		vevent.Set(NewProperty("SUMMARY", EscapeText(e.Subject)))
+	}
+	if e.Body != nil {
+		vevent.Remove("DESCRIPTION")
+		if e.Body.Content != "" {
+			vevent.Add(NewProperty("DESCRIPTION", EscapeText(e.Body.Content)))
+		}
+	}
+	if e.Location != nil {
+		vevent.Remove("LOCATION")
+		if e.Location.DisplayName != "" {
+			vevent.Add(NewProperty("LOCATION", EscapeText(e.Location.DisplayName)))
+		}
+	}
+	switch e.Importance {
+	case "high":
+		vevent.Set(NewProperty("PRIORITY", "1"))
+	case "low":
+		vevent.Set(NewProperty("PRIORITY", "9"))
+	case "normal":
+		vevent.Remove("PRIORITY")
+	}
+	if e.Sensitivity != "" {
+		vevent.Remove("CLASS")
+		if c := class(e.Sensitivity); c != "" {
+			vevent.Add(NewProperty("CLASS", c))
+		}
+	}
+
+	nextSequence(vevent)
+	return nil
+}
+
+// CancelEvent marks the VEVENT as cancelled, with an optional comment for the
+// attendees.
+func CancelEvent(vevent *Component, comment string) {
+	vevent.Set(NewProperty("STATUS", "CANCELLED"))
+	if comment != "" {
+		vevent.Set(NewProperty("COMMENT", EscapeText(comment)))
+	}
+	nextSequence(vevent)
+}
+
+func nextSequence(vevent *Component) {
+	sequence, _ := strconv.Atoi(vevent.Text("SEQUENCE"))
+	vevent.Set(NewProperty("SEQUENCE", strconv.Itoa(sequence+1)))
+	vevent.Set(NewProperty("DTSTAMP", time.Now().UTC().Format(UTCDateTimeFormat)))
+}
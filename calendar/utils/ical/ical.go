@@ -0,0 +1,362 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+// Package ical reads and writes the subset of iCalendar (RFC 5545) used by
+// the CalDAV and ICS calendar providers.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CompCalendar = "VCALENDAR"
+	CompEvent    = "VEVENT"
+	CompAlarm    = "VALARM"
+	CompTimezone = "VTIMEZONE"
+
+	maxLineOctets = 75
+)
+
+// Property is a single content line. Value holds the raw, still escaped value.
+type Property struct {
+	Name   string
+	Params map[string][]string
+	Value  string
+}
+
+// Component is a BEGIN/END block with its properties and nested components.
+type Component struct {
+	Name     string
+	Props    []*Property
+	Children []*Component
+}
+
+func NewProperty(name, value string) *Property {
+	return &Property{
+		Name:   strings.ToUpper(name),
+		Params: map[string][]string{},
+		Value:  value,
+	}
+}
+
+// Param returns the first value of the given parameter.
+func (p *Property) Param(name string) string {
+	values := p.Params[strings.ToUpper(name)]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (p *Property) SetParam(name, value string) {
+	if p.Params == nil {
+		p.Params = map[string][]string{}
+	}
+	p.Params[strings.ToUpper(name)] = []string{value}
+}
+
+// Text returns the unescaped value of a TEXT property.
+func (p *Property) Text() string {
+	return UnescapeText(p.Value)
+}
+
+func NewComponent(name string) *Component {
+	return &Component{Name: strings.ToUpper(name)}
+}
+
+// Prop returns the first property with the given name, or nil.
+func (c *Component) Prop(name string) *Property {
+	name = strings.ToUpper(name)
+	for _, p := range c.Props {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// PropsNamed returns all properties with the given name.
+func (c *Component) PropsNamed(name string) []*Property {
+	name = strings.ToUpper(name)
+	result := []*Property{}
+	for _, p := range c.Props {
+		if p.Name == name {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Text returns the unescaped value of the first property with the given
+// name, or an empty string.
+func (c *Component) Text(name string) string {
+	p := c.Prop(name)
+	if p == nil {
+		return ""
+	}
+	return p.Text()
+}
+
+// Set replaces all properties with the given name by a single one.
+func (c *Component) Set(p *Property) {
+	c.Remove(p.Name)
+	c.Props = append(c.Props, p)
+}
+
+func (c *Component) Add(p *Property) {
+	c.Props = append(c.Props, p)
+}
+
+func (c *Component) Remove(name string) {
+	name = strings.ToUpper(name)
+	props := c.Props[:0]
+	for _, p := range c.Props {
+		if p.Name != name {
+			props = append(props, p)
+		}
+	}
+	c.Props = props
+}
+
+// ChildrenNamed returns the nested components with the given name.
+func (c *Component) ChildrenNamed(name string) []*Component {
+	name = strings.ToUpper(name)
+	result := []*Component{}
+	for _, child := range c.Children {
+		if child.Name == name {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// Parse reads a single top-level component, usually a VCALENDAR.
+func Parse(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *Component
+	stack := []*Component{}
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", i+1)
+		}
+
+		switch p.Name {
+		case "BEGIN":
+			comp := NewComponent(p.Value)
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, comp)
+			} else if root != nil {
+				return nil, errors.Errorf("line %d: more than one top-level component", i+1)
+			} else {
+				root = comp
+			}
+			stack = append(stack, comp)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(p.Value) {
+				return nil, errors.Errorf("line %d: unexpected END:%s", i+1, p.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, errors.Errorf("line %d: property outside of a component", i+1)
+			}
+			comp := stack[len(stack)-1]
+			comp.Props = append(comp.Props, p)
+		}
+	}
+
+	if root == nil {
+		return nil, errors.New("no component found")
+	}
+	if len(stack) > 0 {
+		return nil, errors.Errorf("missing END:%s", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+// unfold joins continuation lines, which start with a space or a tab.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	lines := []string{}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read iCalendar data")
+	}
+	return lines, nil
+}
+
+func parseLine(line string) (*Property, error) {
+	p := &Property{Params: map[string][]string{}}
+
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return nil, errors.Errorf("invalid content line %q", line)
+	}
+	p.Name = strings.ToUpper(line[:end])
+	rest := line[end:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return nil, errors.Errorf("invalid parameter in %q", line)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		values := []string{}
+		for {
+			var value string
+			if strings.HasPrefix(rest, `"`) {
+				closing := strings.IndexByte(rest[1:], '"')
+				if closing < 0 {
+					return nil, errors.Errorf("unterminated quoted parameter in %q", line)
+				}
+				value = rest[1 : closing+1]
+				rest = rest[closing+2:]
+			} else {
+				stop := strings.IndexAny(rest, ",;:")
+				if stop < 0 {
+					return nil, errors.Errorf("invalid parameter in %q", line)
+				}
+				value = rest[:stop]
+				rest = rest[stop:]
+			}
+			values = append(values, value)
+			if !strings.HasPrefix(rest, ",") {
+				break
+			}
+			rest = rest[1:]
+		}
+		p.Params[name] = append(p.Params[name], values...)
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return nil, errors.Errorf("missing value in %q", line)
+	}
+	p.Value = rest[1:]
+	return p, nil
+}
+
+// Encode writes the component using CRLF line endings, folding lines longer
+// than 75 octets.
+func (c *Component) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	c.encode(bw)
+	return bw.Flush()
+}
+
+func (c *Component) String() string {
+	sb := &strings.Builder{}
+	_ = c.Encode(sb)
+	return sb.String()
+}
+
+func (c *Component) encode(w *bufio.Writer) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, p := range c.Props {
+		writeLine(w, p.line())
+	}
+	for _, child := range c.Children {
+		child.encode(w)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+func (p *Property) line() string {
+	sb := &strings.Builder{}
+	sb.WriteString(p.Name)
+
+	names := make([]string, 0, len(p.Params))
+	for name := range p.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString(";" + name + "=")
+		for i, value := range p.Params[name] {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			if strings.ContainsAny(value, ",;:") {
+				value = `"` + value + `"`
+			}
+			sb.WriteString(value)
+		}
+	}
+	sb.WriteString(":" + p.Value)
+	return sb.String()
+}
+
+func writeLine(w *bufio.Writer, line string) {
+	first := true
+	for len(line) > 0 {
+		limit := maxLineOctets
+		if !first {
+			// Continuation lines start with a space.
+			limit--
+			_, _ = w.WriteString(" ")
+		}
+		cut := len(line)
+		if cut > limit {
+			cut = limit
+			// Don't split multi-byte UTF-8 sequences.
+			for cut > 0 && line[cut]&0xC0 == 0x80 {
+				cut--
+			}
+		}
+		_, _ = w.WriteString(line[:cut] + "\r\n")
+		line = line[cut:]
+		first = false
+	}
+}
+
+var (
+	textEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	textUnescaper = strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	)
+)
+
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func UnescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
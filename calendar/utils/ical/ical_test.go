@@ -0,0 +1,167 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Test//Test//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-1@example.com\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20240115T090000\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"SUMMARY:Planning\\, Q1\r\n" +
+	"DESCRIPTION:First line\\nsecond line that is long enough to be folded by \r\n" +
+	" the producer\r\n" +
+	"PRIORITY:1\r\n" +
+	"ORGANIZER;CN=Alice:mailto:alice@example.com\r\n" +
+	"ATTENDEE;CN=\"Bob; Jr.\";PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:bob@example.com\r\n" +
+	"ATTENDEE;CUTYPE=ROOM;PARTSTAT=ACCEPTED:mailto:room@example.com\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	cal, err := Parse(strings.NewReader(testCalendar))
+	require.NoError(t, err)
+	require.Equal(t, CompCalendar, cal.Name)
+
+	events := cal.ChildrenNamed(CompEvent)
+	require.Len(t, events, 1)
+	vevent := events[0]
+
+	require.Equal(t, "Planning, Q1", vevent.Text("SUMMARY"))
+	require.Equal(t, "First line\nsecond line that is long enough to be folded by the producer", vevent.Text("DESCRIPTION"))
+	require.Equal(t, "Bob; Jr.", vevent.PropsNamed("ATTENDEE")[0].Param("CN"))
+	require.Len(t, vevent.ChildrenNamed(CompAlarm), 1)
+
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{name: "missing END", data: "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"},
+		{name: "mismatched END", data: "BEGIN:VCALENDAR\r\nEND:VEVENT\r\n"},
+		{name: "property outside component", data: "VERSION:2.0\r\n"},
+		{name: "invalid line", data: "BEGIN:VCALENDAR\r\nINVALID\r\nEND:VCALENDAR\r\n"},
+		{name: "empty", data: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.data))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	cal, err := Parse(strings.NewReader(testCalendar))
+	require.NoError(t, err)
+
+	encoded := cal.String()
+	for _, line := range strings.Split(encoded, "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineOctets)
+	}
+
+	again, err := Parse(strings.NewReader(encoded))
+	require.NoError(t, err)
+	require.Equal(t, cal, again)
+}
+
+func TestToEvent(t *testing.T) {
+	cal, err := Parse(strings.NewReader(testCalendar))
+	require.NoError(t, err)
+	vevent := cal.ChildrenNamed(CompEvent)[0]
+
+	e, err := ToEvent(vevent, time.UTC, "mailto:bob@example.com")
+	require.NoError(t, err)
+	require.Equal(t, "event-1@example.com", e.ICalUID)
+	require.Equal(t, "Planning, Q1", e.Subject)
+	require.Equal(t, "2024-01-15T08:00:00Z", e.Start.String())
+	require.Equal(t, "2024-01-15T09:30:00Z", e.End.String())
+	require.Equal(t, "high", e.Importance)
+	require.Equal(t, 15, e.ReminderMinutesBeforeStart)
+	require.False(t, e.IsOrganizer)
+	require.True(t, e.ResponseRequested)
+	require.Equal(t, remote.EventResponseStatusNotAnswered, e.ResponseStatus.Response)
+	require.Equal(t, "tentative", e.ShowAs)
+	require.Equal(t, "alice@example.com", e.Organizer.EmailAddress.Address)
+	require.Len(t, e.Attendees, 2)
+	require.Equal(t, "resource", e.Attendees[1].Type)
+	require.Equal(t, remote.SensitivityNormal, e.Sensitivity)
+
+	organizerView, err := ToEvent(vevent, time.UTC, "alice@example.com")
+	require.NoError(t, err)
+	require.True(t, organizerView.IsOrganizer)
+	require.False(t, organizerView.ResponseRequested)
+	require.Equal(t, "busy", organizerView.ShowAs)
+}
+
+func TestFromEventAndSetPartStat(t *testing.T) {
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	in := &remote.Event{
+		Subject:                    "Sync; weekly",
+		Start:                      remote.NewDateTime(start, "UTC"),
+		End:                        remote.NewDateTime(start.Add(time.Hour), "UTC"),
+		Body:                       &remote.ItemBody{Content: "Agenda:\n1. Status"},
+		Sensitivity:                remote.SensitivityPrivate,
+		ReminderMinutesBeforeStart: 10,
+		Attendees: []*remote.Attendee{{
+			EmailAddress: &remote.EmailAddress{Address: "bob@example.com", Name: "Bob"},
+		}},
+	}
+
+	cal, err := FromEvent(in, "uid-1", "alice@example.com")
+	require.NoError(t, err)
+
+	parsed, err := Parse(strings.NewReader(cal.String()))
+	require.NoError(t, err)
+	vevent := parsed.ChildrenNamed(CompEvent)[0]
+
+	require.True(t, SetPartStat(vevent, remote.EventResponseStatusAccepted, "BOB@example.com"))
+	require.False(t, SetPartStat(vevent, remote.EventResponseStatusAccepted, "carol@example.com"))
+
+	out, err := ToEvent(vevent, time.UTC, "bob@example.com")
+	require.NoError(t, err)
+	require.Equal(t, "uid-1", out.ID)
+	require.Equal(t, in.Subject, out.Subject)
+	require.Equal(t, in.Body.Content, out.Body.Content)
+	require.Equal(t, start, out.Start.Time())
+	require.Equal(t, 10, out.ReminderMinutesBeforeStart)
+	require.Equal(t, remote.EventResponseStatusAccepted, out.ResponseStatus.Response)
+	require.Equal(t, "busy", out.ShowAs)
+	require.Equal(t, remote.SensitivityPrivate, out.Sensitivity)
+
+	_, err = FromEvent(&remote.Event{Subject: "No time"}, "uid-2", "")
+	require.Error(t, err)
+}
+
+func TestParseDuration(t *testing.T) {
+	for in, expected := range map[string]time.Duration{
+		"PT15M":      15 * time.Minute,
+		"-PT1H":      -time.Hour,
+		"P1D":        24 * time.Hour,
+		"P1W":        7 * 24 * time.Hour,
+		"P1DT2H3M4S": 26*time.Hour + 3*time.Minute + 4*time.Second,
+	} {
+		d, err := ParseDuration(in)
+		require.NoError(t, err, in)
+		require.Equal(t, expected, d, in)
+	}
+
+	for _, in := range []string{"", "P", "15M", "PT15", "P1H"} {
+		_, err := ParseDuration(in)
+		require.Error(t, err, in)
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const recurrenceDateFormat = "2006-01-02"
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RecurrenceFromRemote converts the recurrence of a series master starting at
+// dtstart into a rule. Occurrences are computed in the location of dtstart.
+func RecurrenceFromRemote(p *remote.PatternedRecurrence, dtstart time.Time) (*Recurrence, error) {
+	if p == nil || p.Pattern == nil {
+		return nil, errors.New("missing recurrence pattern")
+	}
+	pattern := p.Pattern
+
+	r := &Recurrence{
+		Interval:  pattern.Interval,
+		WeekStart: time.Monday,
+	}
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	if r.Interval < 0 {
+		return nil, errors.Errorf("invalid recurrence interval %d", pattern.Interval)
+	}
+	if pattern.FirstDayOfWeek != "" {
+		day, err := parseDayName(pattern.FirstDayOfWeek)
+		if err != nil {
+			return nil, err
+		}
+		r.WeekStart = day
+	}
+
+	days := []WeekdayNum{}
+	for _, name := range pattern.DaysOfWeek {
+		day, err := parseDayName(name)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, WeekdayNum{Day: day})
+	}
+
+	month := time.Month(pattern.Month)
+	if month == 0 {
+		month = dtstart.Month()
+	}
+	dayOfMonth := pattern.DayOfMonth
+	if dayOfMonth == 0 {
+		dayOfMonth = dtstart.Day()
+	}
+
+	switch pattern.Type {
+	case remote.RecurrencePatternDaily:
+		r.Freq = FreqDaily
+	case remote.RecurrencePatternWeekly:
+		r.Freq = FreqWeekly
+		r.ByDay = days
+	case remote.RecurrencePatternAbsoluteMonthly:
+		r.Freq = FreqMonthly
+		r.ByMonthDay = []int{dayOfMonth}
+	case remote.RecurrencePatternAbsoluteYearly:
+		r.Freq = FreqYearly
+		r.ByMonth = []time.Month{month}
+		r.ByMonthDay = []int{dayOfMonth}
+	case remote.RecurrencePatternRelativeMonthly, remote.RecurrencePatternRelativeYearly:
+		r.Freq = FreqMonthly
+		if pattern.Type == remote.RecurrencePatternRelativeYearly {
+			r.Freq = FreqYearly
+			r.ByMonth = []time.Month{month}
+		}
+		if len(days) == 0 {
+			return nil, errors.Errorf("%s recurrence requires days of the week", pattern.Type)
+		}
+		pos, err := setPosFromIndex(pattern.Index)
+		if err != nil {
+			return nil, err
+		}
+		r.ByDay = days
+		r.BySetPos = []int{pos}
+	default:
+		return nil, errors.Errorf("unsupported recurrence pattern %q", pattern.Type)
+	}
+	if dayOfMonth < 1 || dayOfMonth > 31 {
+		return nil, errors.Errorf("invalid recurrence day of month %d", dayOfMonth)
+	}
+
+	if p.Range == nil {
+		return r, nil
+	}
+	switch p.Range.Type {
+	case remote.RecurrenceRangeNumbered:
+		if p.Range.NumberOfOccurrences < 1 {
+			return nil, errors.New("numbered recurrence requires a number of occurrences")
+		}
+		r.Count = p.Range.NumberOfOccurrences
+	case remote.RecurrenceRangeEndDate:
+		end, err := time.ParseInLocation(recurrenceDateFormat, p.Range.EndDate, dtstart.Location())
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid recurrence end date")
+		}
+		r.Until = end.AddDate(0, 0, 1).Add(-time.Second)
+	case remote.RecurrenceRangeNoEnd, "":
+	default:
+		return nil, errors.Errorf("unsupported recurrence range %q", p.Range.Type)
+	}
+	return r, nil
+}
+
+// ToRemote converts the rule of a series master starting at dtstart. It
+// returns nil for rules that can't be expressed as a remote recurrence, such
+// as rules with several months or days of the month.
+func (r *Recurrence) ToRemote(dtstart time.Time) *remote.PatternedRecurrence {
+	pattern := &remote.RecurrencePattern{
+		Interval:       r.Interval,
+		FirstDayOfWeek: dayName(r.WeekStart),
+	}
+
+	allDays := true
+	ordinal := 0
+	for i, wn := range r.ByDay {
+		pattern.DaysOfWeek = append(pattern.DaysOfWeek, dayName(wn.Day))
+		if wn.N != 0 {
+			allDays = false
+		}
+		if i == 0 {
+			ordinal = wn.N
+		} else if wn.N != ordinal {
+			return nil
+		}
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		if len(r.ByDay) > 0 || len(r.ByMonthDay) > 0 || len(r.ByMonth) > 0 || len(r.BySetPos) > 0 {
+			return nil
+		}
+		pattern.Type = remote.RecurrencePatternDaily
+		pattern.DaysOfWeek = nil
+
+	case FreqWeekly:
+		if !allDays || len(r.ByMonthDay) > 0 || len(r.ByMonth) > 0 || len(r.BySetPos) > 0 {
+			return nil
+		}
+		pattern.Type = remote.RecurrencePatternWeekly
+		if len(pattern.DaysOfWeek) == 0 {
+			pattern.DaysOfWeek = []string{dayName(dtstart.Weekday())}
+		}
+
+	case FreqMonthly, FreqYearly:
+		absolute, relative := remote.RecurrencePatternAbsoluteMonthly, remote.RecurrencePatternRelativeMonthly
+		if r.Freq == FreqYearly {
+			if len(r.ByMonth) > 1 {
+				return nil
+			}
+			absolute, relative = remote.RecurrencePatternAbsoluteYearly, remote.RecurrencePatternRelativeYearly
+			pattern.Month = int(dtstart.Month())
+			if len(r.ByMonth) == 1 {
+				pattern.Month = int(r.ByMonth[0])
+			}
+		} else if len(r.ByMonth) > 0 {
+			return nil
+		}
+
+		switch {
+		case len(r.ByDay) == 0:
+			if len(r.ByMonthDay) > 1 || len(r.BySetPos) > 0 {
+				return nil
+			}
+			pattern.Type = absolute
+			pattern.DayOfMonth = dtstart.Day()
+			if len(r.ByMonthDay) == 1 {
+				if r.ByMonthDay[0] < 0 {
+					return nil
+				}
+				pattern.DayOfMonth = r.ByMonthDay[0]
+			}
+		case len(r.ByMonthDay) > 0:
+			return nil
+		default:
+			pos := ordinal
+			if allDays {
+				if len(r.BySetPos) != 1 {
+					return nil
+				}
+				pos = r.BySetPos[0]
+			} else if len(r.BySetPos) > 0 {
+				return nil
+			}
+			index, ok := indexFromSetPos(pos)
+			if !ok {
+				return nil
+			}
+			pattern.Type = relative
+			pattern.Index = index
+		}
+
+	default:
+		return nil
+	}
+
+	rng := &remote.RecurrenceRange{
+		Type:               remote.RecurrenceRangeNoEnd,
+		StartDate:          dtstart.Format(recurrenceDateFormat),
+		RecurrenceTimeZone: dtstart.Location().String(),
+	}
+	switch {
+	case r.Count > 0:
+		rng.Type = remote.RecurrenceRangeNumbered
+		rng.NumberOfOccurrences = r.Count
+	case !r.Until.IsZero():
+		rng.Type = remote.RecurrenceRangeEndDate
+		rng.EndDate = r.Until.In(dtstart.Location()).Format(recurrenceDateFormat)
+	}
+
+	return &remote.PatternedRecurrence{
+		Pattern: pattern,
+		Range:   rng,
+	}
+}
+
+// String formats the rule as the value of an RRULE property.
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(UTCDateTimeFormat))
+	}
+	if len(r.ByDay) > 0 {
+		days := []string{}
+		for _, wn := range r.ByDay {
+			day := weekdayCodes[wn.Day]
+			if wn.N != 0 {
+				day = strconv.Itoa(wn.N) + day
+			}
+			days = append(days, day)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+	if len(r.ByMonth) > 0 {
+		months := []int{}
+		for _, m := range r.ByMonth {
+			months = append(months, int(m))
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(r.BySetPos))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCodes[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+func joinInts(values []int) string {
+	s := []string{}
+	for _, v := range values {
+		s = append(s, strconv.Itoa(v))
+	}
+	return strings.Join(s, ",")
+}
+
+func dayName(day time.Weekday) string {
+	return strings.ToLower(day.String())
+}
+
+func parseDayName(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, nil
+		}
+	}
+	return 0, errors.Errorf("invalid day of the week %q", name)
+}
+
+// setPosFromIndex maps the week index of a relative pattern to a BYSETPOS
+// value: "first" is 1 and "last" is -1.
+func setPosFromIndex(index string) (int, error) {
+	if index == "" {
+		return 1, nil
+	}
+	for i, v := range remote.WeekIndexes {
+		if !strings.EqualFold(index, v) {
+			continue
+		}
+		if i == len(remote.WeekIndexes)-1 {
+			return -1, nil
+		}
+		return i + 1, nil
+	}
+	return 0, errors.Errorf("invalid recurrence index %q", index)
+}
+
+func indexFromSetPos(pos int) (string, bool) {
+	last := len(remote.WeekIndexes) - 1
+	switch {
+	case pos == -1:
+		return remote.WeekIndexes[last], true
+	case pos >= 1 && pos <= last:
+		return remote.WeekIndexes[pos-1], true
+	}
+	return "", false
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestRecurrenceFromRemote(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	dtstart := time.Date(2024, 1, 8, 9, 0, 0, 0, berlin) // Monday
+
+	for _, tc := range []struct {
+		name      string
+		in        *remote.PatternedRecurrence
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "daily without end",
+			in:       &remote.PatternedRecurrence{Pattern: &remote.RecurrencePattern{Type: "daily"}, Range: &remote.RecurrenceRange{Type: "noEnd"}},
+			expected: "FREQ=DAILY",
+		},
+		{
+			name: "weekly with count",
+			in: &remote.PatternedRecurrence{
+				Pattern: &remote.RecurrencePattern{Type: "weekly", Interval: 2, DaysOfWeek: []string{"monday", "Wednesday"}, FirstDayOfWeek: "sunday"},
+				Range:   &remote.RecurrenceRange{Type: "numbered", NumberOfOccurrences: 4},
+			},
+			expected: "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=MO,WE;WKST=SU",
+		},
+		{
+			name: "absolute monthly until the end date",
+			in: &remote.PatternedRecurrence{
+				Pattern: &remote.RecurrencePattern{Type: "absoluteMonthly", Interval: 1, DayOfMonth: 15},
+				Range:   &remote.RecurrenceRange{Type: "endDate", EndDate: "2024-06-30"},
+			},
+			expected: "FREQ=MONTHLY;UNTIL=20240630T215959Z;BYMONTHDAY=15",
+		},
+		{
+			name: "relative monthly on the last friday",
+			in: &remote.PatternedRecurrence{
+				Pattern: &remote.RecurrencePattern{Type: "relativeMonthly", Interval: 1, DaysOfWeek: []string{"friday"}, Index: "last"},
+			},
+			expected: "FREQ=MONTHLY;BYDAY=FR;BYSETPOS=-1",
+		},
+		{
+			name:     "absolute yearly defaults to the start date",
+			in:       &remote.PatternedRecurrence{Pattern: &remote.RecurrencePattern{Type: "absoluteYearly"}},
+			expected: "FREQ=YEARLY;BYMONTHDAY=8;BYMONTH=1",
+		},
+		{
+			name:      "relative pattern without days",
+			in:        &remote.PatternedRecurrence{Pattern: &remote.RecurrencePattern{Type: "relativeMonthly", Index: "first"}},
+			expectErr: true,
+		},
+		{
+			name:      "unknown day",
+			in:        &remote.PatternedRecurrence{Pattern: &remote.RecurrencePattern{Type: "weekly", DaysOfWeek: []string{"someday"}}},
+			expectErr: true,
+		},
+		{
+			name:      "unknown pattern",
+			in:        &remote.PatternedRecurrence{Pattern: &remote.RecurrencePattern{Type: "hourly"}},
+			expectErr: true,
+		},
+		{
+			name:      "missing pattern",
+			in:        &remote.PatternedRecurrence{},
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := RecurrenceFromRemote(tc.in, dtstart)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, r.String())
+
+			parsed, err := ParseRecurrence(r.String(), berlin)
+			require.NoError(t, err)
+			require.Equal(t, r.Occurrences(dtstart, time.Hour, dtstart, dtstart.AddDate(2, 0, 0)), parsed.Occurrences(dtstart, time.Hour, dtstart, dtstart.AddDate(2, 0, 0)))
+		})
+	}
+}
+
+func TestRecurrenceToRemote(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	dtstart := time.Date(2024, 1, 8, 9, 0, 0, 0, berlin) // Monday
+
+	for _, tc := range []struct {
+		rrule           string
+		expectedPattern *remote.RecurrencePattern
+		expectedRange   *remote.RecurrenceRange
+	}{
+		{
+			rrule:           "FREQ=DAILY;INTERVAL=3",
+			expectedPattern: &remote.RecurrencePattern{Type: "daily", Interval: 3, FirstDayOfWeek: "monday"},
+			expectedRange:   &remote.RecurrenceRange{Type: "noEnd", StartDate: "2024-01-08", RecurrenceTimeZone: "Europe/Berlin"},
+		},
+		{
+			rrule:           "FREQ=WEEKLY;COUNT=5",
+			expectedPattern: &remote.RecurrencePattern{Type: "weekly", Interval: 1, DaysOfWeek: []string{"monday"}, FirstDayOfWeek: "monday"},
+			expectedRange:   &remote.RecurrenceRange{Type: "numbered", StartDate: "2024-01-08", RecurrenceTimeZone: "Europe/Berlin", NumberOfOccurrences: 5},
+		},
+		{
+			rrule:           "FREQ=MONTHLY;BYDAY=2TU;UNTIL=20241231T230000Z",
+			expectedPattern: &remote.RecurrencePattern{Type: "relativeMonthly", Interval: 1, DaysOfWeek: []string{"tuesday"}, FirstDayOfWeek: "monday", Index: "second"},
+			expectedRange:   &remote.RecurrenceRange{Type: "endDate", StartDate: "2024-01-08", EndDate: "2025-01-01", RecurrenceTimeZone: "Europe/Berlin"},
+		},
+		{
+			rrule:           "FREQ=YEARLY;BYMONTH=3;BYDAY=SU;BYSETPOS=-1",
+			expectedPattern: &remote.RecurrencePattern{Type: "relativeYearly", Interval: 1, Month: 3, DaysOfWeek: []string{"sunday"}, FirstDayOfWeek: "monday", Index: "last"},
+			expectedRange:   &remote.RecurrenceRange{Type: "noEnd", StartDate: "2024-01-08", RecurrenceTimeZone: "Europe/Berlin"},
+		},
+		{rrule: "FREQ=DAILY;BYDAY=MO,TU"},
+		{rrule: "FREQ=MONTHLY;BYMONTHDAY=1,15"},
+		{rrule: "FREQ=MONTHLY;BYDAY=1MO,-1FR"},
+		{rrule: "FREQ=YEARLY;BYMONTH=1,7"},
+	} {
+		t.Run(tc.rrule, func(t *testing.T) {
+			r, err := ParseRecurrence(tc.rrule, berlin)
+			require.NoError(t, err)
+
+			out := r.ToRemote(dtstart)
+			if tc.expectedPattern == nil {
+				require.Nil(t, out)
+				return
+			}
+			require.Equal(t, tc.expectedPattern, out.Pattern)
+			require.Equal(t, tc.expectedRange, out.Range)
+		})
+	}
+}
+
+func TestFromEventRecurring(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2024, 3, 25, 9, 0, 0, 0, berlin)
+
+	in := &remote.Event{
+		Subject: "Standup",
+		Start:   remote.NewDateTime(start, "Europe/Berlin"),
+		End:     remote.NewDateTime(start.Add(15*time.Minute), "Europe/Berlin"),
+		Recurrence: &remote.PatternedRecurrence{
+			Pattern: &remote.RecurrencePattern{Type: "weekly", Interval: 1, DaysOfWeek: []string{"monday"}},
+			Range:   &remote.RecurrenceRange{Type: "numbered", NumberOfOccurrences: 2},
+		},
+	}
+	cal, err := FromEvent(in, "standup", "")
+	require.NoError(t, err)
+
+	vevent := cal.ChildrenNamed(CompEvent)[0]
+	require.Equal(t, "Europe/Berlin", vevent.Prop("DTSTART").Param("TZID"))
+	require.Equal(t, "FREQ=WEEKLY;COUNT=2;BYDAY=MO", vevent.Text("RRULE"))
+
+	master, err := ToEvent(vevent, time.UTC)
+	require.NoError(t, err)
+	require.Equal(t, remote.EventTypeSeriesMaster, master.Type)
+	require.Equal(t, []string{"monday"}, master.Recurrence.Pattern.DaysOfWeek)
+
+	// The wall clock time is kept across the change to summer time.
+	events := ExpandEvents([]*Component{vevent}, time.UTC, start, start.AddDate(0, 1, 0))
+	require.Len(t, events, 2)
+	require.Equal(t, time.Date(2024, 3, 25, 8, 0, 0, 0, time.UTC), events[0].Start.Time())
+	require.Equal(t, time.Date(2024, 4, 1, 7, 0, 0, 0, time.UTC), events[1].Start.Time())
+	require.Equal(t, remote.EventTypeOccurrence, events[1].Type)
+	require.Equal(t, "standup", events[1].SeriesMasterID)
+	require.Nil(t, events[1].Recurrence)
+}
@@ -0,0 +1,542 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+
+	// maxRecurrencePeriods bounds the expansion of rules whose BY* parts
+	// never match, such as FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30.
+	maxRecurrencePeriods = 100000
+
+	instanceIDSeparator = "_"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY value such as "MO", "2TU" or "-1FR".
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Recurrence is a parsed RRULE (RFC 5545, section 3.3.10). Rules with a
+// frequency below DAILY are not supported.
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// ParseRecurrence parses the value of an RRULE property. A floating or
+// DATE UNTIL is interpreted in loc.
+func ParseRecurrence(rrule string, loc *time.Location) (*Recurrence, error) {
+	r := &Recurrence{
+		Interval:  1,
+		WeekStart: time.Monday,
+	}
+
+	for _, part := range strings.Split(rrule, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid RRULE part %q", part)
+		}
+		name, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		var err error
+		switch name {
+		case "FREQ":
+			r.Freq = value
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval < 1 {
+				err = errors.New("must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+		case "UNTIL":
+			var allDay bool
+			r.Until, allDay, err = ParseTime(NewProperty("UNTIL", value), loc)
+			if allDay {
+				r.Until = r.Until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		case "BYDAY":
+			for _, v := range strings.Split(value, ",") {
+				var wn WeekdayNum
+				wn, err = parseWeekdayNum(v)
+				if err != nil {
+					break
+				}
+				r.ByDay = append(r.ByDay, wn)
+			}
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseInts(value, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(value, 12)
+			for _, m := range months {
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			r.BySetPos, err = parseInts(value, 366)
+		case "WKST":
+			day, ok := weekdays[value]
+			if !ok {
+				err = errors.New("unknown weekday")
+			}
+			r.WeekStart = day
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid RRULE %s", name)
+		}
+	}
+
+	switch r.Freq {
+	case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+	default:
+		return nil, errors.Errorf("unsupported RRULE FREQ %q", r.Freq)
+	}
+	return r, nil
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return WeekdayNum{}, errors.Errorf("invalid weekday %q", s)
+	}
+	day, ok := weekdays[s[len(s)-2:]]
+	if !ok {
+		return WeekdayNum{}, errors.Errorf("invalid weekday %q", s)
+	}
+	wn := WeekdayNum{Day: day}
+	if n := strings.TrimPrefix(s[:len(s)-2], "+"); n != "" {
+		var err error
+		wn.N, err = strconv.Atoi(n)
+		if err != nil {
+			return WeekdayNum{}, errors.Errorf("invalid weekday %q", s)
+		}
+	}
+	return wn, nil
+}
+
+func parseInts(s string, limit int) ([]int, error) {
+	result := []int{}
+	for _, v := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(v), "+"))
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 || n > limit || n < -limit {
+			return nil, errors.Errorf("%d is out of range", n)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// Occurrences returns the start times of the occurrences of an event starting
+// at dtstart and lasting d that overlap [from, to). Occurrences are computed
+// in the location of dtstart, so they keep their wall clock time across
+// daylight saving time changes. The first occurrence is always dtstart.
+func (r *Recurrence) Occurrences(dtstart time.Time, d time.Duration, from, to time.Time) []time.Time {
+	result := []time.Time{}
+	count := 0
+	add := func(t time.Time) bool {
+		if (!r.Until.IsZero() && t.After(r.Until)) || !t.Before(to) {
+			return false
+		}
+		count++
+		if t.Add(d).After(from) || (d == 0 && !t.Before(from)) {
+			result = append(result, t)
+		}
+		return r.Count == 0 || count < r.Count
+	}
+
+	if !add(dtstart) {
+		return result
+	}
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		if !r.periodStart(dtstart, period).Before(to) {
+			return result
+		}
+		for _, t := range r.periodCandidates(dtstart, period) {
+			if !t.After(dtstart) {
+				continue
+			}
+			if !add(t) {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// periodStart returns the first day of the n-th period (day, week, month or
+// year) of the rule, at the time of day of DTSTART.
+func (r *Recurrence) periodStart(dtstart time.Time, n int) time.Time {
+	y, m, d := dtstart.Date()
+	switch r.Freq {
+	case FreqWeekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		d += 7*n*r.Interval - offset
+	case FreqMonthly:
+		m, d = m+time.Month(n*r.Interval), 1
+	case FreqYearly:
+		y, m, d = y+n*r.Interval, time.January, 1
+	default:
+		d += n * r.Interval
+	}
+	return time.Date(y, m, d, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+}
+
+// periodCandidates returns the sorted occurrences falling in the n-th
+// period (day, week, month or year) of the rule.
+func (r *Recurrence) periodCandidates(dtstart time.Time, n int) []time.Time {
+	_, m, d := dtstart.Date()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+	start := r.periodStart(dtstart, n)
+
+	var days []time.Time
+	switch r.Freq {
+	case FreqDaily:
+		day := start
+		if r.matchesMonth(day) && r.matchesMonthDay(day) && r.matchesWeekday(day) {
+			days = []time.Time{day}
+		}
+
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			days = []time.Time{start.AddDate(0, 0, (int(dtstart.Weekday())-int(r.WeekStart)+7)%7)}
+			break
+		}
+		for i := 0; i < 7; i++ {
+			day := start.AddDate(0, 0, i)
+			if r.matchesWeekday(day) {
+				days = append(days, day)
+			}
+		}
+		days = filterTimes(days, r.matchesMonth)
+
+	case FreqMonthly:
+		if r.matchesMonth(start) {
+			days = r.monthDays(start, d)
+		}
+
+	case FreqYearly:
+		year := start.Year()
+		switch {
+		case len(r.ByMonth) > 0:
+			for _, month := range r.ByMonth {
+				days = append(days, r.monthDays(at(year, month, 1), d)...)
+			}
+		case len(r.ByDay) > 0 && len(r.ByMonthDay) == 0:
+			days = byDayIn(at(year, time.January, 1), at(year+1, time.January, 1), r.ByDay)
+		case len(r.ByMonthDay) > 0:
+			for month := time.January; month <= time.December; month++ {
+				days = append(days, r.monthDays(at(year, month, 1), d)...)
+			}
+		default:
+			days = r.monthDays(at(year, m, 1), d)
+		}
+	}
+
+	sortTimes(days)
+	return r.applySetPos(days)
+}
+
+// monthDays returns the days of the month starting at first that match the
+// BYMONTHDAY and BYDAY parts, defaulting to the day of month of DTSTART.
+func (r *Recurrence) monthDays(first time.Time, defaultDay int) []time.Time {
+	next := first.AddDate(0, 1, 0)
+	lastDay := next.AddDate(0, 0, -1).Day()
+
+	var days []time.Time
+	if len(r.ByMonthDay) > 0 {
+		for _, md := range r.ByMonthDay {
+			if md < 0 {
+				md = lastDay + md + 1
+			}
+			if md >= 1 && md <= lastDay {
+				days = append(days, first.AddDate(0, 0, md-1))
+			}
+		}
+		if len(r.ByDay) > 0 {
+			days = filterTimes(days, r.matchesWeekday)
+		}
+		return days
+	}
+	if len(r.ByDay) > 0 {
+		return byDayIn(first, next, r.ByDay)
+	}
+	if defaultDay <= lastDay {
+		days = append(days, first.AddDate(0, 0, defaultDay-1))
+	}
+	return days
+}
+
+// byDayIn returns the days in [first, next) matching the BYDAY values, with
+// ordinals counted from the start (or end, when negative) of the range.
+func byDayIn(first, next time.Time, byDay []WeekdayNum) []time.Time {
+	days := []time.Time{}
+	for _, wn := range byDay {
+		matching := []time.Time{}
+		for day := first; day.Before(next); day = day.AddDate(0, 0, 1) {
+			if day.Weekday() == wn.Day {
+				matching = append(matching, day)
+			}
+		}
+		switch {
+		case wn.N == 0:
+			days = append(days, matching...)
+		case wn.N > 0 && wn.N <= len(matching):
+			days = append(days, matching[wn.N-1])
+		case wn.N < 0 && -wn.N <= len(matching):
+			days = append(days, matching[len(matching)+wn.N])
+		}
+	}
+	return days
+}
+
+func (r *Recurrence) applySetPos(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+	result := []time.Time{}
+	for _, pos := range r.BySetPos {
+		switch {
+		case pos > 0 && pos <= len(days):
+			result = append(result, days[pos-1])
+		case pos < 0 && -pos <= len(days):
+			result = append(result, days[len(days)+pos])
+		}
+	}
+	sortTimes(result)
+	return result
+}
+
+func (r *Recurrence) matchesMonth(t time.Time) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if t.Month() == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recurrence) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	lastDay := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, md := range r.ByMonthDay {
+		if t.Day() == md || t.Day() == lastDay+md+1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Recurrence) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wn := range r.ByDay {
+		if t.Weekday() == wn.Day {
+			return true
+		}
+	}
+	return false
+}
+
+func filterTimes(times []time.Time, keep func(time.Time) bool) []time.Time {
+	result := []time.Time{}
+	for _, t := range times {
+		if keep(t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func sortTimes(times []time.Time) {
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+}
+
+// InstanceID returns the ID of the occurrence of a recurring event starting
+// at start.
+func InstanceID(uid string, start time.Time) string {
+	return uid + instanceIDSeparator + start.UTC().Format(UTCDateTimeFormat)
+}
+
+// ParseInstanceID splits an ID returned by InstanceID. It returns false for
+// IDs of non-recurring events.
+func ParseInstanceID(id string) (uid string, start time.Time, ok bool) {
+	i := strings.LastIndex(id, instanceIDSeparator)
+	if i < 0 {
+		return "", time.Time{}, false
+	}
+	start, err := time.Parse(UTCDateTimeFormat, id[i+1:])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return id[:i], start, true
+}
+
+// ExpandEvents converts the VEVENTs of a calendar into the remote events
+// overlapping [from, to), expanding recurring events into one event per
+// occurrence. Occurrences modified by a VEVENT with a RECURRENCE-ID are
+// replaced by it. VEVENTs that can't be parsed are skipped, so one malformed
+// event doesn't hide the rest of the calendar.
+func ExpandEvents(vevents []*Component, loc *time.Location, from, to time.Time, ownerAddresses ...string) []*remote.Event {
+	overridden := map[string]bool{}
+	for _, vevent := range vevents {
+		if recurrenceID := vevent.Prop("RECURRENCE-ID"); recurrenceID != nil {
+			if t, _, err := ParseTime(recurrenceID, loc); err == nil {
+				overridden[InstanceID(vevent.Text("UID"), t)] = true
+			}
+		}
+	}
+
+	events := []*remote.Event{}
+	for _, vevent := range vevents {
+		e, err := ToEvent(vevent, loc, ownerAddresses...)
+		if err != nil {
+			continue
+		}
+		start, end := e.Start.Time(), e.End.Time()
+
+		if recurrenceID := vevent.Prop("RECURRENCE-ID"); recurrenceID != nil {
+			t, _, err := ParseTime(recurrenceID, loc)
+			if err != nil {
+				continue
+			}
+			e.ID = InstanceID(e.ICalUID, t)
+			e.Type = remote.EventTypeException
+			if overlaps(start, end, from, to) {
+				events = append(events, e)
+			}
+			continue
+		}
+
+		rrule, rdates := vevent.Prop("RRULE"), vevent.PropsNamed("RDATE")
+		if rrule == nil && len(rdates) == 0 {
+			if overlaps(start, end, from, to) {
+				events = append(events, e)
+			}
+			continue
+		}
+
+		dtstart, _, err := ParseTime(vevent.Prop("DTSTART"), loc)
+		if err != nil {
+			continue
+		}
+		d := end.Sub(start)
+
+		starts := []time.Time{}
+		if rrule != nil {
+			r, err := ParseRecurrence(rrule.Value, dtstart.Location())
+			if err != nil {
+				// Show the first occurrence rather than nothing.
+				r = &Recurrence{Freq: FreqDaily, Interval: 1, Count: 1}
+			}
+			starts = r.Occurrences(dtstart, d, from, to)
+		}
+		for _, p := range rdates {
+			for _, t := range timeList(p, dtstart.Location()) {
+				if overlaps(t, t.Add(d), from, to) {
+					starts = append(starts, t)
+				}
+			}
+		}
+
+		excluded := map[int64]bool{}
+		for _, p := range vevent.PropsNamed("EXDATE") {
+			for _, t := range timeList(p, dtstart.Location()) {
+				excluded[t.Unix()] = true
+			}
+		}
+
+		sortTimes(starts)
+		seen := map[int64]bool{}
+		for _, t := range starts {
+			id := InstanceID(e.ICalUID, t)
+			if excluded[t.Unix()] || seen[t.Unix()] || overridden[id] {
+				continue
+			}
+			seen[t.Unix()] = true
+
+			instance := *e
+			instance.ID = id
+			instance.Type = remote.EventTypeOccurrence
+			instance.SeriesMasterID = e.ICalUID
+			instance.Recurrence = nil
+			instance.Start = remote.NewDateTime(t.UTC(), "UTC")
+			instance.End = remote.NewDateTime(t.Add(d).UTC(), "UTC")
+			events = append(events, &instance)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Time().Before(events[j].Start.Time())
+	})
+	return events
+}
+
+func overlaps(start, end, from, to time.Time) bool {
+	if !start.Before(to) {
+		return false
+	}
+	return end.After(from) || (end.Equal(start) && !start.Before(from))
+}
+
+// timeList parses a property holding a comma-separated list of times, such
+// as EXDATE or RDATE. Invalid values are skipped.
+func timeList(p *Property, loc *time.Location) []time.Time {
+	times := []time.Time{}
+	for _, value := range strings.Split(p.Value, ",") {
+		single := *p
+		single.Value = value
+		t, _, err := ParseTime(&single, loc)
+		if err == nil {
+			times = append(times, t)
+		}
+	}
+	return times
+}
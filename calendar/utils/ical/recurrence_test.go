@@ -0,0 +1,152 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOccurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	dtstart := time.Date(2024, 1, 1, 9, 0, 0, 0, berlin) // Monday
+	from := dtstart
+	to := time.Date(2026, 1, 1, 0, 0, 0, 0, berlin)
+
+	dates := func(times []time.Time) []string {
+		result := []string{}
+		for _, t := range times {
+			result = append(result, t.In(berlin).Format("2006-01-02 15:04"))
+		}
+		return result
+	}
+
+	for _, tc := range []struct {
+		name     string
+		rrule    string
+		from     time.Time
+		expected []string
+	}{
+		{
+			name:     "daily count",
+			rrule:    "FREQ=DAILY;COUNT=3",
+			expected: []string{"2024-01-01 09:00", "2024-01-02 09:00", "2024-01-03 09:00"},
+		},
+		{
+			name:     "weekly by day with interval",
+			rrule:    "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4",
+			expected: []string{"2024-01-01 09:00", "2024-01-03 09:00", "2024-01-15 09:00", "2024-01-17 09:00"},
+		},
+		{
+			name:     "weekly until date",
+			rrule:    "FREQ=WEEKLY;UNTIL=20240115",
+			expected: []string{"2024-01-01 09:00", "2024-01-08 09:00", "2024-01-15 09:00"},
+		},
+		{
+			name:     "monthly on the last friday",
+			rrule:    "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			expected: []string{"2024-01-01 09:00", "2024-01-26 09:00", "2024-02-23 09:00"},
+		},
+		{
+			name:     "monthly on the last weekday",
+			rrule:    "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=3",
+			expected: []string{"2024-01-01 09:00", "2024-01-31 09:00", "2024-02-29 09:00"},
+		},
+		{
+			name:     "yearly skips invalid dates",
+			rrule:    "FREQ=YEARLY;BYMONTH=2,4;BYMONTHDAY=30;COUNT=3",
+			expected: []string{"2024-01-01 09:00", "2024-04-30 09:00", "2025-04-30 09:00"},
+		},
+		{
+			name:     "keeps wall clock time across DST",
+			rrule:    "FREQ=WEEKLY;BYDAY=MO",
+			from:     time.Date(2024, 3, 25, 0, 0, 0, 0, berlin),
+			expected: []string{"2024-03-25 09:00", "2024-04-01 09:00"},
+		},
+		{
+			name:     "count is applied before the window",
+			rrule:    "FREQ=DAILY;COUNT=5",
+			from:     time.Date(2024, 1, 4, 0, 0, 0, 0, berlin),
+			expected: []string{"2024-01-04 09:00", "2024-01-05 09:00"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tc.rrule, berlin)
+			require.NoError(t, err)
+
+			start, end := from, to
+			if !tc.from.IsZero() {
+				start, end = tc.from, tc.from.AddDate(0, 0, 14)
+			}
+			require.Equal(t, tc.expected, dates(r.Occurrences(dtstart, time.Hour, start, end)))
+		})
+	}
+
+	for _, rrule := range []string{"FREQ=HOURLY", "FREQ=DAILY;INTERVAL=0", "FREQ=WEEKLY;BYDAY=XX", "FREQ=MONTHLY;BYMONTHDAY=32", "FREQ"} {
+		_, err := ParseRecurrence(rrule, berlin)
+		require.Error(t, err, rrule)
+	}
+}
+
+func TestExpandEvents(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:standup\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20240101T090000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20240101T091500\r\n" +
+		"RRULE:FREQ=DAILY\r\n" +
+		"EXDATE;TZID=Europe/Berlin:20240102T090000\r\n" +
+		"SUMMARY:Standup\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:standup\r\n" +
+		"RECURRENCE-ID;TZID=Europe/Berlin:20240103T090000\r\n" +
+		"DTSTART;TZID=Europe/Berlin:20240103T100000\r\n" +
+		"DTEND;TZID=Europe/Berlin:20240103T101500\r\n" +
+		"SUMMARY:Standup (moved)\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:review\r\n" +
+		"DTSTART:20240102T150000Z\r\n" +
+		"DTEND:20240102T160000Z\r\n" +
+		"SUMMARY:Review\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:broken\r\n" +
+		"DTSTART:not a date\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := Parse(strings.NewReader(data))
+	require.NoError(t, err)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+	events := ExpandEvents(cal.ChildrenNamed(CompEvent), time.UTC, from, to)
+
+	summaries := []string{}
+	for _, e := range events {
+		summaries = append(summaries, e.Start.Time().Format("02 15:04")+" "+e.Subject)
+	}
+	require.Equal(t, []string{
+		"01 08:00 Standup",
+		"02 15:00 Review",
+		"03 09:00 Standup (moved)",
+	}, summaries)
+
+	uid, start, ok := ParseInstanceID(events[0].ID)
+	require.True(t, ok)
+	require.Equal(t, "standup", uid)
+	require.Equal(t, time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), start)
+	require.Equal(t, "standup_20240103T080000Z", events[2].ID)
+
+	_, _, ok = ParseInstanceID(events[1].ID)
+	require.False(t, ok)
+}
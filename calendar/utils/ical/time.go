@@ -0,0 +1,162 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ical
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/tz"
+)
+
+const (
+	DateFormat        = "20060102"
+	DateTimeFormat    = "20060102T150405"
+	UTCDateTimeFormat = "20060102T150405Z"
+)
+
+// ParseTime parses a DATE or DATE-TIME property. Floating times, and times
+// with a TZID that cannot be resolved, are interpreted in loc.
+func ParseTime(p *Property, loc *time.Location) (t time.Time, allDay bool, err error) {
+	if p == nil {
+		return time.Time{}, false, errors.New("missing date-time property")
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	value := strings.TrimSpace(p.Value)
+	if strings.EqualFold(p.Param("VALUE"), "DATE") || len(value) == len(DateFormat) {
+		t, err = time.ParseInLocation(DateFormat, value, loc)
+		if err != nil {
+			return time.Time{}, false, errors.Wrapf(err, "invalid %s", p.Name)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(UTCDateTimeFormat, value)
+		if err != nil {
+			return time.Time{}, false, errors.Wrapf(err, "invalid %s", p.Name)
+		}
+		return t, false, nil
+	}
+
+	if tzid := p.Param("TZID"); tzid != "" {
+		loc = LoadLocation(tzid, loc)
+	}
+	t, err = time.ParseInLocation(DateTimeFormat, value, loc)
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "invalid %s", p.Name)
+	}
+	return t, false, nil
+}
+
+// LoadLocation resolves an IANA or Windows time zone name, falling back to
+// def when the name is unknown.
+func LoadLocation(name string, def *time.Location) *time.Location {
+	// Some producers prefix the TZID with a globally unique path.
+	name = strings.TrimPrefix(name, "/")
+	goName := tz.Go(name)
+	if goName == "" {
+		return def
+	}
+	loc, err := time.LoadLocation(goName)
+	if err != nil {
+		return def
+	}
+	return loc
+}
+
+// NewTimeProperty returns a DATE property for all-day values, and a UTC
+// DATE-TIME property otherwise.
+func NewTimeProperty(name string, t time.Time, allDay bool) *Property {
+	if allDay {
+		p := NewProperty(name, t.Format(DateFormat))
+		p.SetParam("VALUE", "DATE")
+		return p
+	}
+	return NewProperty(name, t.UTC().Format(UTCDateTimeFormat))
+}
+
+// NewZonedTimeProperty returns a DATE property for all-day values, and a
+// DATE-TIME property with the TZID of the location of t otherwise. UTC times
+// are formatted as by NewTimeProperty.
+func NewZonedTimeProperty(name string, t time.Time, allDay bool) *Property {
+	if allDay || t.Location() == time.UTC || t.Location() == time.Local {
+		return NewTimeProperty(name, t, allDay)
+	}
+	p := NewProperty(name, t.Format(DateTimeFormat))
+	p.SetParam("TZID", t.Location().String())
+	return p
+}
+
+// ParseDuration parses an RFC 5545 duration such as "PT15M", "-P1D" or "P2W".
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, errors.Errorf("invalid duration %q", orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, errors.Errorf("invalid duration %q", orig)
+		}
+		num = ""
+
+		switch {
+		case r == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, errors.Errorf("invalid duration %q", orig)
+		}
+	}
+	if num != "" {
+		return 0, errors.Errorf("invalid duration %q", orig)
+	}
+	return sign * d, nil
+}
+
+// FormatDuration formats a duration as an RFC 5545 duration, in minutes.
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	return sign + "PT" + strconv.Itoa(int(d/time.Minute)) + "M"
+}
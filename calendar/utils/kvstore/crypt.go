@@ -4,6 +4,7 @@
 package kvstore
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -119,7 +120,31 @@ func (s encryptedKeyStore) StoreTTL(key string, data []byte, ttlSeconds int64) e
 	return s.store.StoreTTL(key, encryptedData, ttlSeconds)
 }
 
+// StoreWithOptions stores the encrypted data. Values are encrypted with a
+// random nonce, so the old value of an atomic write is compared decrypted, and
+// the write made against the encrypted value read. Nil data deletes the key.
 func (s encryptedKeyStore) StoreWithOptions(key string, data []byte, opts model.PluginKVSetOptions) (bool, error) {
+	if opts.Atomic && opts.OldValue != nil {
+		current, err := s.store.Load(key)
+		if err == ErrNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		plain, err := decrypt(s.encryptionKey, current)
+		if err != nil {
+			return false, errors.Wrap(err, "error decrypting data")
+		}
+		if !bytes.Equal(plain, opts.OldValue) {
+			return false, nil
+		}
+		opts.OldValue = current
+	}
+
+	if data == nil {
+		return s.store.StoreWithOptions(key, nil, opts)
+	}
 	encryptedData, err := encrypt(s.encryptionKey, data)
 	if err != nil {
 		return false, errors.Wrap(err, "error encrypting data")
@@ -4,9 +4,12 @@
 package kvstore
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/mattermost/mattermost/server/public/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEncryptDecrypt(t *testing.T) {
@@ -110,3 +113,74 @@ func TestDecrypt(t *testing.T) {
 		})
 	}
 }
+
+// memKVStore keeps values in memory, with the semantics of the plugin KV
+// store for atomic writes.
+type memKVStore map[string][]byte
+
+func (m memKVStore) Load(key string) ([]byte, error) {
+	value, ok := m[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+func (m memKVStore) Store(key string, data []byte) error {
+	m[key] = data
+	return nil
+}
+
+func (m memKVStore) StoreTTL(key string, data []byte, _ int64) error {
+	return m.Store(key, data)
+}
+
+func (m memKVStore) StoreWithOptions(key string, data []byte, opts model.PluginKVSetOptions) (bool, error) {
+	if opts.Atomic && !bytes.Equal(m[key], opts.OldValue) {
+		return false, nil
+	}
+	if data == nil {
+		delete(m, key)
+		return true, nil
+	}
+	m[key] = data
+	return true, nil
+}
+
+func (m memKVStore) Delete(key string) error {
+	delete(m, key)
+	return nil
+}
+
+func (m memKVStore) List(_, _ int) ([]string, error) {
+	return nil, nil
+}
+
+func TestEncryptedKeyStoreAtomicModify(t *testing.T) {
+	underlying := memKVStore{}
+	s := NewEncryptedKeyStore(underlying, make([]byte, 16))
+
+	for _, value := range []string{"first", "second"} {
+		err := AtomicModify(s, "key", func(_ []byte, _ error) ([]byte, error) {
+			return []byte(value), nil
+		})
+		require.NoError(t, err)
+		loaded, err := s.Load("key")
+		require.NoError(t, err)
+		require.Equal(t, value, string(loaded))
+		require.NotEqual(t, value, string(underlying["key"]))
+	}
+
+	// A value changed meanwhile is not overwritten
+	success, err := s.StoreWithOptions("key", []byte("third"), model.PluginKVSetOptions{Atomic: true, OldValue: []byte("first")})
+	require.NoError(t, err)
+	require.False(t, success)
+
+	// Nil deletes the key
+	err = AtomicModify(s, "key", func(_ []byte, _ error) ([]byte, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	_, err = s.Load("key")
+	require.Equal(t, ErrNotFound, err)
+}
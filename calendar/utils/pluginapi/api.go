@@ -163,6 +163,13 @@ func (a *API) GetPost(postID string) (*model.Post, error) {
 	return p, nil
 }
 
+func (a *API) OpenInteractiveDialog(dialog model.OpenDialogRequest) error {
+	if appErr := a.api.OpenInteractiveDialog(dialog); appErr != nil {
+		return appErr
+	}
+	return nil
+}
+
 func (a *API) PublishWebsocketEvent(mattermostUserID, event string, payload map[string]any) {
 	a.api.PublishWebSocketEvent(event, payload, &model.WebsocketBroadcast{UserId: mattermostUserID})
 }
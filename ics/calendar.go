@@ -0,0 +1,154 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ics
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+func (c *client) GetCalendars(_ string) ([]*remote.Calendar, error) {
+	if err := c.checkUserConnected(); err != nil {
+		return nil, err
+	}
+
+	me, err := c.GetMe()
+	if err != nil {
+		return nil, errors.Wrap(err, "ics GetCalendars")
+	}
+	return []*remote.Calendar{{
+		ID:    me.ID,
+		Name:  me.DisplayName,
+		Owner: me,
+	}}, nil
+}
+
+// GetSharedCalendars is not implemented: an ICS feed holds a single
+// calendar.
+func (c *client) GetSharedCalendars(_ string) ([]*remote.SharedCalendar, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetDefaultCalendarView(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	return c.GetEventsBetweenDates(remoteUserID, start, end)
+}
+
+// DoBatchCalendarViewDeltaRequests is not implemented: an ICS feed has no change
+// tracking, so its views are always read from the feed.
+func (c *client) DoBatchCalendarViewDeltaRequests(_ []*remote.CalendarViewDeltaParams) ([]*remote.CalendarViewDeltaResponse, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetEventsBetweenDates(_ string, start, end time.Time) ([]*remote.Event, error) {
+	if err := c.checkUserConnected(); err != nil {
+		return nil, err
+	}
+
+	cal, err := c.fetch()
+	if err != nil {
+		return nil, errors.Wrap(err, "ics GetEventsBetweenDates")
+	}
+	return ical.ExpandEvents(cal.ChildrenNamed(ical.CompEvent), location(cal), start, end), nil
+}
+
+// GetCalendarView reads the feed, the only calendar of the user. Its ID is
+// the remote user ID, as listed by GetCalendars.
+func (c *client) GetCalendarView(_, calendarID string, start, end time.Time) ([]*remote.Event, error) {
+	if calendarID != c.remoteUserID() {
+		return nil, errors.New("404 Not Found: an ICS feed holds a single calendar")
+	}
+	return c.GetEventsBetweenDates(c.remoteUserID(), start, end)
+}
+
+// GetEvent finds an event by UID, or an occurrence of a recurring event by
+// the instance ID returned in calendar views.
+func (c *client) GetEvent(_, eventID string) (*remote.Event, error) {
+	if err := c.checkUserConnected(); err != nil {
+		return nil, err
+	}
+
+	cal, err := c.fetch()
+	if err != nil {
+		return nil, errors.Wrap(err, "ics GetEvent")
+	}
+	vevents := cal.ChildrenNamed(ical.CompEvent)
+
+	if _, start, ok := ical.ParseInstanceID(eventID); ok {
+		for _, e := range ical.ExpandEvents(vevents, location(cal), start, start.Add(time.Second)) {
+			if e.ID == eventID {
+				return e, nil
+			}
+		}
+	}
+	for _, vevent := range vevents {
+		if vevent.Text("UID") == eventID && vevent.Prop("RECURRENCE-ID") == nil {
+			return ical.ToEvent(vevent, location(cal))
+		}
+	}
+	return nil, errors.New("404 Not Found: the event was not found")
+}
+
+// DoBatchViewCalendarRequests only serves the feed of the user. Requests for
+// other users fail individually.
+func (c *client) DoBatchViewCalendarRequests(allParams []*remote.ViewCalendarParams) ([]*remote.ViewCalendarResponse, error) {
+	result := []*remote.ViewCalendarResponse{}
+	for _, params := range allParams {
+		res := &remote.ViewCalendarResponse{
+			RemoteUserID: params.RemoteUserID,
+		}
+		if params.RemoteUserID != c.remoteUserID() {
+			res.Error = &remote.APIError{
+				Code:    "ErrorAccessDenied",
+				Message: "an ICS feed only holds the calendar of its owner",
+			}
+			result = append(result, res)
+			continue
+		}
+
+		var events []*remote.Event
+		var err error
+		if params.CalendarID != "" {
+			events, err = c.GetCalendarView(params.RemoteUserID, params.CalendarID, params.StartTime, params.EndTime)
+		} else {
+			events, err = c.GetEventsBetweenDates(params.RemoteUserID, params.StartTime, params.EndTime)
+		}
+		if err != nil {
+			res.Error = &remote.APIError{Message: err.Error()}
+		}
+		res.Events = events
+		result = append(result, res)
+	}
+	return result, nil
+}
+
+// GetSchedule builds the free/busy schedule of the user from their events.
+// A user client can only read its own calendar, so requests for other users
+// fail individually.
+func (c *client) GetSchedule(requests []*remote.ScheduleUserInfo, startTime, endTime *remote.DateTime, availabilityViewInterval int) ([]*remote.ScheduleInformation, error) {
+	start, end := startTime.Time(), endTime.Time()
+	result := []*remote.ScheduleInformation{}
+	for _, req := range requests {
+		if req.RemoteUserID != c.remoteUserID() {
+			result = append(result, &remote.ScheduleInformation{
+				ScheduleID: req.Mail,
+				Error: &remote.ScheduleInformationError{
+					ResponseCode: "ErrorAccessDenied",
+					Message:      "an ICS feed only holds the calendar of its owner",
+				},
+			})
+			continue
+		}
+
+		events, err := c.GetEventsBetweenDates(req.RemoteUserID, start, end)
+		if err != nil {
+			return nil, errors.Wrap(err, "ics GetSchedule")
+		}
+		result = append(result, remote.NewScheduleInformation(req.Mail, events, start, end, availabilityViewInterval))
+	}
+	return result, nil
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ics
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+const (
+	ErrorUserInactive = "You have been marked inactive because your ICS feed can no longer be read. Please disconnect and reconnect your account again."
+	LogUserInactive   = "User %s is inactive. Please disconnect and reconnect your account."
+
+	defaultTimeZone = "UTC"
+
+	// maxFeedSize bounds the size of a feed read from the network.
+	maxFeedSize = 10 * 1024 * 1024
+
+	remoteUserIDPrefix = "ics:"
+)
+
+// ErrFeedUnavailable is returned when the feed can't be read, the reason is
+// logged.
+var ErrFeedUnavailable = errors.New("unable to read the ICS feed, please check that its URL is public and serves a calendar")
+
+type client struct {
+	// caching the context here since it's a "single-use" client, usually used
+	// within a single API request
+	ctx context.Context
+
+	httpClient       *http.Client
+	feedURL          string
+	mattermostUserID string
+	tokenHelpers     remote.UserTokenHelpers
+
+	// calendar is fetched on first use.
+	calendar *ical.Component
+
+	bot.Logger
+	bot.Poster
+}
+
+// remoteUserID identifies the feed of the user without exposing its URL,
+// which usually embeds a secret. Public feeds can be connected by several
+// users, so the ID also depends on the Mattermost user.
+func (c *client) remoteUserID() string {
+	sum := sha256.Sum256([]byte(c.mattermostUserID + "\n" + c.feedURL))
+	return remoteUserIDPrefix + hex.EncodeToString(sum[:16])
+}
+
+// fetch downloads and parses the feed. The URL of the feed is chosen by the
+// user, so the reason it can't be read is logged rather than returned, not
+// to tell what is on the network of the server.
+func (c *client) fetch() (*ical.Component, error) {
+	if c.calendar != nil {
+		return c.calendar, nil
+	}
+
+	cal, err := c.download()
+	if err != nil {
+		// The URL usually embeds a secret, only its host is logged.
+		host := ""
+		if u, parseErr := url.Parse(c.feedURL); parseErr == nil {
+			host = u.Hostname()
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		c.Logger.With(bot.LogContext{
+			"mattermostUserID": c.mattermostUserID,
+			"host":             host,
+			"err":              err.Error(),
+		}).Warnf("ics: failed to read the feed.")
+		return nil, ErrFeedUnavailable
+	}
+
+	c.calendar = cal
+	return cal, nil
+}
+
+// download reads the feed. Any status other than 2xx is returned as an error
+// holding the HTTP status, e.g. "GET 404 Not Found".
+func (c *client) download() (*ical.Component, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, errors.Errorf("%s %s", http.MethodGet, resp.Status)
+	}
+
+	// A feed larger than the limit is refused rather than cut, which would
+	// silently drop its last events.
+	if resp.ContentLength > maxFeedSize {
+		return nil, errors.Errorf("the feed is larger than %d bytes", maxFeedSize)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFeedSize {
+		return nil, errors.Errorf("the feed is larger than %d bytes", maxFeedSize)
+	}
+
+	cal, err := ical.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ICS feed")
+	}
+	if cal.Name != ical.CompCalendar {
+		return nil, errors.Errorf("invalid ICS feed: unexpected %s component", cal.Name)
+	}
+	return cal, nil
+}
+
+// location returns the time zone floating times of the feed are interpreted
+// in, from the X-WR-TIMEZONE property or the first time zone definition.
+func location(cal *ical.Component) *time.Location {
+	name := cal.Text("X-WR-TIMEZONE")
+	if name == "" {
+		if timezones := cal.ChildrenNamed("VTIMEZONE"); len(timezones) > 0 {
+			name = timezones[0].Text("TZID")
+		}
+	}
+	if name == "" {
+		return time.UTC
+	}
+	return ical.LoadLocation(name, time.UTC)
+}
+
+func (c *client) checkUserConnected() error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+	return nil
+}
+
+func (c *client) GetMe() (*remote.User, error) {
+	cal, err := c.fetch()
+	if err != nil {
+		return nil, errors.Wrap(err, "ics GetMe")
+	}
+
+	displayName := strings.TrimSpace(cal.Text("X-WR-CALNAME"))
+	if displayName == "" {
+		displayName = c.feedURL
+		if u, err := url.Parse(c.feedURL); err == nil {
+			displayName = u.Hostname()
+		}
+	}
+
+	// A feed has no mailbox: the user can't be found by mail.
+	return &remote.User{
+		ID:          c.remoteUserID(),
+		DisplayName: displayName,
+	}, nil
+}
+
+func (c *client) GetMailboxSettings(_ string) (*remote.MailboxSettings, error) {
+	cal, err := c.fetch()
+	if err != nil {
+		return nil, errors.Wrap(err, "ics GetMailboxSettings")
+	}
+
+	timeZone := defaultTimeZone
+	if loc := location(cal); loc != time.UTC {
+		timeZone = loc.String()
+	}
+
+	settings := &remote.MailboxSettings{
+		TimeZone: timeZone,
+		WorkingHours: remote.WorkingHours{
+			StartTime:  "08:00:00.0000000",
+			EndTime:    "17:00:00.0000000",
+			DaysOfWeek: []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+		},
+	}
+	settings.WorkingHours.TimeZone.Name = timeZone
+	return settings, nil
+}
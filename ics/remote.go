@@ -0,0 +1,147 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ics
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// Kind is the first argument of the connect command for ICS feeds, as in
+// `/mscalendar connect ics <url>`.
+const Kind = "ics"
+
+// TokenType marks the tokens of users connected to an ICS feed. The access
+// token holds the feed URL.
+const TokenType = "ICS"
+
+const fetchTimeout = 30 * time.Second
+
+// impl serves the users connected to an ICS feed, and forwards everything
+// else to the remote of the configured provider.
+type impl struct {
+	remote.Remote
+
+	conf       *config.Config
+	logger     bot.Logger
+	httpClient *http.Client
+}
+
+// WithFeeds wraps the maker of a provider so that users can also connect a
+// read-only ICS feed instead of an account of the provider.
+func WithFeeds(maker func(*config.Config, bot.Logger) remote.Remote) func(*config.Config, bot.Logger) remote.Remote {
+	return func(conf *config.Config, logger bot.Logger) remote.Remote {
+		return newRemote(maker(conf, logger), conf, logger, newFeedHTTPClient())
+	}
+}
+
+func newRemote(primary remote.Remote, conf *config.Config, logger bot.Logger, httpClient *http.Client) *impl {
+	return &impl{
+		Remote:     primary,
+		conf:       conf,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+func isFeedToken(token *oauth2.Token) bool {
+	return token != nil && token.TokenType == TokenType
+}
+
+// MakeUserClient creates a feed client for users connected to an ICS feed,
+// and a client of the provider for everyone else.
+func (r *impl) MakeUserClient(ctx context.Context, oauthToken *oauth2.Token, mattermostUserID string, poster bot.Poster, userTokenHelpers remote.UserTokenHelpers) (remote.Client, error) {
+	if !isFeedToken(oauthToken) {
+		return r.Remote.MakeUserClient(ctx, oauthToken, mattermostUserID, poster, userTokenHelpers)
+	}
+
+	return r.makeClient(ctx, oauthToken.AccessToken, mattermostUserID, poster, userTokenHelpers), nil
+}
+
+func (r *impl) makeClient(ctx context.Context, feedURL, mattermostUserID string, poster bot.Poster, userTokenHelpers remote.UserTokenHelpers) *client {
+	return &client{
+		ctx:              ctx,
+		httpClient:       r.httpClient,
+		feedURL:          feedURL,
+		mattermostUserID: mattermostUserID,
+		tokenHelpers:     userTokenHelpers,
+		Logger:           r.logger,
+		Poster:           poster,
+	}
+}
+
+// RequiresUserClient is true for users connected to an ICS feed: the
+// superuser client of the provider can't read their calendar.
+func (r *impl) RequiresUserClient(token *oauth2.Token) bool {
+	return isFeedToken(token)
+}
+
+// OAuth2HTTPClient forwards the client of the provider for its OAuth2 token
+// endpoint, if any.
+func (r *impl) OAuth2HTTPClient() *http.Client {
+	if p, ok := r.Remote.(remote.OAuth2HTTPClientProvider); ok {
+		return p.OAuth2HTTPClient()
+	}
+	return nil
+}
+
+// NewTokenFromCredentials handles `connect ics <url>` by checking that the
+// URL serves a calendar. Other arguments are passed on to the provider, when
+// it supports connecting with credentials.
+func (r *impl) NewTokenFromCredentials(ctx context.Context, args []string) (*oauth2.Token, error) {
+	if len(args) == 0 || !strings.EqualFold(args[0], Kind) {
+		if connector, ok := r.Remote.(remote.CredentialsConnector); ok {
+			return connector.NewTokenFromCredentials(ctx, args)
+		}
+		return nil, errors.Errorf("unknown connection type, please use `%s <url>`", Kind)
+	}
+	if len(args) != 2 {
+		return nil, errors.New("please provide the URL of your ICS feed")
+	}
+
+	feedURL, err := parseFeedURL(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.makeClient(ctx, feedURL, "", nil, nil).fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: feedURL,
+		TokenType:   TokenType,
+	}, nil
+}
+
+// parseFeedURL validates the URL of a feed, turning webcal:// links into
+// https:// ones. Feeds are only read over HTTPS.
+func parseFeedURL(feedURL string) (string, error) {
+	// Mattermost renders pasted links as <url>.
+	feedURL = strings.Trim(strings.TrimSpace(feedURL), "<>")
+	u, err := url.Parse(feedURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return "", errors.New("invalid ICS feed URL")
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+	case "webcal", "webcals":
+		u.Scheme = "https"
+	default:
+		return "", errors.New("invalid ICS feed URL, please use an https:// or webcal:// link")
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package ics
+
+import (
+	"net/url"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// An ICS feed is read-only and has no push notifications: none of the write
+// or subscription methods are implemented.
+
+func (c *client) CreateEvent(_ string, _ *remote.Event) (*remote.Event, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) UpdateEvent(_, _ string, _ *remote.Event) (*remote.Event, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) CancelEvent(_, _, _ string) error {
+	return remote.ErrNotImplemented
+}
+
+func (c *client) DeleteEvent(_, _ string) error {
+	return remote.ErrNotImplemented
+}
+
+func (c *client) AcceptEvent(_, _ string, _ *remote.EventResponse) error {
+	return remote.ErrNotImplemented
+}
+
+func (c *client) DeclineEvent(_, _ string, _ *remote.EventResponse) error {
+	return remote.ErrNotImplemented
+}
+
+func (c *client) TentativelyAcceptEvent(_, _ string, _ *remote.EventResponse) error {
+	return remote.ErrNotImplemented
+}
+
+func (c *client) CreateCalendar(_ *remote.Calendar) (*remote.Calendar, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) DeleteCalendar(_ string) error {
+	return remote.ErrNotImplemented
+}
+
+func (c *client) FindMeetingTimes(_ *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetRoomLists() ([]*remote.RoomList, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetRooms(_ string) ([]*remote.Room, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) CreateMySubscription(_, _, _ string) (*remote.Subscription, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) DeleteSubscription(_ *remote.Subscription) error {
+	return remote.ErrNotImplemented
+}
+
+func (c *client) RenewSubscription(_, _ string, _ *remote.Subscription) (*remote.Subscription, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) ListSubscriptions() ([]*remote.Subscription, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetNotificationData(_ *remote.Notification) (*remote.Notification, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) GetSuperuserToken() (string, error) {
+	return "", remote.ErrSuperUserClientNotSupported
+}
+
+func (c *client) CallJSON(_, _ string, _, _ interface{}) ([]byte, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+func (c *client) CallFormPost(_, _ string, _ url.Values, _ interface{}) ([]byte, error) {
+	return nil, remote.ErrNotImplemented
+}
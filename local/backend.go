@@ -0,0 +1,826 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/ical"
+)
+
+const (
+	defaultCalendarName = "Calendar"
+	defaultTimeZone     = "UTC"
+	subscribeTTL        = 48 * time.Hour
+	deliveryTimeout     = 10 * time.Second
+
+	showAsFree      = "free"
+	showAsTentative = "tentative"
+	showAsBusy      = "busy"
+)
+
+var (
+	errEventNotFound        = errors.New("404 Not Found: the event was not found")
+	errCalendarNotFound     = errors.New("404 Not Found: the calendar was not found")
+	errEventCanceled        = errors.New("You can't respond to a meeting that's been canceled.")                              //nolint:revive
+	errNotOrganizer         = errors.New("Your request can't be completed. You need to be an organizer to cancel a meeting.") //nolint:revive
+	errOccurrenceChange     = errors.New("single occurrences of a recurring event can't be changed")
+	errDefaultCalendar      = errors.New("the default calendar cannot be deleted")
+	errSubscriptionNotFound = errors.New("404 Not Found: The object was not found")
+	errDeltaLinkExpired     = errors.New("410 Gone: the delta link has expired")
+)
+
+// defaultBackend is shared by every remote created through NewRemote, so data
+// survives configuration reloads for the lifetime of the plugin process.
+var defaultBackend = newBackend(nil)
+
+// backend holds all calendars, events and subscriptions of the local
+// provider. Mailboxes are keyed by remote user ID, which for this provider is
+// the Mattermost user ID.
+type backend struct {
+	lock          sync.RWMutex
+	hostname      string
+	mailboxes     map[string]*mailbox
+	subscriptions map[string]*remote.Subscription
+	deltas        map[string]*deltaSnapshot
+
+	// deliver posts a webhook payload to a subscription's notification URL.
+	deliver func(notificationURL string, payload []byte)
+}
+
+type mailbox struct {
+	user      *remote.User
+	settings  *remote.MailboxSettings
+	calendars []*remote.Calendar
+	events    map[string]*storedEvent
+}
+
+type storedEvent struct {
+	calendarID string
+	event      *remote.Event
+}
+
+// deltaSnapshot is the default calendar view of a user as returned by a
+// delta query, kept to compute the changes of the next query. Events are
+// kept marshaled, which makes comparing them straightforward.
+type deltaSnapshot struct {
+	remoteUserID string
+	start        time.Time
+	end          time.Time
+	events       map[string][]byte
+}
+
+func newBackend(deliver func(notificationURL string, payload []byte)) *backend {
+	b := &backend{
+		mailboxes:     map[string]*mailbox{},
+		subscriptions: map[string]*remote.Subscription{},
+		deltas:        map[string]*deltaSnapshot{},
+		deliver:       deliver,
+	}
+	if b.deliver == nil {
+		b.deliver = postWebhook
+	}
+	return b
+}
+
+func (b *backend) setHostname(hostname string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.hostname = hostname
+}
+
+func postWebhook(notificationURL string, payload []byte) {
+	httpClient := &http.Client{Timeout: deliveryTimeout}
+	resp, err := httpClient.Post(notificationURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+func defaultMailboxSettings() *remote.MailboxSettings {
+	settings := &remote.MailboxSettings{
+		TimeZone: defaultTimeZone,
+		WorkingHours: remote.WorkingHours{
+			StartTime:  "08:00:00.0000000",
+			EndTime:    "17:00:00.0000000",
+			DaysOfWeek: []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+		},
+	}
+	settings.WorkingHours.TimeZone.Name = defaultTimeZone
+	return settings
+}
+
+// mailbox returns the mailbox of the given user, creating it with a default
+// calendar the first time the user is seen. The caller must hold the write lock.
+func (b *backend) mailbox(remoteUserID string) *mailbox {
+	mb, ok := b.mailboxes[remoteUserID]
+	if ok {
+		return mb
+	}
+
+	hostname := b.hostname
+	if hostname == "" {
+		hostname = "localhost"
+	}
+
+	mb = &mailbox{
+		user: &remote.User{
+			ID:                remoteUserID,
+			DisplayName:       remoteUserID,
+			UserPrincipalName: remoteUserID + "@" + hostname,
+			Mail:              remoteUserID + "@" + hostname,
+		},
+		settings: defaultMailboxSettings(),
+		calendars: []*remote.Calendar{{
+			ID:   model.NewId(),
+			Name: defaultCalendarName,
+		}},
+		events: map[string]*storedEvent{},
+	}
+	mb.calendars[0].Owner = mb.user
+	b.mailboxes[remoteUserID] = mb
+	return mb
+}
+
+func (b *backend) mailboxByEmail(address string) *mailbox {
+	for _, mb := range b.mailboxes {
+		if strings.EqualFold(mb.user.Mail, address) {
+			return mb
+		}
+	}
+	return nil
+}
+
+func (mb *mailbox) defaultCalendarID() string {
+	return mb.calendars[0].ID
+}
+
+// calendarID returns the ID of the calendar, or of the default calendar when
+// id is empty.
+func (mb *mailbox) calendarID(id string) (string, error) {
+	if id == "" {
+		return mb.defaultCalendarID(), nil
+	}
+	for _, cal := range mb.calendars {
+		if cal.ID == id {
+			return id, nil
+		}
+	}
+	return "", errCalendarNotFound
+}
+
+func (mb *mailbox) eventByICalUID(iCalUID string) *storedEvent {
+	for _, se := range mb.events {
+		if se.event.ICalUID == iCalUID {
+			return se
+		}
+	}
+	return nil
+}
+
+func (b *backend) getMe(remoteUserID string) *remote.User {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	u := *b.mailbox(remoteUserID).user
+	return &u
+}
+
+func (b *backend) getMailboxSettings(remoteUserID string) *remote.MailboxSettings {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	settings := *b.mailbox(remoteUserID).settings
+	return &settings
+}
+
+func (b *backend) getCalendars(remoteUserID string) []*remote.Calendar {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	result := []*remote.Calendar{}
+	for _, cal := range b.mailbox(remoteUserID).calendars {
+		c := *cal
+		result = append(result, &c)
+	}
+	return result
+}
+
+func (b *backend) createCalendar(remoteUserID string, in *remote.Calendar) *remote.Calendar {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	mb := b.mailbox(remoteUserID)
+	cal := &remote.Calendar{
+		ID:    model.NewId(),
+		Name:  in.Name,
+		Owner: mb.user,
+	}
+	mb.calendars = append(mb.calendars, cal)
+
+	out := *cal
+	return &out
+}
+
+func (b *backend) deleteCalendar(remoteUserID, calendarID string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	mb := b.mailbox(remoteUserID)
+	if mb.defaultCalendarID() == calendarID {
+		return errDefaultCalendar
+	}
+
+	for i, cal := range mb.calendars {
+		if cal.ID != calendarID {
+			continue
+		}
+		mb.calendars = append(mb.calendars[:i], mb.calendars[i+1:]...)
+		for id, se := range mb.events {
+			if se.calendarID == calendarID {
+				delete(mb.events, id)
+			}
+		}
+		return nil
+	}
+
+	return errCalendarNotFound
+}
+
+func (b *backend) getEvent(remoteUserID, eventID string) (*remote.Event, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	mb := b.mailbox(remoteUserID)
+	if se, ok := mb.events[eventID]; ok {
+		return cloneEvent(se.event), nil
+	}
+
+	se, start, ok := mb.occurrence(eventID)
+	if !ok {
+		return nil, errEventNotFound
+	}
+	for _, e := range occurrences(se.event, start, start.Add(time.Second)) {
+		if e.ID == eventID {
+			return e, nil
+		}
+	}
+	return nil, errEventNotFound
+}
+
+// occurrence finds the series master of an occurrence ID, as returned by
+// getEventsBetween, and the start of the occurrence.
+func (mb *mailbox) occurrence(eventID string) (*storedEvent, time.Time, bool) {
+	masterID, start, ok := ical.ParseInstanceID(eventID)
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	se, ok := mb.events[masterID]
+	if !ok || se.event.Recurrence == nil {
+		return nil, time.Time{}, false
+	}
+	return se, start, true
+}
+
+// getEventsBetween returns the events of a calendar overlapping the given
+// range, sorted by start time. The default calendar is read when calendarID is
+// empty.
+func (b *backend) getEventsBetween(remoteUserID, calendarID string, start, end time.Time) ([]*remote.Event, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	mb := b.mailbox(remoteUserID)
+	calendarID, err := mb.calendarID(calendarID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*remote.Event{}
+	for _, se := range mb.events {
+		if se.calendarID != calendarID {
+			continue
+		}
+		result = append(result, occurrences(se.event, start, end)...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Time().Before(result[j].Start.Time())
+	})
+	return result, nil
+}
+
+// calendarViewDelta returns the changes to the default calendar view of the
+// user since the query that returned deltaLink, or the whole view when
+// deltaLink is empty. Delta links can be used once: the view is compared
+// to the snapshot taken by that query, which is replaced by a new one.
+func (b *backend) calendarViewDelta(remoteUserID string, start, end time.Time, deltaLink string) (*remote.EventsDelta, error) {
+	events, err := b.getEventsBetween(remoteUserID, "", start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var previous *deltaSnapshot
+	if deltaLink != "" {
+		previous = b.deltas[deltaLink]
+		delete(b.deltas, deltaLink)
+		if previous == nil || previous.remoteUserID != remoteUserID || !previous.start.Equal(start) || !previous.end.Equal(end) {
+			return nil, errDeltaLinkExpired
+		}
+	}
+
+	snapshot := &deltaSnapshot{
+		remoteUserID: remoteUserID,
+		start:        start,
+		end:          end,
+		events:       map[string][]byte{},
+	}
+	delta := &remote.EventsDelta{
+		DeltaLink: model.NewId(),
+		Events:    []*remote.Event{},
+		Reset:     previous == nil,
+	}
+	for _, e := range events {
+		var data []byte
+		data, err = json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.events[e.ID] = data
+		if previous == nil || !bytes.Equal(previous.events[e.ID], data) {
+			delta.Events = append(delta.Events, e)
+		}
+	}
+	if previous != nil {
+		for id := range previous.events {
+			if _, ok := snapshot.events[id]; !ok {
+				delta.RemovedIDs = append(delta.RemovedIDs, id)
+			}
+		}
+		sort.Strings(delta.RemovedIDs)
+	}
+
+	b.deltas[delta.DeltaLink] = snapshot
+	return delta, nil
+}
+
+// createEvent stores the event in a calendar of the organizer, their default
+// calendar when calendarID is empty, and sends an invitation to every
+// attendee that has a local mailbox.
+func (b *backend) createEvent(organizerID, calendarID string, in *remote.Event) (*remote.Event, error) {
+	b.lock.Lock()
+
+	organizer := b.mailbox(organizerID)
+	calendarID, err := organizer.calendarID(calendarID)
+	if err != nil {
+		b.lock.Unlock()
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	event := cloneEvent(in)
+	event.ID = model.NewId()
+	event.ICalUID = model.NewId()
+	event.IsOrganizer = true
+	event.ResponseRequested = false
+	event.Organizer = &remote.Attendee{
+		RemoteID: organizer.user.ID,
+		EmailAddress: &remote.EmailAddress{
+			Address: organizer.user.Mail,
+			Name:    organizer.user.DisplayName,
+		},
+	}
+	event.ResponseStatus = &remote.EventResponseStatus{
+		Response: remote.EventResponseStatusAccepted,
+		Time:     now,
+	}
+	if event.ShowAs == "" {
+		event.ShowAs = showAsBusy
+	}
+	if event.Importance == "" {
+		event.Importance = "normal"
+	}
+	if event.Location == nil {
+		event.Location = &remote.Location{}
+	}
+	if event.Body != nil && event.BodyPreview == "" {
+		event.BodyPreview = event.Body.Content
+	}
+	event.Type = remote.EventTypeSingleInstance
+	if event.Recurrence != nil {
+		event.Type = remote.EventTypeSeriesMaster
+	}
+
+	invited := []*mailbox{}
+	for _, a := range event.Attendees {
+		if a.Type == "" {
+			a.Type = "required"
+		}
+		a.Status = &remote.EventResponseStatus{Response: remote.EventResponseStatusNotAnswered}
+		if a.EmailAddress == nil {
+			continue
+		}
+		mb := b.mailboxByEmail(a.EmailAddress.Address)
+		if mb == nil || mb == organizer {
+			continue
+		}
+		a.RemoteID = mb.user.ID
+		if a.EmailAddress.Name == "" {
+			a.EmailAddress.Name = mb.user.DisplayName
+		}
+		invited = append(invited, mb)
+	}
+	event.ResponseRequested = len(invited) > 0
+
+	organizer.events[event.ID] = &storedEvent{
+		calendarID: calendarID,
+		event:      event,
+	}
+
+	notifications := b.notificationsFor(organizer.user.ID, event.ID, "created")
+	for _, mb := range invited {
+		invite := cloneEvent(event)
+		invite.ID = model.NewId()
+		invite.IsOrganizer = false
+		invite.ResponseRequested = true
+		invite.ShowAs = showAsTentative
+		invite.ResponseStatus = &remote.EventResponseStatus{
+			Response: remote.EventResponseStatusNotAnswered,
+		}
+		mb.events[invite.ID] = &storedEvent{
+			calendarID: mb.defaultCalendarID(),
+			event:      invite,
+		}
+		notifications = append(notifications, b.notificationsFor(mb.user.ID, invite.ID, "created")...)
+	}
+
+	out := cloneEvent(event)
+	b.lock.Unlock()
+
+	b.send(notifications)
+	return out, nil
+}
+
+// respondToEvent records an attendee response and, when it is sent, reflects
+// it on the organizer's copy of the event along with any proposed new time.
+// Responding to an occurrence of a recurring event answers the whole series.
+func (b *backend) respondToEvent(remoteUserID, eventID, response string, details *remote.EventResponse) error {
+	b.lock.Lock()
+
+	mb := b.mailbox(remoteUserID)
+	se, ok := mb.events[eventID]
+	if !ok {
+		se, _, ok = mb.occurrence(eventID)
+	}
+	if !ok {
+		b.lock.Unlock()
+		return errEventNotFound
+	}
+	eventID = se.event.ID
+	if se.event.IsCancelled {
+		b.lock.Unlock()
+		return errEventCanceled
+	}
+
+	status := &remote.EventResponseStatus{
+		Response: response,
+		Time:     time.Now().UTC().Format(time.RFC3339),
+	}
+	se.event.ResponseStatus = status
+	switch response {
+	case remote.EventResponseStatusAccepted:
+		se.event.ShowAs = showAsBusy
+	case remote.EventResponseStatusDeclined:
+		se.event.ShowAs = showAsFree
+	default:
+		se.event.ShowAs = showAsTentative
+	}
+
+	notifications := b.notificationsFor(remoteUserID, eventID, "updated")
+	if details.SendResponse && se.event.Organizer != nil && se.event.Organizer.RemoteID != "" && se.event.Organizer.RemoteID != remoteUserID {
+		organizer := b.mailbox(se.event.Organizer.RemoteID)
+		if original := organizer.eventByICalUID(se.event.ICalUID); original != nil {
+			for _, a := range original.event.Attendees {
+				if a.RemoteID == remoteUserID {
+					s := *status
+					a.Status = &s
+					a.ProposedNewTime = details.ProposedNewTime
+				}
+			}
+			notifications = append(notifications, b.notificationsFor(organizer.user.ID, original.event.ID, "updated")...)
+		}
+	}
+	b.lock.Unlock()
+
+	b.send(notifications)
+	return nil
+}
+
+// updateEvent applies the fields set on the given event to the user's copy
+// of the event. Changes made by the organizer are sent to every attendee.
+func (b *backend) updateEvent(remoteUserID, eventID string, in *remote.Event) (*remote.Event, error) {
+	b.lock.Lock()
+
+	mb := b.mailbox(remoteUserID)
+	se, err := mb.changeableEvent(eventID)
+	if err != nil {
+		b.lock.Unlock()
+		return nil, err
+	}
+
+	applyUpdate(se.event, in)
+	notifications := b.notificationsFor(remoteUserID, se.event.ID, "updated")
+	if se.event.IsOrganizer {
+		for _, other := range b.mailboxes {
+			if other == mb {
+				continue
+			}
+			if invite := other.eventByICalUID(se.event.ICalUID); invite != nil {
+				applyUpdate(invite.event, in)
+				notifications = append(notifications, b.notificationsFor(other.user.ID, invite.event.ID, "updated")...)
+			}
+		}
+	}
+	out := cloneEvent(se.event)
+	b.lock.Unlock()
+
+	b.send(notifications)
+	return out, nil
+}
+
+// deleteEvent removes the event from the user's calendar. When the user
+// organizes the event, the copies of the attendees are marked as cancelled,
+// as Outlook does. Only organizers can cancel an event.
+func (b *backend) deleteEvent(remoteUserID, eventID string, cancel bool) error {
+	b.lock.Lock()
+
+	mb := b.mailbox(remoteUserID)
+	se, err := mb.changeableEvent(eventID)
+	if err == nil && cancel && !se.event.IsOrganizer {
+		err = errNotOrganizer
+	}
+	if err != nil {
+		b.lock.Unlock()
+		return err
+	}
+	delete(mb.events, se.event.ID)
+
+	notifications := b.notificationsFor(remoteUserID, se.event.ID, "deleted")
+	if se.event.IsOrganizer {
+		for _, other := range b.mailboxes {
+			if other == mb {
+				continue
+			}
+			invite := other.eventByICalUID(se.event.ICalUID)
+			if invite == nil || invite.event.IsCancelled {
+				continue
+			}
+			invite.event.IsCancelled = true
+			invite.event.ShowAs = showAsFree
+			invite.event.Subject = "Canceled: " + invite.event.Subject
+			notifications = append(notifications, b.notificationsFor(other.user.ID, invite.event.ID, "updated")...)
+		}
+	}
+	b.lock.Unlock()
+
+	b.send(notifications)
+	return nil
+}
+
+// changeableEvent returns the stored event with the given ID. Occurrences of
+// recurring events can only be changed through their series.
+func (mb *mailbox) changeableEvent(eventID string) (*storedEvent, error) {
+	if se, ok := mb.events[eventID]; ok {
+		return se, nil
+	}
+	if _, _, ok := mb.occurrence(eventID); ok {
+		return nil, errOccurrenceChange
+	}
+	return nil, errEventNotFound
+}
+
+// applyUpdate copies the fields set on in to the event.
+func applyUpdate(e, in *remote.Event) {
+	in = cloneEvent(in)
+	if in.Subject != "" {
+		e.Subject = in.Subject
+	}
+	if in.Body != nil {
+		e.Body = in.Body
+		e.BodyPreview = in.Body.Content
+	}
+	if in.Location != nil {
+		e.Location = in.Location
+	}
+	if in.Start != nil && in.End != nil {
+		e.Start = in.Start
+		e.End = in.End
+		e.IsAllDay = in.IsAllDay
+	}
+	if in.Recurrence != nil {
+		e.Recurrence = in.Recurrence
+		e.Type = remote.EventTypeSeriesMaster
+	}
+	if in.Importance != "" {
+		e.Importance = in.Importance
+	}
+	if in.ReminderMinutesBeforeStart != 0 {
+		e.ReminderMinutesBeforeStart = in.ReminderMinutesBeforeStart
+	}
+}
+
+func (b *backend) createSubscription(remoteUserID, notificationURL string) *remote.Subscription {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.mailbox(remoteUserID)
+	sub := &remote.Subscription{
+		ID:                 model.NewId(),
+		Resource:           "users/" + remoteUserID + "/events",
+		ChangeType:         "created,updated,deleted",
+		NotificationURL:    notificationURL,
+		ExpirationDateTime: time.Now().Add(subscribeTTL).Format(time.RFC3339),
+		ClientState:        newRandomString(),
+		CreatorID:          remoteUserID,
+	}
+	b.subscriptions[sub.ID] = sub
+
+	out := *sub
+	return &out
+}
+
+func (b *backend) renewSubscription(subscriptionID string) (*remote.Subscription, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	sub, ok := b.subscriptions[subscriptionID]
+	if !ok {
+		return nil, errSubscriptionNotFound
+	}
+	sub.ExpirationDateTime = time.Now().Add(subscribeTTL).Format(time.RFC3339)
+
+	out := *sub
+	return &out, nil
+}
+
+func (b *backend) deleteSubscription(subscriptionID string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.subscriptions[subscriptionID]; !ok {
+		return errSubscriptionNotFound
+	}
+	delete(b.subscriptions, subscriptionID)
+	return nil
+}
+
+// listSubscriptions returns the subscriptions created by the given user, or
+// all of them if remoteUserID is empty.
+func (b *backend) listSubscriptions(remoteUserID string) []*remote.Subscription {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	result := []*remote.Subscription{}
+	for _, sub := range b.subscriptions {
+		if remoteUserID != "" && sub.CreatorID != remoteUserID {
+			continue
+		}
+		s := *sub
+		result = append(result, &s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+type pendingNotification struct {
+	url     string
+	payload []byte
+}
+
+// notificationsFor builds the webhook payloads for every subscription of the
+// given user. The caller must hold the lock.
+func (b *backend) notificationsFor(remoteUserID, eventID, changeType string) []pendingNotification {
+	result := []pendingNotification{}
+	for _, sub := range b.subscriptions {
+		if sub.CreatorID != remoteUserID || sub.NotificationURL == "" {
+			continue
+		}
+
+		payload, err := json.Marshal(struct {
+			Value []*webhook `json:"value"`
+		}{
+			Value: []*webhook{{
+				ChangeType:                     changeType,
+				ClientState:                    sub.ClientState,
+				Resource:                       eventResource(remoteUserID, eventID),
+				SubscriptionExpirationDateTime: sub.ExpirationDateTime,
+				SubscriptionID:                 sub.ID,
+			}},
+		})
+		if err != nil {
+			continue
+		}
+		result = append(result, pendingNotification{url: sub.NotificationURL, payload: payload})
+	}
+	return result
+}
+
+func (b *backend) send(notifications []pendingNotification) {
+	for _, n := range notifications {
+		go b.deliver(n.url, n.payload)
+	}
+}
+
+func overlaps(e *remote.Event, start, end time.Time) bool {
+	if e.Start == nil || e.End == nil {
+		return false
+	}
+	return e.Start.Time().Before(end) && e.End.Time().After(start)
+}
+
+// occurrences returns copies of the occurrences of the event overlapping the
+// given range. Events that don't repeat are their own single occurrence.
+func occurrences(e *remote.Event, start, end time.Time) []*remote.Event {
+	if e.Recurrence == nil || e.Start == nil || e.End == nil {
+		if !overlaps(e, start, end) {
+			return nil
+		}
+		return []*remote.Event{cloneEvent(e)}
+	}
+
+	dtstart := e.Start.Time()
+	d := e.End.Time().Sub(dtstart)
+	r, err := ical.RecurrenceFromRemote(e.Recurrence, dtstart)
+	if err != nil {
+		// Show the first occurrence rather than nothing.
+		r = &ical.Recurrence{Freq: ical.FreqDaily, Interval: 1, Count: 1}
+	}
+
+	result := []*remote.Event{}
+	for _, t := range r.Occurrences(dtstart, d, start, end) {
+		o := cloneEvent(e)
+		o.ID = ical.InstanceID(e.ID, t)
+		o.Type = remote.EventTypeOccurrence
+		o.SeriesMasterID = e.ID
+		o.Recurrence = nil
+		o.Start = remote.NewDateTime(t.UTC(), "UTC")
+		o.End = remote.NewDateTime(t.Add(d).UTC(), "UTC")
+		result = append(result, o)
+	}
+	return result
+}
+
+// cloneEvent deep-copies an event so that callers can freely modify what the
+// backend returns, as the views do when converting time zones.
+func cloneEvent(in *remote.Event) *remote.Event {
+	out := &remote.Event{}
+	data, err := json.Marshal(in)
+	if err != nil {
+		return out
+	}
+	_ = json.Unmarshal(data, out)
+	return out
+}
+
+// remoteUserIDByEmail resolves the address of a local mailbox, returning an
+// empty string for unknown addresses.
+func (b *backend) remoteUserIDByEmail(address string) string {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	mb := b.mailboxByEmail(address)
+	if mb == nil {
+		return ""
+	}
+	return mb.user.ID
+}
+
+// isBusy reports whether the user has an event in the given range that is
+// not shown as free.
+func (b *backend) isBusy(remoteUserID string, start, end time.Time) bool {
+	events, _ := b.getEventsBetween(remoteUserID, "", start, end)
+	for _, e := range events {
+		if e.IsCancelled || e.ShowAs == showAsFree {
+			continue
+		}
+		return true
+	}
+	return false
+}
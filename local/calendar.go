@@ -0,0 +1,222 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const (
+	defaultMeetingDuration  = 30 * time.Minute
+	defaultMaxCandidates    = 5
+	defaultSearchWindow     = 7 * 24 * time.Hour
+	meetingTimeSearchStep   = 30 * time.Minute
+	emptySuggestionNoSlots  = "AttendeesUnavailable"
+	suggestionReasonAllFree = "Suggested because it is free for all attendees."
+)
+
+func (c *client) GetCalendars(remoteUserID string) ([]*remote.Calendar, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	return c.backend.getCalendars(remoteUserID), nil
+}
+
+// GetSharedCalendars returns no calendars: calendars of the in-memory store
+// are not shared between users.
+func (c *client) GetSharedCalendars(_ string) ([]*remote.SharedCalendar, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	return []*remote.SharedCalendar{}, nil
+}
+
+// GetRoomLists returns no room lists: the in-memory store has no rooms.
+func (c *client) GetRoomLists() ([]*remote.RoomList, error) {
+	return []*remote.RoomList{}, nil
+}
+
+// GetRooms returns no rooms: the in-memory store has no rooms.
+func (c *client) GetRooms(_ string) ([]*remote.Room, error) {
+	return []*remote.Room{}, nil
+}
+
+func (c *client) GetDefaultCalendarView(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	return c.GetEventsBetweenDates(remoteUserID, start, end)
+}
+
+func (c *client) GetCalendarView(remoteUserID, calendarID string, start, end time.Time) ([]*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	return c.backend.getEventsBetween(remoteUserID, calendarID, start, end)
+}
+
+// DoBatchCalendarViewDeltaRequests computes the delta of every request from
+// the in-memory store, preserving the order of the parameters.
+func (c *client) DoBatchCalendarViewDeltaRequests(allParams []*remote.CalendarViewDeltaParams) ([]*remote.CalendarViewDeltaResponse, error) {
+	result := []*remote.CalendarViewDeltaResponse{}
+	for _, params := range allParams {
+		res := &remote.CalendarViewDeltaResponse{RemoteUserID: params.RemoteUserID}
+		delta, err := c.backend.calendarViewDelta(params.RemoteUserID, params.StartTime, params.EndTime, params.DeltaLink)
+		if err != nil {
+			res.Error = &remote.APIError{Message: err.Error()}
+			res.Expired = errors.Is(err, errDeltaLinkExpired)
+		}
+		res.Delta = delta
+		result = append(result, res)
+	}
+	return result, nil
+}
+
+// DoBatchViewCalendarRequests answers every request from the in-memory
+// store, preserving the order of the parameters.
+func (c *client) DoBatchViewCalendarRequests(allParams []*remote.ViewCalendarParams) ([]*remote.ViewCalendarResponse, error) {
+	result := []*remote.ViewCalendarResponse{}
+	for _, params := range allParams {
+		res := &remote.ViewCalendarResponse{RemoteUserID: params.RemoteUserID}
+		events, err := c.backend.getEventsBetween(params.RemoteUserID, params.CalendarID, params.StartTime, params.EndTime)
+		if err != nil {
+			res.Error = &remote.APIError{Message: err.Error()}
+		}
+		res.Events = events
+		result = append(result, res)
+	}
+	return result, nil
+}
+
+// GetSchedule builds the free/busy schedule of every requested user from
+// their events in the in-memory store.
+func (c *client) GetSchedule(requests []*remote.ScheduleUserInfo, startTime, endTime *remote.DateTime, availabilityViewInterval int) ([]*remote.ScheduleInformation, error) {
+	start, end := startTime.Time(), endTime.Time()
+	result := []*remote.ScheduleInformation{}
+	for _, req := range requests {
+		events, _ := c.backend.getEventsBetween(req.RemoteUserID, "", start, end)
+		result = append(result, remote.NewScheduleInformation(req.Mail, events, start, end, availabilityViewInterval))
+	}
+	return result, nil
+}
+
+func (c *client) CreateCalendar(calIn *remote.Calendar) (*remote.Calendar, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	return c.backend.createCalendar(c.remoteUserID(), calIn), nil
+}
+
+func (c *client) DeleteCalendar(calID string) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+
+	err := c.backend.deleteCalendar(c.remoteUserID(), calID)
+	if err != nil {
+		return errors.Wrap(err, "local DeleteCalendar")
+	}
+	return nil
+}
+
+// FindMeetingTimes suggests slots within the time constraint in which the
+// organizer and every local attendee are free.
+func (c *client) FindMeetingTimes(params *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	duration := defaultMeetingDuration
+	if params.MeetingDuration != nil && *params.MeetingDuration > 0 {
+		duration = *params.MeetingDuration
+	}
+	maxCandidates := defaultMaxCandidates
+	if params.MaxCandidates != nil && *params.MaxCandidates > 0 {
+		maxCandidates = *params.MaxCandidates
+	}
+
+	now := time.Now().UTC().Truncate(meetingTimeSearchStep).Add(meetingTimeSearchStep)
+	slots := []remote.TimeSlot{{
+		Start: remote.NewDateTime(now, "UTC"),
+		End:   remote.NewDateTime(now.Add(defaultSearchWindow), "UTC"),
+	}}
+	if params.TimeConstraint != nil && len(params.TimeConstraint.TimeSlots) > 0 {
+		slots = params.TimeConstraint.TimeSlots
+	}
+
+	remoteUserIDs := []string{c.remoteUserID()}
+	attendees := []*remote.Attendee{}
+	for i := range params.Attendees {
+		a := params.Attendees[i]
+		if a.EmailAddress == nil {
+			continue
+		}
+		if id := c.backend.remoteUserIDByEmail(a.EmailAddress.Address); id != "" {
+			remoteUserIDs = append(remoteUserIDs, id)
+		}
+		attendees = append(attendees, &a)
+	}
+
+	result := &remote.MeetingTimeSuggestionResults{
+		MeetingTimeSuggestions: []*remote.MeetingTimeSuggestion{},
+	}
+	for _, slot := range slots {
+		if slot.Start == nil || slot.End == nil {
+			continue
+		}
+		end := slot.End.Time()
+		for start := slot.Start.Time(); !start.Add(duration).After(end); start = start.Add(meetingTimeSearchStep) {
+			if len(result.MeetingTimeSuggestions) >= maxCandidates {
+				return result, nil
+			}
+			if c.anyBusy(remoteUserIDs, start, start.Add(duration)) {
+				continue
+			}
+
+			availability := []*remote.AttendeeAvailability{}
+			for _, a := range attendees {
+				availability = append(availability, &remote.AttendeeAvailability{
+					Attendee:     a,
+					Availability: showAsFree,
+				})
+			}
+			result.MeetingTimeSuggestions = append(result.MeetingTimeSuggestions, &remote.MeetingTimeSuggestion{
+				MeetingTimeSlot: &remote.TimeSlot{
+					Start: remote.NewDateTime(start.UTC(), "UTC"),
+					End:   remote.NewDateTime(start.Add(duration).UTC(), "UTC"),
+				},
+				SuggestionReason:      suggestionReasonAllFree,
+				OrganizerAvailability: showAsFree,
+				AttendeeAvailability:  availability,
+				Confidence:            100,
+				Order:                 int32(len(result.MeetingTimeSuggestions) + 1),
+			})
+		}
+	}
+
+	if len(result.MeetingTimeSuggestions) == 0 {
+		result.EmptySuggestionReason = emptySuggestionNoSlots
+	}
+	return result, nil
+}
+
+func (c *client) anyBusy(remoteUserIDs []string, start, end time.Time) bool {
+	for _, id := range remoteUserIDs {
+		if c.backend.isBusy(id, start, end) {
+			return true
+		}
+	}
+	return false
+}
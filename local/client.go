@@ -0,0 +1,32 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"context"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+type client struct {
+	// caching the context here since it's a "single-use" client, usually used
+	// within a single API request
+	ctx context.Context
+
+	conf             *config.Config
+	backend          *backend
+	mattermostUserID string
+	tokenHelpers     remote.UserTokenHelpers
+
+	bot.Logger
+	bot.Poster
+}
+
+// remoteUserID returns the local remote ID of the acting user. The local
+// provider uses Mattermost user IDs as remote user IDs.
+func (c *client) remoteUserID() string {
+	return c.mattermostUserID
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func (c *client) GetEvent(_, eventID string) (*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	event, err := c.backend.getEvent(c.remoteUserID(), eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "local GetEvent")
+	}
+	return event, nil
+}
+
+// CreateEvent creates a calendar event
+func (c *client) CreateEvent(calendarID string, in *remote.Event) (*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	return c.backend.createEvent(c.remoteUserID(), calendarID, in)
+}
+
+// UpdateEvent changes the fields set on the given event, leaving the others
+// as they are.
+func (c *client) UpdateEvent(_, eventID string, in *remote.Event) (*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	event, err := c.backend.updateEvent(c.remoteUserID(), eventID, in)
+	if err != nil {
+		return nil, errors.Wrap(err, "local UpdateEvent")
+	}
+	return event, nil
+}
+
+// CancelEvent cancels a meeting organized by the user. There is no mail to
+// send the comment with, so it is dropped.
+func (c *client) CancelEvent(_, eventID, _ string) error {
+	return c.deleteEvent(eventID, true, "local CancelEvent")
+}
+
+func (c *client) DeleteEvent(_, eventID string) error {
+	return c.deleteEvent(eventID, false, "local DeleteEvent")
+}
+
+func (c *client) deleteEvent(eventID string, cancel bool, errContext string) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+
+	err := c.backend.deleteEvent(c.remoteUserID(), eventID, cancel)
+	if err != nil {
+		return errors.Wrap(err, errContext)
+	}
+	return nil
+}
+
+func (c *client) AcceptEvent(_, eventID string, details *remote.EventResponse) error {
+	return c.respond(eventID, remote.EventResponseStatusAccepted, details, "local AcceptEvent")
+}
+
+func (c *client) DeclineEvent(_, eventID string, details *remote.EventResponse) error {
+	return c.respond(eventID, remote.EventResponseStatusDeclined, details, "local DeclineEvent")
+}
+
+func (c *client) TentativelyAcceptEvent(_, eventID string, details *remote.EventResponse) error {
+	return c.respond(eventID, remote.EventResponseStatusTentative, details, "local TentativelyAcceptEvent")
+}
+
+// respond records the response of the user. There is no mail to send the
+// comment with, so it is dropped.
+func (c *client) respond(eventID, response string, details *remote.EventResponse, errContext string) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+
+	err := c.backend.respondToEvent(c.remoteUserID(), eventID, response, details)
+	if err != nil {
+		return errors.Wrap(err, errContext)
+	}
+	return nil
+}
+
+func (c *client) GetEventsBetweenDates(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	return c.backend.getEventsBetween(remoteUserID, "", start, end)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const renewSubscriptionBeforeExpiration = 12 * time.Hour
+
+// webhook mirrors the Microsoft Graph change notification payload, so the
+// local provider exercises the same notification pipeline.
+type webhook struct {
+	ChangeType                     string `json:"changeType"`
+	ClientState                    string `json:"clientState,omitempty"`
+	Resource                       string `json:"resource,omitempty"`
+	SubscriptionExpirationDateTime string `json:"subscriptionExpirationDateTime,omitempty"`
+	SubscriptionID                 string `json:"subscriptionId"`
+}
+
+func eventResource(remoteUserID, eventID string) string {
+	return "users/" + remoteUserID + "/events/" + eventID
+}
+
+// parseEventResource splits a resource built by eventResource into its
+// remote user ID and event ID.
+func parseEventResource(resource string) (remoteUserID, eventID string, ok bool) {
+	parts := strings.Split(resource, "/")
+	if len(parts) != 4 || parts[0] != "users" || parts[2] != "events" {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote.Notification {
+	rawData, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		r.logger.Infof("local: failed to process webhook: `%v`.", err)
+		return nil
+	}
+
+	var v struct {
+		Value []*webhook `json:"value"`
+	}
+	err = json.Unmarshal(rawData, &v)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		r.logger.Infof("local: failed to process webhook: `%v`.", err)
+		return nil
+	}
+
+	// Each notification keeps only its own entry of the webhook, not the
+	// whole payload.
+	var entries struct {
+		Value []json.RawMessage `json:"value"`
+	}
+	err = json.Unmarshal(rawData, &entries)
+	if err != nil || len(entries.Value) != len(v.Value) {
+		w.WriteHeader(http.StatusBadRequest)
+		r.logger.Infof("local: failed to process webhook: `%v`.", err)
+		return nil
+	}
+
+	notifications := []*remote.Notification{}
+	for i, wh := range v.Value {
+		if wh == nil {
+			continue
+		}
+
+		n := &remote.Notification{
+			SubscriptionID: wh.SubscriptionID,
+			ChangeType:     wh.ChangeType,
+			ClientState:    wh.ClientState,
+			IsBare:         true,
+			WebhookRawData: entries.Value[i],
+			Webhook:        wh,
+		}
+
+		// A deleted event cannot be fetched anymore.
+		if wh.ChangeType == remote.ChangeTypeDeleted {
+			_, eventID, _ := parseEventResource(wh.Resource)
+			n.Event = &remote.Event{ID: eventID}
+			n.IsBare = false
+		}
+
+		expires, err := time.Parse(time.RFC3339, wh.SubscriptionExpirationDateTime)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			r.logger.With(bot.LogContext{
+				"SubscriptionID": wh.SubscriptionID,
+			}).Infof("local: invalid subscription expiration in webhook: `%v`.", err)
+			return nil
+		}
+		if time.Now().After(expires.Add(-renewSubscriptionBeforeExpiration)) {
+			n.RecommendRenew = true
+		}
+
+		notifications = append(notifications, n)
+	}
+
+	return notifications
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+)
+
+const (
+	ProviderLocal            = Kind
+	ProviderLocalDisplayName = "Local Calendar"
+	ProviderLocalRepository  = "mattermost-plugin-mscalendar"
+)
+
+func GetLocalProviderConfig() config.ProviderConfig {
+	return config.ProviderConfig{
+		Name:        ProviderLocal,
+		DisplayName: ProviderLocalDisplayName,
+		Repository:  ProviderLocalRepository,
+
+		CommandTrigger: ProviderLocal,
+
+		TelemetryShortName: ProviderLocal,
+
+		BotUsername:    ProviderLocal,
+		BotDisplayName: ProviderLocalDisplayName,
+
+		Features: config.ProviderFeatures{
+			EncryptedStore:     false,
+			EventNotifications: true,
+		},
+	}
+}
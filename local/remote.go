@@ -0,0 +1,125 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+const Kind = "local"
+
+// tokenScheme is served in-process by tokenTransport, so the OAuth2 code
+// exchange performed by the engine never leaves the plugin.
+const tokenScheme = "local"
+
+type impl struct {
+	conf    *config.Config
+	logger  bot.Logger
+	backend *backend
+}
+
+// oauth2HTTPClient exchanges OAuth2 codes and tokens with tokenTransport.
+var oauth2HTTPClient = &http.Client{Transport: tokenTransport{}}
+
+func init() {
+	remote.Makers[Kind] = NewRemote
+}
+
+// NewRemote creates a remote backed by the process-wide in-memory calendar
+// store. Calendar data is shared across configuration reloads and is lost when
+// the plugin restarts.
+func NewRemote(conf *config.Config, logger bot.Logger) remote.Remote {
+	return newRemoteWithBackend(conf, logger, defaultBackend)
+}
+
+func newRemoteWithBackend(conf *config.Config, logger bot.Logger, b *backend) *impl {
+	b.setHostname(conf.MattermostSiteHostname)
+	return &impl{
+		conf:    conf,
+		logger:  logger,
+		backend: b,
+	}
+}
+
+func (r *impl) makeClient(ctx context.Context, mattermostUserID string, poster bot.Poster, userTokenHelpers remote.UserTokenHelpers) *client {
+	return &client{
+		ctx:              ctx,
+		conf:             r.conf,
+		backend:          r.backend,
+		mattermostUserID: mattermostUserID,
+		tokenHelpers:     userTokenHelpers,
+		Logger:           r.logger,
+		Poster:           poster,
+	}
+}
+
+// MakeUserClient creates a new client acting on behalf of the connected user.
+// The stored token is only checked for presence: the in-memory backend has no
+// notion of credentials.
+func (r *impl) MakeUserClient(ctx context.Context, oauthToken *oauth2.Token, mattermostUserID string, poster bot.Poster, userTokenHelpers remote.UserTokenHelpers) (remote.Client, error) {
+	if oauthToken == nil {
+		err := errors.New(ErrorUserInactive)
+		userTokenHelpers.DisconnectUserFromStoreIfNecessary(err, mattermostUserID)
+		return nil, errors.Wrap(err, "local MakeUserClient")
+	}
+
+	return r.makeClient(ctx, mattermostUserID, poster, userTokenHelpers), nil
+}
+
+// MakeSuperuserClient creates a client that can read every local calendar,
+// used by the status sync and daily summary jobs.
+func (r *impl) MakeSuperuserClient(ctx context.Context) (remote.Client, error) {
+	return r.makeClient(ctx, "", nil, noopUserTokenHelpers{}), nil
+}
+
+// noopUserTokenHelpers treats the superuser as always connected.
+type noopUserTokenHelpers struct{}
+
+func (noopUserTokenHelpers) CheckUserConnected(string) bool { return true }
+func (noopUserTokenHelpers) DisconnectUserFromStoreIfNecessary(error, string) {
+}
+func (noopUserTokenHelpers) RefreshAndStoreToken(token *oauth2.Token, _ *oauth2.Config, _ string) (*oauth2.Token, error) {
+	return token, nil
+}
+
+// NewOAuth2Config returns a configuration whose authorization step redirects
+// straight back to the plugin with a fixed code, and whose token endpoint is
+// answered in-process by tokenTransport.
+func (r *impl) NewOAuth2Config() *oauth2.Config {
+	redirectURL := r.conf.PluginURL + config.FullPathOAuth2Redirect
+	return &oauth2.Config{
+		ClientID:     Kind,
+		ClientSecret: Kind,
+		RedirectURL:  redirectURL,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   redirectURL + "?code=" + authorizationCode,
+			TokenURL:  tokenScheme + "://token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+}
+
+// OAuth2HTTPClient returns the client answering the token endpoint of
+// NewOAuth2Config in-process.
+func (r *impl) OAuth2HTTPClient() *http.Client {
+	return oauth2HTTPClient
+}
+
+func (r *impl) CheckConfiguration(_ config.StoredConfig) error {
+	return nil
+}
+
+const (
+	authorizationCode = "local"
+	tokenLifetime     = 10 * 365 * 24 * time.Hour
+)
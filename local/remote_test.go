@@ -0,0 +1,477 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+type deliveryRecorder struct {
+	lock     sync.Mutex
+	payloads [][]byte
+	received chan struct{}
+}
+
+func newDeliveryRecorder() *deliveryRecorder {
+	return &deliveryRecorder{received: make(chan struct{}, 100)}
+}
+
+func (d *deliveryRecorder) deliver(_ string, payload []byte) {
+	d.lock.Lock()
+	d.payloads = append(d.payloads, payload)
+	d.lock.Unlock()
+	d.received <- struct{}{}
+}
+
+func (d *deliveryRecorder) wait(t *testing.T, n int) [][]byte {
+	for i := 0; i < n; i++ {
+		select {
+		case <-d.received:
+		case <-time.After(time.Second):
+			t.Fatalf("expected %d notifications, got %d", n, i)
+		}
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.payloads
+}
+
+func newTestRemote(deliver func(string, []byte)) *impl {
+	conf := &config.Config{
+		PluginURL:              "http://localhost:8065/plugins/com.mattermost.mscalendar",
+		MattermostSiteHostname: "example.com",
+	}
+	return newRemoteWithBackend(conf, &bot.NilLogger{}, newBackend(deliver))
+}
+
+func newTestClient(t *testing.T, r *impl, mattermostUserID string) remote.Client {
+	c, err := r.MakeUserClient(context.Background(), &oauth2.Token{AccessToken: "token"}, mattermostUserID, nil, noopUserTokenHelpers{})
+	require.NoError(t, err)
+	return c
+}
+
+func TestOAuth2Exchange(t *testing.T) {
+	r := newTestRemote(nil)
+	conf := r.NewOAuth2Config()
+
+	require.Contains(t, conf.AuthCodeURL("state"), config.FullPathOAuth2Redirect+"?code="+authorizationCode+"&")
+
+	tok, err := conf.Exchange(remote.OAuth2Context(context.Background(), r), authorizationCode)
+	require.NoError(t, err)
+	require.NotEmpty(t, tok.AccessToken)
+	require.True(t, tok.Valid())
+}
+
+func TestDefaultTransportIsUntouched(t *testing.T) {
+	_, err := http.DefaultClient.Post(tokenScheme+"://token", "application/x-www-form-urlencoded", nil)
+	require.ErrorContains(t, err, "unsupported protocol scheme")
+}
+
+func TestMakeUserClientWithoutToken(t *testing.T) {
+	r := newTestRemote(nil)
+	_, err := r.MakeUserClient(context.Background(), nil, "user1", nil, noopUserTokenHelpers{})
+	require.Error(t, err)
+}
+
+func TestCreateEventAndRespond(t *testing.T) {
+	r := newTestRemote(func(string, []byte) {})
+	organizer := newTestClient(t, r, "organizer")
+	attendee := newTestClient(t, r, "attendee")
+
+	attendeeUser, err := attendee.GetMe()
+	require.NoError(t, err)
+	require.Equal(t, "attendee@example.com", attendeeUser.Mail)
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Minute)
+	created, err := organizer.CreateEvent("", &remote.Event{
+		Subject: "Planning",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(30*time.Minute), "UTC"),
+		Attendees: []*remote.Attendee{{
+			EmailAddress: &remote.EmailAddress{Address: attendeeUser.Mail},
+		}},
+	})
+	require.NoError(t, err)
+	require.True(t, created.IsOrganizer)
+	require.Equal(t, "attendee", created.Attendees[0].RemoteID)
+
+	invites, err := attendee.GetEventsBetweenDates("attendee", start.Add(-time.Hour), start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	require.Equal(t, "Planning", invites[0].Subject)
+	require.Equal(t, remote.EventResponseStatusNotAnswered, invites[0].ResponseStatus.Response)
+	require.Equal(t, created.ICalUID, invites[0].ICalUID)
+
+	require.NoError(t, attendee.AcceptEvent("attendee", invites[0].ID, &remote.EventResponse{SendResponse: true}))
+
+	original, err := organizer.GetEvent("organizer", created.ID)
+	require.NoError(t, err)
+	require.Equal(t, remote.EventResponseStatusAccepted, original.Attendees[0].Status.Response)
+
+	err = attendee.DeclineEvent("attendee", "unknown", &remote.EventResponse{SendResponse: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404 Not Found")
+}
+
+func TestRespondWithProposedTime(t *testing.T) {
+	r := newTestRemote(func(string, []byte) {})
+	organizer := newTestClient(t, r, "organizer")
+	attendee := newTestClient(t, r, "attendee")
+	_, err := attendee.GetMe()
+	require.NoError(t, err)
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Minute)
+	created, err := organizer.CreateEvent("", &remote.Event{
+		Subject: "Planning",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(30*time.Minute), "UTC"),
+		Attendees: []*remote.Attendee{{
+			EmailAddress: &remote.EmailAddress{Address: "attendee@example.com"},
+		}},
+	})
+	require.NoError(t, err)
+
+	invites, err := attendee.GetEventsBetweenDates("attendee", start.Add(-time.Hour), start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+
+	require.NoError(t, attendee.TentativelyAcceptEvent("attendee", invites[0].ID, &remote.EventResponse{}))
+	invite, err := attendee.GetEvent("attendee", invites[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, remote.EventResponseStatusTentative, invite.ResponseStatus.Response)
+	original, err := organizer.GetEvent("organizer", created.ID)
+	require.NoError(t, err)
+	require.Equal(t, remote.EventResponseStatusNotAnswered, original.Attendees[0].Status.Response)
+
+	proposed := &remote.TimeSlot{
+		Start: remote.NewDateTime(start.Add(24*time.Hour), "UTC"),
+		End:   remote.NewDateTime(start.Add(24*time.Hour+30*time.Minute), "UTC"),
+	}
+	require.NoError(t, attendee.DeclineEvent("attendee", invites[0].ID, &remote.EventResponse{
+		Comment:         "I'm out of office today",
+		SendResponse:    true,
+		ProposedNewTime: proposed,
+	}))
+	original, err = organizer.GetEvent("organizer", created.ID)
+	require.NoError(t, err)
+	require.Equal(t, remote.EventResponseStatusDeclined, original.Attendees[0].Status.Response)
+	require.Equal(t, proposed, original.Attendees[0].ProposedNewTime)
+}
+
+func TestRecurringEvent(t *testing.T) {
+	r := newTestRemote(func(string, []byte) {})
+	organizer := newTestClient(t, r, "organizer")
+	attendee := newTestClient(t, r, "attendee")
+	_, err := attendee.GetMe()
+	require.NoError(t, err)
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Minute)
+	created, err := organizer.CreateEvent("", &remote.Event{
+		Subject: "Standup",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(15*time.Minute), "UTC"),
+		Recurrence: &remote.PatternedRecurrence{
+			Pattern: &remote.RecurrencePattern{Type: remote.RecurrencePatternWeekly, Interval: 1},
+			Range:   &remote.RecurrenceRange{Type: remote.RecurrenceRangeNumbered, NumberOfOccurrences: 3},
+		},
+		Attendees: []*remote.Attendee{{
+			EmailAddress: &remote.EmailAddress{Address: "attendee@example.com"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, remote.EventTypeSeriesMaster, created.Type)
+
+	events, err := organizer.GetEventsBetweenDates("organizer", start.Add(-time.Hour), start.AddDate(0, 0, 28))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	for i, e := range events {
+		require.Equal(t, remote.EventTypeOccurrence, e.Type)
+		require.Equal(t, created.ID, e.SeriesMasterID)
+		require.Nil(t, e.Recurrence)
+		require.Equal(t, start.AddDate(0, 0, 7*i), e.Start.Time())
+	}
+
+	occurrence, err := organizer.GetEvent("organizer", events[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, events[1].Start.Time(), occurrence.Start.Time())
+
+	invites, err := attendee.GetEventsBetweenDates("attendee", start.AddDate(0, 0, 6), start.AddDate(0, 0, 8))
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	require.NoError(t, attendee.AcceptEvent("attendee", invites[0].ID, &remote.EventResponse{SendResponse: true}))
+
+	invites, err = attendee.GetEventsBetweenDates("attendee", start.Add(-time.Hour), start.AddDate(0, 0, 28))
+	require.NoError(t, err)
+	require.Len(t, invites, 3)
+	for _, invite := range invites {
+		require.Equal(t, remote.EventResponseStatusAccepted, invite.ResponseStatus.Response)
+	}
+}
+
+func TestUpdateAndCancelEvent(t *testing.T) {
+	r := newTestRemote(func(string, []byte) {})
+	organizer := newTestClient(t, r, "organizer")
+	attendee := newTestClient(t, r, "attendee")
+	_, err := attendee.GetMe()
+	require.NoError(t, err)
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Minute)
+	created, err := organizer.CreateEvent("", &remote.Event{
+		Subject: "Planning",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(30*time.Minute), "UTC"),
+		Attendees: []*remote.Attendee{{
+			EmailAddress: &remote.EmailAddress{Address: "attendee@example.com"},
+		}},
+	})
+	require.NoError(t, err)
+
+	moved := start.Add(2 * time.Hour)
+	updated, err := organizer.UpdateEvent("organizer", created.ID, &remote.Event{
+		Subject: "Quarterly planning",
+		Start:   remote.NewDateTime(moved, "UTC"),
+		End:     remote.NewDateTime(moved.Add(time.Hour), "UTC"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Quarterly planning", updated.Subject)
+	require.Equal(t, moved, updated.Start.Time())
+	require.Len(t, updated.Attendees, 1)
+
+	invites, err := attendee.GetEventsBetweenDates("attendee", moved, moved.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	require.Equal(t, "Quarterly planning", invites[0].Subject)
+
+	err = attendee.CancelEvent("attendee", invites[0].ID, "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), errNotOrganizer.Error())
+
+	require.NoError(t, organizer.CancelEvent("organizer", created.ID, "Moved to next week"))
+	_, err = organizer.GetEvent("organizer", created.ID)
+	require.Error(t, err)
+
+	invite, err := attendee.GetEvent("attendee", invites[0].ID)
+	require.NoError(t, err)
+	require.True(t, invite.IsCancelled)
+	err = attendee.AcceptEvent("attendee", invite.ID, &remote.EventResponse{SendResponse: true})
+	require.Error(t, err)
+
+	require.NoError(t, attendee.DeleteEvent("attendee", invite.ID))
+	err = attendee.DeleteEvent("attendee", invite.ID)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404 Not Found")
+}
+
+func TestNotificationRoundTrip(t *testing.T) {
+	recorder := newDeliveryRecorder()
+	r := newTestRemote(recorder.deliver)
+	c := newTestClient(t, r, "user1")
+
+	sub, err := c.CreateMySubscription("http://localhost/webhook", "", "user1")
+	require.NoError(t, err)
+
+	start := time.Now().Add(time.Hour)
+	created, err := c.CreateEvent("", &remote.Event{
+		Subject: "Focus",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(time.Hour), "UTC"),
+	})
+	require.NoError(t, err)
+
+	payloads := recorder.wait(t, 1)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payloads[0]))
+	notifications := r.HandleWebhook(w, req)
+	require.Len(t, notifications, 1)
+	require.Equal(t, sub.ID, notifications[0].SubscriptionID)
+	require.Equal(t, sub.ClientState, notifications[0].ClientState)
+	require.True(t, notifications[0].IsBare)
+	require.False(t, notifications[0].RecommendRenew)
+
+	n, err := c.GetNotificationData(notifications[0])
+	require.NoError(t, err)
+	require.False(t, n.IsBare)
+	require.Equal(t, "created", n.ChangeType)
+	require.Equal(t, created.ID, n.Event.ID)
+
+	// Notifications are read back from the queue as JSON.
+	data, err := json.Marshal(notifications[0])
+	require.NoError(t, err)
+	queued := &remote.Notification{}
+	require.NoError(t, json.Unmarshal(data, queued))
+	n, err = c.GetNotificationData(queued)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, n.Event.ID)
+
+	subs, err := c.ListSubscriptions()
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+
+	require.NoError(t, c.DeleteSubscription(sub))
+	_, err = c.RenewSubscription("", "", sub)
+	require.Error(t, err)
+}
+
+func TestDoBatchViewCalendarRequests(t *testing.T) {
+	r := newTestRemote(func(string, []byte) {})
+	start := time.Now().Add(time.Hour)
+	for _, id := range []string{"user1", "user2"} {
+		_, err := newTestClient(t, r, id).CreateEvent("", &remote.Event{
+			Subject: "Event of " + id,
+			Start:   remote.NewDateTime(start, "UTC"),
+			End:     remote.NewDateTime(start.Add(time.Hour), "UTC"),
+		})
+		require.NoError(t, err)
+	}
+
+	superuser, err := r.MakeSuperuserClient(context.Background())
+	require.NoError(t, err)
+
+	res, err := superuser.DoBatchViewCalendarRequests([]*remote.ViewCalendarParams{
+		{RemoteUserID: "user2", StartTime: start.Add(-time.Hour), EndTime: start.Add(2 * time.Hour)},
+		{RemoteUserID: "user1", StartTime: start.Add(-time.Hour), EndTime: start.Add(2 * time.Hour)},
+		{RemoteUserID: "user3", StartTime: start.Add(-time.Hour), EndTime: start.Add(2 * time.Hour)},
+	})
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+	require.Equal(t, "Event of user2", res[0].Events[0].Subject)
+	require.Equal(t, "Event of user1", res[1].Events[0].Subject)
+	require.Empty(t, res[2].Events)
+}
+
+func TestCreateEventInCalendar(t *testing.T) {
+	r := newTestRemote(func(string, []byte) {})
+	c := newTestClient(t, r, "user1")
+	start := time.Now().Add(time.Hour)
+
+	team, err := c.CreateCalendar(&remote.Calendar{Name: "Team"})
+	require.NoError(t, err)
+	_, err = c.CreateEvent(team.ID, &remote.Event{
+		Subject: "Team sync",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(time.Hour), "UTC"),
+	})
+	require.NoError(t, err)
+
+	events, err := c.GetCalendarView("user1", team.ID, start.Add(-time.Hour), start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "Team sync", events[0].Subject)
+
+	events, err = c.GetEventsBetweenDates("user1", start.Add(-time.Hour), start.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	_, err = c.CreateEvent("unknown", &remote.Event{Subject: "Lost"})
+	require.Error(t, err)
+
+	superuser, err := r.MakeSuperuserClient(context.Background())
+	require.NoError(t, err)
+	res, err := superuser.DoBatchViewCalendarRequests([]*remote.ViewCalendarParams{
+		{RemoteUserID: "user1", CalendarID: team.ID, StartTime: start.Add(-time.Hour), EndTime: start.Add(2 * time.Hour)},
+		{RemoteUserID: "user1", CalendarID: "unknown", StartTime: start.Add(-time.Hour), EndTime: start.Add(2 * time.Hour)},
+	})
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Equal(t, "Team sync", res[0].Events[0].Subject)
+	require.NotNil(t, res[1].Error)
+}
+
+func TestDoBatchCalendarViewDeltaRequests(t *testing.T) {
+	r := newTestRemote(func(string, []byte) {})
+	c := newTestClient(t, r, "user1")
+	start := time.Now().Add(time.Hour)
+	windowStart, windowEnd := start.Add(-time.Hour), start.Add(3*time.Hour)
+
+	kept, err := c.CreateEvent("", &remote.Event{
+		Subject: "Kept",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(time.Hour), "UTC"),
+	})
+	require.NoError(t, err)
+	deleted, err := c.CreateEvent("", &remote.Event{
+		Subject: "Deleted",
+		Start:   remote.NewDateTime(start, "UTC"),
+		End:     remote.NewDateTime(start.Add(time.Hour), "UTC"),
+	})
+	require.NoError(t, err)
+
+	superuser, err := r.MakeSuperuserClient(context.Background())
+	require.NoError(t, err)
+	deltaOf := func(deltaLink string) *remote.CalendarViewDeltaResponse {
+		res, err := superuser.DoBatchCalendarViewDeltaRequests([]*remote.CalendarViewDeltaParams{
+			{RemoteUserID: "user1", StartTime: windowStart, EndTime: windowEnd, DeltaLink: deltaLink},
+		})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		return res[0]
+	}
+
+	res := deltaOf("")
+	require.Nil(t, res.Error)
+	require.True(t, res.Delta.Reset)
+	require.Len(t, res.Delta.Events, 2)
+	require.NotEmpty(t, res.Delta.DeltaLink)
+	first := res.Delta.DeltaLink
+
+	_, err = c.UpdateEvent("user1", kept.ID, &remote.Event{Subject: "Renamed"})
+	require.NoError(t, err)
+	require.NoError(t, c.DeleteEvent("user1", deleted.ID))
+	added, err := c.CreateEvent("", &remote.Event{
+		Subject: "Added",
+		Start:   remote.NewDateTime(start.Add(time.Hour), "UTC"),
+		End:     remote.NewDateTime(start.Add(2*time.Hour), "UTC"),
+	})
+	require.NoError(t, err)
+
+	res = deltaOf(first)
+	require.Nil(t, res.Error)
+	require.False(t, res.Delta.Reset)
+	require.Len(t, res.Delta.Events, 2)
+	require.Equal(t, "Renamed", res.Delta.Events[0].Subject)
+	require.Equal(t, added.ID, res.Delta.Events[1].ID)
+	require.Equal(t, []string{deleted.ID}, res.Delta.RemovedIDs)
+
+	res = deltaOf(res.Delta.DeltaLink)
+	require.Nil(t, res.Error)
+	require.Empty(t, res.Delta.Events)
+	require.Empty(t, res.Delta.RemovedIDs)
+
+	res = deltaOf(first)
+	require.NotNil(t, res.Error)
+	require.True(t, res.Expired)
+	require.Nil(t, res.Delta)
+}
+
+func TestNoSharedCalendarsOrRooms(t *testing.T) {
+	r := newTestRemote(func(string, []byte) {})
+	c := newTestClient(t, r, "user1")
+
+	calendars, err := c.GetSharedCalendars("user1")
+	require.NoError(t, err)
+	require.Empty(t, calendars)
+
+	roomLists, err := c.GetRoomLists()
+	require.NoError(t, err)
+	require.Empty(t, roomLists)
+
+	rooms, err := c.GetRooms("")
+	require.NoError(t, err)
+	require.Empty(t, rooms)
+}
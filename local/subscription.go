@@ -0,0 +1,98 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+func (c *client) CreateMySubscription(notificationURL, _, _ string) (*remote.Subscription, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	sub := c.backend.createSubscription(c.remoteUserID(), notificationURL)
+
+	c.Logger.With(bot.LogContext{
+		"subscriptionID":     sub.ID,
+		"resource":           sub.Resource,
+		"expirationDateTime": sub.ExpirationDateTime,
+	}).Debugf("local: created subscription.")
+
+	return sub, nil
+}
+
+func (c *client) DeleteSubscription(sub *remote.Subscription) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+
+	err := c.backend.deleteSubscription(sub.ID)
+	if err != nil {
+		return errors.Wrap(err, "local DeleteSubscription")
+	}
+	return nil
+}
+
+func (c *client) RenewSubscription(_, _ string, oldSub *remote.Subscription) (*remote.Subscription, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	sub, err := c.backend.renewSubscription(oldSub.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "local RenewSubscription")
+	}
+	return sub, nil
+}
+
+// ListSubscriptions returns the acting user's subscriptions, or every
+// subscription when called with the superuser client.
+func (c *client) ListSubscriptions() ([]*remote.Subscription, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	return c.backend.listSubscriptions(c.mattermostUserID), nil
+}
+
+func (c *client) GetNotificationData(orig *remote.Notification) (*remote.Notification, error) {
+	n := *orig
+	wh := &webhook{}
+	err := n.DecodeWebhook(wh)
+	if err != nil {
+		return nil, errors.Wrap(err, "local GetNotificationData: unexpected webhook")
+	}
+
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	remoteUserID, eventID, ok := parseEventResource(wh.Resource)
+	if !ok {
+		return nil, errors.New("unknown resource: " + wh.Resource)
+	}
+
+	event, err := c.backend.getEvent(remoteUserID, eventID)
+	if err != nil {
+		c.Logger.With(bot.LogContext{
+			"Resource":       wh.Resource,
+			"subscriptionID": wh.SubscriptionID,
+		}).Infof("local: failed to fetch notification data resource: `%v`.", err)
+		return nil, errors.Wrap(err, "local GetNotificationData")
+	}
+
+	n.Event = event
+	n.ChangeType = wh.ChangeType
+	n.IsBare = false
+	return &n, nil
+}
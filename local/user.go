@@ -0,0 +1,26 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const (
+	ErrorUserInactive = "You have been marked inactive because your refresh token is expired. Please disconnect and reconnect your account again."
+	LogUserInactive   = "User %s is inactive. Please disconnect and reconnect your account."
+)
+
+func (c *client) GetMe() (*remote.User, error) {
+	if c.mattermostUserID == "" {
+		return nil, errors.New("local GetMe: no acting user")
+	}
+	return c.backend.getMe(c.remoteUserID()), nil
+}
+
+func (c *client) GetMailboxSettings(remoteUserID string) (*remote.MailboxSettings, error) {
+	return c.backend.getMailboxSettings(remoteUserID), nil
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package local
+
+import (
+	"net/url"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// GetSuperuserToken returns a throwaway token: the local superuser client
+// does not authenticate against anything.
+func (c *client) GetSuperuserToken() (string, error) {
+	return newRandomString(), nil
+}
+
+// CallJSON is not implemented: it sends raw Microsoft Graph requests, which
+// only the msgraph provider understands. The local provider answers every
+// request of the Client interface from the in-memory store instead.
+func (c *client) CallJSON(_, _ string, _, _ interface{}) ([]byte, error) {
+	return nil, remote.ErrNotImplemented
+}
+
+// CallFormPost is not implemented, for the same reason as CallJSON.
+func (c *client) CallFormPost(_, _ string, _ url.Values, _ interface{}) ([]byte, error) {
+	return nil, remote.ErrNotImplemented
+}
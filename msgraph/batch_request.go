@@ -4,10 +4,31 @@
 package msgraph
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
 
-const maxNumRequestsPerBatch = 20
+const (
+	maxNumRequestsPerBatch = 20
+
+	// maxConcurrentBatches is the number of batches of a call sent at the
+	// same time.
+	maxConcurrentBatches = 4
+
+	// maxBatchItemRetries is the number of times the requests failing inside
+	// a batch are sent again in a new batch.
+	maxBatchItemRetries = 3
+)
 
 type singleRequest struct {
 	Body    interface{}       `json:"body"`
@@ -21,13 +42,198 @@ type fullBatchRequest struct {
 	Requests []*singleRequest `json:"requests"`
 }
 
-func (c *client) batchRequest(req fullBatchRequest, out interface{}) error {
-	u := "https://graph.microsoft.com/v1.0/$batch"
+// batchItemResponse is the response to a request of a batch. Its body is
+// decoded by the caller.
+type batchItemResponse struct {
+	Headers map[string]string `json:"headers"`
+	ID      string            `json:"id"`
+	Body    json.RawMessage   `json:"body"`
+	Status  int               `json:"status"`
+}
+
+type fullBatchResponse struct {
+	Responses []*batchItemResponse `json:"responses"`
+}
 
-	_, err := c.CallJSON(http.MethodPost, u, req, out)
+func (c *client) batchRequest(req fullBatchRequest, out interface{}) error {
+	_, err := c.CallJSON(http.MethodPost, "/$batch", req, out)
 	return err
 }
 
+// doBatch sends the requests in batches, and returns their responses in the
+// order of the requests. Requests throttled or failing with a server error
+// inside a batch are retried on their own, so one failing mailbox doesn't
+// fail the others, within the wait limit of the context, see
+// throttleWaitLimit. Batches throttled or failing to reach Graph as a whole
+// are retried the same way. The response of a request is nil when its batch
+// failed; an error is returned only when no batch succeeded.
+func (c *client) doBatch(requests []*singleRequest) ([]*batchItemResponse, error) {
+	for i, req := range requests {
+		req.ID = strconv.Itoa(i)
+	}
+
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	responses := make([]*batchItemResponse, len(requests))
+	pending := requests
+	var batchErr error
+	waitLimit := throttleWaitLimit(ctx)
+	var waited time.Duration
+	for attempt := 0; len(pending) > 0; attempt++ {
+		var (
+			retry     []*singleRequest
+			retryWait time.Duration
+		)
+		retry, retryWait, batchErr = c.sendBatches(ctx, pending, responses)
+		if len(retry) == 0 || attempt == maxBatchItemRetries {
+			break
+		}
+
+		wait := max(backoff(attempt), retryWait)
+		waited += wait
+		if wait > maxRetryAfter || waited > waitLimit {
+			break
+		}
+		if err := batchRetrySleep(ctx, wait); err != nil {
+			break
+		}
+		pending = retry
+	}
+
+	if batchErr != nil {
+		for _, res := range responses {
+			if res != nil {
+				return responses, nil
+			}
+		}
+		return nil, batchErr
+	}
+	return responses, nil
+}
+
+// sendBatches sends the requests in batches of maxNumRequestsPerBatch, at
+// most maxConcurrentBatches at a time, and stores the responses by request
+// index. It returns the requests to retry, the longest wait asked by Graph
+// with Retry-After, and the last batch error.
+func (c *client) sendBatches(ctx context.Context, requests []*singleRequest, responses []*batchItemResponse) ([]*singleRequest, time.Duration, error) {
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		retry     []*singleRequest
+		retryWait time.Duration
+		batchErr  error
+	)
+	waitAtLeast := func(value string) {
+		if d, ok := retryAfter(value, time.Now()); ok && d > retryWait {
+			retryWait = d
+		}
+	}
+	slots := make(chan struct{}, maxConcurrentBatches)
+	for _, batch := range prepareBatchRequests(requests) {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(batch fullBatchRequest) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			res := &fullBatchResponse{}
+			err := c.batchRequest(batch, res)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				c.Warnf("msgraph batch request failed. err=%v", err)
+				batchErr = err
+				if isRetryableBatchError(err) {
+					// The transport already counted the throttled response.
+					retry = append(retry, batch.Requests...)
+					var errResp *msgraph.ErrorResponse
+					if errors.As(err, &errResp) && errResp.Response != nil {
+						waitAtLeast(errResp.Response.Header.Get("Retry-After"))
+					}
+				}
+				return
+			}
+			byID := map[string]*singleRequest{}
+			for _, req := range batch.Requests {
+				byID[req.ID] = req
+			}
+			for _, item := range res.Responses {
+				req, ok := byID[item.ID]
+				if !ok {
+					continue
+				}
+				responses[requestIndex(req)] = item
+				if isRetryableStatus(item.Status) {
+					if item.Status == http.StatusTooManyRequests || item.Status == http.StatusServiceUnavailable {
+						remote.CountThrottled(ctx)
+					}
+					retry = append(retry, req)
+					waitAtLeast(headerValue(item.Headers, "Retry-After"))
+				}
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	return retry, retryWait, batchErr
+}
+
+var batchRetrySleep = sleepContext
+
+// isRetryableBatchError tells whether a batch failed as a whole because
+// Graph throttled it or could not be reached, rather than because of its
+// requests. The requests of users already waited for a throttled Graph as
+// long as they can, see throttledTransport.
+func isRetryableBatchError(err error) bool {
+	if errors.Is(err, remote.ErrThrottled) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var errResp *msgraph.ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.Response != nil && isThrottled(errResp.Response)
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// requestIndex returns the index of a request set by doBatch as its ID.
+func requestIndex(req *singleRequest) int {
+	i, _ := strconv.Atoi(req.ID)
+	return i
+}
+
+// headerValue reads a header of a batch response, whose names are not
+// canonicalized.
+func headerValue(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if http.CanonicalHeaderKey(k) == name {
+			return v
+		}
+	}
+	return ""
+}
+
+// decode reads the body of the response into out.
+func (r *batchItemResponse) decode(out interface{}) error {
+	if len(r.Body) == 0 {
+		return nil
+	}
+	return errors.Wrapf(json.Unmarshal(r.Body, out), "unable to decode the response to request %s", r.ID)
+}
+
 func prepareBatchRequests(requests []*singleRequest) []fullBatchRequest {
 	numFullRequests := len(requests) / maxNumRequestsPerBatch
 	if len(requests)%maxNumRequestsPerBatch != 0 {
@@ -0,0 +1,265 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+// newBatchTestClient returns a client sending its batches to handle, which
+// answers a batch with the status and body of each request.
+func newBatchTestClient(t *testing.T, handle func(batch int, req *singleRequest) (int, string)) *client {
+	mu := sync.Mutex{}
+	batches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/$batch", r.URL.Path)
+		in := fullBatchRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		require.LessOrEqual(t, len(in.Requests), maxNumRequestsPerBatch)
+
+		mu.Lock()
+		batch := batches
+		batches++
+		mu.Unlock()
+
+		out := fullBatchResponse{}
+		for _, req := range in.Requests {
+			status, body := handle(batch, req)
+			if status == 0 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			out.Responses = append(out.Responses, &batchItemResponse{
+				ID:      req.ID,
+				Status:  status,
+				Body:    json.RawMessage(body),
+				Headers: map[string]string{"retry-after": "2"},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(out))
+	}))
+	t.Cleanup(srv.Close)
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	return &client{
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+		Logger:       &bot.NilLogger{},
+	}
+}
+
+func TestDoBatch(t *testing.T) {
+	waits := []time.Duration{}
+	batchRetrySleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { batchRetrySleep = sleepContext }()
+
+	mu := sync.Mutex{}
+	attempts := map[string]int{}
+	c := newBatchTestClient(t, func(_ int, req *singleRequest) (int, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[req.URL]++
+		switch {
+		case req.URL == "/throttled" && attempts[req.URL] == 1:
+			return http.StatusTooManyRequests, `{"error": {"code": "TooManyRequests"}}`
+		case req.URL == "/missing":
+			return http.StatusNotFound, `{"error": {"code": "ErrorItemNotFound"}}`
+		case req.URL == "/broken":
+			return http.StatusServiceUnavailable, `{}`
+		}
+		return http.StatusOK, fmt.Sprintf(`{"url": %q}`, req.URL)
+	})
+
+	requests := []*singleRequest{}
+	for i := 0; i < 45; i++ {
+		u := fmt.Sprintf("/request%d", i)
+		switch i {
+		case 7:
+			u = "/throttled"
+		case 30:
+			u = "/missing"
+		case 44:
+			u = "/broken"
+		}
+		requests = append(requests, &singleRequest{URL: u, Method: http.MethodGet})
+	}
+
+	throttled := &atomic.Int64{}
+	c.ctx = remote.WithThrottleCounter(remote.BackgroundContext(context.Background()), throttled)
+	responses, err := c.doBatch(requests)
+	require.NoError(t, err)
+	require.Len(t, responses, 45)
+	for i, res := range responses {
+		require.Equal(t, fmt.Sprint(i), res.ID)
+	}
+
+	out := map[string]string{}
+	require.NoError(t, responses[7].decode(&out))
+	require.Equal(t, "/throttled", out["url"])
+	require.Equal(t, http.StatusOK, responses[7].Status)
+	require.Equal(t, 2, attempts["/throttled"])
+	require.EqualValues(t, 1+maxBatchItemRetries+1, throttled.Load())
+
+	require.Equal(t, http.StatusNotFound, responses[30].Status)
+	require.Equal(t, 1, attempts["/missing"])
+
+	require.Equal(t, http.StatusServiceUnavailable, responses[44].Status)
+	require.Equal(t, maxBatchItemRetries+1, attempts["/broken"])
+	require.Len(t, waits, maxBatchItemRetries)
+	require.Equal(t, 2*time.Second, waits[0])
+}
+
+func TestDoBatchInteractiveWait(t *testing.T) {
+	waits := []time.Duration{}
+	batchRetrySleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { batchRetrySleep = sleepContext }()
+
+	attempts := 0
+	c := newBatchTestClient(t, func(_ int, _ *singleRequest) (int, string) {
+		attempts++
+		return http.StatusTooManyRequests, `{"error": {"code": "TooManyRequests"}}`
+	})
+
+	// Retry-After asks for 2 seconds each time: a user waits twice at most
+	responses, err := c.doBatch([]*singleRequest{{URL: "/throttled", Method: http.MethodGet}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, responses[0].Status)
+	require.Equal(t, []time.Duration{2 * time.Second, 2 * time.Second}, waits)
+	require.Equal(t, 3, attempts)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestDoBatchRetriedBatch(t *testing.T) {
+	waits := []time.Duration{}
+	batchRetrySleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { batchRetrySleep = sleepContext }()
+
+	// The first batch doesn't reach Graph, the second is throttled as a whole.
+	batches := 0
+	c := newBatchTestClient(t, func(int, *singleRequest) (int, string) {
+		return http.StatusOK, `{}`
+	})
+	base := c.httpClient.Transport
+	c.httpClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		batches++
+		switch batches {
+		case 1:
+			return nil, errors.New("connection reset")
+		case 2:
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": []string{"3"}},
+				Body:       io.NopCloser(strings.NewReader(`{"error": {"code": "ServiceUnavailable"}}`)),
+				Request:    req,
+			}, nil
+		}
+		return base.RoundTrip(req)
+	})}
+
+	requests := []*singleRequest{}
+	for i := 0; i < 5; i++ {
+		requests = append(requests, &singleRequest{URL: fmt.Sprintf("/request%d", i), Method: http.MethodGet})
+	}
+	responses, err := c.doBatch(requests)
+	require.NoError(t, err)
+	for _, res := range responses {
+		require.Equal(t, http.StatusOK, res.Status)
+	}
+	require.Equal(t, 3, batches)
+	require.Len(t, waits, 2)
+	require.Equal(t, 3*time.Second, waits[1])
+
+	// Users already waited for a throttled Graph in the transport.
+	batches = 0
+	c.httpClient = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		batches++
+		return nil, remote.ErrThrottled
+	})}
+	_, err = c.doBatch(requests)
+	require.ErrorIs(t, err, remote.ErrThrottled)
+	require.Equal(t, 1, batches)
+}
+
+func TestDoBatchFailedBatch(t *testing.T) {
+	c := newBatchTestClient(t, func(batch int, _ *singleRequest) (int, string) {
+		if batch == 0 {
+			return 0, ""
+		}
+		return http.StatusOK, `{}`
+	})
+
+	requests := []*singleRequest{}
+	for i := 0; i < 25; i++ {
+		requests = append(requests, &singleRequest{URL: fmt.Sprintf("/request%d", i), Method: http.MethodGet})
+	}
+	responses, err := c.doBatch(requests)
+	require.NoError(t, err)
+	nilResponses := 0
+	for _, res := range responses {
+		if res == nil {
+			nilResponses++
+		}
+	}
+	require.True(t, nilResponses == 20 || nilResponses == 5)
+
+	c = newBatchTestClient(t, func(int, *singleRequest) (int, string) {
+		return 0, ""
+	})
+	_, err = c.doBatch(requests[:3])
+	require.Error(t, err)
+}
+
+func TestGetScheduleBatched(t *testing.T) {
+	c := newBatchTestClient(t, func(_ int, req *singleRequest) (int, string) {
+		if req.URL == "/Users/bad/getSchedule" {
+			return http.StatusNotFound, `{"error": {"code": "ErrorItemNotFound", "message": "not found"}}`
+		}
+		return http.StatusOK, fmt.Sprintf(`{"value": [{"scheduleId": %q}]}`, req.URL)
+	})
+
+	schedules, err := c.GetSchedule([]*remote.ScheduleUserInfo{
+		{RemoteUserID: "first", Mail: "first@example.com"},
+		{RemoteUserID: "bad", Mail: "bad@example.com"},
+		{RemoteUserID: "second", Mail: "second@example.com"},
+	}, remote.NewDateTime(time.Now(), "UTC"), remote.NewDateTime(time.Now().Add(time.Hour), "UTC"), 15)
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	require.Equal(t, "/Users/first/getSchedule", schedules[0].ScheduleID)
+	require.Equal(t, "/Users/second/getSchedule", schedules[1].ScheduleID)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+type calendarViewDeltaEvent struct {
+	Removed *struct {
+		Reason string `json:"reason"`
+	} `json:"@removed,omitempty"`
+	remote.Event
+}
+
+type calendarViewDeltaResponse struct {
+	Error     *remote.APIError          `json:"error,omitempty"`
+	NextLink  string                    `json:"@odata.nextLink,omitempty"`
+	DeltaLink string                    `json:"@odata.deltaLink,omitempty"`
+	Value     []*calendarViewDeltaEvent `json:"value,omitempty"`
+}
+
+// DoBatchCalendarViewDeltaRequests returns the changes to the calendar views
+// of the users, in the order of the parameters. The pages of the deltas are
+// read in batches too, the next page of every delta not read fully at a
+// time. Requests still failing after the retries of the batch, e.g.
+// throttled ones, are returned with their error.
+func (c *client) DoBatchCalendarViewDeltaRequests(allParams []*remote.CalendarViewDeltaParams) ([]*remote.CalendarViewDeltaResponse, error) {
+	result := make([]*remote.CalendarViewDeltaResponse, len(allParams))
+	links := make([]string, len(allParams))
+	pending := make([]int, len(allParams))
+	for i, params := range allParams {
+		result[i] = &remote.CalendarViewDeltaResponse{
+			RemoteUserID: params.RemoteUserID,
+			Delta:        &remote.EventsDelta{Reset: params.DeltaLink == ""},
+		}
+		links[i] = params.DeltaLink
+		if links[i] == "" {
+			links[i] = getCalendarViewDeltaURL(params.RemoteUserID, params.StartTime, params.EndTime)
+		}
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+		requests := make([]*singleRequest, len(pending))
+		for j, i := range pending {
+			requests[j] = &singleRequest{
+				URL:     c.batchRequestURL(links[i]),
+				Method:  http.MethodGet,
+				Headers: map[string]string{},
+			}
+		}
+
+		responses, err := c.doBatch(requests)
+		if err != nil {
+			for _, i := range pending {
+				setDeltaError(result[i], &remote.APIError{Message: err.Error()}, false)
+			}
+			break
+		}
+
+		next := []int{}
+		for j, i := range pending {
+			links[i] = readDeltaPage(result[i], responses[j])
+			if links[i] != "" {
+				next = append(next, i)
+			}
+		}
+		pending = next
+	}
+
+	for _, res := range result {
+		if res.Delta != nil {
+			res.Delta.Events = normalizeEvents(res.Delta.Events)
+		}
+	}
+	return result, nil
+}
+
+// readDeltaPage adds a page of a delta to its result, and returns the link
+// to the next page if any.
+func readDeltaPage(result *remote.CalendarViewDeltaResponse, res *batchItemResponse) string {
+	if res == nil {
+		setDeltaError(result, &remote.APIError{Message: "no response for the request"}, false)
+		return ""
+	}
+
+	page := calendarViewDeltaResponse{}
+	if err := res.decode(&page); err != nil {
+		setDeltaError(result, &remote.APIError{Message: err.Error()}, false)
+		return ""
+	}
+	if page.Error != nil || res.Status >= http.StatusMultipleChoices {
+		if page.Error == nil {
+			page.Error = &remote.APIError{Message: http.StatusText(res.Status)}
+		}
+		// Delta links expire after a while, and Graph answers 410 Gone
+		setDeltaError(result, page.Error, res.Status == http.StatusGone)
+		return ""
+	}
+
+	for _, e := range page.Value {
+		if e.Removed != nil {
+			result.Delta.RemovedIDs = append(result.Delta.RemovedIDs, e.ID)
+			continue
+		}
+		event := e.Event
+		result.Delta.Events = append(result.Delta.Events, &event)
+	}
+	result.Delta.DeltaLink = page.DeltaLink
+	return page.NextLink
+}
+
+func setDeltaError(result *remote.CalendarViewDeltaResponse, apiErr *remote.APIError, expired bool) {
+	result.Delta = nil
+	result.Error = apiErr
+	result.Expired = expired
+}
+
+// batchRequestURL returns a link returned by Graph relative to the root of
+// the API, as the requests of a batch must be.
+func (c *client) batchRequestURL(link string) string {
+	return strings.TrimPrefix(link, strings.TrimSuffix(c.rbuilder.URL(), "/"))
+}
+
+func getCalendarViewDeltaURL(remoteUserID string, start, end time.Time) string {
+	q := url.Values{}
+	q.Add("startDateTime", start.UTC().Format(time.RFC3339))
+	q.Add("endDateTime", end.UTC().Format(time.RFC3339))
+	return "/users/" + url.PathEscape(remoteUserID) + "/calendarView/delta?" + q.Encode()
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestDoBatchCalendarViewDeltaRequests(t *testing.T) {
+	batchRetrySleep = func(_ context.Context, _ time.Duration) error { return nil }
+	defer func() { batchRetrySleep = sleepContext }()
+
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+	var base string
+	c := newBatchTestClient(t, func(_ int, req *singleRequest) (int, string) {
+		switch req.URL {
+		case "/users/synced/calendarView/delta?$deltatoken=previous":
+			return http.StatusOK, fmt.Sprintf(`{
+				"@odata.nextLink": "%s/users/synced/calendarView/delta?$skiptoken=page2",
+				"value": [
+					{"id": "updated", "subject": "Planning", "responseStatus": {"response": "accepted"}},
+					{"id": "deleted", "@removed": {"reason": "deleted"}}
+				]
+			}`, base)
+		case "/users/synced/calendarView/delta?$skiptoken=page2":
+			return http.StatusOK, fmt.Sprintf(`{
+				"@odata.deltaLink": "%s/users/synced/calendarView/delta?$deltatoken=next",
+				"value": [{"id": "created", "subject": "Retro"}]
+			}`, base)
+		case "/users/expired/calendarView/delta?$deltatoken=previous":
+			return http.StatusGone, `{"error": {"code": "SyncStateNotFound", "message": "The sync state is no longer valid."}}`
+		case getCalendarViewDeltaURL("throttled", start, end):
+			return http.StatusTooManyRequests, `{"error": {"code": "ApplicationThrottled", "message": "Too many requests."}}`
+		}
+		return http.StatusNotFound, `{"error": {"code": "NotFound"}}`
+	})
+	base = c.rbuilder.URL()
+
+	responses, err := c.DoBatchCalendarViewDeltaRequests([]*remote.CalendarViewDeltaParams{
+		{RemoteUserID: "synced", DeltaLink: base + "/users/synced/calendarView/delta?$deltatoken=previous"},
+		{RemoteUserID: "expired", DeltaLink: base + "/users/expired/calendarView/delta?$deltatoken=previous"},
+		{RemoteUserID: "throttled", StartTime: start, EndTime: end},
+	})
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+
+	delta := responses[0].Delta
+	require.Nil(t, responses[0].Error)
+	require.False(t, delta.Reset)
+	require.Equal(t, base+"/users/synced/calendarView/delta?$deltatoken=next", delta.DeltaLink)
+	require.Equal(t, []string{"deleted"}, delta.RemovedIDs)
+	require.Len(t, delta.Events, 2)
+	require.Equal(t, "updated", delta.Events[0].ID)
+	require.Equal(t, remote.EventResponseStatusAccepted, delta.Events[0].ResponseStatus.Response)
+	require.Equal(t, "created", delta.Events[1].ID)
+	require.Equal(t, remote.EventResponseStatusNotAnswered, delta.Events[1].ResponseStatus.Response)
+
+	require.Nil(t, responses[1].Delta)
+	require.Equal(t, "SyncStateNotFound", responses[1].Error.Code)
+	require.True(t, responses[1].Expired)
+
+	require.Nil(t, responses[2].Delta)
+	require.Equal(t, "ApplicationThrottled", responses[2].Error.Code)
+	require.False(t, responses[2].Expired)
+}
+
+func TestGetCalendarViewDeltaURL(t *testing.T) {
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+	require.Equal(t,
+		"/users/remote_user_id/calendarView/delta?endDateTime=2024-03-11T00%3A00%3A00Z&startDateTime=2024-03-04T00%3A00%3A00Z",
+		getCalendarViewDeltaURL("remote_user_id", start, end))
+}
@@ -11,17 +11,26 @@ import (
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
 
-// CreateEvent creates a calendar event
-func (c *client) CreateEvent(in *remote.Event) (*remote.Event, error) {
+// CreateEvent creates a calendar event, in the default calendar of the user
+// unless calendarID is set. Graph adds an online meeting to the event when
+// isOnlineMeeting is set, and returns its join URL.
+func (c *client) CreateEvent(calendarID string, in *remote.Event) (*remote.Event, error) {
 	var out = remote.Event{}
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return nil, errors.New(ErrorUserInactive)
 	}
-	err := c.rbuilder.Me().Events().Request().JSONRequest(c.ctx, http.MethodPost, "", &in, &out)
+	var err error
+	if calendarID != "" {
+		err = c.rbuilder.Me().Calendars().ID(calendarID).Events().Request().JSONRequest(c.ctx, http.MethodPost, "", &in, &out)
+	} else {
+		err = c.rbuilder.Me().Events().Request().JSONRequest(c.ctx, http.MethodPost, "", &in, &out)
+	}
 	if err != nil {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
 		return nil, errors.Wrap(err, "msgraph CreateEvent")
 	}
+	normalizeRecurrence(&out)
+	normalizeOnlineMeeting(&out)
 	return &out, nil
 }
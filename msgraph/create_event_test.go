@@ -0,0 +1,49 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestCreateEventWithOnlineMeeting(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/me/events", r.URL.Path)
+		in := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		require.Equal(t, true, in["isOnlineMeeting"])
+		require.Equal(t, remote.OnlineMeetingProviderTeams, in["onlineMeetingProvider"])
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "event_id", "isOnlineMeeting": true, "onlineMeetingProvider": "teamsForBusiness", "onlineMeeting": {"joinUrl": "https://teams.example.com/join"}}`)
+	}))
+	defer srv.Close()
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	c := &client{
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+	}
+
+	event, err := c.CreateEvent("", &remote.Event{
+		Subject:               "Sync",
+		IsOnlineMeeting:       true,
+		OnlineMeetingProvider: remote.OnlineMeetingProviderTeams,
+	})
+	require.NoError(t, err)
+	require.Equal(t, &remote.Conference{Application: "Online Meeting", URL: "https://teams.example.com/join"}, event.Conference)
+}
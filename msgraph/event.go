@@ -5,10 +5,10 @@ package msgraph
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
-	msgraph "github.com/yaegashi/msgraph.go/v1.0"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
@@ -40,16 +40,47 @@ func normalizeEvents(events []*remote.Event) []*remote.Event {
 			events[i].ResponseStatus.Response = responseStatusConversion[events[i].ResponseStatus.Response]
 		}
 
-		if events[i].Conference == nil && events[i].OnlineMeeting != nil && events[i].OnlineMeeting.JoinURL != "" {
-			events[i].Conference = &remote.Conference{
-				Application: "Online Meeting",
-				URL:         events[i].OnlineMeeting.JoinURL,
-			}
-		}
+		normalizeRecurrence(events[i])
+		normalizeOnlineMeeting(events[i])
 	}
 	return events
 }
 
+// normalizeOnlineMeeting exposes the join URL of the online meeting of an
+// event as its conference.
+func normalizeOnlineMeeting(e *remote.Event) {
+	if e.Conference == nil && e.OnlineMeeting != nil && e.OnlineMeeting.JoinURL != "" {
+		e.Conference = &remote.Conference{
+			Application: "Online Meeting",
+			URL:         e.OnlineMeeting.JoinURL,
+		}
+	}
+}
+
+// normalizeRecurrence drops the placeholder values Microsoft returns for the
+// parts of a recurrence that don't apply, such as an end date of 0001-01-01
+// for ranges without an end.
+func normalizeRecurrence(e *remote.Event) {
+	if e.Recurrence == nil {
+		return
+	}
+	if p := e.Recurrence.Pattern; p != nil {
+		switch p.Type {
+		case remote.RecurrencePatternRelativeMonthly, remote.RecurrencePatternRelativeYearly:
+		default:
+			p.Index = ""
+		}
+	}
+	if r := e.Recurrence.Range; r != nil {
+		if r.Type != remote.RecurrenceRangeEndDate {
+			r.EndDate = ""
+		}
+		if r.Type != remote.RecurrenceRangeNumbered {
+			r.NumberOfOccurrences = 0
+		}
+	}
+}
+
 func (c *client) GetEvent(remoteUserID, eventID string) (*remote.Event, error) {
 	e := &remote.Event{}
 
@@ -64,63 +95,71 @@ func (c *client) GetEvent(remoteUserID, eventID string) (*remote.Event, error) {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
 		return nil, errors.Wrap(err, "msgraph GetEvent")
 	}
+	normalizeRecurrence(e)
+	normalizeOnlineMeeting(e)
 	return e, nil
 }
 
-func (c *client) AcceptEvent(remoteUserID, eventID string) error {
-	dummy := &msgraph.EventAcceptRequestParameter{}
-	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
-		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
-		return errors.New(ErrorUserInactive)
-	}
-	err := c.rbuilder.Me().Events().ID(eventID).Accept(dummy).Request().Post(c.ctx)
-	if err != nil {
-		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
-		return errors.Wrap(err, "msgraph Accept Event")
-	}
-	return nil
+func (c *client) AcceptEvent(remoteUserID, eventID string, response *remote.EventResponse) error {
+	return c.respond(remoteUserID, eventID, "/accept", response, "msgraph Accept Event")
+}
+
+func (c *client) DeclineEvent(remoteUserID, eventID string, response *remote.EventResponse) error {
+	return c.respond(remoteUserID, eventID, "/decline", response, "msgraph DeclineEvent")
+}
+
+func (c *client) TentativelyAcceptEvent(remoteUserID, eventID string, response *remote.EventResponse) error {
+	return c.respond(remoteUserID, eventID, "/tentativelyAccept", response, "msgraph TentativelyAcceptEvent")
 }
 
-func (c *client) DeclineEvent(remoteUserID, eventID string) error {
-	dummy := &msgraph.EventDeclineRequestParameter{}
+// respond answers an invitation in the calendar of the user, who may be the
+// principal of the connected user when responding as their delegate.
+func (c *client) respond(remoteUserID, eventID, action string, response *remote.EventResponse, errContext string) error {
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return errors.New(ErrorUserInactive)
 	}
-	err := c.rbuilder.Me().Events().ID(eventID).Decline(dummy).Request().Post(c.ctx)
-	if err != nil {
+	err := c.rbuilder.Users().ID(remoteUserID).Events().ID(eventID).Request().JSONRequest(c.ctx, http.MethodPost, action, response, nil)
+	// Responses are sent asynchronously, and acknowledged with a 202 the
+	// request builder doesn't expect.
+	if err != nil && !strings.Contains(err.Error(), "202 Accepted") {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
-		return errors.Wrap(err, "msgraph DeclineEvent")
+		return errors.Wrap(err, errContext)
 	}
 	return nil
 }
 
-func (c *client) TentativelyAcceptEvent(eventID string) error {
-	dummy := &msgraph.EventTentativelyAcceptRequestParameter{}
+func (c *client) GetEventsBetweenDates(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+	paramStr := getQueryParamStringForCalendarView(start, end)
+	res := &calendarViewResponse{}
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
-		return errors.New(ErrorUserInactive)
+		return nil, errors.New(ErrorUserInactive)
 	}
-	err := c.rbuilder.Me().Events().ID(eventID).TentativelyAccept(dummy).Request().Post(c.ctx)
+	err := c.rbuilder.Me().CalendarView().Request().JSONRequest(
+		c.ctx, http.MethodGet, paramStr, nil, res)
 	if err != nil {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
-		return errors.Wrap(err, "msgraph TentativelyAcceptEvent")
+		return nil, errors.Wrap(err, "msgraph GetEventsBetweenDates")
 	}
-	return nil
+
+	return normalizeEvents(res.Value), nil
 }
 
-func (c *client) GetEventsBetweenDates(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
+// GetCalendarView returns the events of one of the calendars of the user
+// between start and end.
+func (c *client) GetCalendarView(remoteUserID, calendarID string, start, end time.Time) ([]*remote.Event, error) {
 	paramStr := getQueryParamStringForCalendarView(start, end)
 	res := &calendarViewResponse{}
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return nil, errors.New(ErrorUserInactive)
 	}
-	err := c.rbuilder.Me().CalendarView().Request().JSONRequest(
+	err := c.rbuilder.Users().ID(remoteUserID).Calendars().ID(calendarID).CalendarView().Request().JSONRequest(
 		c.ctx, http.MethodGet, paramStr, nil, res)
 	if err != nil {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
-		return nil, errors.Wrap(err, "msgraph GetEventsBetweenDates")
+		return nil, errors.Wrap(err, "msgraph GetCalendarView")
 	}
 
 	return normalizeEvents(res.Value), nil
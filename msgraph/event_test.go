@@ -0,0 +1,75 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestNormalizeEventsRecurrence(t *testing.T) {
+	data := `{"value": [
+		{
+			"id": "master",
+			"type": "seriesMaster",
+			"recurrence": {
+				"pattern": {"type": "weekly", "interval": 1, "month": 0, "dayOfMonth": 0, "daysOfWeek": ["monday", "wednesday"], "firstDayOfWeek": "sunday", "index": "first"},
+				"range": {"type": "noEnd", "startDate": "2024-01-08", "endDate": "0001-01-01", "recurrenceTimeZone": "W. Europe Standard Time", "numberOfOccurrences": 0}
+			}
+		},
+		{
+			"id": "occurrence",
+			"type": "occurrence",
+			"seriesMasterId": "master",
+			"recurrence": null
+		},
+		{
+			"id": "monthly",
+			"type": "seriesMaster",
+			"recurrence": {
+				"pattern": {"type": "relativeMonthly", "interval": 1, "daysOfWeek": ["friday"], "index": "last"},
+				"range": {"type": "numbered", "startDate": "2024-01-26", "endDate": "0001-01-01", "numberOfOccurrences": 6}
+			}
+		},
+		{
+			"id": "single",
+			"type": "singleInstance"
+		}
+	]}`
+
+	res := &calendarViewResponse{}
+	require.NoError(t, json.Unmarshal([]byte(data), res))
+	events := normalizeEvents(res.Value)
+	require.Len(t, events, 4)
+
+	require.True(t, events[0].IsRecurring())
+	require.Equal(t, &remote.PatternedRecurrence{
+		Pattern: &remote.RecurrencePattern{
+			Type:           remote.RecurrencePatternWeekly,
+			Interval:       1,
+			DaysOfWeek:     []string{"monday", "wednesday"},
+			FirstDayOfWeek: "sunday",
+		},
+		Range: &remote.RecurrenceRange{
+			Type:               remote.RecurrenceRangeNoEnd,
+			StartDate:          "2024-01-08",
+			RecurrenceTimeZone: "W. Europe Standard Time",
+		},
+	}, events[0].Recurrence)
+
+	require.True(t, events[1].IsRecurring())
+	require.Equal(t, remote.EventTypeOccurrence, events[1].Type)
+	require.Equal(t, "master", events[1].SeriesMasterID)
+	require.Nil(t, events[1].Recurrence)
+
+	require.Equal(t, "last", events[2].Recurrence.Pattern.Index)
+	require.Equal(t, 6, events[2].Recurrence.Range.NumberOfOccurrences)
+	require.Empty(t, events[2].Recurrence.Range.EndDate)
+
+	require.False(t, events[3].IsRecurring())
+}
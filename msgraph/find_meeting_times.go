@@ -4,13 +4,22 @@
 package msgraph
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
 )
 
+// findMeetingTimesRequest is the body of a findMeetingTimes request. Graph
+// reads the meeting duration as an ISO 8601 duration, not as nanoseconds.
+type findMeetingTimesRequest struct {
+	*remote.FindMeetingTimesParameters
+	MeetingDuration string `json:"meetingDuration,omitempty"`
+}
+
 // FindMeetingTimes finds meeting time suggestions for a calendar event
 func (c *client) FindMeetingTimes(params *remote.FindMeetingTimesParameters) (*remote.MeetingTimeSuggestionResults, error) {
 	meetingsOut := &remote.MeetingTimeSuggestionResults{}
@@ -18,11 +27,37 @@ func (c *client) FindMeetingTimes(params *remote.FindMeetingTimesParameters) (*r
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return nil, errors.New(ErrorUserInactive)
 	}
+
+	body := &findMeetingTimesRequest{FindMeetingTimesParameters: params}
+	if params.MeetingDuration != nil {
+		body.MeetingDuration = isoDuration(*params.MeetingDuration)
+	}
+
 	req := c.rbuilder.Me().FindMeetingTimes(nil).Request()
-	err := req.JSONRequest(c.ctx, http.MethodPost, "", &params, &meetingsOut)
+	err := req.JSONRequest(c.ctx, http.MethodPost, "", body, &meetingsOut)
 	if err != nil {
 		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
 		return nil, errors.Wrap(err, "msgraph FindMeetingTimes")
 	}
 	return meetingsOut, nil
 }
+
+// isoDuration formats d as an ISO 8601 duration, such as PT1H30M.
+func isoDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return "PT0S"
+	}
+
+	out := "PT"
+	if h := d / time.Hour; h > 0 {
+		out += fmt.Sprintf("%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		out += fmt.Sprintf("%dM", m)
+	}
+	if s := d % time.Minute / time.Second; s > 0 {
+		out += fmt.Sprintf("%dS", s)
+	}
+	return out
+}
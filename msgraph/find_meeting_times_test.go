@@ -0,0 +1,109 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+func TestFindMeetingTimes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/me/findMeetingTimes", r.URL.Path)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "PT45M", body["meetingDuration"])
+		require.EqualValues(t, 5, body["maxCandidates"])
+		require.Len(t, body["attendees"], 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"emptySuggestionsReason": "", "meetingTimeSuggestions": [{
+			"confidence": 100,
+			"order": 1,
+			"organizerAvailability": "free",
+			"meetingTimeSlot": {
+				"start": {"dateTime": "2024-03-04T14:00:00.0000000", "timeZone": "UTC"},
+				"end": {"dateTime": "2024-03-04T14:45:00.0000000", "timeZone": "UTC"}
+			}
+		}]}`)
+	}))
+	defer srv.Close()
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	c := &client{
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+		Logger:       &bot.NilLogger{},
+	}
+
+	duration := 45 * time.Minute
+	maxCandidates := 5
+	results, err := c.FindMeetingTimes(&remote.FindMeetingTimesParameters{
+		MeetingDuration: &duration,
+		MaxCandidates:   &maxCandidates,
+		Attendees: []remote.Attendee{{
+			Type:         "required",
+			EmailAddress: &remote.EmailAddress{Address: "alice@example.com"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results.MeetingTimeSuggestions, 1)
+	suggestion := results.MeetingTimeSuggestions[0]
+	require.Equal(t, time.Date(2024, 3, 4, 14, 45, 0, 0, time.UTC), suggestion.MeetingTimeSlot.End.Time())
+	require.Equal(t, int32(1), suggestion.Order)
+}
+
+func TestFindMeetingTimesEmpty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"emptySuggestionsReason": "AttendeesUnavailable", "meetingTimeSuggestions": []}`)
+	}))
+	defer srv.Close()
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	c := &client{
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+		Logger:       &bot.NilLogger{},
+	}
+
+	results, err := c.FindMeetingTimes(&remote.FindMeetingTimesParameters{})
+	require.NoError(t, err)
+	require.Empty(t, results.MeetingTimeSuggestions)
+	require.Equal(t, "AttendeesUnavailable", results.EmptySuggestionReason)
+}
+
+func TestISODuration(t *testing.T) {
+	for _, tc := range []struct {
+		in       time.Duration
+		expected string
+	}{
+		{in: 45 * time.Minute, expected: "PT45M"},
+		{in: 90 * time.Minute, expected: "PT1H30M"},
+		{in: 2 * time.Hour, expected: "PT2H"},
+		{in: 30 * time.Second, expected: "PT30S"},
+		{in: 0, expected: "PT0S"},
+	} {
+		t.Run(tc.expected, func(t *testing.T) {
+			require.Equal(t, tc.expected, isoDuration(tc.in))
+		})
+	}
+}
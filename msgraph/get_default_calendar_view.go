@@ -18,17 +18,6 @@ type calendarViewResponse struct {
 	Value []*remote.Event  `json:"value,omitempty"`
 }
 
-type calendarViewSingleResponse struct {
-	Headers map[string]string    `json:"headers"`
-	ID      string               `json:"id"`
-	Body    calendarViewResponse `json:"body"`
-	Status  int                  `json:"status"`
-}
-
-type calendarViewBatchResponse struct {
-	Responses []*calendarViewSingleResponse `json:"responses"`
-}
-
 func (c *client) GetDefaultCalendarView(remoteUserID string, start, end time.Time) ([]*remote.Event, error) {
 	return c.GetEventsBetweenDates(remoteUserID, start, end)
 }
@@ -38,7 +27,6 @@ func (c *client) DoBatchViewCalendarRequests(allParams []*remote.ViewCalendarPar
 	for _, params := range allParams {
 		u := getCalendarViewURL(params)
 		req := &singleRequest{
-			ID:      params.RemoteUserID,
 			URL:     u,
 			Method:  http.MethodGet,
 			Headers: map[string]string{},
@@ -46,27 +34,32 @@ func (c *client) DoBatchViewCalendarRequests(allParams []*remote.ViewCalendarPar
 		requests = append(requests, req)
 	}
 
-	batchRequests := prepareBatchRequests(requests)
-	var batchResponses []*calendarViewBatchResponse
-	for _, req := range batchRequests {
-		batchRes := &calendarViewBatchResponse{}
-		err := c.batchRequest(req, batchRes)
-		if err != nil {
-			return nil, errors.Wrap(err, "msgraph ViewCalendar batch request")
-		}
-
-		batchResponses = append(batchResponses, batchRes)
+	responses, err := c.doBatch(requests)
+	if err != nil {
+		return nil, errors.Wrap(err, "msgraph ViewCalendar batch request")
 	}
 
-	result := []*remote.ViewCalendarResponse{}
-	for _, batchRes := range batchResponses {
-		for _, res := range batchRes.Responses {
-			viewCalRes := &remote.ViewCalendarResponse{
-				RemoteUserID: res.ID,
-				Events:       normalizeEvents(res.Body.Value),
-				Error:        res.Body.Error,
+	result := make([]*remote.ViewCalendarResponse, len(allParams))
+	for i, res := range responses {
+		if res == nil {
+			continue
+		}
+		view := calendarViewResponse{}
+		if err = res.decode(&view); err != nil {
+			view.Error = &remote.APIError{Message: err.Error()}
+		}
+		result[i] = &remote.ViewCalendarResponse{
+			RemoteUserID: allParams[i].RemoteUserID,
+			Events:       normalizeEvents(view.Value),
+			Error:        view.Error,
+		}
+	}
+	for i, res := range result {
+		if res == nil {
+			result[i] = &remote.ViewCalendarResponse{
+				RemoteUserID: allParams[i].RemoteUserID,
+				Error:        &remote.APIError{Message: "no response for the request"},
 			}
-			result = append(result, viewCalRes)
 		}
 	}
 
@@ -75,6 +68,9 @@ func (c *client) DoBatchViewCalendarRequests(allParams []*remote.ViewCalendarPar
 
 func getCalendarViewURL(params *remote.ViewCalendarParams) string {
 	paramStr := getQueryParamStringForCalendarView(params.StartTime, params.EndTime)
+	if params.CalendarID != "" {
+		return "/Users/" + url.PathEscape(params.RemoteUserID) + "/calendars/" + url.PathEscape(params.CalendarID) + "/calendarView" + paramStr
+	}
 	return "/Users/" + url.PathEscape(params.RemoteUserID) + "/calendarView" + paramStr
 }
 
@@ -0,0 +1,41 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestGetCalendarViewURL(t *testing.T) {
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	query := "?%24top=20&endDateTime=2024-03-05T00%3A00%3A00Z&startDateTime=2024-03-04T00%3A00%3A00Z"
+
+	tests := []struct {
+		name     string
+		params   *remote.ViewCalendarParams
+		expected string
+	}{
+		{
+			name:     "default calendar",
+			params:   &remote.ViewCalendarParams{RemoteUserID: "remote_user_id", StartTime: start, EndTime: end},
+			expected: "/Users/remote_user_id/calendarView" + query,
+		},
+		{
+			name:     "selected calendar",
+			params:   &remote.ViewCalendarParams{RemoteUserID: "remote_user_id", CalendarID: "AAMk=", StartTime: start, EndTime: end},
+			expected: "/Users/remote_user_id/calendars/AAMk=/calendarView" + query,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, getCalendarViewURL(tt.params))
+		})
+	}
+}
@@ -14,7 +14,11 @@ import (
 
 func (c *client) GetNotificationData(orig *remote.Notification) (*remote.Notification, error) {
 	n := *orig
-	wh := n.Webhook.(*webhook)
+	wh := &webhook{}
+	err := n.DecodeWebhook(wh)
+	if err != nil {
+		return nil, errors.Wrap(err, "msgraph GetNotificationData: unexpected webhook")
+	}
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
 		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
 		return nil, errors.New(ErrorUserInactive)
@@ -22,7 +26,7 @@ func (c *client) GetNotificationData(orig *remote.Notification) (*remote.Notific
 	switch wh.ResourceData.DataType {
 	case "#Microsoft.Graph.Event":
 		event := remote.Event{}
-		_, err := c.CallJSON(http.MethodGet, wh.Resource, nil, &event)
+		_, err = c.CallJSON(http.MethodGet, wh.Resource, nil, &event)
 		if err != nil {
 			c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
 			c.Logger.With(bot.LogContext{
@@ -31,6 +35,8 @@ func (c *client) GetNotificationData(orig *remote.Notification) (*remote.Notific
 			}).Infof("msgraph: failed to fetch notification data resource: `%v`.", err)
 			return nil, errors.Wrap(err, "msgraph GetNotificationData")
 		}
+		normalizeRecurrence(&event)
+		normalizeOnlineMeeting(&event)
 		n.Event = &event
 		n.ChangeType = wh.ChangeType
 		n.IsBare = false
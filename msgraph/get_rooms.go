@@ -0,0 +1,100 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+type getRoomsResponse struct {
+	NextLink string         `json:"@odata.nextLink,omitempty"`
+	Value    []*remote.Room `json:"value,omitempty"`
+}
+
+type getRoomListsResponse struct {
+	NextLink string             `json:"@odata.nextLink,omitempty"`
+	Value    []*remote.RoomList `json:"value,omitempty"`
+}
+
+// GetRoomLists lists the room lists of the organization. All the pages are
+// read.
+func (c *client) GetRoomLists() ([]*remote.RoomList, error) {
+	if !c.conf.EnableRooms {
+		return nil, remote.ErrNotImplemented
+	}
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	result := []*remote.RoomList{}
+	for link := "/places/microsoft.graph.roomlist"; link != ""; {
+		res := &getRoomListsResponse{}
+		_, err := c.call(http.MethodGet, link, "", nil, res)
+		if err != nil {
+			if isForbidden(err) {
+				return nil, c.roomsForbidden(err)
+			}
+			c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+			return nil, errors.Wrap(err, "msgraph GetRoomLists")
+		}
+		result = append(result, res.Value...)
+		link = res.NextLink
+	}
+	return result, nil
+}
+
+// GetRooms lists the rooms of a room list, or of the organization when
+// roomListAddress is empty. All the pages are read.
+func (c *client) GetRooms(roomListAddress string) ([]*remote.Room, error) {
+	if !c.conf.EnableRooms {
+		return nil, remote.ErrNotImplemented
+	}
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	link := "/places/microsoft.graph.room"
+	if roomListAddress != "" {
+		link = "/places/" + url.PathEscape(roomListAddress) + "/microsoft.graph.roomlist/rooms"
+	}
+
+	result := []*remote.Room{}
+	for link != "" {
+		res := &getRoomsResponse{}
+		_, err := c.call(http.MethodGet, link, "", nil, res)
+		if err != nil {
+			if isForbidden(err) {
+				return nil, c.roomsForbidden(err)
+			}
+			c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+			return nil, errors.Wrap(err, "msgraph GetRooms")
+		}
+		result = append(result, res.Value...)
+		link = res.NextLink
+	}
+	return result, nil
+}
+
+// roomsForbidden tells that rooms cannot be read with the token of the user,
+// e.g. connected before rooms were enabled, or the scope was not consented.
+func (c *client) roomsForbidden(err error) error {
+	c.Logger.With(bot.LogContext{
+		"mattermostUserID": c.mattermostUserID,
+	}).Infof("msgraph: not allowed to read rooms, the Place.Read.All permission is missing: `%v`.", err)
+	return remote.ErrNotImplemented
+}
+
+func isForbidden(err error) bool {
+	var errResp *msgraph.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusForbidden
+}
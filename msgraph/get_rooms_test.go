@@ -0,0 +1,107 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+)
+
+func TestGetRooms(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.String() {
+		case "/places/microsoft.graph.roomlist":
+			fmt.Fprint(w, `{"value": [{"id": "list1", "displayName": "Headquarters", "emailAddress": "hq@example.com"}]}`)
+		case "/places/microsoft.graph.room":
+			fmt.Fprintf(w, `{"@odata.nextLink": "%s/places/microsoft.graph.room?$skip=1", "value": [{"id": "room1", "displayName": "Room 1", "emailAddress": "room1@example.com", "capacity": 8}]}`, srv.URL)
+		case "/places/microsoft.graph.room?$skip=1":
+			fmt.Fprint(w, `{"value": [{"id": "room2", "displayName": "Room 2", "emailAddress": "room2@example.com", "building": "North"}]}`)
+		case "/places/hq@example.com/microsoft.graph.roomlist/rooms":
+			fmt.Fprint(w, `{"value": [{"id": "room1", "displayName": "Room 1", "emailAddress": "room1@example.com"}]}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	c := &client{
+		conf:         &config.Config{StoredConfig: config.StoredConfig{EnableRooms: true}},
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+		Logger:       &bot.NilLogger{},
+	}
+
+	lists, err := c.GetRoomLists()
+	require.NoError(t, err)
+	require.Equal(t, []*remote.RoomList{{ID: "list1", DisplayName: "Headquarters", EmailAddress: "hq@example.com"}}, lists)
+
+	rooms, err := c.GetRooms("")
+	require.NoError(t, err)
+	require.Equal(t, []*remote.Room{
+		{ID: "room1", DisplayName: "Room 1", EmailAddress: "room1@example.com", Capacity: 8},
+		{ID: "room2", DisplayName: "Room 2", EmailAddress: "room2@example.com", Building: "North"},
+	}, rooms)
+
+	rooms, err = c.GetRooms("hq@example.com")
+	require.NoError(t, err)
+	require.Len(t, rooms, 1)
+}
+
+func TestGetRoomsNotAvailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error": {"code": "ErrorAccessDenied", "message": "Access is denied."}}`)
+	}))
+	defer srv.Close()
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	c := &client{
+		conf:         &config.Config{},
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+		Logger:       &bot.NilLogger{},
+	}
+
+	// Rooms not enabled
+	_, err := c.GetRooms("")
+	require.ErrorIs(t, err, remote.ErrNotImplemented)
+	_, err = c.GetRoomLists()
+	require.ErrorIs(t, err, remote.ErrNotImplemented)
+
+	// Token without the permission to read rooms
+	c.conf.EnableRooms = true
+	_, err = c.GetRooms("")
+	require.ErrorIs(t, err, remote.ErrNotImplemented)
+	_, err = c.GetRoomLists()
+	require.ErrorIs(t, err, remote.ErrNotImplemented)
+}
+
+func TestNewOAuth2ConfigRoomsScope(t *testing.T) {
+	r := &impl{conf: &config.Config{}}
+	require.NotContains(t, r.NewOAuth2Config().Scopes, "Place.Read.All")
+
+	r.conf.EnableRooms = true
+	require.Contains(t, r.NewOAuth2Config().Scopes, "Place.Read.All")
+}
@@ -17,17 +17,6 @@ type getScheduleResponse struct {
 	Value []*remote.ScheduleInformation `json:"value,omitempty"`
 }
 
-type getScheduleSingleResponse struct {
-	Headers map[string]string   `json:"headers"`
-	ID      string              `json:"id"`
-	Body    getScheduleResponse `json:"body"`
-	Status  int                 `json:"status"`
-}
-
-type getScheduleBatchResponse struct {
-	Responses []*getScheduleSingleResponse `json:"responses"`
-}
-
 type getScheduleRequestParams struct {
 	// Overall start and end of entire search window
 	StartTime *remote.DateTime `json:"startTime"`
@@ -56,29 +45,27 @@ func (c *client) GetSchedule(requests []*remote.ScheduleUserInfo, startTime, end
 	for _, req := range requests {
 		allRequests = append(allRequests, makeSingleRequestForGetSchedule(req, params))
 	}
-	batchRequests := prepareBatchRequests(allRequests)
-
-	var batchResponses []*getScheduleBatchResponse
-
-	for _, req := range batchRequests {
-		res := &getScheduleBatchResponse{}
-		err := c.batchRequest(req, res)
-		if err != nil {
-			return nil, errors.Wrap(err, "msgraph batch GetSchedule")
-		}
-
-		batchResponses = append(batchResponses, res)
+	responses, err := c.doBatch(allRequests)
+	if err != nil {
+		return nil, errors.Wrap(err, "msgraph batch GetSchedule")
 	}
 
 	result := []*remote.ScheduleInformation{}
-	for _, batchRes := range batchResponses {
-		for _, r := range batchRes.Responses {
-			if r.Body.Error == nil {
-				result = append(result, r.Body.Value...)
-			} else {
-				c.Warnf("Failed to process schedule. err=%s", r.Body.Error.Message)
-			}
+	for i, res := range responses {
+		if res == nil {
+			c.Warnf("Failed to process schedule of %s. err=no response for the request", requests[i].RemoteUserID)
+			continue
+		}
+		schedule := getScheduleResponse{}
+		if err = res.decode(&schedule); err != nil {
+			c.Warnf("Failed to process schedule of %s. err=%v", requests[i].RemoteUserID, err)
+			continue
+		}
+		if schedule.Error != nil {
+			c.Warnf("Failed to process schedule of %s. err=%s", requests[i].RemoteUserID, schedule.Error.Message)
+			continue
 		}
+		result = append(result, schedule.Value...)
 	}
 
 	return result, nil
@@ -89,7 +76,6 @@ func makeSingleRequestForGetSchedule(request *remote.ScheduleUserInfo, params *g
 	req := &singleRequest{
 		URL:    u,
 		Method: http.MethodPost,
-		ID:     request.RemoteUserID,
 		Body: &getScheduleRequestParams{
 			Schedules:                []string{request.Mail},
 			StartTime:                params.StartTime,
@@ -0,0 +1,73 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+type graphCalendar struct {
+	Owner *struct {
+		Name    string `json:"name"`
+		Address string `json:"address"`
+	} `json:"owner"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	CanEdit bool   `json:"canEdit"`
+}
+
+// GetSharedCalendars lists the calendars of other users the user has added,
+// after they were shared with them or delegated to them.
+func (c *client) GetSharedCalendars(remoteUserID string) ([]*remote.SharedCalendar, error) {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+
+	userReq := c.rbuilder.Users().ID(remoteUserID).Request()
+	userReq.Select("mail,userPrincipalName")
+	user, err := userReq.Get(c.ctx)
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return nil, errors.Wrap(err, "msgraph GetSharedCalendars")
+	}
+	own := map[string]bool{}
+	if user.Mail != nil {
+		own[strings.ToLower(*user.Mail)] = true
+	}
+	if user.UserPrincipalName != nil {
+		own[strings.ToLower(*user.UserPrincipalName)] = true
+	}
+
+	var v struct {
+		Value []*graphCalendar `json:"value"`
+	}
+	req := c.rbuilder.Users().ID(remoteUserID).Calendars().Request()
+	req.Select("id,name,canEdit,owner")
+	err = req.JSONRequest(c.ctx, http.MethodGet, "", nil, &v)
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return nil, errors.Wrap(err, "msgraph GetSharedCalendars")
+	}
+
+	result := []*remote.SharedCalendar{}
+	for _, cal := range v.Value {
+		if cal.Owner == nil || cal.Owner.Address == "" || own[strings.ToLower(cal.Owner.Address)] {
+			continue
+		}
+		result = append(result, &remote.SharedCalendar{
+			ID:        cal.ID,
+			Name:      cal.Name,
+			OwnerName: cal.Owner.Name,
+			OwnerMail: cal.Owner.Address,
+			CanEdit:   cal.CanEdit,
+		})
+	}
+	return result, nil
+}
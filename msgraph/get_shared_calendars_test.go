@@ -0,0 +1,55 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestGetSharedCalendars(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/remote_user_id":
+			fmt.Fprint(w, `{"mail": "assistant@example.com", "userPrincipalName": "assistant@example.onmicrosoft.com"}`)
+		case "/users/remote_user_id/calendars":
+			fmt.Fprint(w, `{"value": [
+				{"id": "own", "name": "Calendar", "canEdit": true, "owner": {"name": "Assistant", "address": "Assistant@example.com"}},
+				{"id": "manager", "name": "Manager", "canEdit": true, "owner": {"name": "Manager", "address": "manager@example.com"}},
+				{"id": "holidays", "name": "Holidays"}
+			]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	c := &client{
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+	}
+
+	calendars, err := c.GetSharedCalendars("remote_user_id")
+	require.NoError(t, err)
+	require.Equal(t, []*remote.SharedCalendar{{
+		ID:        "manager",
+		Name:      "Manager",
+		OwnerName: "Manager",
+		OwnerMail: "manager@example.com",
+		CanEdit:   true,
+	}}, calendars)
+}
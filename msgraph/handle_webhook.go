@@ -19,13 +19,16 @@ const renewSubscriptionBeforeExpiration = 12 * time.Hour
 
 type webhook struct {
 	ChangeType                     string `json:"changeType"`
+	LifecycleEvent                 string `json:"lifecycleEvent,omitempty"`
 	ClientState                    string `json:"clientState,omitempty"`
 	Resource                       string `json:"resource,omitempty"`
 	SubscriptionExpirationDateTime string `json:"subscriptionExpirationDateTime,omitempty"`
 	SubscriptionID                 string `json:"subscriptionId"`
 	ResourceData                   struct {
 		DataType string `json:"@odata.type"`
+		ID       string `json:"id,omitempty"`
 	} `json:"resourceData"`
+	EncryptedContent *encryptedContent `json:"encryptedContent,omitempty"`
 }
 
 func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote.Notification {
@@ -56,7 +59,8 @@ func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote
 
 	// Get the list of webhooks
 	var v struct {
-		Value []*webhook `json:"value"`
+		Value            []*webhook `json:"value"`
+		ValidationTokens []string   `json:"validationTokens,omitempty"`
 	}
 	err = json.Unmarshal(rawData, &v)
 	if err != nil {
@@ -65,24 +69,70 @@ func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote
 		return nil
 	}
 
+	// Each notification keeps only its own entry of the webhook, not the
+	// whole payload.
+	var entries struct {
+		Value []json.RawMessage `json:"value"`
+	}
+	err = json.Unmarshal(rawData, &entries)
+	if err != nil || len(entries.Value) != len(v.Value) {
+		w.WriteHeader(http.StatusBadRequest)
+		r.logger.Infof("msgraph: failed to process webhook: `%v`.", err)
+		return nil
+	}
+
+	// Rich notifications are authenticated with validation tokens, other
+	// notifications with the client state of their subscription.
+	if len(v.ValidationTokens) > 0 || hasResourceData(v.Value) {
+		err = r.validateTokens(v.ValidationTokens)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			r.logger.Infof("msgraph: rejected webhook: `%v`.", err)
+			return nil
+		}
+	}
+
 	notifications := []*remote.Notification{}
-	for _, wh := range v.Value {
+	for i, wh := range v.Value {
 		if wh == nil {
 			r.logger.Infof("msgraph: skipping null webhook entry in notification payload.")
 			continue
 		}
 
+		// Lifecycle notifications carry no resource data to fetch, see
+		// https://learn.microsoft.com/en-us/graph/change-notifications-lifecycle-events
 		n := &remote.Notification{
 			SubscriptionID: wh.SubscriptionID,
 			ChangeType:     wh.ChangeType,
+			LifecycleEvent: wh.LifecycleEvent,
 			ClientState:    wh.ClientState,
-			IsBare:         true,
-			WebhookRawData: rawData,
+			IsBare:         wh.LifecycleEvent == "",
+			WebhookRawData: entries.Value[i],
 			Webhook:        wh,
 		}
 
+		if wh.EncryptedContent != nil {
+			event, decryptErr := decryptEvent(wh.EncryptedContent, r.conf.NotificationCertificate)
+			if decryptErr != nil {
+				// The resource data is fetched from Graph instead.
+				r.logger.With(bot.LogContext{
+					"SubscriptionID": wh.SubscriptionID,
+				}).Infof("msgraph: failed to decrypt webhook resource data: `%v`.", decryptErr)
+			} else {
+				n.Event = event
+				n.IsBare = false
+			}
+		}
+
+		// A deleted event cannot be fetched anymore.
+		if wh.ChangeType == remote.ChangeTypeDeleted {
+			n.Event = &remote.Event{ID: wh.ResourceData.ID}
+			n.IsBare = false
+		}
+
 		expires, err := time.Parse(time.RFC3339, wh.SubscriptionExpirationDateTime)
 		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
 			r.logger.With(bot.LogContext{
 				"SubscriptionID": wh.SubscriptionID,
 			}).Infof("msgraph: invalid subscription expiration in webhook: `%v`.", err)
@@ -96,6 +146,14 @@ func (r *impl) HandleWebhook(w http.ResponseWriter, req *http.Request) []*remote
 		notifications = append(notifications, n)
 	}
 
-	w.WriteHeader(http.StatusAccepted)
 	return notifications
 }
+
+func hasResourceData(webhooks []*webhook) bool {
+	for _, wh := range webhooks {
+		if wh != nil && wh.EncryptedContent != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -4,15 +4,18 @@
 package msgraph
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
 )
 
 func TestHandleWebhook(t *testing.T) {
@@ -29,39 +32,61 @@ func TestHandleWebhook(t *testing.T) {
 		}]
 	}`
 
+	// Requests with notifications are answered by the caller, after
+	// authenticating the notifications.
 	tests := []struct {
 		name                string
 		body                string
 		wantStatus          int
 		wantNotificationLen int
 		wantSubscriptionID  string
+		wantLifecycleEvent  string
+		wantDeletedEventID  string
+		wantRawData         string
 	}{
 		{
 			name:                "empty value array",
 			body:                `{"value":[]}`,
-			wantStatus:          http.StatusAccepted,
+			wantStatus:          http.StatusOK,
 			wantNotificationLen: 0,
 		},
 		{
 			name:                "null entry in value array",
 			body:                `{"value":[null]}`,
-			wantStatus:          http.StatusAccepted,
+			wantStatus:          http.StatusOK,
 			wantNotificationLen: 0,
 		},
 		{
 			name:                "mixed null and valid entries",
 			body:                `{"value":[null,{"changeType":"updated","subscriptionId":"sub-123","subscriptionExpirationDateTime":"2030-01-01T00:00:00Z"}]}`,
-			wantStatus:          http.StatusAccepted,
+			wantStatus:          http.StatusOK,
 			wantNotificationLen: 1,
 			wantSubscriptionID:  "sub-123",
+			wantRawData:         `{"changeType":"updated","subscriptionId":"sub-123","subscriptionExpirationDateTime":"2030-01-01T00:00:00Z"}`,
 		},
 		{
 			name:                "valid webhook entry",
 			body:                validWebhook,
-			wantStatus:          http.StatusAccepted,
+			wantStatus:          http.StatusOK,
 			wantNotificationLen: 1,
 			wantSubscriptionID:  "sub-123",
 		},
+		{
+			name:                "lifecycle event",
+			body:                `{"value":[{"lifecycleEvent":"subscriptionRemoved","subscriptionId":"sub-123","subscriptionExpirationDateTime":"2030-01-01T00:00:00Z"}]}`,
+			wantStatus:          http.StatusOK,
+			wantNotificationLen: 1,
+			wantSubscriptionID:  "sub-123",
+			wantLifecycleEvent:  "subscriptionRemoved",
+		},
+		{
+			name:                "deleted event",
+			body:                `{"value":[{"changeType":"deleted","subscriptionId":"sub-123","subscriptionExpirationDateTime":"2030-01-01T00:00:00Z","resourceData":{"@odata.type":"#Microsoft.Graph.Event","id":"event-id"}}]}`,
+			wantStatus:          http.StatusOK,
+			wantNotificationLen: 1,
+			wantSubscriptionID:  "sub-123",
+			wantDeletedEventID:  "event-id",
+		},
 		{
 			name:                "invalid json",
 			body:                `{invalid`,
@@ -80,6 +105,14 @@ func TestHandleWebhook(t *testing.T) {
 				require.Len(t, notifications, tc.wantNotificationLen)
 				if tc.wantSubscriptionID != "" {
 					require.Equal(t, tc.wantSubscriptionID, notifications[0].SubscriptionID)
+					require.Equal(t, tc.wantLifecycleEvent, notifications[0].LifecycleEvent)
+					require.Equal(t, tc.wantLifecycleEvent == "" && tc.wantDeletedEventID == "", notifications[0].IsBare)
+					if tc.wantDeletedEventID != "" {
+						require.Equal(t, tc.wantDeletedEventID, notifications[0].Event.ID)
+					}
+					if tc.wantRawData != "" {
+						require.Equal(t, tc.wantRawData, string(notifications[0].WebhookRawData))
+					}
 				}
 			})
 
@@ -88,6 +121,59 @@ func TestHandleWebhook(t *testing.T) {
 	}
 }
 
+func TestHandleWebhookRichNotification(t *testing.T) {
+	now := time.Now()
+	cert, err := certificate.New("test", now, time.Hour)
+	require.NoError(t, err)
+	signingKey := newTestSigningKey(t)
+	remote := &impl{
+		conf: &config.Config{
+			StoredConfig:            config.StoredConfig{OAuth2ClientID: testClientID, OAuth2Authority: testTenantID},
+			NotificationCertificate: cert,
+		},
+		logger:      &bot.NilLogger{},
+		signingKeys: staticKeySet{"kid1": &signingKey.PublicKey},
+	}
+
+	content, err := json.Marshal(encryptForTest(t, cert, []byte(`{"iCalUId":"event_uid","subject":"Planning"}`)))
+	require.NoError(t, err)
+	value := `"value":[{"changeType":"created","subscriptionId":"sub-123","subscriptionExpirationDateTime":"2030-01-01T00:00:00Z","encryptedContent":` + string(content) + `}]`
+	validToken := signTestToken(t, signingKey, "kid1", validTestClaims(now))
+	otherTenantClaims := validTestClaims(now)
+	otherTenantClaims["tid"] = "ffffffff-bbbb-cccc-dddd-eeeeeeeeeeee"
+	otherTenantClaims["iss"] = "https://sts.windows.net/ffffffff-bbbb-cccc-dddd-eeeeeeeeeeee/"
+
+	for name, tc := range map[string]struct {
+		tokens     []string
+		wantStatus int
+	}{
+		"valid validation token":             {tokens: []string{validToken}, wantStatus: http.StatusOK},
+		"missing validation tokens":          {wantStatus: http.StatusUnauthorized},
+		"validation token of another tenant": {tokens: []string{validToken, signTestToken(t, signingKey, "kid1", otherTenantClaims)}, wantStatus: http.StatusUnauthorized},
+		"validation token of a stranger":     {tokens: []string{signTestToken(t, newTestSigningKey(t), "kid1", validTestClaims(now))}, wantStatus: http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tokens, err := json.Marshal(tc.tokens)
+			require.NoError(t, err)
+			body := `{` + value + `,"validationTokens":` + string(tokens) + `}`
+			req := httptest.NewRequest(http.MethodPost, "/notification/v1/event", strings.NewReader(body))
+			rec := httptest.NewRecorder()
+
+			notifications := remote.HandleWebhook(rec, req)
+
+			require.Equal(t, tc.wantStatus, rec.Result().StatusCode)
+			if tc.wantStatus != http.StatusOK {
+				require.Nil(t, notifications)
+				return
+			}
+			require.Len(t, notifications, 1)
+			require.False(t, notifications[0].IsBare)
+			require.Equal(t, "event_uid", notifications[0].Event.ICalUID)
+			require.Equal(t, "Planning", notifications[0].Event.Subject)
+		})
+	}
+}
+
 func TestHandleWebhookValidationToken(t *testing.T) {
 	remote := &impl{
 		conf:   &config.Config{},
@@ -29,6 +29,7 @@ func GetMSCalendarProviderConfig() config.ProviderConfig {
 		Features: config.ProviderFeatures{
 			EncryptedStore:     false,
 			EventNotifications: true,
+			OnlineMeetings:     true,
 		},
 	}
 }
@@ -25,6 +25,8 @@ const Kind = "mscalendar"
 type impl struct {
 	conf   *config.Config
 	logger bot.Logger
+	// signingKeys are the keys validation tokens are signed with.
+	signingKeys keySet
 }
 
 func init() {
@@ -33,14 +35,16 @@ func init() {
 
 func NewRemote(conf *config.Config, logger bot.Logger) remote.Remote {
 	return &impl{
-		conf:   conf,
-		logger: logger,
+		conf:        conf,
+		logger:      logger,
+		signingKeys: microsoftSigningKeys,
 	}
 }
 
 // MakeClient creates a new client for user-delegated permissions.
 func (r *impl) makeClient(ctx context.Context, token *oauth2.Token, mattermostUserID string, poster bot.Poster, userTokenHelpers remote.UserTokenHelpers) remote.Client {
 	httpClient := r.NewOAuth2Config().Client(ctx, token)
+	httpClient.Transport = newThrottledTransport(httpClient.Transport, r.conf.OAuth2Authority)
 	c := &client{
 		conf:             r.conf,
 		ctx:              ctx,
@@ -121,18 +125,25 @@ func (noopUserTokenHelpers) RefreshAndStoreToken(token *oauth2.Token, _ *oauth2.
 }
 
 func (r *impl) NewOAuth2Config() *oauth2.Config {
+	scopes := []string{
+		"offline_access",
+		"User.Read",
+		"Calendars.ReadWrite",
+		"Calendars.ReadWrite.Shared",
+		"MailboxSettings.Read",
+	}
+	// Reading rooms needs an admin consent, it is only asked for when
+	// enabled.
+	if r.conf.EnableRooms {
+		scopes = append(scopes, "Place.Read.All")
+	}
+
 	return &oauth2.Config{
 		ClientID:     r.conf.OAuth2ClientID,
 		ClientSecret: r.conf.OAuth2ClientSecret,
 		RedirectURL:  r.conf.PluginURL + config.FullPathOAuth2Redirect,
-		Scopes: []string{
-			"offline_access",
-			"User.Read",
-			"Calendars.ReadWrite",
-			"Calendars.ReadWrite.Shared",
-			"MailboxSettings.Read",
-		},
-		Endpoint: microsoft.AzureADEndpoint(r.conf.OAuth2Authority),
+		Scopes:       scopes,
+		Endpoint:     microsoft.AzureADEndpoint(r.conf.OAuth2Authority),
 	}
 }
 
@@ -0,0 +1,106 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // RSA-OAEP with SHA-1 is what Graph encrypts the data key with.
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
+)
+
+// richNotificationEventProperties are the properties of the events sent in
+// rich notifications, those used to notify the user.
+const richNotificationEventProperties = "id,iCalUId,subject,bodyPreview,importance,start,end,isAllDay,location,attendees," +
+	"organizer,isOrganizer,responseStatus,responseRequested,recurrence,type,seriesMasterId,isCancelled,showAs," +
+	"sensitivity,webLink,isOnlineMeeting,onlineMeetingProvider,onlineMeeting"
+
+// encryptedContent is the resource data of a rich notification, see
+// https://learn.microsoft.com/en-us/graph/change-notifications-with-resource-data#decrypting-resource-data-from-change-notifications
+type encryptedContent struct {
+	Data                    string `json:"data"`
+	DataSignature           string `json:"dataSignature"`
+	DataKey                 string `json:"dataKey"`
+	EncryptionCertificateID string `json:"encryptionCertificateId"`
+}
+
+// decryptEvent returns the event encrypted in a rich notification.
+func decryptEvent(content *encryptedContent, cert *certificate.Certificate) (*remote.Event, error) {
+	if cert == nil || content.EncryptionCertificateID != cert.ID {
+		return nil, errors.New("unknown encryption certificate")
+	}
+	data, err := decryptContent(content, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &remote.Event{}
+	err = json.Unmarshal(data, event)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encrypted event")
+	}
+	normalizeRecurrence(event)
+	normalizeOnlineMeeting(event)
+	return event, nil
+}
+
+func decryptContent(content *encryptedContent, cert *certificate.Certificate) ([]byte, error) {
+	key, err := cert.Key()
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := base64.StdEncoding.DecodeString(content.DataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data key")
+	}
+	symmetricKey, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, key, encryptedKey, nil) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt the data key")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(content.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data")
+	}
+	signature, err := base64.StdEncoding.DecodeString(content.DataSignature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data signature")
+	}
+	mac := hmac.New(sha256.New, symmetricKey)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return nil, errors.New("data signature does not match")
+	}
+
+	block, err := aes.NewCipher(symmetricKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid data key")
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid data length")
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, symmetricKey[:aes.BlockSize]).CryptBlocks(plain, data)
+	return unpad(plain)
+}
+
+// unpad removes the PKCS7 padding of the decrypted data.
+func unpad(data []byte) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > aes.BlockSize || n > len(data) || !bytes.Equal(data[len(data)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, errors.New("invalid data padding")
+	}
+	return data[:len(data)-n], nil
+}
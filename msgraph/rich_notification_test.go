@@ -0,0 +1,85 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
+)
+
+// encryptForTest encrypts data the way Graph encrypts the resource data of
+// rich notifications.
+func encryptForTest(t *testing.T, cert *certificate.Certificate, data []byte) *encryptedContent {
+	key, err := cert.Key()
+	require.NoError(t, err)
+
+	symmetricKey := make([]byte, 32)
+	_, err = rand.Read(symmetricKey)
+	require.NoError(t, err)
+	encryptedKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, &key.PublicKey, symmetricKey, nil) //nolint:gosec
+	require.NoError(t, err)
+
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	padded := append(append([]byte{}, data...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	block, err := aes.NewCipher(symmetricKey)
+	require.NoError(t, err)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, symmetricKey[:aes.BlockSize]).CryptBlocks(encrypted, padded)
+
+	mac := hmac.New(sha256.New, symmetricKey)
+	mac.Write(encrypted)
+
+	return &encryptedContent{
+		Data:                    base64.StdEncoding.EncodeToString(encrypted),
+		DataSignature:           base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+		DataKey:                 base64.StdEncoding.EncodeToString(encryptedKey),
+		EncryptionCertificateID: cert.ID,
+	}
+}
+
+func TestDecryptEvent(t *testing.T) {
+	cert, err := certificate.New("test", time.Now(), time.Hour)
+	require.NoError(t, err)
+	eventJSON := []byte(`{"id":"event_id","iCalUId":"event_uid","subject":"Planning","responseRequested":true}`)
+
+	t.Run("decrypts the event", func(t *testing.T) {
+		event, err := decryptEvent(encryptForTest(t, cert, eventJSON), cert)
+		require.NoError(t, err)
+		require.Equal(t, "event_uid", event.ICalUID)
+		require.Equal(t, "Planning", event.Subject)
+		require.True(t, event.ResponseRequested)
+	})
+
+	t.Run("unknown certificate", func(t *testing.T) {
+		content := encryptForTest(t, cert, eventJSON)
+		content.EncryptionCertificateID = "other"
+		_, err := decryptEvent(content, cert)
+		require.EqualError(t, err, "unknown encryption certificate")
+	})
+
+	t.Run("no certificate", func(t *testing.T) {
+		_, err := decryptEvent(encryptForTest(t, cert, eventJSON), nil)
+		require.EqualError(t, err, "unknown encryption certificate")
+	})
+
+	t.Run("tampered data", func(t *testing.T) {
+		content := encryptForTest(t, cert, eventJSON)
+		content.DataSignature = encryptForTest(t, cert, eventJSON).DataSignature
+		_, err := decryptEvent(content, cert)
+		require.Error(t, err)
+	})
+}
@@ -23,13 +23,22 @@ func newRandomString() string {
 	return base64.URLEncoding.EncodeToString(b)
 }
 
-func (c *client) CreateMySubscription(notificationURL, _ string) (*remote.Subscription, error) {
+func (c *client) CreateMySubscription(notificationURL, lifecycleNotificationURL, _ string) (*remote.Subscription, error) {
 	sub := &remote.Subscription{
-		Resource:           "me/events",
-		ChangeType:         "created,updated,deleted",
-		NotificationURL:    notificationURL,
-		ExpirationDateTime: time.Now().Add(subscribeTTL).Format(time.RFC3339),
-		ClientState:        newRandomString(),
+		Resource:                 "me/events",
+		ChangeType:               "created,updated,deleted",
+		NotificationURL:          notificationURL,
+		LifecycleNotificationURL: lifecycleNotificationURL,
+		ExpirationDateTime:       time.Now().Add(subscribeTTL).Format(time.RFC3339),
+		ClientState:              newRandomString(),
+	}
+	if cert := c.conf.NotificationCertificate; cert != nil {
+		// Rich notifications require the properties of the event to be
+		// selected.
+		sub.Resource = "me/events?$select=" + richNotificationEventProperties
+		sub.IncludeResourceData = true
+		sub.EncryptionCertificate = cert.Encoded()
+		sub.EncryptionCertificateID = cert.ID
 	}
 
 	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
@@ -44,10 +53,11 @@ func (c *client) CreateMySubscription(notificationURL, _ string) (*remote.Subscr
 	}
 
 	c.Logger.With(bot.LogContext{
-		"subscriptionID":     sub.ID,
-		"resource":           sub.Resource,
-		"changeType":         sub.ChangeType,
-		"expirationDateTime": sub.ExpirationDateTime,
+		"subscriptionID":      sub.ID,
+		"resource":            sub.Resource,
+		"changeType":          sub.ChangeType,
+		"expirationDateTime":  sub.ExpirationDateTime,
+		"includeResourceData": sub.IncludeResourceData,
 	}).Debugf("msgraph: created subscription.")
 
 	return sub, nil
@@ -0,0 +1,82 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	msgraph "github.com/yaegashi/msgraph.go/v1.0"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/bot"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/utils/certificate"
+)
+
+func TestCreateMySubscription(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/subscriptions", r.URL.Path)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "https://example.com/notification/v1/event", body["notificationUrl"])
+		require.Equal(t, "https://example.com/notification/v1/lifecycle", body["lifecycleNotificationUrl"])
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "sub-123", "creatorId": "remote_user_id"}`)
+	}))
+	defer srv.Close()
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	c := &client{
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+		Logger:       &bot.NilLogger{},
+		conf:         &config.Config{},
+	}
+
+	sub, err := c.CreateMySubscription("https://example.com/notification/v1/event", "https://example.com/notification/v1/lifecycle", "remote_user_id")
+	require.NoError(t, err)
+	require.Equal(t, "sub-123", sub.ID)
+}
+
+func TestCreateMySubscriptionWithResourceData(t *testing.T) {
+	cert, err := certificate.New("test", time.Now(), time.Hour)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "me/events?$select="+richNotificationEventProperties, body["resource"])
+		require.Equal(t, true, body["includeResourceData"])
+		require.Equal(t, cert.Encoded(), body["encryptionCertificate"])
+		require.Equal(t, cert.ID, body["encryptionCertificateId"])
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "sub-123", "creatorId": "remote_user_id"}`)
+	}))
+	defer srv.Close()
+
+	rbuilder := msgraph.NewClient(srv.Client())
+	rbuilder.SetURL(srv.URL)
+	c := &client{
+		ctx:          context.Background(),
+		httpClient:   srv.Client(),
+		rbuilder:     rbuilder,
+		tokenHelpers: noopUserTokenHelpers{},
+		Logger:       &bot.NilLogger{},
+		conf:         &config.Config{NotificationCertificate: cert},
+	}
+
+	_, err = c.CreateMySubscription("https://example.com/notification/v1/event", "https://example.com/notification/v1/lifecycle", "remote_user_id")
+	require.NoError(t, err)
+}
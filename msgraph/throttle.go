@@ -0,0 +1,205 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+const (
+	// maxThrottleRetries is the number of times a throttled request is sent
+	// again before its 429 or 503 response is returned to the caller.
+	maxThrottleRetries = 4
+
+	throttleBackoffBase = time.Second
+	throttleBackoffMax  = 30 * time.Second
+
+	// maxRetryAfter bounds the wait asked by Graph with Retry-After. Longer
+	// waits fail the request instead of blocking the caller.
+	maxRetryAfter = 2 * time.Minute
+
+	// maxInteractiveThrottleWait bounds the total wait of a request made for
+	// a user, who gets a "try again later" error rather than waiting for
+	// minutes. Only background jobs wait up to maxRetryAfter.
+	maxInteractiveThrottleWait = 5 * time.Second
+
+	// tenantConcurrencyBudget is the number of requests sent to Graph at
+	// the same time for a tenant, across all the clients of the plugin.
+	tenantConcurrencyBudget = 16
+)
+
+var tenantBudgets = struct {
+	sync.Mutex
+	slots map[string]chan struct{}
+}{slots: map[string]chan struct{}{}}
+
+// tenantBudget returns the semaphore shared by the requests of a tenant.
+func tenantBudget(tenant string) chan struct{} {
+	tenantBudgets.Lock()
+	defer tenantBudgets.Unlock()
+
+	slots, ok := tenantBudgets.slots[tenant]
+	if !ok {
+		slots = make(chan struct{}, tenantConcurrencyBudget)
+		tenantBudgets.slots[tenant] = slots
+	}
+	return slots
+}
+
+// throttledTransport sends the requests of a tenant within its concurrency
+// budget, and retries the requests throttled by Graph, waiting as long as
+// Retry-After asks or backing off exponentially. The requests of users only
+// wait a few seconds in total, see remote.BackgroundContext.
+type throttledTransport struct {
+	base   http.RoundTripper
+	budget chan struct{}
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func newThrottledTransport(base http.RoundTripper, tenant string) *throttledTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &throttledTransport{
+		base:   base,
+		budget: tenantBudget(tenant),
+		sleep:  sleepContext,
+	}
+}
+
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	waitLimit := throttleWaitLimit(ctx)
+	var waited time.Duration
+	for attempt := 0; ; attempt++ {
+		resp, err := t.send(req)
+		if err != nil || !isThrottled(resp) {
+			return resp, err
+		}
+		remote.CountThrottled(ctx)
+
+		if attempt == maxThrottleRetries || (req.Body != nil && req.GetBody == nil) {
+			return giveUpThrottled(ctx, resp)
+		}
+		wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			wait = backoff(attempt)
+		}
+		waited += wait
+		if wait > maxRetryAfter || waited > waitLimit {
+			return giveUpThrottled(ctx, resp)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		// The slot of the request is free while it waits, for the other
+		// requests of the tenant.
+		if err = t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+		req, err = rewind(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send sends the request within the concurrency budget of the tenant.
+func (t *throttledTransport) send(req *http.Request) (*http.Response, error) {
+	select {
+	case t.budget <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-t.budget }()
+
+	return t.base.RoundTrip(req)
+}
+
+// throttleWaitLimit returns how long the requests made with ctx wait in total
+// for Graph to stop throttling them.
+func throttleWaitLimit(ctx context.Context) time.Duration {
+	if remote.IsBackground(ctx) {
+		return maxThrottleRetries * maxRetryAfter
+	}
+	return maxInteractiveThrottleWait
+}
+
+// giveUpThrottled returns the throttled response to background jobs, which
+// handle it like any failed request, and ErrThrottled to users.
+func giveUpThrottled(ctx context.Context, resp *http.Response) (*http.Response, error) {
+	if remote.IsBackground(ctx) {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil, remote.ErrThrottled
+}
+
+func isThrottled(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// retryAfter reads the value of a Retry-After header, given either in
+// seconds or as a date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if at.Before(now) {
+			return 0, true
+		}
+		return at.Sub(now), true
+	}
+	return 0, false
+}
+
+// backoff returns the wait before the retry following the given attempt: it
+// doubles with each attempt up to throttleBackoffMax, with half of it random
+// so that the requests throttled together don't retry together.
+func backoff(attempt int) time.Duration {
+	wait := throttleBackoffMax
+	if attempt < 8 {
+		wait = min(throttleBackoffBase<<attempt, throttleBackoffMax)
+	}
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// rewind returns a copy of the request with a fresh body, to send it again.
+func rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		next.Body = body
+	}
+	return next, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+func TestThrottledTransport(t *testing.T) {
+	tests := []struct {
+		name              string
+		background        bool
+		responses         []int
+		retryAfter        string
+		expectedStatus    int
+		expectedError     error
+		expectedCalls     int
+		expectedThrottled int64
+		expectedWaits     []time.Duration
+	}{
+		{
+			name:           "request not throttled",
+			responses:      []int{http.StatusOK},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+		},
+		{
+			name:              "Retry-After is honored",
+			background:        true,
+			responses:         []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:        "3",
+			expectedStatus:    http.StatusOK,
+			expectedCalls:     3,
+			expectedThrottled: 2,
+			expectedWaits:     []time.Duration{3 * time.Second, 3 * time.Second},
+		},
+		{
+			name:              "Retry-After too long fails the request",
+			background:        true,
+			responses:         []int{http.StatusTooManyRequests},
+			retryAfter:        "600",
+			expectedStatus:    http.StatusTooManyRequests,
+			expectedCalls:     1,
+			expectedThrottled: 1,
+		},
+		{
+			name:              "retries are bounded",
+			background:        true,
+			responses:         []int{503, 503, 503, 503, 503, 503},
+			expectedStatus:    http.StatusServiceUnavailable,
+			expectedCalls:     maxThrottleRetries + 1,
+			expectedThrottled: maxThrottleRetries + 1,
+		},
+		{
+			name:              "user request waits a few seconds",
+			responses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:        "3",
+			expectedStatus:    http.StatusOK,
+			expectedCalls:     2,
+			expectedThrottled: 1,
+			expectedWaits:     []time.Duration{3 * time.Second},
+		},
+		{
+			name:              "user request fails fast",
+			responses:         []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:        "3",
+			expectedError:     remote.ErrThrottled,
+			expectedCalls:     2,
+			expectedThrottled: 2,
+			expectedWaits:     []time.Duration{3 * time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, "payload", string(body))
+
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.responses[calls])
+				calls++
+			}))
+			defer srv.Close()
+
+			throttled := &atomic.Int64{}
+			waits := []time.Duration{}
+			transport := newThrottledTransport(nil, "tenant-"+tt.name)
+			transport.sleep = func(_ context.Context, d time.Duration) error {
+				// Waiting requests don't hold a slot of the tenant
+				require.Empty(t, transport.budget)
+				waits = append(waits, d)
+				return nil
+			}
+
+			ctx := remote.WithThrottleCounter(context.Background(), throttled)
+			if tt.background {
+				ctx = remote.BackgroundContext(ctx)
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, strings.NewReader("payload"))
+			require.NoError(t, err)
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			if tt.expectedError != nil {
+				require.ErrorIs(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				resp.Body.Close()
+				require.Equal(t, tt.expectedStatus, resp.StatusCode)
+			}
+
+			require.Equal(t, tt.expectedCalls, calls)
+			require.Equal(t, tt.expectedThrottled, throttled.Load())
+			if tt.expectedWaits != nil {
+				require.Equal(t, tt.expectedWaits, waits)
+			}
+		})
+	}
+}
+
+func TestThrottledTransportBudget(t *testing.T) {
+	var running, maxRunning atomic.Int64
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newThrottledTransport(nil, "budget-tenant")}
+	wg := sync.WaitGroup{}
+	for i := 0; i < tenantConcurrencyBudget+4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(srv.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+
+	require.Eventually(t, func() bool { return running.Load() == tenantConcurrencyBudget }, time.Second, 10*time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.EqualValues(t, tenantConcurrencyBudget, maxRunning.Load())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "", ok: false},
+		{value: "10", expected: 10 * time.Second, ok: true},
+		{value: "Mon, 04 Mar 2024 09:00:30 GMT", expected: 30 * time.Second, ok: true},
+		{value: "Mon, 04 Mar 2024 08:00:00 GMT", expected: 0, ok: true},
+		{value: "soon", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			wait, ok := retryAfter(tt.value, now)
+
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.expected, wait)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, throttleBackoffMax, throttleBackoffMax} {
+		wait := backoff(attempt)
+		require.GreaterOrEqual(t, wait, expected/2)
+		require.LessOrEqual(t, wait, expected)
+	}
+	require.LessOrEqual(t, backoff(100), throttleBackoffMax)
+}
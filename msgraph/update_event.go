@@ -0,0 +1,69 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+)
+
+// UpdateEvent changes the fields set on the given event, leaving the others
+// as they are. Updating a meeting as its organizer sends the update to the
+// attendees.
+func (c *client) UpdateEvent(remoteUserID, eventID string, in *remote.Event) (*remote.Event, error) {
+	var out = remote.Event{}
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return nil, errors.New(ErrorUserInactive)
+	}
+	err := c.rbuilder.Me().Events().ID(eventID).Request().JSONRequest(c.ctx, http.MethodPatch, "", in, &out)
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return nil, errors.Wrap(err, "msgraph UpdateEvent")
+	}
+	normalizeRecurrence(&out)
+	normalizeOnlineMeeting(&out)
+	return &out, nil
+}
+
+// CancelEvent cancels a meeting organized by the user, sending the comment
+// to the attendees with the cancellation.
+func (c *client) CancelEvent(remoteUserID, eventID, comment string) error {
+	in := struct {
+		Comment string `json:"comment,omitempty"`
+	}{
+		Comment: comment,
+	}
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+	err := c.rbuilder.Me().Events().ID(eventID).Request().JSONRequest(c.ctx, http.MethodPost, "/cancel", &in, nil)
+	// The cancellation is sent asynchronously, and acknowledged with a 202
+	// the request builder doesn't expect.
+	if err != nil && !strings.Contains(err.Error(), "202 Accepted") {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return errors.Wrap(err, "msgraph CancelEvent")
+	}
+	return nil
+}
+
+// DeleteEvent removes the event from the calendar of the user. Deleting a
+// meeting as its organizer sends a cancellation to the attendees.
+func (c *client) DeleteEvent(remoteUserID, eventID string) error {
+	if !c.tokenHelpers.CheckUserConnected(c.mattermostUserID) {
+		c.Logger.Warnf(LogUserInactive, c.mattermostUserID)
+		return errors.New(ErrorUserInactive)
+	}
+	err := c.rbuilder.Me().Events().ID(eventID).Request().Delete(c.ctx)
+	if err != nil {
+		c.tokenHelpers.DisconnectUserFromStoreIfNecessary(err, c.mattermostUserID)
+		return errors.Wrap(err, "msgraph DeleteEvent")
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Validation tokens are JWTs attached by Graph to rich notifications, see
+// https://learn.microsoft.com/en-us/graph/change-notifications-with-resource-data#validating-the-authenticity-of-notifications
+const (
+	// changeTrackingAppID is the ID of the Microsoft Graph Change Tracking
+	// application, which issues the validation tokens.
+	changeTrackingAppID = "0bf30f3b-4a52-48df-9a82-234910c4a086"
+
+	signingKeysURL = "https://login.microsoftonline.com/common/discovery/keys"
+
+	// The signing keys are read again once a day, or when a token is signed
+	// with an unknown key, at most every few minutes.
+	signingKeysRefreshInterval    = 24 * time.Hour
+	signingKeysMinRefreshInterval = 5 * time.Minute
+
+	validationTokenLeeway = 5 * time.Minute
+)
+
+var tenantIDRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-([0-9a-fA-F]{4}-){3}[0-9a-fA-F]{12}$`)
+
+// microsoftSigningKeys is shared by the remotes created on every
+// configuration change, so that the keys stay cached.
+var microsoftSigningKeys = newRemoteKeySet(signingKeysURL, &http.Client{Timeout: 30 * time.Second})
+
+// keySet returns the public keys tokens are signed with, by key ID.
+type keySet interface {
+	key(kid string) (*rsa.PublicKey, error)
+}
+
+type remoteKeySet struct {
+	url        string
+	httpClient *http.Client
+
+	lock      sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	// fetching is set while the keys are read, which is done without
+	// holding the lock.
+	fetching *keyFetch
+	now      func() time.Time
+}
+
+// keyFetch lets the requests needing the keys while they are read wait for
+// them, rather than reading them again.
+type keyFetch struct {
+	done chan struct{}
+	err  error
+}
+
+func newRemoteKeySet(url string, httpClient *http.Client) *remoteKeySet {
+	return &remoteKeySet{
+		url:        url,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+func (s *remoteKeySet) key(kid string) (*rsa.PublicKey, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for {
+		now := s.now()
+		key, ok := s.keys[kid]
+		expired := now.Sub(s.fetchedAt) > signingKeysRefreshInterval
+		if !expired && (ok || now.Sub(s.fetchedAt) <= signingKeysMinRefreshInterval) {
+			if !ok {
+				return nil, errors.Errorf("unknown signing key %q", kid)
+			}
+			return key, nil
+		}
+
+		if f := s.fetching; f != nil {
+			s.lock.Unlock()
+			<-f.done
+			s.lock.Lock()
+			if f.err != nil {
+				return nil, f.err
+			}
+			continue
+		}
+
+		f := &keyFetch{done: make(chan struct{})}
+		s.fetching = f
+		s.lock.Unlock()
+		keys, err := s.fetch()
+		s.lock.Lock()
+		s.fetching = nil
+		f.err = err
+		close(f.done)
+		if err != nil {
+			return nil, err
+		}
+		s.keys = keys
+		s.fetchedAt = now
+	}
+}
+
+func (s *remoteKeySet) fetch() (map[string]*rsa.PublicKey, error) {
+	resp, err := s.httpClient.Get(s.url)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the signing keys")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to read the signing keys: %s", resp.Status)
+	}
+
+	var v struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&v)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signing keys")
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range v.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, nErr := base64.RawURLEncoding.DecodeString(k.N)
+		e, eErr := base64.RawURLEncoding.DecodeString(k.E)
+		if nErr != nil || eErr != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+type validationTokenClaims struct {
+	Audience  audience `json:"aud"`
+	Issuer    string   `json:"iss"`
+	AppID     string   `json:"azp"`
+	TenantID  string   `json:"tid"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+}
+
+// audience is a single audience or a list of audiences.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	err := json.Unmarshal(data, &list)
+	*a = list
+	return err
+}
+
+func (a audience) contains(s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTokens checks that the validation tokens of a webhook were issued
+// by Microsoft Graph to the app, for its tenant unless the app is
+// multi-tenant.
+func (r *impl) validateTokens(tokens []string) error {
+	if len(tokens) == 0 {
+		return errors.New("missing validation tokens")
+	}
+
+	tenantID := ""
+	if tenantIDRegexp.MatchString(r.conf.OAuth2Authority) {
+		tenantID = strings.ToLower(r.conf.OAuth2Authority)
+	}
+	for _, token := range tokens {
+		err := validateToken(token, r.signingKeys, r.conf.OAuth2ClientID, tenantID, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateToken(token string, keys keySet, clientID, tenantID string, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("malformed validation token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err := decodeTokenPart(parts[0], &header)
+	if err != nil {
+		return err
+	}
+	if header.Alg != "RS256" {
+		return errors.Errorf("unexpected validation token algorithm %q", header.Alg)
+	}
+	key, err := keys.key(header.Kid)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.Wrap(err, "malformed validation token signature")
+	}
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature)
+	if err != nil {
+		return errors.Wrap(err, "invalid validation token signature")
+	}
+
+	claims := validationTokenClaims{}
+	err = decodeTokenPart(parts[1], &claims)
+	if err != nil {
+		return err
+	}
+	switch {
+	case now.After(time.Unix(claims.ExpiresAt, 0).Add(validationTokenLeeway)):
+		return errors.New("validation token expired")
+	case now.Add(validationTokenLeeway).Before(time.Unix(claims.NotBefore, 0)):
+		return errors.New("validation token not valid yet")
+	case !claims.Audience.contains(clientID):
+		return errors.New("validation token issued for another app")
+	case claims.AppID != changeTrackingAppID:
+		return errors.New("validation token not issued by Microsoft Graph")
+	case claims.Issuer != "https://sts.windows.net/"+claims.TenantID+"/":
+		return errors.New("unexpected validation token issuer")
+	case tenantID != "" && !strings.EqualFold(claims.TenantID, tenantID):
+		return errors.New("validation token issued for another tenant")
+	}
+	return nil
+}
+
+func decodeTokenPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.Wrap(err, "malformed validation token")
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return errors.Wrap(err, "malformed validation token")
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2019-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+
+package msgraph
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID = "11111111-2222-3333-4444-555555555555"
+	testTenantID = "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
+)
+
+// staticKeySet stands in for the Microsoft signing keys in tests.
+type staticKeySet map[string]*rsa.PublicKey
+
+func (s staticKeySet) key(kid string) (*rsa.PublicKey, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, errors.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func newTestSigningKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}) + "." + encode(claims)
+	hashed := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validTestClaims(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"aud": testClientID,
+		"iss": "https://sts.windows.net/" + testTenantID + "/",
+		"azp": changeTrackingAppID,
+		"tid": testTenantID,
+		"nbf": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	now := time.Now()
+	key := newTestSigningKey(t)
+	keys := staticKeySet{"kid1": &key.PublicKey}
+
+	for name, tc := range map[string]struct {
+		update      func(claims map[string]interface{})
+		kid         string
+		signWith    *rsa.PrivateKey
+		tenantID    string
+		expectedErr string
+	}{
+		"valid token":                      {},
+		"valid token for the tenant":       {tenantID: testTenantID},
+		"audience list":                    {update: func(c map[string]interface{}) { c["aud"] = []string{"other", testClientID} }},
+		"expired":                          {update: func(c map[string]interface{}) { c["exp"] = now.Add(-time.Hour).Unix() }, expectedErr: "validation token expired"},
+		"not valid yet":                    {update: func(c map[string]interface{}) { c["nbf"] = now.Add(time.Hour).Unix() }, expectedErr: "validation token not valid yet"},
+		"another app":                      {update: func(c map[string]interface{}) { c["aud"] = "other" }, expectedErr: "validation token issued for another app"},
+		"not issued by Graph":              {update: func(c map[string]interface{}) { c["azp"] = "other" }, expectedErr: "validation token not issued by Microsoft Graph"},
+		"issuer of another tenant":         {update: func(c map[string]interface{}) { c["iss"] = "https://sts.windows.net/other/" }, expectedErr: "unexpected validation token issuer"},
+		"another tenant":                   {tenantID: "ffffffff-bbbb-cccc-dddd-eeeeeeeeeeee", expectedErr: "validation token issued for another tenant"},
+		"unknown signing key":              {kid: "kid2", expectedErr: `unknown signing key "kid2"`},
+		"signed with another key":          {signWith: newTestSigningKey(t), expectedErr: "invalid validation token signature: crypto/rsa: verification error"},
+		"any tenant for multi-tenant apps": {update: func(c map[string]interface{}) { c["tid"] = "other"; c["iss"] = "https://sts.windows.net/other/" }},
+	} {
+		t.Run(name, func(t *testing.T) {
+			claims := validTestClaims(now)
+			if tc.update != nil {
+				tc.update(claims)
+			}
+			kid := "kid1"
+			if tc.kid != "" {
+				kid = tc.kid
+			}
+			signWith := key
+			if tc.signWith != nil {
+				signWith = tc.signWith
+			}
+
+			err := validateToken(signTestToken(t, signWith, kid, claims), keys, testClientID, tc.tenantID, now)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+
+	t.Run("malformed token", func(t *testing.T) {
+		require.EqualError(t, validateToken("not-a-jwt", keys, testClientID, "", now), "malformed validation token")
+	})
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	key := newTestSigningKey(t)
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"keys":[{"kty":"RSA","kid":"kid1","n":%q,"e":%q}]}`,
+			base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	keys := newRemoteKeySet(srv.URL, srv.Client())
+	keys.now = func() time.Time { return now }
+
+	got, err := keys.key("kid1")
+	require.NoError(t, err)
+	require.True(t, key.PublicKey.Equal(got))
+	_, err = keys.key("kid1")
+	require.NoError(t, err)
+	require.Equal(t, 1, fetches, "keys are cached")
+
+	_, err = keys.key("kid2")
+	require.EqualError(t, err, `unknown signing key "kid2"`)
+	require.Equal(t, 1, fetches, "unknown keys are not looked up again right away")
+
+	now = now.Add(signingKeysMinRefreshInterval + time.Second)
+	_, err = keys.key("kid2")
+	require.Error(t, err)
+	require.Equal(t, 2, fetches)
+
+	now = now.Add(signingKeysRefreshInterval + time.Second)
+	_, err = keys.key("kid1")
+	require.NoError(t, err)
+	require.Equal(t, 3, fetches, "keys are read again once a day")
+}
+
+func TestRemoteKeySetConcurrentFetch(t *testing.T) {
+	key := newTestSigningKey(t)
+	var fetches atomic.Int64
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fetches.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"keys":[{"kty":"RSA","kid":"kid1","n":%q,"e":%q}]}`,
+			base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+	}))
+	defer srv.Close()
+
+	keys := newRemoteKeySet(srv.URL, srv.Client())
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.key("kid1")
+			errs <- err
+		}()
+	}
+
+	<-started
+	// The keys are read without holding the lock
+	require.True(t, keys.lock.TryLock())
+	keys.lock.Unlock()
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 1, fetches.Load(), "concurrent requests wait for the same fetch")
+}
@@ -6,9 +6,13 @@ package main
 import (
 	mattermostplugin "github.com/mattermost/mattermost/server/public/plugin"
 
+	"github.com/mattermost/mattermost-plugin-mscalendar/caldav"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/config"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/engine"
 	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/plugin"
+	"github.com/mattermost/mattermost-plugin-mscalendar/calendar/remote"
+	"github.com/mattermost/mattermost-plugin-mscalendar/ics"
+	"github.com/mattermost/mattermost-plugin-mscalendar/local"
 	"github.com/mattermost/mattermost-plugin-mscalendar/msgraph"
 )
 
@@ -18,7 +22,15 @@ var BuildDate string
 var CalendarProvider string
 
 func main() {
-	config.Provider = msgraph.GetMSCalendarProviderConfig()
+	switch CalendarProvider {
+	case caldav.Kind:
+		config.Provider = caldav.GetCalDAVProviderConfig()
+	case local.Kind:
+		config.Provider = local.GetLocalProviderConfig()
+	default:
+		config.Provider = msgraph.GetMSCalendarProviderConfig()
+	}
+	remote.Makers[config.Provider.Name] = ics.WithFeeds(remote.Makers[config.Provider.Name])
 
 	mattermostplugin.ClientMain(
 		plugin.NewWithEnv(
@@ -14,7 +14,7 @@ import {getTheme} from 'mattermost-redux/selectors/entities/preferences';
 
 import ChannelSelector from '../channel_selector';
 
-import {CreateEventPayload} from '@/types/calendar_api_types';
+import {CreateEventPayload, CreateEventRecurrence} from '@/types/calendar_api_types';
 
 import {getModalStyles} from '@/utils/styles';
 
@@ -27,7 +27,7 @@ import {capitalizeFirstCharacter} from '@/utils/text';
 import {useAppDispatch} from '@/hooks';
 import {createCalendarEvent, refreshActiveCalendarView} from '@/actions';
 import {getEarliestTimeForToday, getTodayString} from '@/utils/datetime';
-import {getCreateEventModal} from '@/selectors';
+import {getCreateEventModal, getProviderConfiguration} from '@/selectors';
 
 import './create_event_form.scss';
 
@@ -174,6 +174,7 @@ type ActualFormProps = {
 
 const ActualForm = (props: ActualFormProps) => {
     const {formValues, setFormValue} = props;
+    const providerConfig = useSelector(getProviderConfiguration);
 
     const components = [
         {
@@ -270,6 +271,38 @@ const ActualForm = (props: ActualFormProps) => {
                 />
             ),
         },
+        {
+            id: 'recurrence',
+            label: 'Repeat',
+            component: (
+                <select
+                    id='recurrence'
+                    onChange={(e) => setFormValue('recurrence', e.target.value ? {frequency: e.target.value as CreateEventRecurrence['frequency']} : undefined)}
+                    value={formValues.recurrence?.frequency || ''}
+                    className='form-control'
+                >
+                    <option value=''>{'Does not repeat'}</option>
+                    <option value='daily'>{'Daily'}</option>
+                    <option value='weekly'>{'Weekly'}</option>
+                    <option value='monthly'>{'Monthly'}</option>
+                </select>
+            ),
+        },
+        ...(providerConfig?.Features?.OnlineMeetings ? [{
+            id: 'online_meeting',
+            label: 'Online meeting',
+            component: (
+                <label>
+                    <input
+                        id='online_meeting'
+                        type='checkbox'
+                        onChange={(e) => setFormValue('online_meeting', e.target.checked)}
+                        checked={Boolean(formValues.online_meeting)}
+                    />
+                    {' Add a Teams meeting link'}
+                </label>
+            ),
+        }] : []),
         {
             id: 'description',
             label: 'Description (optional)',
@@ -169,6 +169,7 @@ export type ProviderFeatures = {
     EncryptedStore: boolean;
     EventNotifications: boolean;
     EnableExperimentalUI: boolean;
+    OnlineMeetings?: boolean;
 }
 
 export type ProviderConfig = {
@@ -9,4 +9,16 @@ export type CreateEventPayload = {
     subject: string;
     location?: string;
     channel_id?: string;
+    calendar_id?: string;
+    recurrence?: CreateEventRecurrence;
+    online_meeting?: boolean;
+    rooms?: string[];
+}
+
+export type CreateEventRecurrence = {
+    frequency: 'daily' | 'weekly' | 'monthly';
+    interval?: number;
+    days_of_week?: string[];
+    end_date?: string;
+    count?: number;
 }